@@ -0,0 +1,93 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import "github.com/leansecurity-co/gwork/internal/config"
+
+// Alert is the check of one alert threshold against the findings of an
+// audit.
+type Alert struct {
+	Key   string // Config key under alert, e.g. "max_public_shares"
+	Label string // What is counted, e.g. "public shares"
+	Count int
+	Limit int
+}
+
+// Breached reports whether the count is above the limit; a count at the
+// limit is allowed.
+func (a Alert) Breached() bool {
+	return a.Count > a.Limit
+}
+
+// ShareCounts are the share totals alert thresholds are checked against.
+type ShareCounts struct {
+	External int
+	Public   int
+	Writers  int
+}
+
+// AddShares counts the public shares and external writers among records.
+// External is left to the caller, who may count records not held.
+func (c *ShareCounts) AddShares(records []ExternalShareRecord) {
+	c.Public += CountPublicShares(records)
+	c.Writers += CountWriters(records)
+}
+
+// EvaluateAlerts checks the thresholds set in cfg against the shares of
+// results and returns one Alert per threshold set, in config order.
+// External shares are counted from TotalExternalShares, so they include
+// records dropped by Truncate; public shares and external writers are
+// counted from the records.
+func EvaluateAlerts(cfg config.AlertConfig, results ...*AuditResult) []Alert {
+	var counts ShareCounts
+	for _, result := range results {
+		counts.External += result.TotalExternalShares
+		counts.AddShares(result.ExternalShares)
+	}
+	return EvaluateAlertCounts(cfg, counts)
+}
+
+// EvaluateAlertCounts checks the thresholds set in cfg against counts, for
+// audits that do not hold their records, and returns one Alert per
+// threshold set, in config order.
+func EvaluateAlertCounts(cfg config.AlertConfig, counts ShareCounts) []Alert {
+	var alerts []Alert
+	for _, check := range []struct {
+		key, label string
+		limit      *int
+		count      int
+	}{
+		{"max_external_shares", "external shares", cfg.MaxExternalShares, counts.External},
+		{"max_public_shares", "public shares", cfg.MaxPublicShares, counts.Public},
+		{"max_external_writers", "external writers", cfg.MaxExternalWriters, counts.Writers},
+	} {
+		if check.limit != nil {
+			alerts = append(alerts, Alert{Key: check.key, Label: check.label, Count: check.count, Limit: *check.limit})
+		}
+	}
+	return alerts
+}
+
+// CountBreached returns the number of alerts whose threshold is breached.
+func CountBreached(alerts []Alert) int {
+	count := 0
+	for _, a := range alerts {
+		if a.Breached() {
+			count++
+		}
+	}
+	return count
+}
+
+// CountWriters returns the number of records granting the writer role or a
+// more privileged one.
+func CountWriters(records []ExternalShareRecord) int {
+	count := 0
+	for _, rec := range records {
+		if order, ok := roleOrder[rec.PermissionRole]; ok && order <= roleOrder["writer"] {
+			count++
+		}
+	}
+	return count
+}
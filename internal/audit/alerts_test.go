@@ -0,0 +1,98 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluateAlerts(t *testing.T) {
+	limit := func(n int) *int { return &n }
+	result := &AuditResult{
+		TotalExternalShares: 5,
+		ExternalShares: []ExternalShareRecord{
+			{PermissionType: "anyone", PermissionRole: "reader"},
+			{PermissionType: "anyone", PermissionRole: "writer"},
+			{PermissionType: "user", PermissionRole: "writer"},
+			{PermissionType: "user", PermissionRole: "fileOrganizer"},
+			{PermissionType: "user", PermissionRole: "commenter"},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		cfg          config.AlertConfig
+		want         []Alert
+		wantBreached int
+	}{
+		{name: "no thresholds"},
+		{
+			name: "below threshold",
+			cfg:  config.AlertConfig{MaxExternalShares: limit(6)},
+			want: []Alert{{Key: "max_external_shares", Label: "external shares", Count: 5, Limit: 6}},
+		},
+		{
+			name: "at threshold",
+			cfg:  config.AlertConfig{MaxPublicShares: limit(2)},
+			want: []Alert{{Key: "max_public_shares", Label: "public shares", Count: 2, Limit: 2}},
+		},
+		{
+			name:         "above threshold",
+			cfg:          config.AlertConfig{MaxExternalWriters: limit(2)},
+			want:         []Alert{{Key: "max_external_writers", Label: "external writers", Count: 3, Limit: 2}},
+			wantBreached: 1,
+		},
+		{
+			name: "zero allows none",
+			cfg:  config.AlertConfig{MaxExternalShares: limit(4), MaxPublicShares: limit(0), MaxExternalWriters: limit(3)},
+			want: []Alert{
+				{Key: "max_external_shares", Label: "external shares", Count: 5, Limit: 4},
+				{Key: "max_public_shares", Label: "public shares", Count: 2, Limit: 0},
+				{Key: "max_external_writers", Label: "external writers", Count: 3, Limit: 3},
+			},
+			wantBreached: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts := EvaluateAlerts(tt.cfg, result)
+			assert.Equal(t, tt.want, alerts)
+			assert.Equal(t, tt.wantBreached, CountBreached(alerts))
+		})
+	}
+}
+
+func TestEvaluateAlerts_SumsResults(t *testing.T) {
+	files := &AuditResult{FileRecords: []FileRecord{{FileID: "f1"}}}
+	sharing := &AuditResult{
+		TotalExternalShares: 3,
+		ExternalShares:      []ExternalShareRecord{{PermissionType: "anyone", PermissionRole: "reader"}},
+	}
+	limit := 2
+	alerts := EvaluateAlerts(config.AlertConfig{MaxExternalShares: &limit}, files, sharing)
+	assert.Equal(t, []Alert{{Key: "max_external_shares", Label: "external shares", Count: 3, Limit: 2}}, alerts)
+	assert.True(t, alerts[0].Breached())
+}
+
+func TestEvaluateAlertCounts(t *testing.T) {
+	var counts ShareCounts
+	counts.AddShares([]ExternalShareRecord{
+		{PermissionType: "anyone", PermissionRole: "reader"},
+		{PermissionType: "user", PermissionRole: "writer"},
+	})
+	counts.AddShares([]ExternalShareRecord{{PermissionType: "anyone", PermissionRole: "writer"}})
+	counts.External = 5
+	assert.Equal(t, ShareCounts{External: 5, Public: 2, Writers: 2}, counts)
+
+	limit := 1
+	alerts := EvaluateAlertCounts(config.AlertConfig{MaxPublicShares: &limit, MaxExternalWriters: &limit}, counts)
+	assert.Equal(t, []Alert{
+		{Key: "max_public_shares", Label: "public shares", Count: 2, Limit: 1},
+		{Key: "max_external_writers", Label: "external writers", Count: 2, Limit: 1},
+	}, alerts)
+}
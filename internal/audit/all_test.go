@@ -0,0 +1,101 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/config"
+	"github.com/leansecurity-co/gwork/internal/drive"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditAll_ListsFilesOnce(t *testing.T) {
+	tests := []struct {
+		name              string
+		includeLinkStatus bool
+		wantPermCalls     int
+	}{
+		{name: "concurrent record conversion", wantPermCalls: 2},
+		{name: "link status runs first", includeLinkStatus: true, wantPermCalls: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockDriveClient)
+			files := []drive.FileInfo{
+				{ID: "file1", Name: "a.txt", OwnerEmail: "alice@example.com"},
+				{ID: "file2", Name: "b.txt", OwnerEmail: "bob@example.com"},
+			}
+			external := drive.Permission{ID: "p1", Type: "anyone", Role: "reader"}
+			owner := drive.Permission{ID: "p2", Type: "user", Role: "owner", EmailAddress: "bob@example.com"}
+
+			mockClient.On("ListAllFiles", mock.Anything).Return(files, nil).Once()
+			mockClient.On("GetFilePermissions", mock.Anything, "file1").Return([]drive.Permission{external}, nil)
+			mockClient.On("GetFilePermissions", mock.Anything, "file2").Return([]drive.Permission{owner}, nil)
+			mockClient.On("IsExternalShare", external).Return(true)
+			mockClient.On("IsExternalShare", owner).Return(false)
+
+			cfg := &config.Config{Audit: config.AuditConfig{Concurrency: 2, IncludeLinkStatus: tt.includeLinkStatus}}
+			filesResult, sharingResult, err := NewAuditorWithClient(cfg, mockClient).AuditAll(context.Background())
+			require.NoError(t, err)
+
+			mockClient.AssertNumberOfCalls(t, "ListAllFiles", 1)
+			mockClient.AssertNumberOfCalls(t, "GetFilePermissions", tt.wantPermCalls)
+
+			require.Len(t, filesResult.FileRecords, 2)
+			assert.Equal(t, 2, filesResult.TotalFiles)
+			assert.Equal(t, 2, sharingResult.TotalFiles)
+			assert.Equal(t, 2, sharingResult.FilesProcessed)
+			require.Len(t, sharingResult.ExternalShares, 1)
+			assert.Equal(t, "file1", sharingResult.ExternalShares[0].FileID)
+
+			if tt.includeLinkStatus {
+				require.NotNil(t, filesResult.FileRecords[0].LinkSharingEnabled)
+				assert.True(t, *filesResult.FileRecords[0].LinkSharingEnabled)
+			}
+		})
+	}
+}
+
+func TestAuditAll_SampleSharedByBothAudits(t *testing.T) {
+	api := newPagedDriveAPI(50, 10)
+	client := drive.NewClientWithAPI(api, "example.com", 10, false)
+
+	auditor := NewAuditorWithClient(&config.Config{Audit: config.AuditConfig{Concurrency: 4}}, client)
+	auditor.SetSample(10, 7)
+	filesResult, sharingResult, err := auditor.AuditAll(context.Background())
+	require.NoError(t, err)
+
+	fileIDs := make(map[string]bool)
+	for _, rec := range filesResult.FileRecords {
+		fileIDs[rec.FileID] = true
+	}
+	assert.Len(t, fileIDs, 10)
+	assert.Equal(t, 10, filesResult.SampledFiles)
+	assert.Equal(t, 10, sharingResult.SampledFiles)
+	for _, rec := range sharingResult.ExternalShares {
+		assert.True(t, fileIDs[rec.FileID], "share on %s is outside the files report", rec.FileID)
+	}
+
+	stats := client.Stats()
+	assert.Equal(t, int64(5), stats.ListFiles.Calls, "files are listed once")
+	assert.Equal(t, stats.ListFiles.Calls, filesResult.Timing.API.ListFiles.Calls)
+	assert.Zero(t, sharingResult.Timing.API.ListFiles.Calls, "the listing is counted in the files result")
+}
+
+func TestAuditAll_ListError(t *testing.T) {
+	mockClient := new(MockDriveClient)
+	mockClient.On("ListAllFiles", mock.Anything).Return(nil, errors.New("quota exceeded"))
+
+	_, _, err := NewAuditorWithClient(&config.Config{}, mockClient).AuditAll(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "files audit failed")
+	mockClient.AssertNumberOfCalls(t, "ListAllFiles", 1)
+	mockClient.AssertNotCalled(t, "GetFilePermissions", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,111 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// anonymizedHashLength is the number of hex characters kept from each hash.
+const anonymizedHashLength = 12
+
+// Anonymizer replaces emails, names, file names and drive names with stable
+// salted hashes. The same input always maps to the same output for a given
+// salt.
+type Anonymizer struct {
+	salt []byte
+}
+
+// NewAnonymizer creates an Anonymizer. An empty salt selects a random salt,
+// so mappings are only stable within a single run.
+func NewAnonymizer(salt string) (*Anonymizer, error) {
+	if salt != "" {
+		return &Anonymizer{salt: []byte(salt)}, nil
+	}
+
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return nil, fmt.Errorf("failed to generate anonymization salt: %w", err)
+	}
+	return &Anonymizer{salt: random}, nil
+}
+
+// Email hashes the local part of an email address and keeps its domain.
+func (a *Anonymizer) Email(email string) string {
+	if email == "" {
+		return ""
+	}
+
+	idx := strings.LastIndex(email, "@")
+	if idx < 0 {
+		return a.hash(email)
+	}
+	return a.hash(email[:idx]) + email[idx:]
+}
+
+// Name hashes a person's display name.
+func (a *Anonymizer) Name(name string) string {
+	if name == "" {
+		return ""
+	}
+	return "name_" + a.hash(name)
+}
+
+// FileName hashes a file name.
+func (a *Anonymizer) FileName(name string) string {
+	if name == "" {
+		return ""
+	}
+	return "file_" + a.hash(name)
+}
+
+// DriveName hashes a shared drive name.
+func (a *Anonymizer) DriveName(name string) string {
+	if name == "" {
+		return ""
+	}
+	return "drive_" + a.hash(name)
+}
+
+// FileRecords returns anonymized copies of the records.
+func (a *Anonymizer) FileRecords(records []FileRecord) []FileRecord {
+	out := make([]FileRecord, len(records))
+	for i, rec := range records {
+		rec.OwnerEmail = a.Email(rec.OwnerEmail)
+		rec.OwnerName = a.Name(rec.OwnerName)
+		rec.FileName = a.FileName(rec.FileName)
+		rec.DriveName = a.DriveName(rec.DriveName)
+		out[i] = rec
+	}
+	return out
+}
+
+// ExternalShares returns anonymized copies of the records.
+func (a *Anonymizer) ExternalShares(records []ExternalShareRecord) []ExternalShareRecord {
+	out := make([]ExternalShareRecord, len(records))
+	for i, rec := range records {
+		rec.OwnerEmail = a.Email(rec.OwnerEmail)
+		rec.OwnerName = a.Name(rec.OwnerName)
+		rec.FileName = a.FileName(rec.FileName)
+		rec.DriveName = a.DriveName(rec.DriveName)
+		if rec.SharedWithEmail != "" {
+			hashed := a.Email(rec.SharedWithEmail)
+			rec.Explanation = strings.ReplaceAll(rec.Explanation, rec.SharedWithEmail, hashed)
+			rec.SharedWithEmail = hashed
+		}
+		out[i] = rec
+	}
+	return out
+}
+
+func (a *Anonymizer) hash(value string) string {
+	mac := hmac.New(sha256.New, a.salt)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))[:anonymizedHashLength]
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnonymizer_Email(t *testing.T) {
+	a, err := NewAnonymizer("fixed-salt")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		email      string
+		wantDomain string
+	}{
+		{name: "standard email", email: "alice@example.com", wantDomain: "@example.com"},
+		{name: "plus addressing", email: "bob+tag@partner.org", wantDomain: "@partner.org"},
+		{name: "multiple @ symbols", email: "user@name@example.co.uk", wantDomain: "@example.co.uk"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := a.Email(tt.email)
+			assert.True(t, strings.HasSuffix(got, tt.wantDomain), "domain should be preserved: %s", got)
+			assert.NotContains(t, got, strings.Split(tt.email, "@")[0])
+			assert.Len(t, got, anonymizedHashLength+len(tt.wantDomain))
+		})
+	}
+
+	assert.Equal(t, "", a.Email(""))
+	assert.Len(t, a.Email("notanemail"), anonymizedHashLength)
+}
+
+func TestAnonymizer_StableWithFixedSalt(t *testing.T) {
+	a1, err := NewAnonymizer("fixed-salt")
+	require.NoError(t, err)
+	a2, err := NewAnonymizer("fixed-salt")
+	require.NoError(t, err)
+	other, err := NewAnonymizer("other-salt")
+	require.NoError(t, err)
+
+	assert.Equal(t, a1.Email("alice@example.com"), a2.Email("alice@example.com"))
+	assert.Equal(t, a1.FileName("Budget.xlsx"), a2.FileName("Budget.xlsx"))
+	assert.NotEqual(t, a1.Email("alice@example.com"), other.Email("alice@example.com"))
+	assert.NotEqual(t, a1.Email("alice@example.com"), a1.Email("bob@example.com"))
+	assert.True(t, strings.HasPrefix(a1.FileName("Budget.xlsx"), "file_"))
+}
+
+func TestAnonymizer_RandomSaltPerRun(t *testing.T) {
+	a1, err := NewAnonymizer("")
+	require.NoError(t, err)
+	a2, err := NewAnonymizer("")
+	require.NoError(t, err)
+
+	assert.Equal(t, a1.Email("alice@example.com"), a1.Email("alice@example.com"))
+	assert.NotEqual(t, a1.Email("alice@example.com"), a2.Email("alice@example.com"))
+}
+
+func TestAnonymizer_Records(t *testing.T) {
+	a, err := NewAnonymizer("fixed-salt")
+	require.NoError(t, err)
+
+	files := []FileRecord{
+		{OwnerEmail: "alice@example.com", OwnerName: "Alice", FileID: "file1", FileName: "secret.pdf", SizeBytes: 10},
+	}
+	shares := []ExternalShareRecord{
+		{OwnerEmail: "alice@example.com", FileID: "file1", FileName: "secret.pdf", SharedWithEmail: "guest@partner.com", SharedWithDomain: "partner.com", PermissionType: "user",
+			Explanation: "guest@partner.com is outside example.com"},
+	}
+
+	anonFiles := a.FileRecords(files)
+	anonShares := a.ExternalShares(shares)
+
+	// Originals are untouched
+	assert.Equal(t, "alice@example.com", files[0].OwnerEmail)
+	assert.Equal(t, "secret.pdf", shares[0].FileName)
+
+	assert.Equal(t, a.Email("alice@example.com"), anonFiles[0].OwnerEmail)
+	assert.Equal(t, a.Name("Alice"), anonFiles[0].OwnerName)
+	assert.Equal(t, a.FileName("secret.pdf"), anonFiles[0].FileName)
+	assert.Equal(t, "file1", anonFiles[0].FileID)
+	assert.Equal(t, int64(10), anonFiles[0].SizeBytes)
+
+	assert.Equal(t, anonFiles[0].OwnerEmail, anonShares[0].OwnerEmail)
+	assert.Equal(t, anonFiles[0].FileName, anonShares[0].FileName)
+	assert.Equal(t, a.Email("guest@partner.com"), anonShares[0].SharedWithEmail)
+	assert.Equal(t, "partner.com", anonShares[0].SharedWithDomain)
+	assert.Equal(t, a.Email("guest@partner.com")+" is outside example.com", anonShares[0].Explanation)
+}
@@ -5,48 +5,142 @@ package audit
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"time"
 
 	"github.com/leansecurity-co/gwork/internal/auth"
 	"github.com/leansecurity-co/gwork/internal/config"
+	"github.com/leansecurity-co/gwork/internal/directory"
 	"github.com/leansecurity-co/gwork/internal/drive"
+	"github.com/leansecurity-co/gwork/pkg/exitcode"
 )
 
 // Auditor orchestrates audit operations.
 type Auditor struct {
-	config      *config.Config
-	driveClient DriveClient
+	config        *config.Config
+	driveClient   DriveClient
+	groupResolver GroupResolver
+	sampleSize    int
+	sampleSeed    int64
+	ignoreFileIDs map[string]struct{}
+
+	flaggedDomains map[string]struct{}
+	classifier     ShareClassifier
+	explain        bool
+
+	// externalRoles limits the external sharing audit to these roles;
+	// nil means every role.
+	externalRoles map[string]struct{}
+
+	// fileSnapshots holds the permissions saved by the previous run; see
+	// SetFileSnapshots.
+	fileSnapshots map[string]FileSnapshot
+
+	// checkpoint is set by SetCheckpoint; checkpointEvery overrides
+	// defaultCheckpointInterval in tests.
+	checkpoint      *checkpointer
+	checkpointEvery int
+
+	// permissionRetryDelay overrides defaultPermissionRetryDelay in tests.
+	permissionRetryDelay time.Duration
 }
 
 // NewAuditor creates a new Auditor instance with the production drive client.
+// It is NewAuditorContext with context.Background().
 func NewAuditor(cfg *config.Config) (*Auditor, error) {
+	return NewAuditorContext(context.Background(), cfg)
+}
+
+// NewAuditorContext creates a new Auditor instance with the production drive
+// client. ctx bounds service creation and is also used to fetch OAuth
+// tokens for the lifetime of the auditor, so it should outlive the audit.
+func NewAuditorContext(ctx context.Context, cfg *config.Config) (*Auditor, error) {
 	authenticator, err := auth.NewAuthenticator(
 		cfg.Google.ServiceAccountFile,
 		cfg.Google.AdminEmail,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create authenticator: %w", err)
+		return nil, exitcode.Wrap(exitcode.AuthError, fmt.Errorf("failed to create authenticator: %w", err))
+	}
+	authenticator.SetQuotaProject(cfg.Google.QuotaProject)
+	authenticator.SetDriveEndpoint(cfg.Google.APIEndpoint)
+	if cfg.Google.ProxyURL != "" || cfg.Google.CACertFile != "" {
+		transport, err := auth.NewTransport(cfg.Google.ProxyURL, cfg.Google.CACertFile)
+		if err != nil {
+			return nil, exitcode.Wrap(exitcode.ConfigError, fmt.Errorf("failed to configure HTTP transport: %w", err))
+		}
+		authenticator.SetTransport(transport)
 	}
 
-	ctx := context.Background()
 	driveService, err := authenticator.GetDriveService(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create drive service: %w", err)
+		return nil, exitcode.Wrap(exitcode.AuthError, fmt.Errorf("failed to create drive service: %w", err))
 	}
 
-	driveClient := drive.NewClient(
-		driveService,
-		cfg.Google.Domain,
-		cfg.Audit.PageSize,
-		cfg.Audit.IncludeSharedDrives,
-	)
+	opts, err := DriveOptions(cfg)
+	if err != nil {
+		return nil, exitcode.Wrap(exitcode.ConfigError, err)
+	}
+	driveClient := drive.NewClientWithOptions(drive.NewGoogleDriveAPI(driveService), opts)
 
-	return &Auditor{
+	auditor := &Auditor{
 		config:      cfg,
 		driveClient: driveClient,
+	}
+	if err := auditor.applyConfig(); err != nil {
+		return nil, exitcode.Wrap(exitcode.ConfigError, err)
+	}
+
+	if cfg.Audit.ExpandGroups {
+		directoryService, err := authenticator.GetDirectoryService(ctx)
+		if err != nil {
+			return nil, exitcode.Wrap(exitcode.AuthError, fmt.Errorf("failed to create directory service: %w", err))
+		}
+		auditor.SetGroupResolver(directory.NewGroupResolver(directoryService))
+	}
+
+	return auditor, nil
+}
+
+// DriveOptions returns the Drive client options set by cfg.
+func DriveOptions(cfg *config.Config) (drive.Options, error) {
+	internalEmails, err := cfg.Google.InternalEmailPattern()
+	if err != nil {
+		return drive.Options{}, err
+	}
+	return drive.Options{
+		Domain:              cfg.Google.Domain,
+		DomainAliases:       cfg.Google.DomainAliases,
+		InternalEmails:      internalEmails,
+		PageSize:            cfg.Audit.PageSize,
+		IncludeSharedDrives: cfg.Audit.IncludeSharedDrives,
+		Corpora:             cfg.Audit.Corpora,
+		DriveIDs:            cfg.Audit.DriveIDs,
+		IncludeTrashed:      cfg.Audit.IncludeTrashed,
+		Query:               cfg.Audit.Query,
+		FileFields:          cfg.Audit.FileFields,
+		PermissionFields:    cfg.Audit.PermissionFields,
+		StrictUnknown:       cfg.Audit.StrictUnknown,
+		MaxAPICalls:         cfg.Audit.MaxAPICalls,
+		MaxQPS:              cfg.Audit.MaxQPS,
+		RetryStatusCodes:    cfg.Audit.RetryStatusCodes,
 	}, nil
 }
 
+// applyConfig sets the ignored files and flagged domains from the config.
+func (a *Auditor) applyConfig() error {
+	ignored, err := a.config.Audit.IgnoredFileIDs()
+	if err != nil {
+		return err
+	}
+	a.SetIgnoreFileIDs(ignored...)
+	a.SetFlaggedDomains(a.config.Audit.FlaggedDomains...)
+	a.SetExternalRolesOfInterest(a.config.Audit.ExternalRolesOfInterest...)
+	return nil
+}
+
 // NewAuditorWithClient creates a new Auditor instance with a custom DriveClient.
 // This is primarily used for testing.
 func NewAuditorWithClient(cfg *config.Config, client DriveClient) *Auditor {
@@ -56,17 +150,178 @@ func NewAuditorWithClient(cfg *config.Config, client DriveClient) *Auditor {
 	}
 }
 
-// AuditAll performs all audit operations.
+// SetGroupResolver enables group expansion in the sharing audit using the
+// given resolver. A nil resolver disables it.
+func (a *Auditor) SetGroupResolver(resolver GroupResolver) {
+	a.groupResolver = resolver
+}
+
+// SetThrottleLog makes the Drive client write a line to w whenever it
+// changes its request rate. Clients without a throttle ignore it.
+func (a *Auditor) SetThrottleLog(w io.Writer) {
+	if client, ok := a.driveClient.(interface{ SetThrottleLog(io.Writer) }); ok {
+		client.SetThrottleLog(w)
+	}
+}
+
+// SetActivityLog makes the Drive client record each API call as a line of
+// JSON in w. A nil w stops recording; clients that cannot record ignore it.
+func (a *Auditor) SetActivityLog(w io.Writer) {
+	if client, ok := a.driveClient.(interface{ SetActivityLog(io.Writer) }); ok {
+		client.SetActivityLog(w)
+	}
+}
+
+// SetShareClassifier sets the classifier that labels and scores each share
+// found by the sharing audits. A nil classifier restores DefaultClassifier.
+func (a *Auditor) SetShareClassifier(classifier ShareClassifier) {
+	a.classifier = classifier
+}
+
+// AuditAll performs the files and external sharing audits. Files are listed
+// once and both audits use the same list, so the reports cover the same
+// file set. Building file records makes no API calls, so it runs while the
+// sharing audit fetches permissions; with audit.include_link_status the
+// files audit fetches permissions too and runs first instead.
+//
+// The listing is counted in the files result only: the sharing result's
+// API stats cover just its permission fetch, and OwnersMissing is only set
+// on the files result.
 func (a *Auditor) AuditAll(ctx context.Context) (*AuditResult, *AuditResult, error) {
-	filesResult, err := a.AuditFiles(ctx)
+	start, startStats := time.Now(), a.apiStats()
+
+	listing, err := a.listFiles(ctx)
 	if err != nil {
 		return nil, nil, fmt.Errorf("files audit failed: %w", err)
 	}
 
-	sharingResult, err := a.AuditExternalSharing(ctx)
+	var filesResult *AuditResult
+	filesDone := make(chan struct{})
+	auditFiles := func() {
+		defer close(filesDone)
+		filesResult = a.auditListedFiles(ctx, listing)
+		filesResult.Timing.Total = time.Since(start)
+	}
+
+	listedStats := a.apiStats()
+	if a.includeLinkStatus() {
+		auditFiles()
+		listedStats = a.apiStats()
+	} else {
+		go auditFiles()
+	}
+
+	sharingResult, err := a.auditListedShares(ctx, listing, a.isExternalShareOfInterest)
 	if err != nil {
+		<-filesDone
+		return nil, nil, fmt.Errorf("sharing audit failed: %w", err)
+	}
+	sharingResult.OwnersMissing = 0
+	sharingResult.Timing.Total = time.Since(start)
+	sharingResult.Timing.API = a.apiStats().Sub(listedStats)
+
+	<-filesDone
+	filesResult.Timing.API = listedStats.Sub(startStats)
+
+	if err := ctx.Err(); err != nil {
 		return filesResult, nil, fmt.Errorf("sharing audit failed: %w", err)
 	}
 
 	return filesResult, sharingResult, nil
 }
+
+// fileListing is the file list an audit works on, after ignored files are
+// removed and sampling is applied.
+type fileListing struct {
+	files          []drive.FileInfo
+	total          int
+	suppressed     int
+	ownersMissing  int
+	budgetExceeded bool
+	duration       time.Duration
+}
+
+// listFiles lists all files for an audit. Running out of API call budget
+// is not an error: the files listed so far are returned and the listing is
+// marked as partial. Sampled audits stream the listing through the sample
+// when the drive client is a FileVisitor.
+func (a *Auditor) listFiles(ctx context.Context) (*fileListing, error) {
+	if visitor, ok := a.driveClient.(FileVisitor); ok && a.sampleSize > 0 {
+		return a.listSample(ctx, visitor)
+	}
+	return a.listFrom(ctx, a.driveClient.ListAllFiles)
+}
+
+// listFrom builds the file listing from the files returned by list.
+func (a *Auditor) listFrom(ctx context.Context, list func(context.Context) ([]drive.FileInfo, error)) (*fileListing, error) {
+	start := time.Now()
+
+	files, err := list(ctx)
+	budgetExceeded := errors.Is(err, drive.ErrBudgetExceeded)
+	if err != nil && !budgetExceeded {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	listing := &fileListing{budgetExceeded: budgetExceeded}
+	files, listing.suppressed = FilterIgnoredFiles(files, a.ignoreFileIDs)
+	listing.total = len(files)
+	listing.ownersMissing = ownersMissing(files)
+	listing.duration = time.Since(start)
+	listing.files = a.sampleFiles(files)
+	return listing, nil
+}
+
+// ownersMissing returns the number of My Drive files listed when none of
+// them has an owner, and 0 otherwise. My Drive files always have an owner,
+// so this means Drive withheld the owners field, e.g. because it is missing
+// from audit.file_fields or the scopes granted do not cover it. Shared
+// drive files have no owner and are not counted.
+func ownersMissing(files []drive.FileInfo) int {
+	var owners ownerTracker
+	for _, f := range files {
+		owners.add(f)
+	}
+	return owners.missing()
+}
+
+// ownerTracker counts the My Drive files listed one at a time, noting
+// whether any had an owner; see ownersMissing.
+type ownerTracker struct {
+	myDrive  int
+	resolved bool
+}
+
+// add counts file.
+func (t *ownerTracker) add(file drive.FileInfo) {
+	if file.DriveID != "" {
+		return
+	}
+	if file.OwnerEmail != "" || file.OwnerName != "" {
+		t.resolved = true
+	}
+	t.myDrive++
+}
+
+// missing returns the number of My Drive files added when none of them
+// had an owner, and 0 otherwise.
+func (t *ownerTracker) missing() int {
+	if t.resolved {
+		return 0
+	}
+	return t.myDrive
+}
+
+// newResult returns an AuditResult carrying the listing totals.
+func (l *fileListing) newResult() *AuditResult {
+	result := &AuditResult{
+		BudgetExceeded:  l.budgetExceeded,
+		SuppressedCount: l.suppressed,
+		TotalFiles:      l.total,
+		OwnersMissing:   l.ownersMissing,
+	}
+	if len(l.files) < l.total {
+		result.SampledFiles = len(l.files)
+	}
+	result.Timing.ListFiles = l.duration
+	return result
+}
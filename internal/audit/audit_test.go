@@ -0,0 +1,27 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAuditorContext_Canceled(t *testing.T) {
+	saFile := filepath.Join(t.TempDir(), "sa.json")
+	require.NoError(t, os.WriteFile(saFile, []byte("{}"), 0o600))
+	cfg := &config.Config{Google: config.GoogleConfig{ServiceAccountFile: saFile, AdminEmail: "admin@example.com"}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := NewAuditorContext(ctx, cfg)
+	assert.ErrorIs(t, err, context.Canceled)
+}
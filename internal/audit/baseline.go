@@ -0,0 +1,53 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"sort"
+	"strings"
+)
+
+// Key identifies the grant behind a share record across runs: the file,
+// the permission type and role, and the grantee. Renames and owner changes
+// do not change the key; a role change does.
+func (r ExternalShareRecord) Key() string {
+	grantee := r.SharedWithEmail
+	if grantee == "" {
+		grantee = r.SharedWithDomain
+	}
+	return strings.Join([]string{r.FileID, r.PermissionType, r.PermissionRole, strings.ToLower(grantee)}, "\x00")
+}
+
+// ShareKeys returns the sorted, deduplicated keys of records.
+func ShareKeys(records []ExternalShareRecord) []string {
+	seen := make(map[string]struct{}, len(records))
+	keys := make([]string, 0, len(records))
+	for _, rec := range records {
+		key := rec.Key()
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// NewShares returns the records whose key is not among baseline, keeping
+// their order.
+func NewShares(records []ExternalShareRecord, baseline []string) []ExternalShareRecord {
+	known := make(map[string]struct{}, len(baseline))
+	for _, key := range baseline {
+		known[key] = struct{}{}
+	}
+
+	added := make([]ExternalShareRecord, 0)
+	for _, rec := range records {
+		if _, ok := known[rec.Key()]; !ok {
+			added = append(added, rec)
+		}
+	}
+	return added
+}
@@ -0,0 +1,60 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExternalShareRecord_Key(t *testing.T) {
+	base := ExternalShareRecord{FileID: "f1", FileName: "Plan", OwnerEmail: "alice@example.com", SharedWithEmail: "Bob@Partner.com", SharedWithDomain: "partner.com", PermissionType: "user", PermissionRole: "reader"}
+
+	renamed := base
+	renamed.FileName = "Plan v2"
+	renamed.OwnerEmail = "carol@example.com"
+	renamed.SharedWithEmail = "bob@partner.com"
+	assert.Equal(t, base.Key(), renamed.Key(), "rename, owner change and email case should not change the key")
+
+	upgraded := base
+	upgraded.PermissionRole = "writer"
+	assert.NotEqual(t, base.Key(), upgraded.Key())
+
+	domain := ExternalShareRecord{FileID: "f1", SharedWithDomain: "partner.com", PermissionType: "domain", PermissionRole: "reader"}
+	public := ExternalShareRecord{FileID: "f1", PermissionType: "anyone", PermissionRole: "reader"}
+	assert.NotEqual(t, domain.Key(), public.Key())
+}
+
+func TestShareKeys(t *testing.T) {
+	records := []ExternalShareRecord{
+		{FileID: "f2", SharedWithEmail: "x@partner.com", PermissionType: "user", PermissionRole: "reader"},
+		{FileID: "f1", SharedWithEmail: "x@partner.com", PermissionType: "user", PermissionRole: "reader"},
+		{FileID: "f2", SharedWithEmail: "X@partner.com", PermissionType: "user", PermissionRole: "reader"},
+	}
+
+	keys := ShareKeys(records)
+	assert.Equal(t, []string{records[1].Key(), records[0].Key()}, keys)
+}
+
+func TestNewShares_SeededBaseline(t *testing.T) {
+	old := []ExternalShareRecord{
+		{FileID: "f1", SharedWithEmail: "bob@partner.com", PermissionType: "user", PermissionRole: "reader"},
+		{FileID: "f2", PermissionType: "anyone", PermissionRole: "reader"},
+	}
+	baseline := ShareKeys(old)
+
+	current := []ExternalShareRecord{
+		{FileID: "f1", FileName: "renamed", SharedWithEmail: "bob@partner.com", PermissionType: "user", PermissionRole: "reader"},
+		{FileID: "f1", SharedWithEmail: "bob@partner.com", PermissionType: "user", PermissionRole: "writer"},
+		{FileID: "f2", PermissionType: "anyone", PermissionRole: "reader"},
+		{FileID: "f3", SharedWithDomain: "partner.com", PermissionType: "domain", PermissionRole: "reader"},
+	}
+
+	added := NewShares(current, baseline)
+	assert.Equal(t, []ExternalShareRecord{current[1], current[3]}, added)
+
+	assert.Equal(t, current, NewShares(current, nil), "every share is new without a baseline")
+	assert.Empty(t, NewShares(old, baseline))
+}
@@ -0,0 +1,38 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/config"
+	"github.com/leansecurity-co/gwork/internal/drive"
+	"github.com/leansecurity-co/gwork/internal/drive/drivetest"
+)
+
+func BenchmarkAuditor_AuditExternalSharing(b *testing.B) {
+	fake := &drivetest.FakeAPI{
+		Files:               2000,
+		Owners:              20,
+		InternalPermissions: 2,
+		ExternalEvery:       5,
+		PublicEvery:         20,
+	}
+	client := drive.NewClientWithAPI(fake, "example.com", 1000, false)
+	cfg := &config.Config{Audit: config.AuditConfig{Concurrency: 10}}
+	auditor := NewAuditorWithClient(cfg, client)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		result, err := auditor.AuditExternalSharing(ctx)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(result.ExternalShares) != fake.ExternalShares() {
+			b.Fatalf("got %d external shares, want %d", len(result.ExternalShares), fake.ExternalShares())
+		}
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"sort"
+
+	"github.com/leansecurity-co/gwork/internal/drive"
+)
+
+// CategorySummary aggregates the files in a single file category.
+type CategorySummary struct {
+	Category   string
+	FileCount  int
+	TotalBytes int64
+}
+
+// SummarizeByCategory aggregates file records by drive.CategorizeMimeType,
+// sorted by file count descending, then by category name.
+func SummarizeByCategory(records []FileRecord) []CategorySummary {
+	index := make(map[string]int)
+	summaries := make([]CategorySummary, 0)
+
+	for _, rec := range records {
+		category := drive.CategorizeMimeType(rec.FileType)
+		i, ok := index[category]
+		if !ok {
+			i = len(summaries)
+			index[category] = i
+			summaries = append(summaries, CategorySummary{Category: category})
+		}
+		summaries[i].FileCount++
+		summaries[i].TotalBytes += rec.SizeBytes
+	}
+
+	sort.SliceStable(summaries, func(i, j int) bool {
+		if summaries[i].FileCount != summaries[j].FileCount {
+			return summaries[i].FileCount > summaries[j].FileCount
+		}
+		return summaries[i].Category < summaries[j].Category
+	})
+
+	return summaries
+}
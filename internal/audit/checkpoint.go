@@ -0,0 +1,257 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/leansecurity-co/gwork/internal/drive"
+)
+
+// defaultCheckpointInterval is the number of files whose permissions a
+// checkpointed sharing audit fetches between two checkpoints.
+const defaultCheckpointInterval = 100
+
+// Checkpoint is the progress of an interrupted sharing audit, read back
+// from its checkpoint file by ReadCheckpoint. Files are audited in ID
+// order, so every file with an ID up to LastFileID has been audited.
+type Checkpoint struct {
+	Domain         string
+	LastFileID     string
+	FilesProcessed int
+	// Shares are the records found so far, with group members, flags,
+	// explanations and labels set.
+	Shares                 []ExternalShareRecord
+	Errors                 []string
+	DroppedErrorCount      int
+	UnexpandedGroups       int
+	UnknownPermissionTypes []string
+	// ReportSize is the size of the report once the shares of the last
+	// batch were appended to it, or 0 if no report was appended to; see
+	// SetCheckpoint.
+	ReportSize int64
+	// Size is the length of the complete lines read. A line cut short
+	// follows them and must be cut off before more lines are appended.
+	Size int64
+}
+
+// checkpointBatch is one line of a checkpoint file. It records what was
+// found in the files after the previous line, up to LastFileID.
+type checkpointBatch struct {
+	Domain                 string                `json:"domain"`
+	LastFileID             string                `json:"last_file_id"`
+	FilesProcessed         int                   `json:"files_processed"`
+	Shares                 []ExternalShareRecord `json:"shares"`
+	Errors                 []string              `json:"errors,omitempty"`
+	DroppedErrorCount      int                   `json:"dropped_error_count,omitempty"`
+	UnexpandedGroups       int                   `json:"unexpanded_groups,omitempty"`
+	UnknownPermissionTypes []string              `json:"unknown_permission_types,omitempty"`
+	ReportSize             int64                 `json:"report_size,omitempty"`
+}
+
+// ShareAppender appends the shares of each checkpointed batch to a report,
+// so the report of a resumed audit keeps the shares written before the
+// interruption.
+type ShareAppender interface {
+	// AppendShares appends records to the report and returns its size.
+	AppendShares(records []ExternalShareRecord) (int64, error)
+}
+
+// checkpointer appends the progress of a sharing audit to a checkpoint
+// file; see Auditor.SetCheckpoint.
+type checkpointer struct {
+	enc    *json.Encoder
+	resume *Checkpoint
+	report ShareAppender
+	err    error // first write error; no further lines are written
+}
+
+// SetCheckpoint makes the sharing audits fetch permissions in file ID
+// order, a batch at a time, appending a line of JSON to w after each batch
+// with the shares found in it, so an interrupted audit can be resumed. When
+// resume is not nil, files with IDs up to its LastFileID are skipped and
+// its shares, counts and errors are carried into the result instead. A nil
+// w disables checkpointing.
+//
+// When report is not nil, the shares of each batch are appended to it
+// before the batch is checkpointed, and the line records the report size,
+// so a resumed audit can cut the report back to the shares its checkpoint
+// covers. Failing to append ends the audit with an error.
+func (a *Auditor) SetCheckpoint(w io.Writer, resume *Checkpoint, report ShareAppender) {
+	if w == nil {
+		a.checkpoint = nil
+		return
+	}
+	a.checkpoint = &checkpointer{enc: json.NewEncoder(w), resume: resume, report: report}
+}
+
+// ReadCheckpoint reads a checkpoint file written by a sharing audit of
+// domain. A last line cut short by the interruption is ignored, and left
+// out of the returned Size. It returns nil if the file has no complete
+// line.
+func ReadCheckpoint(r io.Reader, domain string) (*Checkpoint, error) {
+	var cp *Checkpoint
+	br := bufio.NewReader(r)
+	for line := 1; ; line++ {
+		data, err := br.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			// Without a newline the line was not completely written.
+			return cp, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+		}
+
+		var batch checkpointBatch
+		if err := json.Unmarshal(data, &batch); err != nil {
+			return nil, fmt.Errorf("invalid checkpoint line %d: %w", line, err)
+		}
+		if batch.Domain != domain {
+			return nil, fmt.Errorf("checkpoint is for domain %q, not %q", batch.Domain, domain)
+		}
+		if cp == nil {
+			cp = &Checkpoint{Domain: domain}
+		}
+		cp.Size += int64(len(data))
+		cp.LastFileID = batch.LastFileID
+		cp.FilesProcessed += batch.FilesProcessed
+		cp.Shares = append(cp.Shares, batch.Shares...)
+		cp.Errors = append(cp.Errors, batch.Errors...)
+		cp.DroppedErrorCount += batch.DroppedErrorCount
+		cp.UnexpandedGroups += batch.UnexpandedGroups
+		for _, t := range batch.UnknownPermissionTypes {
+			cp.UnknownPermissionTypes = addUnknownType(cp.UnknownPermissionTypes, t)
+		}
+		cp.ReportSize = batch.ReportSize
+	}
+}
+
+// checkpointInterval returns the number of files fetched per checkpoint.
+func (a *Auditor) checkpointInterval() int {
+	if a.checkpointEvery > 0 {
+		return a.checkpointEvery
+	}
+	return defaultCheckpointInterval
+}
+
+// auditCheckpointed fetches, merges and enriches the permissions of files
+// in ID order, a batch at a time, checkpointing after each batch. A batch
+// cut short by cancellation or the API call budget is checkpointed up to
+// the file before the first one left unfinished, and no further batches
+// are fetched. It returns the error appending to the checkpoint report.
+func (a *Auditor) auditCheckpointed(ctx context.Context, result *AuditResult, files []drive.FileInfo, include func(drive.Permission) bool) error {
+	files = slices.Clone(files)
+	slices.SortFunc(files, func(x, y drive.FileInfo) int { return cmp.Compare(x.ID, y.ID) })
+
+	if resume := a.checkpoint.resume; resume != nil {
+		skip := sort.Search(len(files), func(i int) bool { return files[i].ID > resume.LastFileID })
+		files = files[skip:]
+		result.ResumedFiles = skip
+		result.FilesProcessed += resume.FilesProcessed
+		result.ExternalShares = append(result.ExternalShares, resume.Shares...)
+		for _, msg := range resume.Errors {
+			a.recordError(result, errors.New(msg))
+		}
+		result.DroppedErrorCount += resume.DroppedErrorCount
+		result.UnexpandedGroups += resume.UnexpandedGroups
+		for _, t := range resume.UnknownPermissionTypes {
+			result.UnknownPermissionTypes = addUnknownType(result.UnknownPermissionTypes, t)
+		}
+	}
+
+	groups := make(map[string]groupMembers)
+	for len(files) > 0 {
+		batch := files[:min(a.checkpointInterval(), len(files))]
+		files = files[len(batch):]
+
+		fetchStart := time.Now()
+		outcomes := a.fetchPermissions(ctx, batch)
+		result.Timing.FetchPermissions += time.Since(fetchStart)
+		a.recordSnapshots(result, batch, outcomes)
+
+		// Fetches that failed once ctx was canceled are left for the
+		// resumed audit, like those never attempted.
+		complete := len(batch)
+		for i, outcome := range outcomes {
+			if !outcome.done || errors.Is(outcome.err, drive.ErrBudgetExceeded) || (outcome.err != nil && ctx.Err() != nil) {
+				complete = i
+				break
+			}
+		}
+
+		processed, shares, errs, dropped, unexpanded := result.FilesProcessed, len(result.ExternalShares), len(result.Errors), result.DroppedErrorCount, result.UnexpandedGroups
+		a.mergeOutcomes(ctx, result, batch[:complete], outcomes[:complete], include)
+		a.enrichShares(ctx, result, result.ExternalShares[shares:], groups)
+		if complete > 0 {
+			err := a.writeCheckpoint(result, checkpointBatch{
+				LastFileID:        batch[complete-1].ID,
+				FilesProcessed:    result.FilesProcessed - processed,
+				Shares:            result.ExternalShares[shares:],
+				Errors:            errorStrings(result.Errors[errs:]),
+				DroppedErrorCount: result.DroppedErrorCount - dropped,
+				UnexpandedGroups:  result.UnexpandedGroups - unexpanded,
+				// Types are few; repeating those seen earlier is harmless.
+				UnknownPermissionTypes: result.UnknownPermissionTypes,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if complete < len(batch) {
+			// The unfinished files are neither checkpointed nor appended
+			// to the report, so the resumed audit fetches them again.
+			tail := len(result.ExternalShares)
+			a.mergeOutcomes(ctx, result, batch[complete:], outcomes[complete:], include)
+			a.enrichShares(ctx, result, result.ExternalShares[tail:], groups)
+			return nil
+		}
+	}
+	return nil
+}
+
+// writeCheckpoint appends the shares of batch to the checkpoint report, if
+// any, then batch to the checkpoint file, so a checkpoint never covers
+// shares missing from the report. It returns the error appending to the
+// report. A checkpoint write error is recorded in result once and stops
+// further checkpoints, while the report is still appended to.
+func (a *Auditor) writeCheckpoint(result *AuditResult, batch checkpointBatch) error {
+	cp := a.checkpoint
+	if cp.report != nil {
+		size, err := cp.report.AppendShares(batch.Shares)
+		if err != nil {
+			return fmt.Errorf("failed to append to report: %w", err)
+		}
+		batch.ReportSize = size
+	}
+	if cp.err != nil {
+		return nil
+	}
+	batch.Domain = a.google().Domain
+	if err := cp.enc.Encode(batch); err != nil {
+		cp.err = err
+		a.recordError(result, fmt.Errorf("failed to write checkpoint: %w", err))
+	}
+	return nil
+}
+
+// errorStrings returns the messages of errs.
+func errorStrings(errs []error) []string {
+	if len(errs) == 0 {
+		return nil
+	}
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return msgs
+}
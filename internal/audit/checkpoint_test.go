@@ -0,0 +1,258 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/config"
+	"github.com/leansecurity-co/gwork/internal/drive"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v3 "google.golang.org/api/drive/v3"
+)
+
+// interruptingAPI is a permissionsAPI that records the files whose
+// permissions are listed, fails to list those of the file fail, and calls
+// interrupt after the given number of calls, as if the audit was stopped.
+type interruptingAPI struct {
+	*permissionsAPI
+	fail      string
+	after     int
+	interrupt func()
+
+	mu     sync.Mutex
+	listed []string
+}
+
+func (p *interruptingAPI) ListPermissions(ctx context.Context, fileID string, opts *drive.ListPermissionsOptions) (*drive.ListPermissionsResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	p.listed = append(p.listed, fileID)
+	if len(p.listed) == p.after {
+		p.interrupt()
+	}
+	p.mu.Unlock()
+	if fileID == p.fail {
+		return nil, errors.New("boom")
+	}
+	return p.permissionsAPI.ListPermissions(ctx, fileID, opts)
+}
+
+// newCheckpointAPI returns ten files, listed out of ID order, each shared
+// with one external user.
+func newCheckpointAPI() *permissionsAPI {
+	owner := []*v3.User{{EmailAddress: "alice@example.com"}}
+	api := &permissionsAPI{perms: make(map[string][]*v3.Permission)}
+	for _, n := range []int{7, 2, 9, 0, 4, 1, 8, 3, 6, 5} {
+		id := fmt.Sprintf("f%02d", n)
+		api.files = append(api.files, &v3.File{Id: id, Name: "doc" + id, Owners: owner})
+		api.perms[id] = []*v3.Permission{
+			{Id: "p" + id, Type: "user", Role: "reader", EmailAddress: fmt.Sprintf("guest%d@partner.com", n)},
+		}
+	}
+	return api
+}
+
+// newCheckpointAuditor returns an auditor over api checkpointing to w every
+// three files, appending the shares to report.
+func newCheckpointAuditor(api drive.DriveAPI, w *bytes.Buffer, resume *Checkpoint, report ShareAppender) *Auditor {
+	cfg := &config.Config{Google: config.GoogleConfig{Domain: "example.com"}}
+	auditor := NewAuditorWithClient(cfg, drive.NewClientWithOptions(api, drive.Options{Domain: "example.com"}))
+	auditor.SetCheckpoint(w, resume, report)
+	auditor.checkpointEvery = 3
+	return auditor
+}
+
+func TestAuditExternalSharing_CheckpointResume(t *testing.T) {
+	full, err := newCheckpointAuditor(newCheckpointAPI(), &bytes.Buffer{}, nil, nil).AuditExternalSharing(context.Background())
+	require.NoError(t, err)
+	require.Len(t, full.ExternalShares, 10)
+
+	for _, interruptAfter := range []int{1, 3, 5, 9} {
+		t.Run(fmt.Sprintf("interrupted after %d files", interruptAfter), func(t *testing.T) {
+			// The first run is stopped after interruptAfter files.
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			first := &interruptingAPI{permissionsAPI: newCheckpointAPI(), after: interruptAfter, interrupt: cancel}
+			var checkpoint bytes.Buffer
+			_, err := newCheckpointAuditor(first, &checkpoint, nil, nil).AuditExternalSharing(ctx)
+			require.ErrorIs(t, err, context.Canceled)
+
+			resume, err := ReadCheckpoint(bytes.NewReader(checkpoint.Bytes()), "example.com")
+			require.NoError(t, err)
+			require.NotNil(t, resume)
+			assert.Equal(t, fmt.Sprintf("f%02d", interruptAfter-1), resume.LastFileID, "files are checkpointed in ID order")
+			assert.Equal(t, interruptAfter, resume.FilesProcessed)
+
+			// The resumed run only fetches the remaining files and appends
+			// to the same checkpoint.
+			second := &interruptingAPI{permissionsAPI: newCheckpointAPI(), interrupt: func() {}}
+			result, err := newCheckpointAuditor(second, &checkpoint, resume, nil).AuditExternalSharing(context.Background())
+			require.NoError(t, err)
+
+			var want []string
+			for n := interruptAfter; n < 10; n++ {
+				want = append(want, fmt.Sprintf("f%02d", n))
+			}
+			assert.Equal(t, want, second.listed)
+			assert.Equal(t, interruptAfter, result.ResumedFiles)
+			assert.Equal(t, 10, result.FilesProcessed)
+			assert.Equal(t, full.ExternalShares, result.ExternalShares)
+			assert.Equal(t, 10, result.TotalExternalShares)
+
+			final, err := ReadCheckpoint(bytes.NewReader(checkpoint.Bytes()), "example.com")
+			require.NoError(t, err)
+			assert.Equal(t, "f09", final.LastFileID)
+			assert.Len(t, final.Shares, 10)
+		})
+	}
+}
+
+func TestAuditExternalSharing_CheckpointKeepsErrors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	first := &interruptingAPI{permissionsAPI: newCheckpointAPI(), fail: "f01", after: 4, interrupt: cancel}
+	var checkpoint bytes.Buffer
+	_, err := newCheckpointAuditor(first, &checkpoint, nil, nil).AuditExternalSharing(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+
+	resume, err := ReadCheckpoint(bytes.NewReader(checkpoint.Bytes()), "example.com")
+	require.NoError(t, err)
+	require.Len(t, resume.Errors, 1)
+
+	result, err := newCheckpointAuditor(newCheckpointAPI(), &checkpoint, resume, nil).AuditExternalSharing(context.Background())
+	require.NoError(t, err)
+	require.Len(t, result.Errors, 1, "errors of the interrupted run are kept")
+	assert.Contains(t, result.Errors[0].Error(), "file f01")
+	assert.Equal(t, 9, result.FilesProcessed)
+	assert.Len(t, result.ExternalShares, 9)
+}
+
+// shareLog is a ShareAppender recording the IDs of the files whose shares
+// were appended, with its size counting the shares.
+type shareLog struct {
+	files []string
+	fail  error
+}
+
+func (l *shareLog) AppendShares(records []ExternalShareRecord) (int64, error) {
+	if l.fail != nil {
+		return 0, l.fail
+	}
+	for _, rec := range records {
+		l.files = append(l.files, rec.FileID)
+	}
+	return int64(len(l.files)), nil
+}
+
+func TestAuditExternalSharing_CheckpointAppendsReport(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	first := &interruptingAPI{permissionsAPI: newCheckpointAPI(), after: 5, interrupt: cancel}
+	var checkpoint bytes.Buffer
+	report := &shareLog{}
+	auditor := newCheckpointAuditor(first, &checkpoint, nil, report)
+	auditor.SetFlaggedDomains("partner.com")
+	_, err := auditor.AuditExternalSharing(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+
+	// Only the checkpointed batches reach the report, enriched.
+	resume, err := ReadCheckpoint(bytes.NewReader(checkpoint.Bytes()), "example.com")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"f00", "f01", "f02", "f03", "f04"}, report.files)
+	assert.EqualValues(t, 5, resume.ReportSize)
+	for _, rec := range resume.Shares {
+		assert.True(t, rec.Flagged, rec.FileID)
+		assert.NotEmpty(t, rec.Label, rec.FileID)
+	}
+
+	// The resumed run appends the shares of the remaining files only.
+	auditor = newCheckpointAuditor(newCheckpointAPI(), &checkpoint, resume, report)
+	auditor.SetFlaggedDomains("partner.com")
+	result, err := auditor.AuditExternalSharing(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"f00", "f01", "f02", "f03", "f04", "f05", "f06", "f07", "f08", "f09"}, report.files)
+	assert.Len(t, result.ExternalShares, 10)
+	for _, rec := range result.ExternalShares {
+		assert.True(t, rec.Flagged, rec.FileID)
+	}
+
+	final, err := ReadCheckpoint(bytes.NewReader(checkpoint.Bytes()), "example.com")
+	require.NoError(t, err)
+	assert.EqualValues(t, 10, final.ReportSize)
+}
+
+func TestAuditExternalSharing_CheckpointReportError(t *testing.T) {
+	var checkpoint bytes.Buffer
+	auditor := newCheckpointAuditor(newCheckpointAPI(), &checkpoint, nil, &shareLog{fail: errors.New("disk full")})
+	_, err := auditor.AuditExternalSharing(context.Background())
+	require.ErrorContains(t, err, "failed to append to report: disk full")
+	assert.Empty(t, checkpoint.String(), "nothing is checkpointed that the report lacks")
+}
+
+func TestReadCheckpoint(t *testing.T) {
+	lines := `{"domain":"example.com","last_file_id":"f02","files_processed":3,"shares":[{"file_id":"f01","permission_id":"p1"}]}
+{"domain":"example.com","last_file_id":"f05","files_processed":2,"shares":[{"file_id":"f04","permission_id":"p4"}],"errors":["file f03: boom"],"unknown_permission_types":["audience"]}
+`
+	tests := []struct {
+		name    string
+		data    string
+		domain  string
+		want    *Checkpoint
+		wantErr string
+	}{
+		{name: "empty", data: "", domain: "example.com"},
+		{
+			name:   "batches are combined",
+			data:   lines,
+			domain: "example.com",
+			want: &Checkpoint{
+				Domain:                 "example.com",
+				LastFileID:             "f05",
+				FilesProcessed:         5,
+				Shares:                 []ExternalShareRecord{{FileID: "f01", PermissionID: "p1"}, {FileID: "f04", PermissionID: "p4"}},
+				Errors:                 []string{"file f03: boom"},
+				UnknownPermissionTypes: []string{"audience"},
+				Size:                   int64(len(lines)),
+			},
+		},
+		{
+			name:   "torn last line is ignored",
+			data:   lines + `{"domain":"example.com","last_file_id":"f0`,
+			domain: "example.com",
+			want: &Checkpoint{
+				Domain:                 "example.com",
+				LastFileID:             "f05",
+				FilesProcessed:         5,
+				Shares:                 []ExternalShareRecord{{FileID: "f01", PermissionID: "p1"}, {FileID: "f04", PermissionID: "p4"}},
+				Errors:                 []string{"file f03: boom"},
+				UnknownPermissionTypes: []string{"audience"},
+				Size:                   int64(len(lines)),
+			},
+		},
+		{name: "other domain", data: lines, domain: "other.com", wantErr: `checkpoint is for domain "example.com", not "other.com"`},
+		{name: "corrupt line", data: "not json\n", domain: "example.com", wantErr: "invalid checkpoint line 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadCheckpoint(strings.NewReader(tt.data), tt.domain)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
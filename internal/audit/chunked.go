@@ -0,0 +1,84 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/leansecurity-co/gwork/internal/drive"
+)
+
+// ErrOwnerListingUnsupported is returned by AuditFilesByOwnerChunks when the
+// drive client cannot list files per owner.
+var ErrOwnerListingUnsupported = errors.New("drive client does not support listing files per owner")
+
+// AuditFilesByOwnerChunks performs a files-by-owner audit one owner at a
+// time. Owners are enumerated first, then each owner's files are listed,
+// sorted and passed to write before the next owner is listed, so memory is
+// bounded by the largest owner rather than the whole domain. Owners are
+// visited in email order, which matches SortFileRecords for owners with an
+// email. The returned result carries totals and errors but no FileRecords.
+// Sampling is not applied.
+func (a *Auditor) AuditFilesByOwnerChunks(ctx context.Context, write func([]FileRecord) error) (*AuditResult, error) {
+	lister, ok := a.driveClient.(OwnerFileLister)
+	if !ok {
+		return nil, ErrOwnerListingUnsupported
+	}
+
+	start, startStats := time.Now(), a.apiStats()
+
+	owners, err := lister.ListOwners(ctx)
+	budgetExceeded := errors.Is(err, drive.ErrBudgetExceeded)
+	if err != nil && !budgetExceeded {
+		return nil, fmt.Errorf("failed to list owners: %w", err)
+	}
+
+	result := &AuditResult{BudgetExceeded: budgetExceeded}
+	strict := a.config != nil && a.config.Audit.Strict
+
+	for _, owner := range owners {
+		if result.BudgetExceeded {
+			break
+		}
+
+		files, err := lister.ListOwnerFiles(ctx, owner)
+		if errors.Is(err, drive.ErrBudgetExceeded) {
+			result.BudgetExceeded = true
+		} else if err != nil {
+			return result, fmt.Errorf("failed to list files for %s: %w", owner, err)
+		}
+
+		var suppressed int
+		files, suppressed = FilterIgnoredFiles(files, a.ignoreFileIDs)
+		result.SuppressedCount += suppressed
+		result.TotalFiles += len(files)
+		result.FilesProcessed += len(files)
+
+		records := make([]FileRecord, 0, len(files))
+		for _, f := range files {
+			record, err := parseFileInfo(f, strict)
+			if err != nil {
+				a.recordError(result, fmt.Errorf("file %s: %w", f.ID, err))
+			}
+			record.DriveName = a.driveName(ctx, f.DriveID)
+			records = append(records, record)
+		}
+		if a.includeLinkStatus() {
+			a.setLinkStatus(ctx, files, records, result)
+		}
+		SortFileRecords(records)
+
+		if err := write(records); err != nil {
+			return result, err
+		}
+	}
+
+	result.Timing.ListFiles = time.Since(start)
+	result.Timing.Total = time.Since(start)
+	result.Timing.API = a.apiStats().Sub(startStats)
+	return result, nil
+}
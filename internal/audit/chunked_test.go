@@ -0,0 +1,105 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/config"
+	"github.com/leansecurity-co/gwork/internal/drive"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ownerChunkSource is a DriveClient and OwnerFileLister backed by files per
+// owner. It records the order of calls so tests can check that each
+// owner's files are written before the next owner is listed.
+type ownerChunkSource struct {
+	filesByOwner map[string][]drive.FileInfo
+	owners       []string
+	calls        []string
+}
+
+func (s *ownerChunkSource) ListAllFiles(context.Context) ([]drive.FileInfo, error) {
+	return nil, errors.New("unexpected full listing")
+}
+
+func (s *ownerChunkSource) GetFilePermissions(context.Context, string) ([]drive.Permission, error) {
+	return nil, nil
+}
+
+func (s *ownerChunkSource) IsExternalShare(drive.Permission) bool { return false }
+
+func (s *ownerChunkSource) Domain() string { return "example.com" }
+
+func (s *ownerChunkSource) ListOwners(context.Context) ([]string, error) {
+	return s.owners, nil
+}
+
+func (s *ownerChunkSource) ListOwnerFiles(_ context.Context, owner string) ([]drive.FileInfo, error) {
+	s.calls = append(s.calls, "list "+owner)
+	return s.filesByOwner[owner], nil
+}
+
+func TestAuditor_AuditFilesByOwnerChunks(t *testing.T) {
+	source := &ownerChunkSource{
+		owners: []string{"alice@example.com", "bob@example.com"},
+		filesByOwner: map[string][]drive.FileInfo{
+			"alice@example.com": {
+				{ID: "a2", Name: "zeta.doc", OwnerEmail: "alice@example.com"},
+				{ID: "a1", Name: "alpha.doc", OwnerEmail: "alice@example.com"},
+				{ID: "ignored", Name: "skip.doc", OwnerEmail: "alice@example.com"},
+			},
+			"bob@example.com": {
+				{ID: "b1", Name: "notes.txt", OwnerEmail: "bob@example.com"},
+			},
+		},
+	}
+
+	auditor := NewAuditorWithClient(&config.Config{}, source)
+	auditor.SetIgnoreFileIDs("ignored")
+
+	var chunks [][]string
+	result, err := auditor.AuditFilesByOwnerChunks(context.Background(), func(records []FileRecord) error {
+		ids := make([]string, 0, len(records))
+		for _, rec := range records {
+			ids = append(ids, rec.FileID)
+		}
+		chunks = append(chunks, ids)
+		source.calls = append(source.calls, "write")
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, [][]string{{"a1", "a2"}, {"b1"}}, chunks, "each write holds one owner's sorted records")
+	assert.Equal(t, []string{"list alice@example.com", "write", "list bob@example.com", "write"}, source.calls,
+		"an owner is written before the next is listed")
+	assert.Equal(t, 3, result.TotalFiles)
+	assert.Equal(t, 3, result.FilesProcessed)
+	assert.Equal(t, 1, result.SuppressedCount)
+	assert.Empty(t, result.FileRecords, "records are not accumulated")
+}
+
+func TestAuditor_AuditFilesByOwnerChunks_WriteError(t *testing.T) {
+	source := &ownerChunkSource{
+		owners:       []string{"alice@example.com", "bob@example.com"},
+		filesByOwner: map[string][]drive.FileInfo{"alice@example.com": {{ID: "a1"}}},
+	}
+	writeErr := errors.New("disk full")
+
+	auditor := NewAuditorWithClient(&config.Config{}, source)
+	_, err := auditor.AuditFilesByOwnerChunks(context.Background(), func([]FileRecord) error {
+		return writeErr
+	})
+	require.ErrorIs(t, err, writeErr)
+	assert.Equal(t, []string{"list alice@example.com"}, source.calls)
+}
+
+func TestAuditor_AuditFilesByOwnerChunks_Unsupported(t *testing.T) {
+	auditor := NewAuditorWithClient(&config.Config{}, new(MockDriveClient))
+	_, err := auditor.AuditFilesByOwnerChunks(context.Background(), func([]FileRecord) error { return nil })
+	assert.ErrorIs(t, err, ErrOwnerListingUnsupported)
+}
@@ -0,0 +1,59 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+// LabelFlagged is the DefaultClassifier label for shares to a flagged domain.
+const LabelFlagged = "flagged"
+
+// Risk scores assigned by DefaultClassifier.
+const (
+	RiskLow    = 1
+	RiskMedium = 2
+	RiskHigh   = 3
+)
+
+// DefaultClassifier reproduces the built-in share classification: shares to
+// a flagged domain are LabelFlagged with RiskHigh, shares with anyone are
+// ScopePublic with RiskMedium, shares with the whole organization are
+// ScopeInternal with RiskLow, and all other shares are ScopeExternal with
+// RiskLow.
+type DefaultClassifier struct{}
+
+// Classify returns the label and risk score for a share.
+func (DefaultClassifier) Classify(rec ExternalShareRecord) (string, int) {
+	switch {
+	case rec.Flagged:
+		return LabelFlagged, RiskHigh
+	case IsPublicPermissionType(rec.PermissionType):
+		return ScopePublic, RiskMedium
+	case rec.DomainWide:
+		return ScopeInternal, RiskLow
+	default:
+		return ScopeExternal, RiskLow
+	}
+}
+
+// CountAboveRisk returns the number of records whose risk score is above
+// threshold.
+func CountAboveRisk(records []ExternalShareRecord, threshold int) int {
+	count := 0
+	for _, rec := range records {
+		if rec.Risk > threshold {
+			count++
+		}
+	}
+	return count
+}
+
+// classify sets Label and Risk on each record using the auditor's
+// classifier, or DefaultClassifier if none is set.
+func (a *Auditor) classify(records []ExternalShareRecord) {
+	classifier := a.classifier
+	if classifier == nil {
+		classifier = DefaultClassifier{}
+	}
+	for i := range records {
+		records[i].Label, records[i].Risk = classifier.Classify(records[i])
+	}
+}
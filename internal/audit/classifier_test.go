@@ -0,0 +1,118 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/config"
+	"github.com/leansecurity-co/gwork/internal/drive"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultClassifier(t *testing.T) {
+	tests := []struct {
+		name      string
+		record    ExternalShareRecord
+		wantLabel string
+		wantRisk  int
+	}{
+		{name: "external user", record: ExternalShareRecord{PermissionType: "user"}, wantLabel: ScopeExternal, wantRisk: RiskLow},
+		{name: "anyone", record: ExternalShareRecord{PermissionType: "anyone"}, wantLabel: ScopePublic, wantRisk: RiskMedium},
+		{name: "domain wide", record: ExternalShareRecord{PermissionType: "domain", DomainWide: true}, wantLabel: ScopeInternal, wantRisk: RiskLow},
+		{name: "flagged", record: ExternalShareRecord{PermissionType: "user", Flagged: true}, wantLabel: LabelFlagged, wantRisk: RiskHigh},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			label, risk := DefaultClassifier{}.Classify(tt.record)
+			assert.Equal(t, tt.wantLabel, label)
+			assert.Equal(t, tt.wantRisk, risk)
+		})
+	}
+}
+
+// editorClassifier labels write access to personal mail providers as
+// critical.
+type editorClassifier struct{}
+
+func (editorClassifier) Classify(rec ExternalShareRecord) (string, int) {
+	if rec.PermissionRole == "writer" && strings.HasSuffix(rec.SharedWithEmail, "@gmail.com") {
+		return "personal-editor", 10
+	}
+	return "ok", 0
+}
+
+func TestAuditor_ShareClassifier(t *testing.T) {
+	files := []drive.FileInfo{{ID: "doc", Name: "Plan", OwnerEmail: "alice@example.com"}}
+
+	mockClient := new(MockDriveClient)
+	mockClient.On("ListAllFiles", mock.Anything).Return(files, nil)
+	mockClient.On("GetFilePermissions", mock.Anything, "doc").Return([]drive.Permission{
+		{Type: "user", Role: "writer", EmailAddress: "bob@gmail.com"},
+		{Type: "user", Role: "reader", EmailAddress: "carol@partner.com"},
+	}, nil)
+	mockClient.On("IsExternalShare", mock.Anything).Return(true)
+
+	auditor := NewAuditorWithClient(&config.Config{}, mockClient)
+
+	result, err := auditor.AuditExternalSharing(context.Background())
+	require.NoError(t, err)
+	require.Len(t, result.ExternalShares, 2)
+	for _, rec := range result.ExternalShares {
+		assert.Equal(t, ScopeExternal, rec.Label)
+		assert.Equal(t, RiskLow, rec.Risk)
+	}
+
+	auditor.SetShareClassifier(editorClassifier{})
+	result, err = auditor.AuditExternalSharing(context.Background())
+	require.NoError(t, err)
+
+	byEmail := make(map[string]ExternalShareRecord)
+	for _, rec := range result.ExternalShares {
+		byEmail[rec.SharedWithEmail] = rec
+	}
+	assert.Equal(t, "personal-editor", byEmail["bob@gmail.com"].Label)
+	assert.Equal(t, 10, byEmail["bob@gmail.com"].Risk)
+	assert.Equal(t, "ok", byEmail["carol@partner.com"].Label)
+	assert.Equal(t, 0, byEmail["carol@partner.com"].Risk)
+
+	auditor.SetShareClassifier(nil)
+	result, err = auditor.AuditExternalSharing(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, ScopeExternal, result.ExternalShares[0].Label)
+}
+
+func TestCountAboveRisk(t *testing.T) {
+	records := []ExternalShareRecord{
+		{FileID: "1", Risk: RiskLow},
+		{FileID: "2", Risk: RiskMedium},
+		{FileID: "3", Risk: RiskMedium},
+		{FileID: "4", Risk: RiskHigh},
+	}
+
+	tests := []struct {
+		name      string
+		threshold int
+		expected  int
+	}{
+		{name: "below every score", threshold: 0, expected: 4},
+		{name: "at the lowest score", threshold: RiskLow, expected: 3},
+		{name: "at a middle score", threshold: RiskMedium, expected: 1},
+		{name: "at the highest score", threshold: RiskHigh, expected: 0},
+		{name: "above every score", threshold: 10, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, CountAboveRisk(records, tt.threshold))
+		})
+	}
+
+	assert.Zero(t, CountAboveRisk(nil, 0))
+}
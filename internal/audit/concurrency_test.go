@@ -0,0 +1,161 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/config"
+	"github.com/leansecurity-co/gwork/internal/drive"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v3 "google.golang.org/api/drive/v3"
+)
+
+// pagedDriveAPI is a read-only, concurrency-safe DriveAPI that serves files
+// and permissions across multiple pages.
+type pagedDriveAPI struct {
+	files       []*v3.File
+	permissions map[string][]*v3.Permission
+	failing     map[string]bool
+	pageSize    int
+}
+
+func newPagedDriveAPI(fileCount, pageSize int) *pagedDriveAPI {
+	api := &pagedDriveAPI{
+		permissions: make(map[string][]*v3.Permission),
+		failing:     make(map[string]bool),
+		pageSize:    pageSize,
+	}
+
+	owners := []string{"carol@example.com", "alice@example.com", "bob@example.com"}
+	for i := 0; i < fileCount; i++ {
+		id := fmt.Sprintf("file%03d", i)
+		api.files = append(api.files, &v3.File{
+			Id:     id,
+			Name:   fmt.Sprintf("doc-%d.txt", (fileCount-i)%7),
+			Owners: []*v3.User{{EmailAddress: owners[i%len(owners)]}},
+		})
+
+		perms := []*v3.Permission{
+			{Id: "owner", Type: "user", Role: "owner", EmailAddress: owners[i%len(owners)]},
+		}
+		if i%2 == 0 {
+			perms = append(perms, &v3.Permission{Id: "ext", Type: "user", Role: "reader", EmailAddress: fmt.Sprintf("guest%d@partner.com", i)})
+		}
+		if i%5 == 0 {
+			perms = append(perms, &v3.Permission{Id: "public", Type: "anyone", Role: "reader"})
+		}
+		api.permissions[id] = perms
+
+		if i%11 == 0 {
+			api.failing[id] = true
+		}
+	}
+
+	return api
+}
+
+func (p *pagedDriveAPI) ListFiles(_ context.Context, opts *drive.ListFilesOptions) (*drive.ListFilesResult, error) {
+	start := 0
+	if opts.PageToken != "" {
+		var err error
+		if start, err = strconv.Atoi(opts.PageToken); err != nil {
+			return nil, err
+		}
+	}
+
+	end := min(start+p.pageSize, len(p.files))
+	result := &drive.ListFilesResult{Files: p.files[start:end]}
+	if end < len(p.files) {
+		result.NextPageToken = strconv.Itoa(end)
+	}
+	return result, nil
+}
+
+func (p *pagedDriveAPI) GetFile(_ context.Context, fileID string, _ *drive.GetFileOptions) (*v3.File, error) {
+	for _, f := range p.files {
+		if f.Id == fileID {
+			return f, nil
+		}
+	}
+	return nil, fmt.Errorf("file not found: %s", fileID)
+}
+
+func (p *pagedDriveAPI) GetDrive(_ context.Context, driveID string) (*v3.Drive, error) {
+	return nil, fmt.Errorf("shared drive not found: %s", driveID)
+}
+
+func (p *pagedDriveAPI) ListPermissions(_ context.Context, fileID string, opts *drive.ListPermissionsOptions) (*drive.ListPermissionsResult, error) {
+	if p.failing[fileID] {
+		return nil, errors.New("permission denied")
+	}
+
+	// Serve one permission per page to exercise permission pagination too.
+	perms := p.permissions[fileID]
+	idx := 0
+	if opts.PageToken != "" {
+		idx, _ = strconv.Atoi(opts.PageToken)
+	}
+
+	result := &drive.ListPermissionsResult{Permissions: perms[idx : idx+1]}
+	if idx+1 < len(perms) {
+		result.NextPageToken = strconv.Itoa(idx + 1)
+	}
+	return result, nil
+}
+
+func TestAuditExternalSharing_ConcurrentDeterminism(t *testing.T) {
+	api := newPagedDriveAPI(120, 7)
+
+	run := func(concurrency int) *AuditResult {
+		cfg := &config.Config{
+			Google: config.GoogleConfig{Domain: "example.com"},
+			Audit:  config.AuditConfig{Concurrency: concurrency},
+		}
+		client := drive.NewClientWithAPI(api, "example.com", 7, false)
+		result, err := NewAuditorWithClient(cfg, client).AuditExternalSharing(context.Background())
+		require.NoError(t, err)
+		return result
+	}
+
+	baseline := run(1)
+	require.Equal(t, 120, baseline.TotalFiles)
+	require.Len(t, baseline.Errors, 11)
+	require.Equal(t, 120-11, baseline.FilesProcessed)
+	require.NotEmpty(t, baseline.ExternalShares)
+
+	for i := 1; i < len(baseline.ExternalShares); i++ {
+		prev, cur := baseline.ExternalShares[i-1], baseline.ExternalShares[i]
+		require.LessOrEqual(t, prev.OwnerKey(), cur.OwnerKey(), "shares should be sorted by owner")
+		if prev.OwnerKey() == cur.OwnerKey() {
+			require.LessOrEqual(t, prev.FileName, cur.FileName, "shares should be sorted by file name within owner")
+		}
+	}
+
+	for iter := 0; iter < 50; iter++ {
+		result := run(8)
+		assert.Equal(t, baseline.TotalFiles, result.TotalFiles)
+		assert.Equal(t, baseline.FilesProcessed, result.FilesProcessed)
+		assert.Equal(t, baseline.TotalExternalShares, result.TotalExternalShares)
+		assert.Equal(t, baseline.ExternalShares, result.ExternalShares)
+		assert.Equal(t, baseline.Errors, result.Errors)
+	}
+}
+
+func TestAuditExternalSharing_CanceledContext(t *testing.T) {
+	api := newPagedDriveAPI(20, 5)
+	cfg := &config.Config{Audit: config.AuditConfig{Concurrency: 4}}
+	client := drive.NewClientWithAPI(api, "example.com", 5, false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := NewAuditorWithClient(cfg, client).AuditExternalSharing(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,69 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"sort"
+	"strconv"
+)
+
+// DuplicateGroup is a set of files of one owner with the same name and
+// size, likely copies of each other.
+type DuplicateGroup struct {
+	OwnerEmail string   `json:"owner_email"`
+	OwnerName  string   `json:"owner_name,omitempty"`
+	FileName   string   `json:"file_name"`
+	SizeBytes  int64    `json:"size_bytes"`
+	FileIDs    []string `json:"file_ids"`
+}
+
+// OwnerKey returns the key used to group owners, see FileRecord.OwnerKey.
+func (g DuplicateGroup) OwnerKey() string {
+	return ownerKey(g.OwnerEmail, g.OwnerName)
+}
+
+// FindDuplicates groups file records by owner, file name and size and
+// returns the groups holding more than one file, sorted by owner, then
+// file name and size. File IDs within a group are sorted.
+func FindDuplicates(records []FileRecord) []DuplicateGroup {
+	index := make(map[string]int)
+	groups := make([]DuplicateGroup, 0)
+
+	for _, rec := range records {
+		key := rec.OwnerKey() + "\x00" + rec.FileName + "\x00" + strconv.FormatInt(rec.SizeBytes, 10)
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, DuplicateGroup{
+				OwnerEmail: rec.OwnerEmail,
+				OwnerName:  rec.OwnerName,
+				FileName:   rec.FileName,
+				SizeBytes:  rec.SizeBytes,
+			})
+		}
+		groups[i].FileIDs = append(groups[i].FileIDs, rec.FileID)
+	}
+
+	duplicates := make([]DuplicateGroup, 0)
+	for _, g := range groups {
+		if len(g.FileIDs) > 1 {
+			sort.Strings(g.FileIDs)
+			duplicates = append(duplicates, g)
+		}
+	}
+
+	sort.SliceStable(duplicates, func(i, j int) bool {
+		a, b := duplicates[i], duplicates[j]
+		if a.OwnerKey() != b.OwnerKey() {
+			return a.OwnerKey() < b.OwnerKey()
+		}
+		if a.FileName != b.FileName {
+			return a.FileName < b.FileName
+		}
+		return a.SizeBytes < b.SizeBytes
+	})
+
+	return duplicates
+}
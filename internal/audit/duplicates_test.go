@@ -0,0 +1,48 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	records := []FileRecord{
+		{FileID: "b3", FileName: "report.pdf", OwnerEmail: "bob@example.com", SizeBytes: 2048},
+		{FileID: "a1", FileName: "notes.txt", OwnerEmail: "alice@example.com", SizeBytes: 10},
+		{FileID: "b1", FileName: "report.pdf", OwnerEmail: "bob@example.com", SizeBytes: 2048},
+		{FileID: "a2", FileName: "notes.txt", OwnerEmail: "alice@example.com", SizeBytes: 10},
+		{FileID: "b2", FileName: "report.pdf", OwnerEmail: "bob@example.com", SizeBytes: 2048},
+		// Same name, different size.
+		{FileID: "a3", FileName: "notes.txt", OwnerEmail: "alice@example.com", SizeBytes: 11},
+		// Same name and size, different owner.
+		{FileID: "c1", FileName: "report.pdf", OwnerEmail: "carol@example.com", SizeBytes: 2048},
+		// Unique files.
+		{FileID: "a4", FileName: "plan.docx", OwnerEmail: "alice@example.com", SizeBytes: 10},
+		{FileID: "d1", FileName: "budget.xlsx", OwnerEmail: "dave@example.com", SizeBytes: 99},
+		// Owners without an email are grouped by name.
+		{FileID: "f2", FileName: "old.doc", OwnerName: "Former Employee", SizeBytes: 5},
+		{FileID: "f1", FileName: "old.doc", OwnerName: "Former Employee", SizeBytes: 5},
+	}
+
+	got := FindDuplicates(records)
+
+	assert.Equal(t, []DuplicateGroup{
+		{OwnerEmail: "alice@example.com", FileName: "notes.txt", SizeBytes: 10, FileIDs: []string{"a1", "a2"}},
+		{OwnerEmail: "bob@example.com", FileName: "report.pdf", SizeBytes: 2048, FileIDs: []string{"b1", "b2", "b3"}},
+		{OwnerName: "Former Employee", FileName: "old.doc", SizeBytes: 5, FileIDs: []string{"f1", "f2"}},
+	}, got)
+}
+
+func TestFindDuplicates_NoDuplicates(t *testing.T) {
+	records := []FileRecord{
+		{FileID: "1", FileName: "a.txt", OwnerEmail: "alice@example.com", SizeBytes: 1},
+		{FileID: "2", FileName: "b.txt", OwnerEmail: "alice@example.com", SizeBytes: 1},
+	}
+
+	assert.Empty(t, FindDuplicates(records))
+	assert.NotNil(t, FindDuplicates(nil))
+}
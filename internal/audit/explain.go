@@ -0,0 +1,61 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import "fmt"
+
+// SetExplain enables setting Explanation on share records.
+func (a *Auditor) SetExplain(enabled bool) {
+	a.explain = enabled
+}
+
+// ExplainShare returns a human-readable reason why rec was reported,
+// following the rules of drive.Client.IsExternalShare. domain is the
+// organization's primary domain and internalEmailRegex is
+// google.internal_email_regex, which replaces the domain comparison for
+// users and groups when set. Types drive.IsKnownPermissionType does not
+// recognize, reported only with audit.strict_unknown, are explained as
+// unrecognized.
+func ExplainShare(rec ExternalShareRecord, domain, internalEmailRegex string) string {
+	outside := "outside the organization"
+	if domain != "" {
+		outside = "outside " + domain
+	}
+
+	switch rec.PermissionType {
+	case "anyone":
+		return "shared to anyone with the link"
+	case "domain":
+		if rec.DomainWide {
+			return fmt.Sprintf("shared to everyone at %s", rec.SharedWithDomain)
+		}
+		return fmt.Sprintf("shared to everyone at %s, which is %s", rec.SharedWithDomain, outside)
+	case "user":
+		if internalEmailRegex != "" {
+			return fmt.Sprintf("%s does not match the internal email pattern %s", rec.SharedWithEmail, internalEmailRegex)
+		}
+		return fmt.Sprintf("%s is %s", rec.SharedWithEmail, outside)
+	case "group":
+		if internalEmailRegex != "" {
+			return fmt.Sprintf("group %s does not match the internal email pattern %s", rec.SharedWithEmail, internalEmailRegex)
+		}
+		return fmt.Sprintf("group %s is %s", rec.SharedWithEmail, outside)
+	default:
+		return fmt.Sprintf("unrecognized permission type %q, reported because of strict_unknown", rec.PermissionType)
+	}
+}
+
+// Explain sets Explanation on each record; see ExplainShare.
+func Explain(records []ExternalShareRecord, domain, internalEmailRegex string) {
+	for i := range records {
+		records[i].Explanation = ExplainShare(records[i], domain, internalEmailRegex)
+	}
+}
+
+// explainShares sets Explanation on records using the audited domain and
+// internal email pattern.
+func (a *Auditor) explainShares(records []ExternalShareRecord) {
+	google := a.google()
+	Explain(records, google.Domain, google.InternalEmailRegex)
+}
@@ -0,0 +1,139 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/config"
+	"github.com/leansecurity-co/gwork/internal/drive"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplainShare(t *testing.T) {
+	tests := []struct {
+		name    string
+		rec     ExternalShareRecord
+		domain  string
+		pattern string
+		want    string
+	}{
+		{
+			name:   "anyone",
+			rec:    ExternalShareRecord{PermissionType: "anyone"},
+			domain: "example.com",
+			want:   "shared to anyone with the link",
+		},
+		{
+			name:   "external user",
+			rec:    ExternalShareRecord{PermissionType: "user", SharedWithEmail: "user@competitor.com"},
+			domain: "example.com",
+			want:   "user@competitor.com is outside example.com",
+		},
+		{
+			name:   "external group",
+			rec:    ExternalShareRecord{PermissionType: "group", SharedWithEmail: "team@partner.com"},
+			domain: "example.com",
+			want:   "group team@partner.com is outside example.com",
+		},
+		{
+			name:   "external domain",
+			rec:    ExternalShareRecord{PermissionType: "domain", SharedWithDomain: "partner.com"},
+			domain: "example.com",
+			want:   "shared to everyone at partner.com, which is outside example.com",
+		},
+		{
+			name:   "own domain",
+			rec:    ExternalShareRecord{PermissionType: "domain", SharedWithDomain: "example.com", DomainWide: true},
+			domain: "example.com",
+			want:   "shared to everyone at example.com",
+		},
+		{
+			name: "no primary domain",
+			rec:  ExternalShareRecord{PermissionType: "user", SharedWithEmail: "guest@partner.com"},
+			want: "guest@partner.com is outside the organization",
+		},
+		{
+			name:    "internal email pattern",
+			rec:     ExternalShareRecord{PermissionType: "user", SharedWithEmail: "contractor@example.com"},
+			domain:  "example.com",
+			pattern: `^[a-z]+\.[a-z]+@example\.com$`,
+			want:    `contractor@example.com does not match the internal email pattern ^[a-z]+\.[a-z]+@example\.com$`,
+		},
+		{
+			name:    "group and internal email pattern",
+			rec:     ExternalShareRecord{PermissionType: "group", SharedWithEmail: "vendors@example.com"},
+			domain:  "example.com",
+			pattern: `@staff\.example\.com$`,
+			want:    `group vendors@example.com does not match the internal email pattern @staff\.example\.com$`,
+		},
+		{
+			name:    "domain share ignores the email pattern",
+			rec:     ExternalShareRecord{PermissionType: "domain", SharedWithDomain: "partner.com"},
+			domain:  "example.com",
+			pattern: `@example\.com$`,
+			want:    "shared to everyone at partner.com, which is outside example.com",
+		},
+		{
+			name:   "unknown type",
+			rec:    ExternalShareRecord{PermissionType: "deleted"},
+			domain: "example.com",
+			want:   `unrecognized permission type "deleted", reported because of strict_unknown`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ExplainShare(tt.rec, tt.domain, tt.pattern))
+		})
+	}
+}
+
+func TestAuditor_Explain(t *testing.T) {
+	files := []drive.FileInfo{{ID: "doc", Name: "Roadmap", OwnerEmail: "alice@example.com"}}
+
+	newAuditor := func() *Auditor {
+		mockClient := new(MockDriveClient)
+		mockClient.On("ListAllFiles", mock.Anything).Return(files, nil)
+		mockClient.On("GetFilePermissions", mock.Anything, "doc").Return([]drive.Permission{
+			{Type: "user", Role: "reader", EmailAddress: "bob@rival.com"},
+		}, nil)
+		mockClient.On("IsExternalShare", mock.Anything).Return(true)
+
+		cfg := &config.Config{Google: config.GoogleConfig{Domain: "example.com"}}
+		return NewAuditorWithClient(cfg, mockClient)
+	}
+
+	auditor := newAuditor()
+	result, err := auditor.AuditExternalSharing(context.Background())
+	require.NoError(t, err)
+	require.Len(t, result.ExternalShares, 1)
+	assert.Empty(t, result.ExternalShares[0].Explanation, "explanations are off by default")
+
+	auditor = newAuditor()
+	auditor.SetExplain(true)
+	result, err = auditor.AuditExternalSharing(context.Background())
+	require.NoError(t, err)
+	require.Len(t, result.ExternalShares, 1)
+	assert.Equal(t, "bob@rival.com is outside example.com", result.ExternalShares[0].Explanation)
+}
+
+func TestAuditor_ExplainWithoutConfig(t *testing.T) {
+	mockClient := new(MockDriveClient)
+	mockClient.On("ListAllFiles", mock.Anything).Return([]drive.FileInfo{{ID: "doc", OwnerEmail: "alice@example.com"}}, nil)
+	mockClient.On("GetFilePermissions", mock.Anything, "doc").Return([]drive.Permission{
+		{Type: "user", Role: "reader", EmailAddress: "bob@rival.com"},
+	}, nil)
+	mockClient.On("IsExternalShare", mock.Anything).Return(true)
+
+	auditor := NewAuditorWithClient(nil, mockClient)
+	auditor.SetExplain(true)
+	result, err := auditor.AuditExternalSharing(context.Background())
+	require.NoError(t, err)
+	require.Len(t, result.ExternalShares, 1)
+	assert.Equal(t, "bob@rival.com is outside the organization", result.ExternalShares[0].Explanation)
+}
@@ -0,0 +1,39 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+
+	"github.com/leansecurity-co/gwork/internal/drive"
+)
+
+// AuditExternalOwners reports files owned by accounts outside the primary
+// domain and its aliases, such as external organizers of shared drive
+// content. Files whose owner has no email address are left out.
+func (a *Auditor) AuditExternalOwners(ctx context.Context) (*AuditResult, error) {
+	result, err := a.AuditFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	external := make([]FileRecord, 0)
+	for _, rec := range result.FileRecords {
+		if a.isExternalOwner(rec.OwnerEmail) {
+			external = append(external, rec)
+		}
+	}
+	result.FileRecords = external
+	return result, nil
+}
+
+// isExternalOwner reports whether email is outside the organization. The
+// owner is checked as a user grantee, so the primary domain, its aliases and
+// google.internal_email_regex apply as for shares.
+func (a *Auditor) isExternalOwner(email string) bool {
+	if email == "" {
+		return false
+	}
+	return a.driveClient.IsExternalShare(drive.Permission{Type: "user", EmailAddress: email})
+}
@@ -0,0 +1,50 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/config"
+	"github.com/leansecurity-co/gwork/internal/drive"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v3 "google.golang.org/api/drive/v3"
+)
+
+func TestAuditExternalOwners(t *testing.T) {
+	owned := func(id, owner string) *v3.File {
+		f := &v3.File{Id: id, Name: id + ".txt"}
+		if owner != "" {
+			f.Owners = []*v3.User{{EmailAddress: owner}}
+		}
+		return f
+	}
+	api := &pagedDriveAPI{
+		files: []*v3.File{
+			owned("internal", "alice@example.com"),
+			owned("alias", "bob@example.org"),
+			owned("external", "vendor@partner.com"),
+			owned("subdomain", "carol@eu.example.com"),
+			owned("ownerless", ""),
+		},
+		pageSize: 2,
+	}
+	client := drive.NewClientWithAPI(api, "example.com", 2, false)
+	client.SetDomainAliases("example.org")
+
+	auditor := NewAuditorWithClient(&config.Config{}, client)
+	result, err := auditor.AuditExternalOwners(context.Background())
+	require.NoError(t, err)
+
+	ids := make([]string, 0, len(result.FileRecords))
+	for _, rec := range result.FileRecords {
+		ids = append(ids, rec.FileID)
+	}
+	assert.Equal(t, []string{"external", "subdomain"}, ids,
+		"only owners outside the domain and its aliases are reported; subdomains are not aliases")
+	assert.Equal(t, 5, result.TotalFiles)
+	assert.Equal(t, 5, result.FilesProcessed)
+}
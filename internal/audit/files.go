@@ -5,6 +5,7 @@ package audit
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -13,37 +14,130 @@ import (
 
 // AuditFiles performs a files-by-owner audit.
 func (a *Auditor) AuditFiles(ctx context.Context) (*AuditResult, error) {
-	files, err := a.driveClient.ListAllFiles(ctx)
+	start, startStats := time.Now(), a.apiStats()
+
+	listing, err := a.listFiles(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list files: %w", err)
+		return nil, err
 	}
 
-	result := &AuditResult{
-		TotalFiles:     len(files),
-		FileRecords:    make([]FileRecord, 0, len(files)),
-		FilesProcessed: len(files),
-	}
+	result := a.auditListedFiles(ctx, listing)
+	result.Timing.Total = time.Since(start)
+	result.Timing.API = a.apiStats().Sub(startStats)
+	return result, nil
+}
+
+// auditListedFiles builds the file records of listing. Total time and API
+// stats are left to the caller.
+func (a *Auditor) auditListedFiles(ctx context.Context, listing *fileListing) *AuditResult {
+	result := listing.newResult()
+	result.FileRecords = make([]FileRecord, 0, len(listing.files))
+	result.FilesProcessed = len(listing.files)
 
-	for _, f := range files {
-		record := fileInfoToRecord(f)
+	strict := a.config != nil && a.config.Audit.Strict
+	for _, f := range listing.files {
+		record, err := parseFileInfo(f, strict)
+		if err != nil {
+			a.recordError(result, fmt.Errorf("file %s: %w", f.ID, err))
+		}
+		record.DriveName = a.driveName(ctx, f.DriveID)
 		result.FileRecords = append(result.FileRecords, record)
 	}
 
-	return result, nil
+	if a.includeLinkStatus() {
+		fetchStart := time.Now()
+		a.setLinkStatus(ctx, listing.files, result.FileRecords, result)
+		result.Timing.FetchPermissions = time.Since(fetchStart)
+	}
+
+	return result
+}
+
+// includeLinkStatus reports whether files audits fetch permissions to set
+// FileRecord.LinkSharingEnabled.
+func (a *Auditor) includeLinkStatus() bool {
+	return a.config != nil && a.config.Audit.IncludeLinkStatus
 }
 
-// fileInfoToRecord converts a drive.FileInfo to a FileRecord.
+// setLinkStatus fetches the permissions of each file and sets
+// LinkSharingEnabled on the record at the same index. Files whose
+// permissions could not be fetched are left unset and counted in result.
+func (a *Auditor) setLinkStatus(ctx context.Context, files []drive.FileInfo, records []FileRecord, result *AuditResult) {
+	outcomes := a.fetchPermissions(ctx, files)
+	a.recordSnapshots(result, files, outcomes)
+	for i, outcome := range outcomes {
+		switch {
+		case !outcome.done:
+			continue
+		case errors.Is(outcome.err, drive.ErrBudgetExceeded):
+			result.BudgetExceeded = true
+			continue
+		case outcome.err != nil:
+			a.recordError(result, fmt.Errorf("file %s: %w", files[i].ID, outcome.err))
+			continue
+		}
+
+		enabled := false
+		for _, perm := range outcome.perms {
+			if IsPublicPermissionType(perm.Type) {
+				enabled = true
+				break
+			}
+		}
+		records[i].LinkSharingEnabled = &enabled
+	}
+}
+
+// SortFileRecords sorts records by owner, then file name and file ID. See
+// RecordOrder for other orders.
+func SortFileRecords(records []FileRecord) {
+	RecordOrder{}.SortFileRecords(records)
+}
+
+// fileInfoToRecord converts a drive.FileInfo to a FileRecord. Malformed
+// timestamps become zero times.
 func fileInfoToRecord(f drive.FileInfo) FileRecord {
-	createdTime, _ := time.Parse(time.RFC3339, f.CreatedTime)
-	modifiedTime, _ := time.Parse(time.RFC3339, f.ModifiedTime)
+	record, _ := parseFileInfo(f, false)
+	return record
+}
+
+// parseFileInfo converts a drive.FileInfo to a FileRecord. In strict mode
+// malformed timestamps are reported in the returned error; the record is
+// still returned with zero times for those fields.
+func parseFileInfo(f drive.FileInfo, strict bool) (FileRecord, error) {
+	createdTime, createdErr := parseTimestamp("createdTime", f.CreatedTime, strict)
+	modifiedTime, modifiedErr := parseTimestamp("modifiedTime", f.ModifiedTime, strict)
+	viewedTime, viewedErr := parseTimestamp("viewedByMeTime", f.ViewedByMeTime, strict)
 
 	return FileRecord{
 		OwnerEmail:   f.OwnerEmail,
+		OwnerName:    f.OwnerName,
 		FileID:       f.ID,
 		FileName:     f.Name,
 		FileType:     f.MimeType,
 		CreatedTime:  createdTime,
 		ModifiedTime: modifiedTime,
 		SizeBytes:    f.Size,
+		Trashed:      f.Trashed,
+		Location:     Location(f.DriveID),
+
+		ViewedByMeTime: viewedTime,
+	}, errors.Join(createdErr, modifiedErr, viewedErr)
+}
+
+// parseTimestamp parses an RFC3339 timestamp returned by the Drive API. An
+// empty value is the zero time. A malformed value is the zero time, and in
+// strict mode also an error naming the field.
+func parseTimestamp(field, value string, strict bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		if strict {
+			return time.Time{}, fmt.Errorf("invalid %s %q: %w", field, value, err)
+		}
+		return time.Time{}, nil
 	}
+	return t, nil
 }
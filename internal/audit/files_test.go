@@ -4,11 +4,15 @@
 package audit
 
 import (
+	"context"
 	"testing"
 	"time"
 
+	"github.com/leansecurity-co/gwork/internal/config"
 	"github.com/leansecurity-co/gwork/internal/drive"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFileInfoToRecord(t *testing.T) {
@@ -36,6 +40,7 @@ func TestFileInfoToRecord(t *testing.T) {
 				CreatedTime:  time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
 				ModifiedTime: time.Date(2024, 1, 20, 15, 45, 0, 0, time.UTC),
 				SizeBytes:    1024,
+				Location:     LocationMyDrive,
 			},
 		},
 		{
@@ -57,6 +62,7 @@ func TestFileInfoToRecord(t *testing.T) {
 				CreatedTime:  time.Date(2024, 2, 1, 8, 0, 0, 0, time.UTC),
 				ModifiedTime: time.Date(2024, 2, 1, 8, 0, 0, 0, time.UTC),
 				SizeBytes:    512,
+				Location:     LocationMyDrive,
 			},
 		},
 		{
@@ -78,6 +84,7 @@ func TestFileInfoToRecord(t *testing.T) {
 				CreatedTime:  time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
 				ModifiedTime: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
 				SizeBytes:    0,
+				Location:     LocationMyDrive,
 			},
 		},
 		{
@@ -99,6 +106,7 @@ func TestFileInfoToRecord(t *testing.T) {
 				CreatedTime:  time.Date(2024, 4, 10, 9, 15, 0, 0, time.UTC),
 				ModifiedTime: time.Date(2024, 4, 15, 14, 30, 0, 0, time.UTC),
 				SizeBytes:    2048,
+				Location:     LocationMyDrive,
 			},
 		},
 		{
@@ -120,6 +128,22 @@ func TestFileInfoToRecord(t *testing.T) {
 				CreatedTime:  time.Time{}, // Zero time for invalid timestamp
 				ModifiedTime: time.Time{}, // Zero time for invalid timestamp
 				SizeBytes:    100,
+				Location:     LocationMyDrive,
+			},
+		},
+		{
+			name: "shared drive file",
+			fileInfo: drive.FileInfo{
+				ID:         "file321",
+				Name:       "plan.docx",
+				OwnerEmail: "owner@example.com",
+				DriveID:    "0ABCdriveID",
+			},
+			expected: FileRecord{
+				OwnerEmail: "owner@example.com",
+				FileID:     "file321",
+				FileName:   "plan.docx",
+				Location:   "shared_drive:0ABCdriveID",
 			},
 		},
 	}
@@ -190,3 +214,226 @@ func TestFileInfoToRecord_TimestampParsing(t *testing.T) {
 		})
 	}
 }
+
+func TestFileRecord_OwnerKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		fileInfo drive.FileInfo
+		wantKey  string
+	}{
+		{
+			name:     "email present",
+			fileInfo: drive.FileInfo{OwnerEmail: "owner@example.com", OwnerName: "Owner"},
+			wantKey:  "owner@example.com",
+		},
+		{
+			name:     "display name only",
+			fileInfo: drive.FileInfo{OwnerName: "Former Employee"},
+			wantKey:  "display:Former Employee",
+		},
+		{
+			name:     "fully empty owner",
+			fileInfo: drive.FileInfo{},
+			wantKey:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := fileInfoToRecord(tt.fileInfo)
+			assert.Equal(t, tt.fileInfo.OwnerName, record.OwnerName)
+			assert.Equal(t, tt.wantKey, record.OwnerKey())
+
+			share := permissionToRecord(tt.fileInfo, drive.Permission{Type: "anyone"})
+			assert.Equal(t, tt.fileInfo.OwnerName, share.OwnerName)
+			assert.Equal(t, tt.wantKey, share.OwnerKey())
+		})
+	}
+}
+
+func TestParseFileInfo_Strict(t *testing.T) {
+	malformed := drive.FileInfo{
+		ID:           "file1",
+		CreatedTime:  "2024-13-45",
+		ModifiedTime: "2024-03-20T14:45:00Z",
+	}
+
+	t.Run("lenient", func(t *testing.T) {
+		record, err := parseFileInfo(malformed, false)
+		require.NoError(t, err)
+		assert.True(t, record.CreatedTime.IsZero())
+		assert.Equal(t, time.Date(2024, 3, 20, 14, 45, 0, 0, time.UTC), record.ModifiedTime)
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		record, err := parseFileInfo(malformed, true)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid createdTime "2024-13-45"`)
+		assert.NotContains(t, err.Error(), "modifiedTime")
+		assert.True(t, record.CreatedTime.IsZero())
+		assert.Equal(t, "file1", record.FileID)
+	})
+
+	t.Run("strict allows missing timestamps", func(t *testing.T) {
+		_, err := parseFileInfo(drive.FileInfo{ID: "file2"}, true)
+		assert.NoError(t, err)
+	})
+}
+
+func TestParseFileInfo_ViewedByMeTime(t *testing.T) {
+	record, err := parseFileInfo(drive.FileInfo{ID: "file1", ViewedByMeTime: "2024-05-06T07:08:09Z"}, true)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC), record.ViewedByMeTime)
+
+	record, err = parseFileInfo(drive.FileInfo{ID: "file2"}, true)
+	require.NoError(t, err)
+	assert.True(t, record.ViewedByMeTime.IsZero())
+}
+
+func TestAuditFiles_Strict(t *testing.T) {
+	files := []drive.FileInfo{
+		{ID: "good", CreatedTime: "2024-01-01T00:00:00Z", ModifiedTime: "2024-01-02T00:00:00Z"},
+		{ID: "bad", CreatedTime: "yesterday", ModifiedTime: "today"},
+	}
+
+	tests := []struct {
+		name       string
+		strict     bool
+		wantErrors int
+	}{
+		{name: "lenient", strict: false, wantErrors: 0},
+		{name: "strict", strict: true, wantErrors: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockDriveClient)
+			mockClient.On("ListAllFiles", mock.Anything).Return(files, nil)
+
+			cfg := &config.Config{Audit: config.AuditConfig{Strict: tt.strict}}
+			result, err := NewAuditorWithClient(cfg, mockClient).AuditFiles(context.Background())
+			require.NoError(t, err)
+
+			assert.Len(t, result.FileRecords, 2, "malformed files are still reported")
+			require.Len(t, result.Errors, tt.wantErrors)
+			if tt.wantErrors > 0 {
+				assert.Contains(t, result.Errors[0].Error(), "file bad: invalid createdTime")
+				assert.Contains(t, result.Errors[0].Error(), "invalid modifiedTime")
+			}
+		})
+	}
+}
+
+func TestAuditFiles_OwnersMissing(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []drive.FileInfo
+		want  int
+	}{
+		{
+			name: "owners field withheld",
+			files: []drive.FileInfo{
+				{ID: "a"},
+				{ID: "b"},
+				{ID: "shared", DriveID: "drive1"},
+			},
+			want: 2,
+		},
+		{
+			name: "some owners resolved",
+			files: []drive.FileInfo{
+				{ID: "a"},
+				{ID: "b", OwnerEmail: "alice@example.com"},
+			},
+			want: 0,
+		},
+		{
+			name: "display name only",
+			files: []drive.FileInfo{
+				{ID: "a"},
+				{ID: "b", OwnerName: "Deleted User"},
+			},
+			want: 0,
+		},
+		{
+			name:  "shared drive files only",
+			files: []drive.FileInfo{{ID: "shared", DriveID: "drive1"}},
+			want:  0,
+		},
+		{
+			name: "no files",
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockDriveClient)
+			mockClient.On("ListAllFiles", mock.Anything).Return(tt.files, nil)
+
+			result, err := NewAuditorWithClient(&config.Config{}, mockClient).AuditFiles(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result.OwnersMissing)
+		})
+	}
+}
+
+func TestAuditAll_OwnersMissingOnFilesResult(t *testing.T) {
+	mockClient := new(MockDriveClient)
+	mockClient.On("ListAllFiles", mock.Anything).Return([]drive.FileInfo{{ID: "a"}, {ID: "b"}}, nil)
+	mockClient.On("GetFilePermissions", mock.Anything, mock.Anything).Return([]drive.Permission{}, nil)
+
+	filesResult, sharingResult, err := NewAuditorWithClient(&config.Config{}, mockClient).AuditAll(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, filesResult.OwnersMissing)
+	assert.Zero(t, sharingResult.OwnersMissing, "the listing is only reported once")
+}
+
+func TestAuditFiles_IncludeLinkStatus(t *testing.T) {
+	mockClient := new(MockDriveClient)
+
+	files := []drive.FileInfo{
+		{ID: "file1", Name: "link.doc", OwnerEmail: "owner@example.com"},
+		{ID: "file2", Name: "private.doc", OwnerEmail: "owner@example.com"},
+		{ID: "file3", Name: "public.doc", OwnerEmail: "owner@example.com"},
+		{ID: "file4", Name: "broken.doc", OwnerEmail: "owner@example.com"},
+	}
+	mockClient.On("ListAllFiles", mock.Anything).Return(files, nil)
+	mockClient.On("GetFilePermissions", mock.Anything, "file1").Return([]drive.Permission{
+		{ID: "p1", Type: "user", Role: "owner", EmailAddress: "owner@example.com"},
+		{ID: "anyoneWithLink", Type: "anyone", Role: "reader"},
+	}, nil)
+	mockClient.On("GetFilePermissions", mock.Anything, "file2").Return([]drive.Permission{
+		{ID: "p3", Type: "user", Role: "owner", EmailAddress: "owner@example.com"},
+		{ID: "p4", Type: "domain", Role: "reader", Domain: "example.com"},
+	}, nil)
+	mockClient.On("GetFilePermissions", mock.Anything, "file3").Return([]drive.Permission{
+		{ID: "p5", Type: "anyone", Role: "reader"},
+	}, nil)
+	mockClient.On("GetFilePermissions", mock.Anything, "file4").Return([]drive.Permission(nil), assert.AnError)
+
+	cfg := &config.Config{Audit: config.AuditConfig{IncludeLinkStatus: true}}
+	result, err := NewAuditorWithClient(cfg, mockClient).AuditFiles(context.Background())
+	require.NoError(t, err)
+	require.Len(t, result.FileRecords, 4)
+
+	require.NotNil(t, result.FileRecords[0].LinkSharingEnabled)
+	assert.True(t, *result.FileRecords[0].LinkSharingEnabled)
+	require.NotNil(t, result.FileRecords[1].LinkSharingEnabled)
+	assert.False(t, *result.FileRecords[1].LinkSharingEnabled)
+	require.NotNil(t, result.FileRecords[2].LinkSharingEnabled)
+	assert.True(t, *result.FileRecords[2].LinkSharingEnabled)
+	assert.Nil(t, result.FileRecords[3].LinkSharingEnabled, "unknown when permissions could not be fetched")
+	assert.Len(t, result.Errors, 1)
+}
+
+func TestAuditFiles_LinkStatusNotFetchedByDefault(t *testing.T) {
+	mockClient := new(MockDriveClient)
+	mockClient.On("ListAllFiles", mock.Anything).Return([]drive.FileInfo{{ID: "file1"}}, nil)
+
+	result, err := NewAuditorWithClient(&config.Config{}, mockClient).AuditFiles(context.Background())
+	require.NoError(t, err)
+	require.Len(t, result.FileRecords, 1)
+	assert.Nil(t, result.FileRecords[0].LinkSharingEnabled)
+	mockClient.AssertNotCalled(t, "GetFilePermissions", mock.Anything, mock.Anything)
+}
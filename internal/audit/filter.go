@@ -0,0 +1,244 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/leansecurity-co/gwork/internal/drivesyntax"
+)
+
+// FilterDirectOnly returns the records whose permission was granted directly
+// on the file, dropping permissions inherited from a parent folder or
+// shared drive.
+func FilterDirectOnly(records []ExternalShareRecord) []ExternalShareRecord {
+	out := make([]ExternalShareRecord, 0, len(records))
+	for _, rec := range records {
+		if !rec.Inherited {
+			out = append(out, rec)
+		}
+	}
+	return out
+}
+
+// FilterDeletedGrantees returns the records whose grantee account Drive
+// reports as deleted.
+func FilterDeletedGrantees(records []ExternalShareRecord) []ExternalShareRecord {
+	out := make([]ExternalShareRecord, 0, len(records))
+	for _, rec := range records {
+		if rec.GranteeDeleted {
+			out = append(out, rec)
+		}
+	}
+	return out
+}
+
+// FilterExpiringWithin returns the records whose permission expires within
+// the given duration of now. Permissions that never expire are dropped, as
+// are permissions that have already expired.
+func FilterExpiringWithin(records []ExternalShareRecord, now time.Time, within time.Duration) []ExternalShareRecord {
+	deadline := now.Add(within)
+	out := make([]ExternalShareRecord, 0, len(records))
+	for _, rec := range records {
+		if rec.ExpirationTime.IsZero() || rec.ExpirationTime.Before(now) {
+			continue
+		}
+		if !rec.ExpirationTime.After(deadline) {
+			out = append(out, rec)
+		}
+	}
+	return out
+}
+
+// ResumeFromOwner returns the records whose owner key sorts at or after
+// owner, skipping every owner before it, so the cutoff owner and everyone
+// after it are kept. records need not be sorted and keep their order. An
+// empty owner returns records unchanged.
+func ResumeFromOwner[T interface{ OwnerKey() string }](records []T, owner string) []T {
+	if owner == "" {
+		return records
+	}
+	out := make([]T, 0, len(records))
+	for _, rec := range records {
+		if rec.OwnerKey() >= owner {
+			out = append(out, rec)
+		}
+	}
+	return out
+}
+
+// ExcludeOwners returns the records whose owner key is none of emails,
+// compared case-insensitively, e.g. to drop the impersonated admin's own
+// files. With no emails, records are returned unchanged.
+func ExcludeOwners[T interface{ OwnerKey() string }](records []T, emails []string) []T {
+	if len(emails) == 0 {
+		return records
+	}
+	out := make([]T, 0, len(records))
+	for _, rec := range records {
+		if !slices.ContainsFunc(emails, func(email string) bool {
+			return strings.EqualFold(strings.TrimSpace(email), rec.OwnerKey())
+		}) {
+			out = append(out, rec)
+		}
+	}
+	return out
+}
+
+// FilterNotAccessedSince returns the file records last viewed before
+// cutoff. Records without a viewed time are dropped, since the Drive API only
+// reports it for files the impersonated admin has opened.
+func FilterNotAccessedSince(records []FileRecord, cutoff time.Time) []FileRecord {
+	out := make([]FileRecord, 0, len(records))
+	for _, rec := range records {
+		if !rec.ViewedByMeTime.IsZero() && rec.ViewedByMeTime.Before(cutoff) {
+			out = append(out, rec)
+		}
+	}
+	return out
+}
+
+// FilterFilesByOwnerDomain returns the file records whose owner email is in
+// one of domains, compared case-insensitively. Records without an owner
+// email never match. With no domains, records are returned unchanged.
+func FilterFilesByOwnerDomain(records []FileRecord, domains []string) []FileRecord {
+	if len(domains) == 0 {
+		return records
+	}
+	match := ownerDomainMatcher(domains)
+	out := make([]FileRecord, 0, len(records))
+	for _, rec := range records {
+		if match(rec.OwnerEmail) {
+			out = append(out, rec)
+		}
+	}
+	return out
+}
+
+// FilterSharesByOwnerDomain returns the share records whose file owner email
+// is in one of domains, like FilterFilesByOwnerDomain.
+func FilterSharesByOwnerDomain(records []ExternalShareRecord, domains []string) []ExternalShareRecord {
+	if len(domains) == 0 {
+		return records
+	}
+	match := ownerDomainMatcher(domains)
+	out := make([]ExternalShareRecord, 0, len(records))
+	for _, rec := range records {
+		if match(rec.OwnerEmail) {
+			out = append(out, rec)
+		}
+	}
+	return out
+}
+
+// ownerDomainMatcher reports whether an owner email belongs to one of
+// domains.
+func ownerDomainMatcher(domains []string) func(email string) bool {
+	set := make(map[string]struct{}, len(domains))
+	for _, d := range domains {
+		set[strings.ToLower(strings.TrimSpace(d))] = struct{}{}
+	}
+	return func(email string) bool {
+		domain := strings.ToLower(drivesyntax.ExtractDomain(email))
+		if domain == "" {
+			return false
+		}
+		_, ok := set[domain]
+		return ok
+	}
+}
+
+// MatchesGrantee reports whether the grantee of rec matches pattern: an
+// email address, matched exactly, or "@domain" (also written "*@domain"),
+// matching every grantee in that domain, including domain-type shares.
+// Subdomains are not matched, and public shares never match. Matching is
+// case-insensitive.
+func MatchesGrantee(rec ExternalShareRecord, pattern string) bool {
+	if IsPublicPermissionType(rec.PermissionType) {
+		return false
+	}
+	pattern = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(pattern), "*"))
+	if domain, ok := strings.CutPrefix(pattern, "@"); ok {
+		return domain != "" && granteeDomain(rec) == domain
+	}
+	return pattern != "" && strings.ToLower(rec.SharedWithEmail) == pattern
+}
+
+// FilterSharedWith returns the records whose grantee matches any of
+// patterns, as defined by MatchesGrantee.
+func FilterSharedWith(records []ExternalShareRecord, patterns []string) []ExternalShareRecord {
+	if len(patterns) == 0 {
+		return records
+	}
+	out := make([]ExternalShareRecord, 0, len(records))
+	for _, rec := range records {
+		for _, pattern := range patterns {
+			if MatchesGrantee(rec, pattern) {
+				out = append(out, rec)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// FilterCount is the number of records one filter stage removed.
+type FilterCount struct {
+	Stage  string `json:"stage"`
+	Files  int    `json:"files"`
+	Shares int    `json:"shares"`
+}
+
+// FilterStats lists the records removed by each filter stage applied to a
+// result, in the order the stages first ran. A stage that removed nothing
+// is still listed, so users can tell it ran.
+type FilterStats []FilterCount
+
+// Add records that stage removed files file records and shares share
+// records, adding to the counts of an earlier entry for the same stage.
+func (s *FilterStats) Add(stage string, files, shares int) {
+	for i := range *s {
+		if (*s)[i].Stage == stage {
+			(*s)[i].Files += files
+			(*s)[i].Shares += shares
+			return
+		}
+	}
+	*s = append(*s, FilterCount{Stage: stage, Files: files, Shares: shares})
+}
+
+// Merge adds the counts of other to s, stage by stage.
+func (s *FilterStats) Merge(other FilterStats) {
+	for _, c := range other {
+		s.Add(c.Stage, c.Files, c.Shares)
+	}
+}
+
+// FilterFiles replaces the file records of r with those kept by filter and
+// records how many the stage removed in r.Filtered. Results without file
+// records, such as those of sharing audits, are left alone.
+func (r *AuditResult) FilterFiles(stage string, filter func([]FileRecord) []FileRecord) {
+	if r.FileRecords == nil {
+		return
+	}
+	before := len(r.FileRecords)
+	r.FileRecords = filter(r.FileRecords)
+	r.Filtered.Add(stage, before-len(r.FileRecords), 0)
+}
+
+// FilterShares replaces the share records of r with those kept by filter,
+// updates TotalExternalShares and records how many the stage removed in
+// r.Filtered. Results without share records, such as those of files
+// audits, are left alone.
+func (r *AuditResult) FilterShares(stage string, filter func([]ExternalShareRecord) []ExternalShareRecord) {
+	if r.ExternalShares == nil {
+		return
+	}
+	before := len(r.ExternalShares)
+	r.ExternalShares = filter(r.ExternalShares)
+	r.TotalExternalShares = len(r.ExternalShares)
+	r.Filtered.Add(stage, 0, before-len(r.ExternalShares))
+}
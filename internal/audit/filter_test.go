@@ -0,0 +1,373 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/leansecurity-co/gwork/internal/config"
+	"github.com/leansecurity-co/gwork/internal/drive"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterDirectOnly_ThroughAudit(t *testing.T) {
+	mockClient := new(MockDriveClient)
+
+	files := []drive.FileInfo{
+		{ID: "file1", Name: "direct.txt", OwnerEmail: "owner@example.com"},
+		{ID: "file2", Name: "inherited.txt", OwnerEmail: "owner@example.com"},
+	}
+	direct := drive.Permission{ID: "p1", Type: "user", Role: "reader", EmailAddress: "guest@other.com"}
+	inherited := drive.Permission{ID: "p2", Type: "anyone", Role: "reader", Inherited: true, InheritedFrom: "folder1"}
+
+	mockClient.On("ListAllFiles", mock.Anything).Return(files, nil)
+	mockClient.On("GetFilePermissions", mock.Anything, "file1").Return([]drive.Permission{direct}, nil)
+	mockClient.On("GetFilePermissions", mock.Anything, "file2").Return([]drive.Permission{inherited}, nil)
+	mockClient.On("IsExternalShare", mock.Anything).Return(true)
+
+	auditor := NewAuditorWithClient(&config.Config{}, mockClient)
+	result, err := auditor.AuditExternalSharing(context.Background())
+	require.NoError(t, err)
+	require.Len(t, result.ExternalShares, 2)
+
+	byFile := map[string]ExternalShareRecord{}
+	for _, rec := range result.ExternalShares {
+		byFile[rec.FileID] = rec
+	}
+	assert.False(t, byFile["file1"].Inherited)
+	assert.True(t, byFile["file2"].Inherited)
+	assert.Equal(t, "folder1", byFile["file2"].InheritedFrom)
+
+	filtered := FilterDirectOnly(result.ExternalShares)
+	require.Len(t, filtered, 1)
+	assert.Equal(t, "file1", filtered[0].FileID)
+}
+
+func TestFilterDeletedGrantees_ThroughAudit(t *testing.T) {
+	mockClient := new(MockDriveClient)
+
+	files := []drive.FileInfo{{ID: "file1", Name: "plan.txt", OwnerEmail: "owner@example.com"}}
+	deleted := drive.Permission{ID: "p1", Type: "user", Role: "writer", EmailAddress: "former@other.com", Deleted: true}
+	active := drive.Permission{ID: "p2", Type: "user", Role: "reader", EmailAddress: "current@other.com"}
+
+	mockClient.On("ListAllFiles", mock.Anything).Return(files, nil)
+	mockClient.On("GetFilePermissions", mock.Anything, "file1").Return([]drive.Permission{deleted, active}, nil)
+	mockClient.On("IsExternalShare", mock.Anything).Return(true)
+
+	auditor := NewAuditorWithClient(&config.Config{}, mockClient)
+	result, err := auditor.AuditExternalSharing(context.Background())
+	require.NoError(t, err)
+	require.Len(t, result.ExternalShares, 2)
+
+	byGrantee := map[string]ExternalShareRecord{}
+	for _, rec := range result.ExternalShares {
+		byGrantee[rec.SharedWithEmail] = rec
+	}
+	assert.True(t, byGrantee["former@other.com"].GranteeDeleted)
+	assert.False(t, byGrantee["current@other.com"].GranteeDeleted)
+
+	result.FilterShares("deleted-grantees-only", FilterDeletedGrantees)
+	require.Len(t, result.ExternalShares, 1)
+	assert.Equal(t, "former@other.com", result.ExternalShares[0].SharedWithEmail)
+	assert.Equal(t, 1, result.TotalExternalShares)
+	assert.Equal(t, FilterStats{{Stage: "deleted-grantees-only", Shares: 1}}, result.Filtered)
+}
+
+func TestFilterDirectOnly(t *testing.T) {
+	records := []ExternalShareRecord{
+		{FileID: "a", Inherited: false},
+		{FileID: "b", Inherited: true},
+		{FileID: "c", Inherited: false},
+	}
+
+	filtered := FilterDirectOnly(records)
+	assert.Equal(t, []ExternalShareRecord{{FileID: "a"}, {FileID: "c"}}, filtered)
+	assert.Empty(t, FilterDirectOnly(nil))
+}
+
+func TestFilterExpiringWithin(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	records := []ExternalShareRecord{
+		{FileID: "never"},
+		{FileID: "soon", ExpirationTime: now.Add(24 * time.Hour)},
+		{FileID: "boundary", ExpirationTime: now.Add(7 * 24 * time.Hour)},
+		{FileID: "later", ExpirationTime: now.Add(30 * 24 * time.Hour)},
+		{FileID: "expired", ExpirationTime: now.Add(-time.Hour)},
+	}
+
+	tests := []struct {
+		name   string
+		within time.Duration
+		want   []string
+	}{
+		{name: "one week", within: 7 * 24 * time.Hour, want: []string{"soon", "boundary"}},
+		{name: "one day", within: 24 * time.Hour, want: []string{"soon"}},
+		{name: "one hour", within: time.Hour, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids := []string{}
+			for _, rec := range FilterExpiringWithin(records, now, tt.within) {
+				ids = append(ids, rec.FileID)
+			}
+			assert.Equal(t, tt.want, ids)
+		})
+	}
+}
+
+func TestFilterByOwnerDomain(t *testing.T) {
+	files := []FileRecord{
+		{FileID: "1", OwnerEmail: "alice@example.com"},
+		{FileID: "2", OwnerEmail: "bob@Subsidiary.example"},
+		{FileID: "3", OwnerEmail: "carol@partner.com"},
+		{FileID: "4", OwnerName: "Former Employee"},
+		{FileID: "5", OwnerEmail: "dave@sub.example.com"},
+	}
+	shares := make([]ExternalShareRecord, len(files))
+	for i, f := range files {
+		shares[i] = ExternalShareRecord{FileID: f.FileID, OwnerEmail: f.OwnerEmail, OwnerName: f.OwnerName}
+	}
+
+	tests := []struct {
+		name    string
+		domains []string
+		wantIDs []string
+	}{
+		{name: "no domains keeps all", domains: nil, wantIDs: []string{"1", "2", "3", "4", "5"}},
+		{name: "single domain", domains: []string{"example.com"}, wantIDs: []string{"1"}},
+		{name: "case-insensitive", domains: []string{"SUBSIDIARY.example"}, wantIDs: []string{"2"}},
+		{name: "multiple domains", domains: []string{"example.com", "partner.com"}, wantIDs: []string{"1", "3"}},
+		{name: "no match", domains: []string{"other.org"}, wantIDs: []string{}},
+		{name: "empty domain never matches missing owner", domains: []string{""}, wantIDs: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantIDs, fileIDs(FilterFilesByOwnerDomain(files, tt.domains)))
+
+			gotShares := FilterSharesByOwnerDomain(shares, tt.domains)
+			shareIDs := make([]string, 0, len(gotShares))
+			for _, rec := range gotShares {
+				shareIDs = append(shareIDs, rec.FileID)
+			}
+			assert.Equal(t, tt.wantIDs, shareIDs)
+		})
+	}
+}
+
+func TestResumeFromOwner(t *testing.T) {
+	files := []FileRecord{
+		{OwnerEmail: "alice@example.com", FileID: "1"},
+		{OwnerEmail: "bob@example.com", FileID: "2"},
+		{OwnerEmail: "bob@example.com", FileID: "3"},
+		{OwnerEmail: "carol@example.com", FileID: "4"},
+	}
+	SortFileRecords(files)
+
+	tests := []struct {
+		name    string
+		owner   string
+		wantIDs []string
+	}{
+		{name: "no cutoff", owner: "", wantIDs: []string{"1", "2", "3", "4"}},
+		{name: "cutoff owner included", owner: "bob@example.com", wantIDs: []string{"2", "3", "4"}},
+		{name: "cutoff between owners", owner: "b", wantIDs: []string{"2", "3", "4"}},
+		{name: "first owner", owner: "alice@example.com", wantIDs: []string{"1", "2", "3", "4"}},
+		{name: "after last owner", owner: "dave@example.com", wantIDs: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantIDs, fileIDs(ResumeFromOwner(files, tt.owner)))
+		})
+	}
+
+	shares := []ExternalShareRecord{
+		{OwnerEmail: "carol@example.com", FileID: "c"},
+		{OwnerEmail: "alice@example.com", FileID: "a"},
+		{OwnerEmail: "bob@example.com", FileID: "b"},
+	}
+	SortExternalShares(shares)
+	resumed := ResumeFromOwner(shares, "bob@example.com")
+	require.Len(t, resumed, 2)
+	assert.Equal(t, "b", resumed[0].FileID)
+	assert.Equal(t, "c", resumed[1].FileID)
+}
+
+func TestResumeFromOwnerUnsorted(t *testing.T) {
+	// Files come in Drive listing order, and merged domains are appended
+	// without re-sorting.
+	files := []FileRecord{
+		{OwnerEmail: "zed@example.com", FileID: "1"},
+		{OwnerEmail: "alice@example.com", FileID: "2"},
+		{OwnerEmail: "bob@example.com", FileID: "3"},
+		{OwnerEmail: "alice@example.com", FileID: "4"},
+	}
+
+	assert.Equal(t, []string{"1", "3"}, fileIDs(ResumeFromOwner(files, "bob@example.com")))
+}
+
+func TestExcludeOwners(t *testing.T) {
+	files := []FileRecord{
+		{FileID: "1", OwnerEmail: "admin@example.com"},
+		{FileID: "2", OwnerEmail: "alice@example.com"},
+		{FileID: "3", OwnerEmail: "Admin@Example.com"},
+		{FileID: "4", OwnerName: "Former Employee"},
+		{FileID: "5", OwnerEmail: "admin@partner.com"},
+	}
+
+	tests := []struct {
+		name    string
+		emails  []string
+		wantIDs []string
+	}{
+		{name: "no emails keeps all", wantIDs: []string{"1", "2", "3", "4", "5"}},
+		{name: "admin files dropped", emails: []string{"admin@example.com"}, wantIDs: []string{"2", "4", "5"}},
+		{name: "case-insensitive", emails: []string{" ADMIN@example.com "}, wantIDs: []string{"2", "4", "5"}},
+		{name: "several admins", emails: []string{"admin@example.com", "admin@partner.com"}, wantIDs: []string{"2", "4"}},
+		{name: "no match", emails: []string{"root@example.com"}, wantIDs: []string{"1", "2", "3", "4", "5"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantIDs, fileIDs(ExcludeOwners(files, tt.emails)))
+		})
+	}
+
+	shares := []ExternalShareRecord{
+		{OwnerEmail: "admin@example.com", FileID: "a"},
+		{OwnerEmail: "bob@example.com", FileID: "b"},
+	}
+	kept := ExcludeOwners(shares, []string{"admin@example.com"})
+	require.Len(t, kept, 1)
+	assert.Equal(t, "b", kept[0].FileID)
+}
+
+func TestFilterNotAccessedSince(t *testing.T) {
+	cutoff := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	records := []FileRecord{
+		{FileID: "old", ViewedByMeTime: cutoff.Add(-24 * time.Hour)},
+		{FileID: "just-before", ViewedByMeTime: cutoff.Add(-time.Second)},
+		{FileID: "at-cutoff", ViewedByMeTime: cutoff},
+		{FileID: "recent", ViewedByMeTime: cutoff.Add(time.Hour)},
+		{FileID: "never-viewed"},
+	}
+
+	assert.Equal(t, []string{"old", "just-before"}, fileIDs(FilterNotAccessedSince(records, cutoff)))
+	assert.Empty(t, FilterNotAccessedSince([]FileRecord{{FileID: "never-viewed"}}, cutoff))
+}
+
+func TestMatchesGrantee(t *testing.T) {
+	user := ExternalShareRecord{PermissionType: "user", SharedWithEmail: "Alice@Vendor.com", SharedWithDomain: "Vendor.com"}
+	domain := ExternalShareRecord{PermissionType: "domain", SharedWithDomain: "vendor.com"}
+	public := ExternalShareRecord{PermissionType: "anyone"}
+
+	tests := []struct {
+		name    string
+		rec     ExternalShareRecord
+		pattern string
+		want    bool
+	}{
+		{name: "exact email", rec: user, pattern: "alice@vendor.com", want: true},
+		{name: "exact email other user", rec: user, pattern: "bob@vendor.com", want: false},
+		{name: "domain", rec: user, pattern: "@vendor.com", want: true},
+		{name: "domain wildcard", rec: user, pattern: "*@VENDOR.com", want: true},
+		{name: "other domain", rec: user, pattern: "@partner.com", want: false},
+		{name: "parent domain is not a suffix match", rec: user, pattern: "@com", want: false},
+		{name: "subdomain not matched", rec: ExternalShareRecord{PermissionType: "user", SharedWithEmail: "a@eu.vendor.com"}, pattern: "@vendor.com", want: false},
+		{name: "domain share", rec: domain, pattern: "@vendor.com", want: true},
+		{name: "domain share and email", rec: domain, pattern: "alice@vendor.com", want: false},
+		{name: "public share", rec: public, pattern: "@vendor.com", want: false},
+		{name: "empty domain", rec: public, pattern: "@", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MatchesGrantee(tt.rec, tt.pattern))
+		})
+	}
+}
+
+func TestFilterSharedWith(t *testing.T) {
+	records := []ExternalShareRecord{
+		{FileID: "1", PermissionType: "user", SharedWithEmail: "alice@vendor.com"},
+		{FileID: "2", PermissionType: "user", SharedWithEmail: "bob@partner.com"},
+		{FileID: "3", PermissionType: "group", SharedWithEmail: "team@other.com"},
+		{FileID: "4", PermissionType: "anyone"},
+	}
+
+	ids := func(recs []ExternalShareRecord) []string {
+		out := make([]string, 0, len(recs))
+		for _, rec := range recs {
+			out = append(out, rec.FileID)
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{"1", "2", "3", "4"}, ids(FilterSharedWith(records, nil)), "no patterns keeps everything")
+	assert.Equal(t, []string{"1"}, ids(FilterSharedWith(records, []string{"@vendor.com"})))
+	assert.Equal(t, []string{"1", "3"}, ids(FilterSharedWith(records, []string{"team@other.com", "@vendor.com"})), "patterns are ORed")
+	assert.Empty(t, FilterSharedWith(records, []string{"@nowhere.com"}))
+}
+
+func TestFilterStats_StackedFilters(t *testing.T) {
+	files := &AuditResult{FileRecords: []FileRecord{
+		{OwnerEmail: "alice@example.com"},
+		{OwnerEmail: "bob@example.com"},
+		{OwnerEmail: "carol@other.com"},
+	}}
+	sharing := &AuditResult{ExternalShares: []ExternalShareRecord{
+		{OwnerEmail: "alice@example.com", Inherited: true},
+		{OwnerEmail: "alice@example.com"},
+		{OwnerEmail: "bob@example.com", Flagged: true},
+		{OwnerEmail: "carol@other.com", Flagged: true},
+	}}
+
+	for _, result := range []*AuditResult{files, sharing} {
+		result.FilterFiles("owner-domain", func(records []FileRecord) []FileRecord {
+			return FilterFilesByOwnerDomain(records, []string{"example.com"})
+		})
+		result.FilterShares("owner-domain", func(records []ExternalShareRecord) []ExternalShareRecord {
+			return FilterSharesByOwnerDomain(records, []string{"example.com"})
+		})
+		result.FilterShares("direct-only", FilterDirectOnly)
+		result.FilterShares("flagged-only", FilterFlagged)
+	}
+
+	assert.Len(t, files.FileRecords, 2)
+	assert.Equal(t, FilterStats{{Stage: "owner-domain", Files: 1}}, files.Filtered,
+		"share filters leave a files result alone")
+
+	assert.Len(t, sharing.ExternalShares, 1)
+	assert.Equal(t, 1, sharing.TotalExternalShares)
+	assert.Equal(t, FilterStats{
+		{Stage: "owner-domain", Shares: 1},
+		{Stage: "direct-only", Shares: 1},
+		{Stage: "flagged-only", Shares: 1},
+	}, sharing.Filtered)
+
+	assert.Equal(t, FilterStats{
+		{Stage: "owner-domain", Files: 1, Shares: 1},
+		{Stage: "direct-only", Shares: 1},
+		{Stage: "flagged-only", Shares: 1},
+	}, Summarize(files, sharing).Filtered)
+}
+
+func TestFilterStats_Add(t *testing.T) {
+	var stats FilterStats
+	stats.Add("shared-with", 0, 2)
+	stats.Add("not-accessed-since", 4, 0)
+	stats.Add("shared-with", 0, 3)
+	assert.Equal(t, FilterStats{{Stage: "shared-with", Shares: 5}, {Stage: "not-accessed-since", Files: 4}}, stats)
+
+	var merged FilterStats
+	merged.Merge(stats)
+	merged.Merge(stats)
+	assert.Equal(t, FilterStats{{Stage: "shared-with", Shares: 10}, {Stage: "not-accessed-since", Files: 8}}, merged)
+}
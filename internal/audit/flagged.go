@@ -0,0 +1,59 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"strings"
+
+	"github.com/leansecurity-co/gwork/internal/drivesyntax"
+)
+
+// SetFlaggedDomains sets sensitive grantee domains, such as competitors or
+// sanctioned organizations. Shares with a grantee in one of them are marked
+// as flagged. Domains are compared case-insensitively.
+func (a *Auditor) SetFlaggedDomains(domains ...string) {
+	if len(domains) == 0 {
+		a.flaggedDomains = nil
+		return
+	}
+	a.flaggedDomains = make(map[string]struct{}, len(domains))
+	for _, d := range domains {
+		a.flaggedDomains[strings.ToLower(d)] = struct{}{}
+	}
+}
+
+// MarkFlagged sets Flagged and FlagReason on each record whose grantee
+// domain is in flagged, whose keys must be lowercase.
+func MarkFlagged(records []ExternalShareRecord, flagged map[string]struct{}) {
+	if len(flagged) == 0 {
+		return
+	}
+	for i := range records {
+		domain := granteeDomain(records[i])
+		if _, ok := flagged[domain]; ok && domain != "" {
+			records[i].Flagged = true
+			records[i].FlagReason = "flagged domain " + domain
+		}
+	}
+}
+
+// granteeDomain returns the lowercase domain a share was granted to.
+func granteeDomain(rec ExternalShareRecord) string {
+	domain := rec.SharedWithDomain
+	if domain == "" {
+		domain = drivesyntax.ExtractDomain(rec.SharedWithEmail)
+	}
+	return strings.ToLower(domain)
+}
+
+// FilterFlagged returns the records marked as flagged.
+func FilterFlagged(records []ExternalShareRecord) []ExternalShareRecord {
+	out := make([]ExternalShareRecord, 0, len(records))
+	for _, rec := range records {
+		if rec.Flagged {
+			out = append(out, rec)
+		}
+	}
+	return out
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/config"
+	"github.com/leansecurity-co/gwork/internal/drive"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkFlagged(t *testing.T) {
+	flagged := map[string]struct{}{"rival.com": {}}
+
+	tests := []struct {
+		name       string
+		record     ExternalShareRecord
+		wantFlag   bool
+		wantReason string
+	}{
+		{
+			name:       "grantee domain matches",
+			record:     ExternalShareRecord{SharedWithEmail: "spy@rival.com", SharedWithDomain: "rival.com"},
+			wantFlag:   true,
+			wantReason: "flagged domain rival.com",
+		},
+		{
+			name:       "match is case-insensitive",
+			record:     ExternalShareRecord{SharedWithEmail: "Spy@Rival.COM", SharedWithDomain: "Rival.COM"},
+			wantFlag:   true,
+			wantReason: "flagged domain rival.com",
+		},
+		{
+			name:       "domain taken from email when missing",
+			record:     ExternalShareRecord{SharedWithEmail: "spy@rival.com"},
+			wantFlag:   true,
+			wantReason: "flagged domain rival.com",
+		},
+		{
+			name:     "domain share",
+			record:   ExternalShareRecord{PermissionType: "domain", SharedWithDomain: "rival.com"},
+			wantFlag: true, wantReason: "flagged domain rival.com",
+		},
+		{name: "other domain", record: ExternalShareRecord{SharedWithEmail: "a@partner.com", SharedWithDomain: "partner.com"}},
+		{name: "subdomain does not match", record: ExternalShareRecord{SharedWithEmail: "a@eu.rival.com"}},
+		{name: "public share", record: ExternalShareRecord{PermissionType: "anyone"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records := []ExternalShareRecord{tt.record}
+			MarkFlagged(records, flagged)
+			assert.Equal(t, tt.wantFlag, records[0].Flagged)
+			assert.Equal(t, tt.wantReason, records[0].FlagReason)
+		})
+	}
+}
+
+func TestFilterFlagged(t *testing.T) {
+	records := []ExternalShareRecord{
+		{FileID: "1", Flagged: true},
+		{FileID: "2"},
+		{FileID: "3", Flagged: true},
+	}
+
+	filtered := FilterFlagged(records)
+	require.Len(t, filtered, 2)
+	assert.Equal(t, "1", filtered[0].FileID)
+	assert.Equal(t, "3", filtered[1].FileID)
+	assert.Empty(t, FilterFlagged(nil))
+}
+
+func TestAuditor_FlaggedDomains(t *testing.T) {
+	files := []drive.FileInfo{{ID: "doc", Name: "Roadmap", OwnerEmail: "alice@example.com"}}
+
+	mockClient := new(MockDriveClient)
+	mockClient.On("ListAllFiles", mock.Anything).Return(files, nil)
+	mockClient.On("GetFilePermissions", mock.Anything, "doc").Return([]drive.Permission{
+		{Type: "user", Role: "reader", EmailAddress: "bob@RIVAL.com"},
+		{Type: "user", Role: "writer", EmailAddress: "carol@partner.com"},
+	}, nil)
+	mockClient.On("IsExternalShare", mock.Anything).Return(true)
+
+	cfg := &config.Config{Audit: config.AuditConfig{FlaggedDomains: []string{"Rival.com"}}}
+	auditor := NewAuditorWithClient(cfg, mockClient)
+	auditor.SetFlaggedDomains(cfg.Audit.FlaggedDomains...)
+
+	result, err := auditor.AuditExternalSharing(context.Background())
+	require.NoError(t, err)
+	require.Len(t, result.ExternalShares, 2)
+
+	byEmail := make(map[string]ExternalShareRecord)
+	for _, rec := range result.ExternalShares {
+		byEmail[rec.SharedWithEmail] = rec
+	}
+	assert.True(t, byEmail["bob@RIVAL.com"].Flagged)
+	assert.Equal(t, "flagged domain rival.com", byEmail["bob@RIVAL.com"].FlagReason)
+	assert.False(t, byEmail["carol@partner.com"].Flagged)
+}
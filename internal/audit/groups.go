@@ -0,0 +1,97 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/leansecurity-co/gwork/internal/drive"
+	"github.com/leansecurity-co/gwork/internal/drivesyntax"
+)
+
+// expandGroups resolves the members of every group share in records and
+// records the member count and whether any member is outside the domain.
+// Groups already in cache are not resolved again, and those resolved are
+// added to it. Resolution failures are recorded in result.Errors and leave
+// the group's records unchanged. Groups of other domains are counted in
+// result.UnexpandedGroups instead, since the Directory API only lists
+// groups in the organization's own Workspace.
+func (a *Auditor) expandGroups(ctx context.Context, result *AuditResult, records []ExternalShareRecord, cache map[string]groupMembers) {
+	for i := range records {
+		rec := &records[i]
+		if rec.PermissionType != "group" || rec.SharedWithEmail == "" {
+			continue
+		}
+
+		group, seen := cache[rec.SharedWithEmail]
+		if !seen {
+			if a.isDirectoryGroup(rec.SharedWithEmail) {
+				var err error
+				if group, err = a.resolveGroup(ctx, rec.SharedWithEmail); err != nil {
+					a.recordError(result, err)
+				}
+			} else {
+				result.UnexpandedGroups++
+			}
+			cache[rec.SharedWithEmail] = group
+		}
+		group.apply(rec)
+	}
+}
+
+// groupMembers summarizes the members of a group shared with.
+type groupMembers struct {
+	count    int
+	external bool
+	ok       bool // false if the members could not be resolved
+}
+
+// apply sets the member fields of rec if the group was resolved.
+func (g groupMembers) apply(rec *ExternalShareRecord) {
+	if g.ok {
+		rec.GroupMemberCount = g.count
+		rec.HasExternalMembers = g.external
+	}
+}
+
+// isDirectoryGroup reports whether the Directory API can list the members
+// of the group email: whether it is in the primary domain or one of its
+// aliases. Without a configured domain every group is tried.
+func (a *Auditor) isDirectoryGroup(email string) bool {
+	google := a.google()
+	if google.Domain == "" {
+		return true
+	}
+	domain := drivesyntax.ExtractDomain(email)
+	if strings.EqualFold(domain, google.Domain) {
+		return true
+	}
+	for _, alias := range google.DomainAliases {
+		if strings.EqualFold(domain, alias) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveGroup fetches the members of the group email.
+func (a *Auditor) resolveGroup(ctx context.Context, email string) (groupMembers, error) {
+	members, err := a.groupResolver.GroupMembers(ctx, email)
+	if err != nil {
+		return groupMembers{}, fmt.Errorf("group %s: %w", email, err)
+	}
+	return groupMembers{count: len(members), external: a.hasExternalMember(members), ok: true}, nil
+}
+
+// hasExternalMember reports whether any member email is outside the domain.
+func (a *Auditor) hasExternalMember(members []string) bool {
+	for _, member := range members {
+		if a.driveClient.IsExternalShare(drive.Permission{Type: "user", EmailAddress: member}) {
+			return true
+		}
+	}
+	return false
+}
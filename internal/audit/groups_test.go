@@ -0,0 +1,167 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/config"
+	"github.com/leansecurity-co/gwork/internal/drive"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGroupResolver returns canned members per group and counts lookups.
+type fakeGroupResolver struct {
+	members map[string][]string
+	errs    map[string]error
+	calls   map[string]int
+}
+
+func (f *fakeGroupResolver) GroupMembers(_ context.Context, groupEmail string) ([]string, error) {
+	if f.calls == nil {
+		f.calls = make(map[string]int)
+	}
+	f.calls[groupEmail]++
+	if err := f.errs[groupEmail]; err != nil {
+		return nil, err
+	}
+	return f.members[groupEmail], nil
+}
+
+// isExternal treats everything outside example.com as external.
+func isExternal(p drive.Permission) bool {
+	return !strings.HasSuffix(p.EmailAddress, "@example.com")
+}
+
+func TestAuditExternalSharing_ExpandGroups(t *testing.T) {
+	mockClient := new(MockDriveClient)
+
+	files := []drive.FileInfo{
+		{ID: "file1", Name: "a.txt", OwnerEmail: "owner@example.com"},
+		{ID: "file2", Name: "b.txt", OwnerEmail: "owner@example.com"},
+		{ID: "file3", Name: "c.txt", OwnerEmail: "owner@example.com"},
+	}
+	mixed := drive.Permission{ID: "p1", Type: "group", Role: "reader", EmailAddress: "mixed@partner.com"}
+	internalOnly := drive.Permission{ID: "p2", Type: "group", Role: "reader", EmailAddress: "staff@partner.com"}
+	user := drive.Permission{ID: "p3", Type: "user", Role: "reader", EmailAddress: "guest@other.com"}
+
+	mockClient.On("ListAllFiles", mock.Anything).Return(files, nil)
+	mockClient.On("GetFilePermissions", mock.Anything, "file1").Return([]drive.Permission{mixed, user}, nil)
+	mockClient.On("GetFilePermissions", mock.Anything, "file2").Return([]drive.Permission{mixed}, nil)
+	mockClient.On("GetFilePermissions", mock.Anything, "file3").Return([]drive.Permission{internalOnly}, nil)
+	mockClient.On("IsExternalShare", mock.MatchedBy(isExternal)).Return(true)
+	mockClient.On("IsExternalShare", mock.Anything).Return(false)
+
+	resolver := &fakeGroupResolver{
+		members: map[string][]string{
+			"mixed@partner.com": {"alice@example.com", "bob@partner.com", "carol@example.com"},
+			"staff@partner.com": {"dave@example.com", "erin@example.com"},
+		},
+	}
+
+	auditor := NewAuditorWithClient(&config.Config{}, mockClient)
+	auditor.SetGroupResolver(resolver)
+
+	result, err := auditor.AuditExternalSharing(context.Background())
+	require.NoError(t, err)
+	require.Len(t, result.ExternalShares, 4)
+	assert.Empty(t, result.Errors)
+
+	for _, rec := range result.ExternalShares {
+		switch rec.SharedWithEmail {
+		case "mixed@partner.com":
+			assert.Equal(t, 3, rec.GroupMemberCount)
+			assert.True(t, rec.HasExternalMembers)
+		case "staff@partner.com":
+			assert.Equal(t, 2, rec.GroupMemberCount)
+			assert.False(t, rec.HasExternalMembers)
+		default:
+			assert.Zero(t, rec.GroupMemberCount)
+			assert.False(t, rec.HasExternalMembers)
+		}
+	}
+
+	// Each group is resolved once even when shared on several files.
+	assert.Equal(t, 1, resolver.calls["mixed@partner.com"])
+	assert.Equal(t, 1, resolver.calls["staff@partner.com"])
+}
+
+func TestAuditExternalSharing_ExpandGroupsError(t *testing.T) {
+	mockClient := new(MockDriveClient)
+
+	files := []drive.FileInfo{{ID: "file1", Name: "a.txt", OwnerEmail: "owner@example.com"}}
+	group := drive.Permission{ID: "p1", Type: "group", Role: "reader", EmailAddress: "hidden@partner.com"}
+
+	mockClient.On("ListAllFiles", mock.Anything).Return(files, nil)
+	mockClient.On("GetFilePermissions", mock.Anything, "file1").Return([]drive.Permission{group}, nil)
+	mockClient.On("IsExternalShare", mock.Anything).Return(true)
+
+	resolver := &fakeGroupResolver{
+		errs: map[string]error{"hidden@partner.com": errors.New("forbidden")},
+	}
+
+	auditor := NewAuditorWithClient(&config.Config{}, mockClient)
+	auditor.SetGroupResolver(resolver)
+
+	result, err := auditor.AuditExternalSharing(context.Background())
+	require.NoError(t, err)
+	require.Len(t, result.ExternalShares, 1)
+	require.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors[0].Error(), "hidden@partner.com")
+	assert.Zero(t, result.ExternalShares[0].GroupMemberCount)
+}
+
+func TestAuditExternalSharing_GroupsNotExpandedByDefault(t *testing.T) {
+	mockClient := new(MockDriveClient)
+
+	files := []drive.FileInfo{{ID: "file1", Name: "a.txt", OwnerEmail: "owner@example.com"}}
+	group := drive.Permission{ID: "p1", Type: "group", Role: "reader", EmailAddress: "mixed@partner.com"}
+
+	mockClient.On("ListAllFiles", mock.Anything).Return(files, nil)
+	mockClient.On("GetFilePermissions", mock.Anything, "file1").Return([]drive.Permission{group}, nil)
+	mockClient.On("IsExternalShare", mock.Anything).Return(true)
+
+	auditor := NewAuditorWithClient(&config.Config{}, mockClient)
+
+	result, err := auditor.AuditExternalSharing(context.Background())
+	require.NoError(t, err)
+	require.Len(t, result.ExternalShares, 1)
+	assert.Zero(t, result.ExternalShares[0].GroupMemberCount)
+	assert.False(t, result.ExternalShares[0].HasExternalMembers)
+}
+
+func TestAuditExternalSharing_SkipsGroupsOutsideDomain(t *testing.T) {
+	mockClient := new(MockDriveClient)
+
+	files := []drive.FileInfo{{ID: "file1", Name: "a.txt", OwnerEmail: "owner@example.com"}}
+	external := drive.Permission{ID: "p1", Type: "group", Role: "reader", EmailAddress: "team@partner.com"}
+	internal := drive.Permission{ID: "p2", Type: "group", Role: "reader", EmailAddress: "staff@example.org"}
+
+	mockClient.On("ListAllFiles", mock.Anything).Return(files, nil)
+	mockClient.On("GetFilePermissions", mock.Anything, "file1").Return([]drive.Permission{external, internal}, nil)
+	mockClient.On("IsExternalShare", mock.Anything).Return(true)
+
+	resolver := &fakeGroupResolver{
+		members: map[string][]string{"staff@example.org": {"bob@partner.com"}},
+	}
+
+	cfg := &config.Config{}
+	cfg.Google.Domain = "example.com"
+	cfg.Google.DomainAliases = []string{"Example.org"}
+	auditor := NewAuditorWithClient(cfg, mockClient)
+	auditor.SetGroupResolver(resolver)
+
+	result, err := auditor.AuditExternalSharing(context.Background())
+	require.NoError(t, err)
+	require.Len(t, result.ExternalShares, 2)
+	assert.Empty(t, result.Errors)
+	assert.Equal(t, 1, result.UnexpandedGroups)
+	assert.Zero(t, resolver.calls["team@partner.com"])
+	assert.Equal(t, 1, resolver.calls["staff@example.org"])
+}
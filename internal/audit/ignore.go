@@ -0,0 +1,36 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import "github.com/leansecurity-co/gwork/internal/drive"
+
+// SetIgnoreFileIDs sets files to leave out of audits, such as templates that
+// are public on purpose. Ignored files are dropped right after listing, so
+// their permissions are never fetched, and counted in SuppressedCount.
+func (a *Auditor) SetIgnoreFileIDs(ids ...string) {
+	if len(ids) == 0 {
+		a.ignoreFileIDs = nil
+		return
+	}
+	a.ignoreFileIDs = make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		a.ignoreFileIDs[id] = struct{}{}
+	}
+}
+
+// FilterIgnoredFiles returns the files whose ID is not in ignored, in their
+// original order, and the number of files dropped.
+func FilterIgnoredFiles(files []drive.FileInfo, ignored map[string]struct{}) ([]drive.FileInfo, int) {
+	if len(ignored) == 0 {
+		return files, 0
+	}
+
+	kept := make([]drive.FileInfo, 0, len(files))
+	for _, f := range files {
+		if _, ok := ignored[f.ID]; !ok {
+			kept = append(kept, f)
+		}
+	}
+	return kept, len(files) - len(kept)
+}
@@ -0,0 +1,82 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/config"
+	"github.com/leansecurity-co/gwork/internal/drive"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterIgnoredFiles(t *testing.T) {
+	files := []drive.FileInfo{{ID: "a"}, {ID: "b"}, {ID: "c"}, {ID: "b"}}
+
+	tests := []struct {
+		name           string
+		ignored        map[string]struct{}
+		wantIDs        []string
+		wantSuppressed int
+	}{
+		{name: "nil ignore list", ignored: nil, wantIDs: []string{"a", "b", "c", "b"}},
+		{name: "no match", ignored: map[string]struct{}{"z": {}}, wantIDs: []string{"a", "b", "c", "b"}},
+		{name: "single match", ignored: map[string]struct{}{"a": {}}, wantIDs: []string{"b", "c", "b"}, wantSuppressed: 1},
+		{name: "duplicate listing", ignored: map[string]struct{}{"b": {}}, wantIDs: []string{"a", "c"}, wantSuppressed: 2},
+		{name: "IDs are case-sensitive", ignored: map[string]struct{}{"A": {}}, wantIDs: []string{"a", "b", "c", "b"}},
+		{name: "all ignored", ignored: map[string]struct{}{"a": {}, "b": {}, "c": {}}, wantIDs: []string{}, wantSuppressed: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kept, suppressed := FilterIgnoredFiles(files, tt.ignored)
+
+			ids := make([]string, 0, len(kept))
+			for _, f := range kept {
+				ids = append(ids, f.ID)
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+			assert.Equal(t, tt.wantSuppressed, suppressed)
+		})
+	}
+}
+
+func TestAuditor_IgnoreFileIDs(t *testing.T) {
+	files := []drive.FileInfo{
+		{ID: "template", Name: "Public template", OwnerEmail: "alice@example.com"},
+		{ID: "report", Name: "Report", OwnerEmail: "bob@example.com"},
+	}
+
+	mockClient := new(MockDriveClient)
+	mockClient.On("ListAllFiles", mock.Anything).Return(files, nil)
+	mockClient.On("GetFilePermissions", mock.Anything, "report").
+		Return([]drive.Permission{{Type: "anyone", Role: "reader"}}, nil)
+	mockClient.On("IsExternalShare", mock.Anything).Return(true)
+
+	auditor := NewAuditorWithClient(&config.Config{}, mockClient)
+	auditor.SetIgnoreFileIDs("template")
+
+	sharing, err := auditor.AuditExternalSharing(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, sharing.SuppressedCount)
+	assert.Equal(t, 1, sharing.TotalFiles)
+	require.Len(t, sharing.ExternalShares, 1)
+	assert.Equal(t, "report", sharing.ExternalShares[0].FileID)
+	mockClient.AssertNotCalled(t, "GetFilePermissions", mock.Anything, "template")
+
+	files2, err := auditor.AuditFiles(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, files2.SuppressedCount)
+	require.Len(t, files2.FileRecords, 1)
+	assert.Equal(t, "report", files2.FileRecords[0].FileID)
+
+	auditor.SetIgnoreFileIDs()
+	files2, err = auditor.AuditFiles(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, files2.SuppressedCount)
+	assert.Len(t, files2.FileRecords, 2)
+}
@@ -17,3 +17,68 @@ type DriveClient interface {
 	IsExternalShare(perm drive.Permission) bool
 	Domain() string
 }
+
+// StatsProvider is implemented by drive clients that count their API calls.
+// The drive.Client implements this interface; timing is reported without
+// API stats for clients that do not.
+type StatsProvider interface {
+	Stats() drive.Stats
+}
+
+// FileVisitor visits listed files a page at a time, so a sampled audit only
+// holds its sample in memory. The drive.Client implements this interface.
+type FileVisitor interface {
+	// VisitAllFiles calls visit for each file ListAllFiles would return,
+	// in the same order.
+	VisitAllFiles(ctx context.Context, visit func(drive.FileInfo)) error
+}
+
+// OwnerFileLister lists files one owner at a time, so a files audit only
+// holds a single owner's files in memory. The drive.Client implements this
+// interface.
+type OwnerFileLister interface {
+	// ListOwners returns the distinct owner emails, sorted.
+	ListOwners(ctx context.Context) ([]string, error)
+	// ListOwnerFiles returns the files owned by owner.
+	ListOwnerFiles(ctx context.Context, owner string) ([]drive.FileInfo, error)
+}
+
+// RetryClassifier decides which API errors are worth retrying. The
+// drive.Client implements this interface with audit.retry_status_codes.
+type RetryClassifier interface {
+	IsRetryable(err error) bool
+}
+
+// SharedWithMeLister lists the files shared with the authenticated user.
+// The drive.Client implements this interface.
+type SharedWithMeLister interface {
+	ListSharedWithMe(ctx context.Context) ([]drive.FileInfo, error)
+}
+
+// DriveNamer resolves shared drive IDs to their names. The drive.Client
+// implements this interface.
+type DriveNamer interface {
+	DriveName(ctx context.Context, driveID string) (string, error)
+}
+
+// FileGetter fetches a single file by ID. The drive.Client implements this
+// interface.
+type FileGetter interface {
+	GetFile(ctx context.Context, fileID string) (drive.FileInfo, error)
+}
+
+// ShareClassifier assigns an org-specific label and risk score to each
+// share found by the sharing audits. DefaultClassifier implements this
+// interface.
+type ShareClassifier interface {
+	// Classify returns the label and risk score for a share. Higher risk
+	// scores are more severe.
+	Classify(rec ExternalShareRecord) (label string, risk int)
+}
+
+// GroupResolver resolves the members of a Google group.
+// The directory.GroupResolver implements this interface.
+type GroupResolver interface {
+	// GroupMembers returns the email addresses of the members of a group.
+	GroupMembers(ctx context.Context, groupEmail string) ([]string, error)
+}
@@ -0,0 +1,38 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import "context"
+
+// LocationMyDrive is the location of files in a user's My Drive.
+const LocationMyDrive = "my_drive"
+
+// LocationSharedDrivePrefix prefixes the shared drive ID in the location of
+// files that live in a shared drive.
+const LocationSharedDrivePrefix = "shared_drive:"
+
+// Location returns where a file lives: LocationMyDrive when driveID is
+// empty, otherwise "shared_drive:<id>".
+func Location(driveID string) string {
+	if driveID == "" {
+		return LocationMyDrive
+	}
+	return LocationSharedDrivePrefix + driveID
+}
+
+// driveName returns the name of the shared drive driveID, or "" for files
+// in a My Drive, for drive clients that cannot look drives up and for
+// drives the admin cannot see. The drive.Client caches lookups, so this is
+// one API call per shared drive.
+func (a *Auditor) driveName(ctx context.Context, driveID string) string {
+	namer, ok := a.driveClient.(DriveNamer)
+	if driveID == "" || !ok {
+		return ""
+	}
+	name, err := namer.DriveName(ctx, driveID)
+	if err != nil {
+		return ""
+	}
+	return name
+}
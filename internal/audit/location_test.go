@@ -0,0 +1,105 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/config"
+	"github.com/leansecurity-co/gwork/internal/drive"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v3 "google.golang.org/api/drive/v3"
+)
+
+func TestLocation(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     drive.FileInfo
+		expected string
+	}{
+		{name: "my drive file", file: drive.FileInfo{ID: "1"}, expected: "my_drive"},
+		{name: "shared drive file", file: drive.FileInfo{ID: "2", DriveID: "0AbCd"}, expected: "shared_drive:0AbCd"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Location(tt.file.DriveID))
+			assert.Equal(t, tt.expected, fileInfoToRecord(tt.file).Location)
+			assert.Equal(t, tt.expected, permissionToRecord(tt.file, drive.Permission{Type: "anyone"}).Location)
+		})
+	}
+}
+
+// sharedDriveAPI is a DriveAPI whose files live in shared drives, each
+// shared with anyone, and which counts the drive lookups it serves.
+type sharedDriveAPI struct {
+	files       []*v3.File
+	names       map[string]string
+	driveLookup atomic.Int64
+}
+
+func (s *sharedDriveAPI) ListFiles(_ context.Context, _ *drive.ListFilesOptions) (*drive.ListFilesResult, error) {
+	return &drive.ListFilesResult{Files: s.files}, nil
+}
+
+func (s *sharedDriveAPI) ListPermissions(_ context.Context, _ string, _ *drive.ListPermissionsOptions) (*drive.ListPermissionsResult, error) {
+	return &drive.ListPermissionsResult{Permissions: []*v3.Permission{{Id: "anyoneWithLink", Type: "anyone", Role: "reader"}}}, nil
+}
+
+func (s *sharedDriveAPI) GetFile(_ context.Context, fileID string, _ *drive.GetFileOptions) (*v3.File, error) {
+	for _, f := range s.files {
+		if f.Id == fileID {
+			return f, nil
+		}
+	}
+	return nil, fmt.Errorf("file not found: %s", fileID)
+}
+
+func (s *sharedDriveAPI) GetDrive(_ context.Context, driveID string) (*v3.Drive, error) {
+	s.driveLookup.Add(1)
+	name, ok := s.names[driveID]
+	if !ok {
+		return nil, fmt.Errorf("shared drive not found: %s", driveID)
+	}
+	return &v3.Drive{Id: driveID, Name: name}, nil
+}
+
+func TestAuditor_DriveNames(t *testing.T) {
+	api := &sharedDriveAPI{
+		files: []*v3.File{
+			{Id: "f1", Name: "budget.xlsx", DriveId: "0AFin"},
+			{Id: "f2", Name: "forecast.xlsx", DriveId: "0AFin"},
+			{Id: "f3", Name: "contract.pdf", DriveId: "0ALegal"},
+			{Id: "f4", Name: "secret.pdf", DriveId: "0AHidden"},
+			{Id: "f5", Name: "notes.txt", Owners: []*v3.User{{EmailAddress: "alice@example.com"}}},
+		},
+		names: map[string]string{"0AFin": "Finance", "0ALegal": "Legal"},
+	}
+	cfg := &config.Config{Audit: config.AuditConfig{Concurrency: 4}}
+	auditor := NewAuditorWithClient(cfg, drive.NewClientWithAPI(api, "example.com", 100, true))
+	want := map[string]string{"f1": "Finance", "f2": "Finance", "f3": "Legal", "f4": "", "f5": ""}
+
+	files, err := auditor.AuditFiles(context.Background())
+	require.NoError(t, err)
+	got := make(map[string]string)
+	for _, rec := range files.FileRecords {
+		got[rec.FileID] = rec.DriveName
+	}
+	assert.Equal(t, want, got)
+	assert.Empty(t, files.Errors, "drives the admin cannot see leave the name empty")
+
+	shares, err := auditor.AuditExternalSharing(context.Background())
+	require.NoError(t, err)
+	got = make(map[string]string)
+	for _, rec := range shares.ExternalShares {
+		got[rec.FileID] = rec.DriveName
+	}
+	assert.Equal(t, want, got)
+
+	assert.Equal(t, int64(3), api.driveLookup.Load(), "each shared drive is looked up once across audits")
+}
@@ -0,0 +1,170 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/leansecurity-co/gwork/internal/config"
+)
+
+// DomainError is the failure of one domain's audit in a multi-domain run.
+type DomainError struct {
+	Domain string
+	Err    error
+}
+
+// Error implements the error interface.
+func (e DomainError) Error() string {
+	return e.Domain + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e DomainError) Unwrap() error {
+	return e.Err
+}
+
+// MarshalJSON encodes the error as {"domain": ..., "error": ...}.
+func (e DomainError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Domain string `json:"domain"`
+		Error  string `json:"error"`
+	}{e.Domain, e.Err.Error()})
+}
+
+// RunMultiDomainAudit runs RunAudit for each of cfg.Google.Domains, at most
+// audit.domain_concurrency at a time, each with its own drive client
+// impersonating that domain's admin. Records are tagged with their
+// SourceDomain and merged into one report whose Domain lists the domains.
+//
+// A domain whose audit fails does not stop the others: it is recorded in
+// DomainErrors and its records are left out. An error is returned only when
+// every domain fails.
+func RunMultiDomainAudit(ctx context.Context, cfg *config.Config, opts RunOptions) (*AuditReport, error) {
+	domains := cfg.Google.Domains
+	if len(domains) == 0 {
+		return nil, errors.New("google.domains is empty")
+	}
+
+	limit := max(cfg.Audit.DomainConcurrency, 1)
+	sem := make(chan struct{}, limit)
+	reports := make([]*AuditReport, len(domains))
+	errs := make([]error, len(domains))
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i, d := range domains {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			reports[i], errs[i] = runDomainAudit(ctx, cfg.ForDomain(d), opts)
+		}()
+	}
+	wg.Wait()
+
+	var names []string
+	var filesResults, sharingResults []*AuditResult
+	var domainErrors []DomainError
+	for i, d := range domains {
+		names = append(names, d.Domain)
+		if errs[i] != nil {
+			domainErrors = append(domainErrors, DomainError{Domain: d.Domain, Err: errs[i]})
+			continue
+		}
+		filesResults = append(filesResults, reports[i].FilesResult)
+		sharingResults = append(sharingResults, reports[i].SharingResult)
+	}
+
+	if len(domainErrors) == len(domains) {
+		failures := make([]error, len(domainErrors))
+		for i, err := range domainErrors {
+			failures[i] = err
+		}
+		return nil, fmt.Errorf("every domain failed: %w", errors.Join(failures...))
+	}
+
+	elapsed := time.Since(start)
+	report := NewAuditReport(strings.Join(names, ","), start.UTC(),
+		mergeResults(filesResults, elapsed), mergeResults(sharingResults, elapsed))
+	report.DomainErrors = domainErrors
+	return report, nil
+}
+
+// runDomainAudit runs the audit of a single domain of a multi-domain run
+// and tags its records with the domain.
+func runDomainAudit(ctx context.Context, cfg *config.Config, opts RunOptions) (*AuditReport, error) {
+	opts.Client = nil
+	if opts.ClientFor != nil {
+		client, err := opts.ClientFor(cfg.Google.Domain)
+		if err != nil {
+			return nil, err
+		}
+		opts.Client = client
+	}
+
+	report, err := RunAudit(ctx, cfg, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, result := range []*AuditResult{report.FilesResult, report.SharingResult} {
+		for i := range result.FileRecords {
+			result.FileRecords[i].SourceDomain = cfg.Google.Domain
+		}
+		for i := range result.ExternalShares {
+			result.ExternalShares[i].SourceDomain = cfg.Google.Domain
+		}
+		for i, err := range result.Errors {
+			result.Errors[i] = fmt.Errorf("%s: %w", cfg.Google.Domain, err)
+		}
+	}
+	return report, nil
+}
+
+// mergeResults combines the results of the same audit run on several
+// domains. Timing.Total is set to elapsed, the wall time of the whole run;
+// the other timings and API stats are summed.
+func mergeResults(results []*AuditResult, elapsed time.Duration) *AuditResult {
+	merged := &AuditResult{}
+	for _, r := range results {
+		merged.TotalFiles += r.TotalFiles
+		merged.SampledFiles += r.SampledFiles
+		merged.SuppressedCount += r.SuppressedCount
+		merged.TotalExternalShares += r.TotalExternalShares
+		merged.FilesProcessed += r.FilesProcessed
+		merged.Errors = append(merged.Errors, r.Errors...)
+		merged.DroppedErrorCount += r.DroppedErrorCount
+		merged.BudgetExceeded = merged.BudgetExceeded || r.BudgetExceeded
+		merged.OwnersMissing += r.OwnersMissing
+		merged.UnexpandedGroups += r.UnexpandedGroups
+		for _, t := range r.UnknownPermissionTypes {
+			merged.UnknownPermissionTypes = addUnknownType(merged.UnknownPermissionTypes, t)
+		}
+		merged.FileRecords = append(merged.FileRecords, r.FileRecords...)
+		merged.ExternalShares = append(merged.ExternalShares, r.ExternalShares...)
+		merged.Filtered.Merge(r.Filtered)
+		merged.Timing.ListFiles += r.Timing.ListFiles
+		merged.Timing.FetchPermissions += r.Timing.FetchPermissions
+		merged.Timing.API = merged.Timing.API.Add(r.Timing.API)
+		merged.CachedFiles += r.CachedFiles
+
+		if r.FileSnapshots != nil {
+			if merged.FileSnapshots == nil {
+				merged.FileSnapshots = make(map[string]FileSnapshot)
+			}
+			maps.Copy(merged.FileSnapshots, r.FileSnapshots)
+		}
+	}
+	merged.Timing.Total = elapsed
+	return merged
+}
@@ -0,0 +1,155 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/leansecurity-co/gwork/internal/config"
+	"github.com/leansecurity-co/gwork/internal/drive"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newDomainClient returns a fake drive client for a domain holding one
+// file shared with anyone.
+func newDomainClient(fileID, owner string) *MockDriveClient {
+	client := new(MockDriveClient)
+	client.On("ListAllFiles", mock.Anything).Return([]drive.FileInfo{
+		{ID: fileID, Name: fileID, OwnerEmail: owner, Size: 10},
+	}, nil)
+	client.On("GetFilePermissions", mock.Anything, fileID).Return([]drive.Permission{
+		{ID: "anyoneWithLink", Type: "anyone", Role: "reader"},
+	}, nil)
+	client.On("IsExternalShare", mock.Anything).Return(true)
+	return client
+}
+
+func multiDomainConfig(domains ...string) *config.Config {
+	cfg := &config.Config{}
+	for _, d := range domains {
+		cfg.Google.Domains = append(cfg.Google.Domains, config.DomainConfig{Domain: d, AdminEmail: "admin@" + d})
+	}
+	return cfg
+}
+
+func TestRunMultiDomainAudit_MergesTaggedRecords(t *testing.T) {
+	clients := map[string]DriveClient{
+		"a.example": newDomainClient("fileA", "alice@a.example"),
+		"b.example": newDomainClient("fileB", "bob@b.example"),
+	}
+	cfg := multiDomainConfig("a.example", "b.example")
+
+	report, err := RunMultiDomainAudit(context.Background(), cfg, RunOptions{
+		ClientFor: func(domain string) (DriveClient, error) { return clients[domain], nil },
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "a.example,b.example", report.Domain)
+	assert.Empty(t, report.DomainErrors)
+	assert.Equal(t, Totals{Files: 2, ExternalShares: 2, PublicShares: 2, Bytes: 20}, report.Summary)
+
+	require.Len(t, report.Files, 2)
+	assert.Equal(t, "a.example", report.Files[0].SourceDomain)
+	assert.Equal(t, "fileA", report.Files[0].FileID)
+	assert.Equal(t, "b.example", report.Files[1].SourceDomain)
+
+	require.Len(t, report.ExternalShares, 2)
+	for _, rec := range report.ExternalShares {
+		want := map[string]string{"fileA": "a.example", "fileB": "b.example"}[rec.FileID]
+		assert.Equal(t, want, rec.SourceDomain, rec.FileID)
+	}
+}
+
+func TestRunMultiDomainAudit_DomainErrorsAreIndependent(t *testing.T) {
+	failing := new(MockDriveClient)
+	failing.On("ListAllFiles", mock.Anything).Return([]drive.FileInfo(nil), errors.New("delegation denied"))
+
+	tests := []struct {
+		name      string
+		clientFor func(domain string) (DriveClient, error)
+	}{
+		{
+			name: "audit fails",
+			clientFor: func(domain string) (DriveClient, error) {
+				if domain == "b.example" {
+					return failing, nil
+				}
+				return newDomainClient("fileA", "alice@a.example"), nil
+			},
+		},
+		{
+			name: "client cannot be created",
+			clientFor: func(domain string) (DriveClient, error) {
+				if domain == "b.example" {
+					return nil, errors.New("delegation denied")
+				}
+				return newDomainClient("fileA", "alice@a.example"), nil
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := multiDomainConfig("a.example", "b.example")
+			report, err := RunMultiDomainAudit(context.Background(), cfg, RunOptions{ClientFor: tt.clientFor})
+			require.NoError(t, err)
+
+			require.Len(t, report.DomainErrors, 1)
+			assert.Equal(t, "b.example", report.DomainErrors[0].Domain)
+			assert.ErrorContains(t, report.DomainErrors[0], "delegation denied")
+
+			require.Len(t, report.Files, 1, "the other domain is still audited")
+			assert.Equal(t, "a.example", report.Files[0].SourceDomain)
+			require.Len(t, report.ExternalShares, 1)
+
+			data, err := json.Marshal(report)
+			require.NoError(t, err)
+			assert.Contains(t, string(data), `"domain_errors":[{"domain":"b.example","error":`)
+		})
+	}
+}
+
+func TestRunMultiDomainAudit_AllDomainsFail(t *testing.T) {
+	cfg := multiDomainConfig("a.example", "b.example")
+	_, err := RunMultiDomainAudit(context.Background(), cfg, RunOptions{
+		ClientFor: func(domain string) (DriveClient, error) { return nil, errors.New("no access to " + domain) },
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no access to a.example")
+	assert.Contains(t, err.Error(), "no access to b.example")
+}
+
+func TestRunMultiDomainAudit_DomainConcurrency(t *testing.T) {
+	domains := []string{"a.example", "b.example", "c.example", "d.example"}
+	cfg := multiDomainConfig(domains...)
+	cfg.Audit.DomainConcurrency = 2
+
+	var running, peak atomic.Int32
+	var mu sync.Mutex
+	clientFor := func(domain string) (DriveClient, error) {
+		client := new(MockDriveClient)
+		client.On("ListAllFiles", mock.Anything).Run(func(mock.Arguments) {
+			n := running.Add(1)
+			mu.Lock()
+			peak.Store(max(peak.Load(), n))
+			mu.Unlock()
+			time.Sleep(20 * time.Millisecond)
+			running.Add(-1)
+		}).Return([]drive.FileInfo{}, nil)
+		return client, nil
+	}
+
+	report, err := RunMultiDomainAudit(context.Background(), cfg, RunOptions{ClientFor: clientFor})
+	require.NoError(t, err)
+	assert.Empty(t, report.DomainErrors)
+	assert.Equal(t, int32(2), peak.Load(), "at most audit.domain_concurrency domains run at once")
+}
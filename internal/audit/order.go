@@ -0,0 +1,126 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ownerComparators are the owner orders of output.sort_owners_by.
+var ownerComparators = map[string]func(a, b OwnerSummary) int{
+	"email": func(a, b OwnerSummary) int { return cmp.Compare(a.OwnerKey(), b.OwnerKey()) },
+	"bytes": func(a, b OwnerSummary) int { return cmp.Compare(b.TotalBytes, a.TotalBytes) },
+	"count": func(a, b OwnerSummary) int { return cmp.Compare(b.FileCount, a.FileCount) },
+}
+
+// fileComparators are the file orders of output.sort_files_by.
+var fileComparators = map[string]func(a, b FileRecord) int{
+	"name":     func(a, b FileRecord) int { return cmp.Compare(a.FileName, b.FileName) },
+	"size":     func(a, b FileRecord) int { return cmp.Compare(b.SizeBytes, a.SizeBytes) },
+	"modified": func(a, b FileRecord) int { return b.ModifiedTime.Compare(a.ModifiedTime) },
+}
+
+// RecordOrder orders file records and owner summaries. Owners compares two
+// owners by their summaries and Files compares two files of the same
+// owner; nil compares owners by OwnerKey and files by name. Ties fall back
+// to OwnerKey, then file name and ID, so each owner's records stay
+// together and the order is deterministic. The zero value is the order of
+// SortFileRecords.
+type RecordOrder struct {
+	Owners func(a, b OwnerSummary) int
+	Files  func(a, b FileRecord) int
+}
+
+// NewRecordOrder returns the order selected by output.sort_owners_by and
+// output.sort_files_by: owners by email, bytes or count, the largest
+// first, and files by name, size (largest first) or modified (newest
+// first). Empty values keep the default.
+func NewRecordOrder(owners, files string) (RecordOrder, error) {
+	var order RecordOrder
+	if owners != "" {
+		cmpOwners, ok := ownerComparators[owners]
+		if !ok {
+			return RecordOrder{}, fmt.Errorf("unknown owner order %q (must be one of: %s)", owners, orderNames(ownerComparators))
+		}
+		order.Owners = cmpOwners
+	}
+	if files != "" {
+		cmpFiles, ok := fileComparators[files]
+		if !ok {
+			return RecordOrder{}, fmt.Errorf("unknown file order %q (must be one of: %s)", files, orderNames(fileComparators))
+		}
+		order.Files = cmpFiles
+	}
+	return order, nil
+}
+
+// orderNames returns the sorted keys of comparators, comma separated.
+func orderNames[F any](comparators map[string]F) string {
+	names := make([]string, 0, len(comparators))
+	for name := range comparators {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return strings.Join(names, ", ")
+}
+
+// SortFileRecords sorts records by owner, then by file within each owner.
+// The owners are compared by the summaries of their records in records.
+func (o RecordOrder) SortFileRecords(records []FileRecord) {
+	var owners map[string]OwnerSummary
+	if o.Owners != nil {
+		owners = make(map[string]OwnerSummary)
+		for _, summary := range summarize(records) {
+			owners[summary.OwnerKey()] = summary
+		}
+	}
+
+	slices.SortStableFunc(records, func(a, b FileRecord) int {
+		if keyA, keyB := a.OwnerKey(), b.OwnerKey(); keyA != keyB {
+			if o.Owners != nil {
+				if c := o.Owners(owners[keyA], owners[keyB]); c != 0 {
+					return c
+				}
+			}
+			return cmp.Compare(keyA, keyB)
+		}
+		if o.Files != nil {
+			if c := o.Files(a, b); c != 0 {
+				return c
+			}
+		}
+		return cmp.Or(cmp.Compare(a.FileName, b.FileName), cmp.Compare(a.FileID, b.FileID))
+	})
+}
+
+// SortOwnerSummaries sorts summaries by Owners, then OwnerKey. Without
+// Owners, summaries keep their order, by file count for SummarizeByOwner.
+func (o RecordOrder) SortOwnerSummaries(summaries []OwnerSummary) {
+	if o.Owners == nil {
+		return
+	}
+	slices.SortStableFunc(summaries, func(a, b OwnerSummary) int {
+		return cmp.Or(o.Owners(a, b), cmp.Compare(a.OwnerKey(), b.OwnerKey()))
+	})
+}
+
+// GroupByOwner splits file records into one group per distinct owner,
+// with owners and the records of each group sorted by o.
+func (o RecordOrder) GroupByOwner(records []FileRecord) [][]FileRecord {
+	sorted := make([]FileRecord, len(records))
+	copy(sorted, records)
+	o.SortFileRecords(sorted)
+
+	var groups [][]FileRecord
+	for i, rec := range sorted {
+		if i == 0 || rec.OwnerKey() != sorted[i-1].OwnerKey() {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], rec)
+	}
+	return groups
+}
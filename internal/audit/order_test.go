@@ -0,0 +1,137 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/leansecurity-co/gwork/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// orderTestRecords returns files of three owners: alice has two small
+// files, bob one large file and carol three tiny ones.
+func orderTestRecords() []FileRecord {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+	return []FileRecord{
+		{OwnerEmail: "carol@example.com", FileID: "c1", FileName: "b.txt", SizeBytes: 1, ModifiedTime: day(3)},
+		{OwnerEmail: "bob@example.com", FileID: "b1", FileName: "big.iso", SizeBytes: 5000, ModifiedTime: day(1)},
+		{OwnerEmail: "alice@example.com", FileID: "a1", FileName: "notes.txt", SizeBytes: 100, ModifiedTime: day(2)},
+		{OwnerEmail: "carol@example.com", FileID: "c2", FileName: "a.txt", SizeBytes: 3, ModifiedTime: day(1)},
+		{OwnerEmail: "alice@example.com", FileID: "a2", FileName: "budget.xlsx", SizeBytes: 300, ModifiedTime: day(5)},
+		{OwnerEmail: "carol@example.com", FileID: "c3", FileName: "c.txt", SizeBytes: 2},
+	}
+}
+
+func TestRecordOrder_SortFileRecords(t *testing.T) {
+	tests := []struct {
+		name   string
+		owners string
+		files  string
+		want   []string
+	}{
+		{name: "default", want: []string{"a2", "a1", "b1", "c2", "c1", "c3"}},
+		{name: "owners by email", owners: "email", want: []string{"a2", "a1", "b1", "c2", "c1", "c3"}},
+		{name: "owners by bytes", owners: "bytes", want: []string{"b1", "a2", "a1", "c2", "c1", "c3"}},
+		{name: "owners by count", owners: "count", want: []string{"c2", "c1", "c3", "a2", "a1", "b1"}},
+		{name: "files by name", files: "name", want: []string{"a2", "a1", "b1", "c2", "c1", "c3"}},
+		{name: "files by size", files: "size", want: []string{"a2", "a1", "b1", "c2", "c3", "c1"}},
+		{name: "files by modified", files: "modified", want: []string{"a2", "a1", "b1", "c1", "c2", "c3"}},
+		{name: "owners by bytes, files by size", owners: "bytes", files: "size", want: []string{"b1", "a2", "a1", "c2", "c3", "c1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, err := NewRecordOrder(tt.owners, tt.files)
+			require.NoError(t, err)
+
+			records := orderTestRecords()
+			order.SortFileRecords(records)
+
+			got := make([]string, len(records))
+			for i, rec := range records {
+				got[i] = rec.FileID
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRecordOrder_OwnerTiesByEmail(t *testing.T) {
+	order, err := NewRecordOrder("count", "")
+	require.NoError(t, err)
+
+	records := []FileRecord{
+		{OwnerEmail: "bob@example.com", FileID: "b1"},
+		{OwnerEmail: "alice@example.com", FileID: "a1"},
+		{OwnerEmail: "bob@example.com", FileID: "b2"},
+		{OwnerEmail: "alice@example.com", FileID: "a2"},
+	}
+	order.SortFileRecords(records)
+
+	var owners []string
+	for _, rec := range records {
+		owners = append(owners, rec.OwnerEmail)
+	}
+	assert.Equal(t, []string{"alice@example.com", "alice@example.com", "bob@example.com", "bob@example.com"}, owners,
+		"owners with equal counts stay together, in email order")
+}
+
+func TestRecordOrder_SortOwnerSummaries(t *testing.T) {
+	tests := []struct {
+		owners string
+		want   []string
+	}{
+		{owners: "", want: []string{"carol@example.com", "alice@example.com", "bob@example.com"}},
+		{owners: "email", want: []string{"alice@example.com", "bob@example.com", "carol@example.com"}},
+		{owners: "bytes", want: []string{"bob@example.com", "alice@example.com", "carol@example.com"}},
+		{owners: "count", want: []string{"carol@example.com", "alice@example.com", "bob@example.com"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.owners, func(t *testing.T) {
+			order, err := NewRecordOrder(tt.owners, "")
+			require.NoError(t, err)
+
+			summaries := SummarizeByOwner(orderTestRecords())
+			order.SortOwnerSummaries(summaries)
+
+			got := make([]string, len(summaries))
+			for i, s := range summaries {
+				got[i] = s.OwnerEmail
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRecordOrder_GroupByOwner(t *testing.T) {
+	order, err := NewRecordOrder("bytes", "size")
+	require.NoError(t, err)
+
+	groups := order.GroupByOwner(orderTestRecords())
+	require.Len(t, groups, 3)
+	assert.Equal(t, "bob@example.com", groups[0][0].OwnerEmail)
+	assert.Equal(t, "alice@example.com", groups[1][0].OwnerEmail)
+	assert.Equal(t, []string{"c2", "c3", "c1"}, []string{groups[2][0].FileID, groups[2][1].FileID, groups[2][2].FileID})
+}
+
+func TestNewRecordOrder_Unknown(t *testing.T) {
+	_, err := NewRecordOrder("risk", "")
+	assert.EqualError(t, err, `unknown owner order "risk" (must be one of: bytes, count, email)`)
+
+	_, err = NewRecordOrder("", "created")
+	assert.EqualError(t, err, `unknown file order "created" (must be one of: modified, name, size)`)
+}
+
+func TestComparatorsMatchValidOrders(t *testing.T) {
+	assert.ElementsMatch(t, config.ValidOwnerOrders, slices.Collect(maps.Keys(ownerComparators)),
+		"config.ValidOwnerOrders must list every owner comparator")
+	assert.ElementsMatch(t, config.ValidFileOrders, slices.Collect(maps.Keys(fileComparators)),
+		"config.ValidFileOrders must list every file comparator")
+}
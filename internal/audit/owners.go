@@ -0,0 +1,61 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import "sort"
+
+// OwnerSummary aggregates the files owned by a single owner.
+type OwnerSummary struct {
+	OwnerEmail string `json:"owner_email"`
+	OwnerName  string `json:"owner_name,omitempty"`
+	FileCount  int    `json:"file_count"`
+	TotalBytes int64  `json:"total_bytes"`
+}
+
+// OwnerKey returns the key used to group owners, see FileRecord.OwnerKey.
+func (s OwnerSummary) OwnerKey() string {
+	return ownerKey(s.OwnerEmail, s.OwnerName)
+}
+
+// GroupByOwner splits file records into one group per distinct owner, in
+// OwnerKey order. Records within a group are sorted by file name and ID.
+func GroupByOwner(records []FileRecord) [][]FileRecord {
+	return RecordOrder{}.GroupByOwner(records)
+}
+
+// SummarizeByOwner aggregates file records into one summary per distinct
+// owner, sorted by file count descending, then by owner.
+func SummarizeByOwner(records []FileRecord) []OwnerSummary {
+	summaries := summarize(records)
+	sort.SliceStable(summaries, func(i, j int) bool {
+		if summaries[i].FileCount != summaries[j].FileCount {
+			return summaries[i].FileCount > summaries[j].FileCount
+		}
+		return summaries[i].OwnerKey() < summaries[j].OwnerKey()
+	})
+	return summaries
+}
+
+// summarize aggregates file records into one summary per distinct owner,
+// in the order owners first appear.
+func summarize(records []FileRecord) []OwnerSummary {
+	index := make(map[string]int)
+	summaries := make([]OwnerSummary, 0)
+
+	for _, rec := range records {
+		key := rec.OwnerKey()
+		i, ok := index[key]
+		if !ok {
+			i = len(summaries)
+			index[key] = i
+			summaries = append(summaries, OwnerSummary{
+				OwnerEmail: rec.OwnerEmail,
+				OwnerName:  rec.OwnerName,
+			})
+		}
+		summaries[i].FileCount++
+		summaries[i].TotalBytes += rec.SizeBytes
+	}
+	return summaries
+}
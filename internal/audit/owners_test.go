@@ -0,0 +1,97 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/config"
+	"github.com/leansecurity-co/gwork/internal/drive"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarizeByOwner_ThroughAudit(t *testing.T) {
+	mockClient := new(MockDriveClient)
+
+	files := []drive.FileInfo{
+		{ID: "1", Name: "a.txt", OwnerEmail: "bob@example.com", Size: 100},
+		{ID: "2", Name: "b.txt", OwnerEmail: "alice@example.com", Size: 10},
+		{ID: "3", Name: "c.txt", OwnerEmail: "bob@example.com", Size: 250},
+		{ID: "4", Name: "d.txt", OwnerEmail: "carol@example.com", Size: 5},
+		{ID: "5", Name: "e.txt", OwnerEmail: "bob@example.com"},
+		{ID: "6", Name: "f.txt", OwnerEmail: "alice@example.com", Size: 40},
+		{ID: "7", Name: "g.txt", OwnerName: "Former Employee", Size: 7},
+	}
+	mockClient.On("ListAllFiles", mock.Anything).Return(files, nil)
+
+	auditor := NewAuditorWithClient(&config.Config{}, mockClient)
+	result, err := auditor.AuditFiles(context.Background())
+	require.NoError(t, err)
+
+	summaries := SummarizeByOwner(result.FileRecords)
+
+	assert.Equal(t, []OwnerSummary{
+		{OwnerEmail: "bob@example.com", FileCount: 3, TotalBytes: 350},
+		{OwnerEmail: "alice@example.com", FileCount: 2, TotalBytes: 50},
+		{OwnerEmail: "carol@example.com", FileCount: 1, TotalBytes: 5},
+		{OwnerName: "Former Employee", FileCount: 1, TotalBytes: 7},
+	}, summaries)
+
+	// Owners are enumerated without any permission calls.
+	mockClient.AssertNotCalled(t, "GetFilePermissions", mock.Anything, mock.Anything)
+}
+
+func TestSummarizeByOwner_Empty(t *testing.T) {
+	assert.Empty(t, SummarizeByOwner(nil))
+}
+
+func TestSummarizeByCategory(t *testing.T) {
+	records := []FileRecord{
+		{FileType: "application/vnd.google-apps.document", SizeBytes: 0},
+		{FileType: "application/pdf", SizeBytes: 300},
+		{FileType: "application/msword", SizeBytes: 20},
+		{FileType: "image/png", SizeBytes: 50},
+		{FileType: "application/pdf", SizeBytes: 100},
+		{FileType: "application/x-unknown", SizeBytes: 1},
+	}
+
+	assert.Equal(t, []CategorySummary{
+		{Category: drive.CategoryDocuments, FileCount: 2, TotalBytes: 20},
+		{Category: drive.CategoryPDFs, FileCount: 2, TotalBytes: 400},
+		{Category: drive.CategoryImages, FileCount: 1, TotalBytes: 50},
+		{Category: drive.CategoryOther, FileCount: 1, TotalBytes: 1},
+	}, SummarizeByCategory(records))
+	assert.Empty(t, SummarizeByCategory(nil))
+}
+
+func TestGroupByOwner(t *testing.T) {
+	records := []FileRecord{
+		{FileID: "1", FileName: "b.txt", OwnerEmail: "bob@example.com"},
+		{FileID: "2", FileName: "z.txt", OwnerEmail: "alice@example.com"},
+		{FileID: "3", FileName: "a.txt", OwnerEmail: "bob@example.com"},
+		{FileID: "4", FileName: "c.txt", OwnerName: "Former Employee"},
+		{FileID: "5", FileName: "a.txt", OwnerEmail: "alice@example.com"},
+	}
+
+	groups := GroupByOwner(records)
+
+	require.Len(t, groups, 3)
+	assert.Equal(t, []string{"5", "2"}, fileIDs(groups[0]))
+	assert.Equal(t, []string{"3", "1"}, fileIDs(groups[1]))
+	assert.Equal(t, []string{"4"}, fileIDs(groups[2]))
+	assert.Equal(t, "1", records[0].FileID, "input order should be unchanged")
+
+	assert.Empty(t, GroupByOwner(nil))
+}
+
+func fileIDs(records []FileRecord) []string {
+	ids := make([]string, len(records))
+	for i, rec := range records {
+		ids[i] = rec.FileID
+	}
+	return ids
+}
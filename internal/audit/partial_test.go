@@ -0,0 +1,182 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/config"
+	"github.com/leansecurity-co/gwork/internal/drive"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	v3 "google.golang.org/api/drive/v3"
+)
+
+// secondPageFailsAPI is a DriveAPI whose permission listing succeeds on the
+// first page and fails on the second.
+type secondPageFailsAPI struct{}
+
+func (secondPageFailsAPI) ListFiles(_ context.Context, _ *drive.ListFilesOptions) (*drive.ListFilesResult, error) {
+	return &drive.ListFilesResult{Files: []*v3.File{
+		{Id: "file1", Name: "report.pdf", Owners: []*v3.User{{EmailAddress: "owner@example.com"}}},
+	}}, nil
+}
+
+func (secondPageFailsAPI) GetFile(_ context.Context, fileID string, _ *drive.GetFileOptions) (*v3.File, error) {
+	return &v3.File{Id: fileID, Name: "report.pdf", Owners: []*v3.User{{EmailAddress: "owner@example.com"}}}, nil
+}
+
+func (secondPageFailsAPI) GetDrive(_ context.Context, driveID string) (*v3.Drive, error) {
+	return nil, fmt.Errorf("shared drive not found: %s", driveID)
+}
+
+func (secondPageFailsAPI) ListPermissions(_ context.Context, _ string, opts *drive.ListPermissionsOptions) (*drive.ListPermissionsResult, error) {
+	if opts.PageToken != "" {
+		return nil, errors.New("backend error")
+	}
+	return &drive.ListPermissionsResult{
+		Permissions: []*v3.Permission{
+			{Id: "p1", Type: "user", Role: "reader", EmailAddress: "guest@partner.com"},
+			{Id: "p2", Type: "user", Role: "writer", EmailAddress: "colleague@example.com"},
+		},
+		NextPageToken: "page2",
+	}, nil
+}
+
+func TestAuditExternalSharing_KeepsPartialPermissions(t *testing.T) {
+	cfg := &config.Config{Google: config.GoogleConfig{Domain: "example.com"}}
+	client := drive.NewClientWithAPI(secondPageFailsAPI{}, "example.com", 100, false)
+
+	result, err := NewAuditorWithClient(cfg, client).AuditExternalSharing(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors[0].Error(), "file1")
+	assert.Contains(t, result.Errors[0].Error(), "backend error")
+	assert.Equal(t, 0, result.FilesProcessed, "a file with a failed page is not fully processed")
+
+	require.Len(t, result.ExternalShares, 1, "page-1 shares should flow through")
+	assert.Equal(t, "guest@partner.com", result.ExternalShares[0].SharedWithEmail)
+	assert.Equal(t, 1, result.TotalExternalShares)
+}
+
+func TestAuditExternalSharing_ErrorCap(t *testing.T) {
+	const failing = 50
+	files := make([]drive.FileInfo, failing)
+	for i := range files {
+		files[i] = drive.FileInfo{ID: fmt.Sprintf("file%02d", i)}
+	}
+
+	mockClient := new(MockDriveClient)
+	mockClient.On("ListAllFiles", mock.Anything).Return(files, nil)
+	mockClient.On("GetFilePermissions", mock.Anything, mock.Anything).Return(nil, errors.New("backend error"))
+
+	cfg := &config.Config{Audit: config.AuditConfig{Concurrency: 8, MaxErrors: 10}}
+	result, err := NewAuditorWithClient(cfg, mockClient).AuditExternalSharing(context.Background())
+	require.NoError(t, err)
+
+	assert.Len(t, result.Errors, 10)
+	assert.Equal(t, failing-10, result.DroppedErrorCount)
+	assert.Equal(t, failing, result.ErrorCount())
+	assert.Contains(t, result.Errors[0].Error(), "file00", "the first errors in file order are kept")
+	assert.Contains(t, result.Errors[9].Error(), "file09")
+}
+
+func TestAuditExternalSharing_ErrorCapDefault(t *testing.T) {
+	files := make([]drive.FileInfo, config.DefaultMaxErrors+5)
+	for i := range files {
+		files[i] = drive.FileInfo{ID: fmt.Sprintf("file%d", i)}
+	}
+
+	mockClient := new(MockDriveClient)
+	mockClient.On("ListAllFiles", mock.Anything).Return(files, nil)
+	mockClient.On("GetFilePermissions", mock.Anything, mock.Anything).Return(nil, errors.New("backend error"))
+
+	result, err := NewAuditorWithClient(&config.Config{}, mockClient).AuditExternalSharing(context.Background())
+	require.NoError(t, err)
+
+	assert.Len(t, result.Errors, config.DefaultMaxErrors)
+	assert.Equal(t, 5, result.DroppedErrorCount)
+}
+
+func TestAuditExternalSharing_Timing(t *testing.T) {
+	cfg := &config.Config{Google: config.GoogleConfig{Domain: "example.com"}}
+	client := drive.NewClientWithAPI(secondPageFailsAPI{}, "example.com", 100, false)
+	auditor := NewAuditorWithClient(cfg, client)
+
+	result, err := auditor.AuditExternalSharing(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(1), result.Timing.API.ListFiles.Calls)
+	assert.Equal(t, int64(2), result.Timing.API.ListPermissions.Calls)
+	assert.GreaterOrEqual(t, result.Timing.Total, result.Timing.ListFiles+result.Timing.FetchPermissions)
+
+	// A second audit on the same client only reports its own calls.
+	result, err = auditor.AuditFiles(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), result.Timing.API.ListFiles.Calls)
+	assert.Zero(t, result.Timing.API.ListPermissions.Calls)
+}
+
+// manyFilesAPI is a DriveAPI listing n files in one page, each shared with
+// one external user.
+type manyFilesAPI struct{ n int }
+
+func (f manyFilesAPI) ListFiles(_ context.Context, _ *drive.ListFilesOptions) (*drive.ListFilesResult, error) {
+	files := make([]*v3.File, f.n)
+	for i := range files {
+		files[i] = &v3.File{Id: fmt.Sprintf("file%d", i), Owners: []*v3.User{{EmailAddress: "owner@example.com"}}}
+	}
+	return &drive.ListFilesResult{Files: files}, nil
+}
+
+func (manyFilesAPI) GetFile(_ context.Context, fileID string, _ *drive.GetFileOptions) (*v3.File, error) {
+	return &v3.File{Id: fileID, Owners: []*v3.User{{EmailAddress: "owner@example.com"}}}, nil
+}
+
+func (manyFilesAPI) GetDrive(_ context.Context, driveID string) (*v3.Drive, error) {
+	return nil, fmt.Errorf("shared drive not found: %s", driveID)
+}
+
+func (manyFilesAPI) ListPermissions(_ context.Context, _ string, _ *drive.ListPermissionsOptions) (*drive.ListPermissionsResult, error) {
+	return &drive.ListPermissionsResult{Permissions: []*v3.Permission{
+		{Id: "p1", Type: "user", Role: "reader", EmailAddress: "guest@partner.com"},
+	}}, nil
+}
+
+func TestAuditExternalSharing_BudgetExceeded(t *testing.T) {
+	cfg := &config.Config{Google: config.GoogleConfig{Domain: "example.com"}}
+	client := drive.NewClientWithAPI(manyFilesAPI{n: 10}, "example.com", 100, false)
+	client.SetMaxAPICalls(4) // one files.list page and three permissions.list calls
+
+	result, err := NewAuditorWithClient(cfg, client).AuditExternalSharing(context.Background())
+	require.NoError(t, err)
+
+	assert.True(t, result.BudgetExceeded)
+	assert.Empty(t, result.Errors, "running out of budget is not a per-file error")
+	assert.Equal(t, 10, result.TotalFiles)
+	assert.Equal(t, 3, result.FilesProcessed)
+	assert.Len(t, result.ExternalShares, 3)
+	assert.Equal(t, int64(4), client.Stats().Total().Calls)
+}
+
+func TestAuditFiles_BudgetExceeded(t *testing.T) {
+	client := drive.NewClientWithAPI(manyFilesAPI{n: 10}, "example.com", 100, false)
+	client.SetMaxAPICalls(1)
+
+	auditor := NewAuditorWithClient(&config.Config{}, client)
+	result, err := auditor.AuditFiles(context.Background())
+	require.NoError(t, err)
+	assert.False(t, result.BudgetExceeded, "a single page fits in the budget")
+	assert.Len(t, result.FileRecords, 10)
+
+	result, err = auditor.AuditFiles(context.Background())
+	require.NoError(t, err)
+	assert.True(t, result.BudgetExceeded)
+	assert.Empty(t, result.FileRecords)
+}
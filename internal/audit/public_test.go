@@ -0,0 +1,108 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/config"
+	"github.com/leansecurity-co/gwork/internal/drive"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditPublicShares(t *testing.T) {
+	mockClient := new(MockDriveClient)
+
+	files := []drive.FileInfo{
+		{ID: "file1", Name: "public.pdf", OwnerEmail: "owner@example.com", WebViewLink: "https://drive.google.com/file/d/file1/view"},
+		{ID: "file2", Name: "partner.pdf", OwnerEmail: "owner@example.com"},
+	}
+	perms1 := []drive.Permission{
+		{ID: "p1", Type: "anyone", Role: "reader"},
+		{ID: "p2", Type: "user", Role: "writer", EmailAddress: "guest@other.com"},
+	}
+	perms2 := []drive.Permission{
+		{ID: "p3", Type: "domain", Role: "reader", Domain: "partner.com"},
+		{ID: "anyoneWithLink", Type: "anyone", Role: "commenter"},
+		{ID: "p5", Type: "group", Role: "reader", EmailAddress: "team@other.com"},
+	}
+
+	mockClient.On("ListAllFiles", mock.Anything).Return(files, nil)
+	mockClient.On("GetFilePermissions", mock.Anything, "file1").Return(perms1, nil)
+	mockClient.On("GetFilePermissions", mock.Anything, "file2").Return(perms2, nil)
+
+	auditor := NewAuditorWithClient(&config.Config{}, mockClient)
+	result, err := auditor.AuditPublicShares(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, result.ExternalShares, 2)
+	assert.Equal(t, 2, result.TotalExternalShares)
+	assert.Equal(t, 2, result.FilesProcessed)
+
+	for _, rec := range result.ExternalShares {
+		assert.True(t, IsPublicPermissionType(rec.PermissionType), "unexpected %s share", rec.PermissionType)
+	}
+
+	byFile := map[string]ExternalShareRecord{}
+	for _, rec := range result.ExternalShares {
+		byFile[rec.FileID] = rec
+	}
+	assert.Equal(t, "anyone", byFile["file1"].PermissionType)
+	assert.Equal(t, "https://drive.google.com/file/d/file1/view", byFile["file1"].WebViewLink)
+	assert.Equal(t, "anyone", byFile["file2"].PermissionType)
+	assert.Equal(t, "commenter", byFile["file2"].PermissionRole)
+
+	// Public shares are selected by type, not by the external-share check.
+	mockClient.AssertNotCalled(t, "IsExternalShare", mock.Anything)
+}
+
+func TestAuditDomainShares(t *testing.T) {
+	mockClient := new(MockDriveClient)
+
+	files := []drive.FileInfo{
+		{ID: "file1", Name: "handbook.pdf", OwnerEmail: "hr@example.com"},
+		{ID: "file2", Name: "private.doc", OwnerEmail: "alice@example.com"},
+		{ID: "file3", Name: "roadmap.doc", OwnerEmail: "bob@example.com"},
+	}
+	internal := func(perm drive.Permission) bool {
+		return perm.Domain == "example.com" || perm.Domain == "example.org" ||
+			strings.HasSuffix(perm.EmailAddress, "@example.com")
+	}
+
+	mockClient.On("ListAllFiles", mock.Anything).Return(files, nil)
+	mockClient.On("GetFilePermissions", mock.Anything, "file1").Return([]drive.Permission{
+		{ID: "p1", Type: "domain", Role: "reader", Domain: "example.com"},
+		{ID: "p2", Type: "anyone", Role: "reader"},
+	}, nil)
+	mockClient.On("GetFilePermissions", mock.Anything, "file2").Return([]drive.Permission{
+		{ID: "p3", Type: "user", Role: "owner", EmailAddress: "alice@example.com"},
+		{ID: "p4", Type: "user", Role: "writer", EmailAddress: "bob@example.com"},
+	}, nil)
+	mockClient.On("GetFilePermissions", mock.Anything, "file3").Return([]drive.Permission{
+		{ID: "p5", Type: "domain", Role: "writer", Domain: "example.org"},
+		{ID: "p6", Type: "domain", Role: "reader", Domain: "partner.com"},
+	}, nil)
+	mockClient.On("IsExternalShare", mock.MatchedBy(internal)).Return(false)
+	mockClient.On("IsExternalShare", mock.Anything).Return(true)
+
+	auditor := NewAuditorWithClient(&config.Config{}, mockClient)
+	result, err := auditor.AuditDomainShares(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, result.ExternalShares, 2, "private files and external domains are not domain-wide")
+	assert.Equal(t, 3, result.FilesProcessed)
+
+	assert.Equal(t, "file3", result.ExternalShares[0].FileID)
+	assert.Equal(t, "example.org", result.ExternalShares[0].SharedWithDomain)
+	assert.Equal(t, "writer", result.ExternalShares[0].PermissionRole)
+	assert.Equal(t, "file1", result.ExternalShares[1].FileID)
+	for _, rec := range result.ExternalShares {
+		assert.True(t, rec.DomainWide)
+		assert.Equal(t, ScopeInternal, rec.Label)
+	}
+}
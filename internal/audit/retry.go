@@ -0,0 +1,56 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/leansecurity-co/gwork/internal/drive"
+)
+
+const (
+	// permissionRetries is the number of times a file's permissions are
+	// fetched again after a transient failure.
+	permissionRetries = 3
+
+	// defaultPermissionRetryDelay is the wait before the first refetch; it
+	// doubles with each further attempt.
+	defaultPermissionRetryDelay = 2 * time.Second
+)
+
+// getFilePermissions fetches a file's permissions, fetching them again
+// when the call fails with an error the drive client classifies as
+// retryable by audit.retry_status_codes. This sits above the retries of
+// single API calls made by the drive client, so a file whose pages keep
+// failing is retried as a whole; clients that do not classify errors are
+// not retried. Only the last failure is returned, so retried attempts never
+// count against audit.max_errors. Waiting stops as soon as ctx is canceled.
+func (a *Auditor) getFilePermissions(ctx context.Context, fileID string) ([]drive.Permission, error) {
+	classifier, ok := a.driveClient.(RetryClassifier)
+	if !ok {
+		return a.driveClient.GetFilePermissions(ctx, fileID)
+	}
+
+	delay := a.permissionRetryDelay
+	if delay == 0 {
+		delay = defaultPermissionRetryDelay
+	}
+
+	for attempt := 0; ; attempt++ {
+		perms, err := a.driveClient.GetFilePermissions(ctx, fileID)
+		if err == nil || attempt == permissionRetries || !classifier.IsRetryable(err) {
+			return perms, err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return perms, ctx.Err()
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/leansecurity-co/gwork/internal/config"
+	"github.com/leansecurity-co/gwork/internal/drive"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/googleapi"
+)
+
+// flakyDriveAPI wraps pagedDriveAPI, failing the first permission fetches
+// of some files with err.
+type flakyDriveAPI struct {
+	*pagedDriveAPI
+	err error
+
+	mu       sync.Mutex
+	failures map[string]int // remaining failures per file ID
+	calls    map[string]int
+}
+
+func newFlakyDriveAPI(api *pagedDriveAPI, err error, failures map[string]int) *flakyDriveAPI {
+	return &flakyDriveAPI{pagedDriveAPI: api, err: err, failures: failures, calls: make(map[string]int)}
+}
+
+func (f *flakyDriveAPI) ListPermissions(ctx context.Context, fileID string, opts *drive.ListPermissionsOptions) (*drive.ListPermissionsResult, error) {
+	f.mu.Lock()
+	f.calls[fileID]++
+	fail := f.failures[fileID] > 0
+	if fail {
+		f.failures[fileID]--
+	}
+	f.mu.Unlock()
+
+	if fail {
+		return nil, f.err
+	}
+	return f.pagedDriveAPI.ListPermissions(ctx, fileID, opts)
+}
+
+// rateLimitError is the 429 Drive returns when a rate limit is hit.
+var rateLimitError = &googleapi.Error{Code: http.StatusTooManyRequests}
+
+// retryingClient is a drive.Client whose errors with one of codes are
+// retried per file, standing in for audit.retry_status_codes without the
+// client's own backoff between calls.
+type retryingClient struct {
+	*drive.Client
+	codes []int
+}
+
+func (c retryingClient) IsRetryable(err error) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && slices.Contains(c.codes, apiErr.Code)
+}
+
+func newRetryTestAuditor(api drive.DriveAPI, maxErrors int) *Auditor {
+	cfg := &config.Config{
+		Google: config.GoogleConfig{Domain: "example.com"},
+		Audit:  config.AuditConfig{Concurrency: 4, MaxErrors: maxErrors},
+	}
+	client := retryingClient{
+		Client: drive.NewClientWithAPI(api, "example.com", 5, false),
+		codes:  []int{http.StatusTooManyRequests, http.StatusInternalServerError},
+	}
+	auditor := NewAuditorWithClient(cfg, client)
+	auditor.permissionRetryDelay = time.Nanosecond
+	return auditor
+}
+
+func TestAuditExternalSharing_RetriesTransientPermissionErrors(t *testing.T) {
+	paged := newPagedDriveAPI(6, 5)
+	paged.failing = map[string]bool{}
+	api := newFlakyDriveAPI(paged, rateLimitError, map[string]int{"file000": 2})
+
+	result, err := newRetryTestAuditor(api, 0).AuditExternalSharing(context.Background())
+	require.NoError(t, err)
+
+	assert.Empty(t, result.Errors)
+	assert.Equal(t, 6, result.FilesProcessed)
+	assert.Equal(t, 5, api.calls["file000"], "two failures, then the file's three pages")
+
+	var shares []string
+	for _, rec := range result.ExternalShares {
+		if rec.FileID == "file000" {
+			shares = append(shares, rec.PermissionType)
+		}
+	}
+	assert.ElementsMatch(t, []string{"user", "anyone"}, shares, "the file's permissions are captured after the retries")
+}
+
+func TestAuditExternalSharing_PermissionRetries(t *testing.T) {
+	forbidden := &googleapi.Error{
+		Code:   http.StatusForbidden,
+		Errors: []googleapi.ErrorItem{{Reason: "insufficientFilePermissions"}},
+	}
+
+	tests := []struct {
+		name      string
+		err       error
+		failures  int
+		wantCalls int
+	}{
+		{name: "error without a retry code is not retried", err: forbidden, failures: 1, wantCalls: 1},
+		{name: "persistent retryable error gives up", err: rateLimitError, failures: 100, wantCalls: permissionRetries + 1},
+		// One failure, then the file's three pages.
+		{name: "server error is retried", err: &googleapi.Error{Code: http.StatusInternalServerError}, failures: 1, wantCalls: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paged := newPagedDriveAPI(2, 5)
+			paged.failing = map[string]bool{}
+			api := newFlakyDriveAPI(paged, tt.err, map[string]int{"file000": tt.failures})
+
+			result, err := newRetryTestAuditor(api, 0).AuditExternalSharing(context.Background())
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantCalls, api.calls["file000"])
+			if tt.wantCalls > tt.failures {
+				assert.Empty(t, result.Errors)
+			} else {
+				require.Len(t, result.Errors, 1)
+				assert.ErrorAs(t, result.Errors[0], new(*googleapi.Error))
+			}
+		})
+	}
+}
+
+func TestAuditExternalSharing_PermissionRetriesRespectErrorCap(t *testing.T) {
+	paged := newPagedDriveAPI(3, 5)
+	paged.failing = map[string]bool{}
+	api := newFlakyDriveAPI(paged, rateLimitError, map[string]int{"file000": 100, "file001": 100, "file002": 100})
+
+	result, err := newRetryTestAuditor(api, 1).AuditExternalSharing(context.Background())
+	require.NoError(t, err)
+
+	assert.Len(t, result.Errors, 1, "each file's retries end in a single error")
+	assert.Equal(t, 2, result.DroppedErrorCount)
+	assert.Equal(t, 3, result.ErrorCount())
+}
+
+func TestGetFilePermissions_NoRetryCodes(t *testing.T) {
+	paged := newPagedDriveAPI(1, 5)
+	paged.failing = map[string]bool{}
+	api := newFlakyDriveAPI(paged, rateLimitError, map[string]int{"file000": 1})
+
+	cfg := &config.Config{Google: config.GoogleConfig{Domain: "example.com"}}
+	auditor := NewAuditorWithClient(cfg, drive.NewClientWithAPI(api, "example.com", 5, false))
+	auditor.permissionRetryDelay = time.Nanosecond
+
+	_, err := auditor.getFilePermissions(context.Background(), "file000")
+
+	require.ErrorAs(t, err, new(*googleapi.Error))
+	assert.Equal(t, 1, api.calls["file000"], "an empty audit.retry_status_codes fetches once")
+}
+
+func TestGetFilePermissions_CanceledWhileWaiting(t *testing.T) {
+	paged := newPagedDriveAPI(1, 5)
+	paged.failing = map[string]bool{}
+	api := newFlakyDriveAPI(paged, rateLimitError, map[string]int{"file000": 100})
+
+	auditor := newRetryTestAuditor(api, 0)
+	auditor.permissionRetryDelay = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := auditor.getFilePermissions(ctx, "file000")
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, api.calls["file000"])
+}
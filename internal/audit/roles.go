@@ -0,0 +1,86 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import "sort"
+
+// Share scopes used to bucket a role distribution.
+const (
+	ScopePublic   = "public"
+	ScopeExternal = "external"
+	ScopeInternal = "internal"
+)
+
+// scopeOrder is the order scopes are reported in, most exposed first.
+var scopeOrder = map[string]int{ScopePublic: 0, ScopeExternal: 1, ScopeInternal: 2}
+
+// roleOrder is the order Drive roles are reported in, most privileged first.
+// Unknown roles sort after these, alphabetically.
+var roleOrder = map[string]int{
+	"owner":         0,
+	"organizer":     1,
+	"fileOrganizer": 2,
+	"writer":        3,
+	"commenter":     4,
+	"reader":        5,
+}
+
+// RoleCount is the number of shares granting a role within a scope.
+type RoleCount struct {
+	Scope string `json:"scope"`
+	Role  string `json:"role"`
+	Count int    `json:"count"`
+}
+
+// RoleDistribution counts share records by scope and permission role.
+// Records shared with anyone are public; records shared with one of
+// internalDomains are internal; everything else is external. The result is
+// sorted by scope (public, external, internal), then by role from most to
+// least privileged.
+func RoleDistribution(records []ExternalShareRecord, internalDomains ...string) []RoleCount {
+	index := make(map[RoleCount]int)
+	counts := make([]RoleCount, 0)
+
+	for _, rec := range records {
+		key := RoleCount{Scope: shareScope(rec, internalDomains), Role: rec.PermissionRole}
+		i, ok := index[key]
+		if !ok {
+			i = len(counts)
+			index[key] = i
+			counts = append(counts, key)
+		}
+		counts[i].Count++
+	}
+
+	sort.SliceStable(counts, func(i, j int) bool {
+		if counts[i].Scope != counts[j].Scope {
+			return scopeOrder[counts[i].Scope] < scopeOrder[counts[j].Scope]
+		}
+		ri, iKnown := roleOrder[counts[i].Role]
+		rj, jKnown := roleOrder[counts[j].Role]
+		if iKnown != jKnown {
+			return iKnown
+		}
+		if ri != rj {
+			return ri < rj
+		}
+		return counts[i].Role < counts[j].Role
+	})
+
+	return counts
+}
+
+// shareScope returns the scope of a share record.
+func shareScope(rec ExternalShareRecord, internalDomains []string) string {
+	if IsPublicPermissionType(rec.PermissionType) {
+		return ScopePublic
+	}
+
+	for _, domain := range internalDomains {
+		if rec.SharedWithDomain != "" && rec.SharedWithDomain == domain {
+			return ScopeInternal
+		}
+	}
+	return ScopeExternal
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/config"
+	"github.com/leansecurity-co/gwork/internal/drive"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v3 "google.golang.org/api/drive/v3"
+)
+
+func TestAuditor_ExternalRolesOfInterest(t *testing.T) {
+	newAPI := func() *pagedDriveAPI {
+		return &pagedDriveAPI{
+			files: []*v3.File{{Id: "doc", Name: "plan.docx", Owners: []*v3.User{{EmailAddress: "alice@example.com"}}}},
+			permissions: map[string][]*v3.Permission{
+				"doc": {
+					{Id: "owner", Type: "user", Role: "owner", EmailAddress: "alice@example.com"},
+					{Id: "reader", Type: "user", Role: "reader", EmailAddress: "reader@partner.com"},
+					{Id: "commenter", Type: "user", Role: "commenter", EmailAddress: "commenter@partner.com"},
+					{Id: "writer", Type: "user", Role: "writer", EmailAddress: "writer@partner.com"},
+					{Id: "internal-writer", Type: "user", Role: "writer", EmailAddress: "bob@example.com"},
+					{Id: "link", Type: "anyone", Role: "reader"},
+				},
+			},
+			pageSize: 10,
+		}
+	}
+
+	grantees := func(records []ExternalShareRecord) []string {
+		out := make([]string, 0, len(records))
+		for _, rec := range records {
+			out = append(out, rec.PermissionType+":"+rec.SharedWithEmail)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name  string
+		roles []string
+		want  []string
+	}{
+		{
+			name: "every role by default",
+			want: []string{"anyone:", "user:commenter@partner.com", "user:reader@partner.com", "user:writer@partner.com"},
+		},
+		{
+			name:  "writers and owners only",
+			roles: []string{"writer", "owner"},
+			want:  []string{"user:writer@partner.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Audit: config.AuditConfig{ExternalRolesOfInterest: tt.roles}}
+			client := drive.NewClientWithAPI(newAPI(), "example.com", 10, false)
+
+			report, err := RunAudit(context.Background(), cfg, RunOptions{Client: client})
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.want, grantees(report.ExternalShares), "audit all")
+
+			auditor := NewAuditorWithClient(cfg, client)
+			auditor.SetExternalRolesOfInterest(tt.roles...)
+			result, err := auditor.AuditExternalSharing(context.Background())
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.want, grantees(result.ExternalShares), "audit sharing")
+			assert.Equal(t, len(tt.want), result.TotalExternalShares)
+		})
+	}
+}
+
+func TestAuditor_ExternalRolesOfInterest_PublicAuditUnaffected(t *testing.T) {
+	api := &pagedDriveAPI{
+		files:       []*v3.File{{Id: "doc", Name: "plan.docx"}},
+		permissions: map[string][]*v3.Permission{"doc": {{Id: "link", Type: "anyone", Role: "reader"}}},
+		pageSize:    10,
+	}
+	auditor := NewAuditorWithClient(&config.Config{}, drive.NewClientWithAPI(api, "example.com", 10, false))
+	auditor.SetExternalRolesOfInterest("writer")
+
+	result, err := auditor.AuditPublicShares(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, result.ExternalShares, 1)
+}
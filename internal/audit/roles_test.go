@@ -0,0 +1,51 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleDistribution(t *testing.T) {
+	records := []ExternalShareRecord{
+		{PermissionType: "user", PermissionRole: "reader", SharedWithDomain: "partner.com"},
+		{PermissionType: "anyone", PermissionRole: "reader"},
+		{PermissionType: "user", PermissionRole: "writer", SharedWithDomain: "vendor.io"},
+		{PermissionType: "domain", PermissionRole: "reader", SharedWithDomain: "partner.com"},
+		{PermissionType: "anyone", PermissionRole: "commenter"},
+		{PermissionType: "group", PermissionRole: "writer", SharedWithDomain: "example.org"},
+		{PermissionType: "user", PermissionRole: "customRole", SharedWithDomain: "partner.com"},
+		{PermissionType: "anyone", PermissionRole: "reader"},
+		{PermissionType: "user", PermissionRole: "commenter", SharedWithDomain: "partner.com"},
+	}
+
+	got := RoleDistribution(records, "example.com", "example.org")
+
+	assert.Equal(t, []RoleCount{
+		{Scope: ScopePublic, Role: "commenter", Count: 1},
+		{Scope: ScopePublic, Role: "reader", Count: 2},
+		{Scope: ScopeExternal, Role: "writer", Count: 1},
+		{Scope: ScopeExternal, Role: "commenter", Count: 1},
+		{Scope: ScopeExternal, Role: "reader", Count: 2},
+		{Scope: ScopeExternal, Role: "customRole", Count: 1},
+		{Scope: ScopeInternal, Role: "writer", Count: 1},
+	}, got)
+}
+
+func TestRoleDistribution_NoInternalDomains(t *testing.T) {
+	records := []ExternalShareRecord{
+		{PermissionType: "user", PermissionRole: "reader", SharedWithDomain: "example.com"},
+		{PermissionType: "user", PermissionRole: "reader"},
+	}
+
+	assert.Equal(t, []RoleCount{
+		{Scope: ScopeExternal, Role: "reader", Count: 2},
+	}, RoleDistribution(records))
+}
+
+func TestRoleDistribution_Empty(t *testing.T) {
+	assert.Empty(t, RoleDistribution(nil, "example.com"))
+}
@@ -0,0 +1,148 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/leansecurity-co/gwork/internal/config"
+)
+
+// RunOptions adjusts a RunAudit call. The zero value audits every file with
+// the production drive client.
+type RunOptions struct {
+	// Client replaces the production drive client, e.g. with a fake.
+	Client DriveClient
+	// ClientFor replaces the production drive client of each domain of
+	// RunMultiDomainAudit. Client is ignored there.
+	ClientFor func(domain string) (DriveClient, error)
+	// GroupResolver expands group shares when set. Without a Client, it is
+	// created from the config when audit.expand_groups is set.
+	GroupResolver GroupResolver
+
+	// SampleSize audits a uniform random sample of this many files when
+	// positive; SampleSeed makes the selection reproducible.
+	SampleSize int
+	SampleSeed int64
+
+	// Explain sets Explanation on share records.
+	Explain bool
+
+	// ThrottleLog receives a line whenever the Drive client changes its
+	// request rate in response to rate limiting; nil discards them.
+	ThrottleLog io.Writer
+
+	// ActivityLog receives a line of JSON for each Drive API call, as an
+	// auditable trail of the run; nil disables it. See drive.ActivityLog.
+	// Multi-domain audits write to it concurrently, one line per Write, as
+	// an *os.File accepts.
+	ActivityLog io.Writer
+
+	// FileSnapshots is passed to Auditor.SetFileSnapshots: permissions of
+	// unchanged files are reused from it, and the sharing result returns
+	// the permissions to save for the next run.
+	FileSnapshots map[string]FileSnapshot
+}
+
+// AuditReport is the aggregated result of a files and sharing audit, for
+// callers that use gwork as a library rather than reading reports from
+// disk.
+type AuditReport struct {
+	Domain    string    `json:"domain"`
+	Timestamp time.Time `json:"timestamp"`
+	Summary   Totals    `json:"summary"`
+
+	Files          []FileRecord          `json:"files"`
+	ExternalShares []ExternalShareRecord `json:"external_sharing"`
+
+	// Partial is set when the API call budget ran out before every file
+	// was audited.
+	Partial bool `json:"partial"`
+	// Errors holds the per-file errors kept by the audits; ErrorCount also
+	// counts those dropped once audit.max_errors was reached.
+	Errors     []string `json:"errors"`
+	ErrorCount int      `json:"error_count"`
+
+	// DomainErrors lists the domains of a multi-domain audit whose audit
+	// failed; their records are missing from the report.
+	DomainErrors []DomainError `json:"domain_errors,omitempty"`
+
+	// FilesResult and SharingResult are the underlying audit results, with
+	// statistics and timing.
+	FilesResult   *AuditResult `json:"-"`
+	SharingResult *AuditResult `json:"-"`
+}
+
+// RunAudit runs the files and sharing audits for cfg, as gwork audit all
+// does, and returns the aggregated report without writing anything.
+// Records are sorted as in the reports.
+func RunAudit(ctx context.Context, cfg *config.Config, opts RunOptions) (*AuditReport, error) {
+	var auditor *Auditor
+	if opts.Client != nil {
+		auditor = NewAuditorWithClient(cfg, opts.Client)
+		if err := auditor.applyConfig(); err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		if auditor, err = NewAuditorContext(ctx, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.GroupResolver != nil {
+		auditor.SetGroupResolver(opts.GroupResolver)
+	}
+	if opts.SampleSize > 0 {
+		auditor.SetSample(opts.SampleSize, opts.SampleSeed)
+	}
+	auditor.SetExplain(opts.Explain)
+	auditor.SetThrottleLog(opts.ThrottleLog)
+	auditor.SetActivityLog(opts.ActivityLog)
+	auditor.SetFileSnapshots(opts.FileSnapshots)
+
+	timestamp := time.Now().UTC()
+	filesResult, sharingResult, err := auditor.AuditAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewAuditReport(cfg.Google.Domain, timestamp, filesResult, sharingResult), nil
+}
+
+// NewAuditReport aggregates the files and sharing results of a run.
+func NewAuditReport(domain string, timestamp time.Time, filesResult, sharingResult *AuditResult) *AuditReport {
+	report := &AuditReport{
+		Domain:         domain,
+		Timestamp:      timestamp,
+		Summary:        Summarize(filesResult, sharingResult),
+		Files:          filesResult.FileRecords,
+		ExternalShares: sharingResult.ExternalShares,
+		Partial:        filesResult.BudgetExceeded || sharingResult.BudgetExceeded,
+		Errors:         []string{},
+		ErrorCount:     filesResult.ErrorCount() + sharingResult.ErrorCount(),
+		FilesResult:    filesResult,
+		SharingResult:  sharingResult,
+	}
+
+	// Marshal empty arrays rather than null when there are no records.
+	if report.Files == nil {
+		report.Files = []FileRecord{}
+	}
+	if report.ExternalShares == nil {
+		report.ExternalShares = []ExternalShareRecord{}
+	}
+	SortFileRecords(report.Files)
+	SortExternalShares(report.ExternalShares)
+
+	for _, result := range []*AuditResult{filesResult, sharingResult} {
+		for _, err := range result.Errors {
+			report.Errors = append(report.Errors, err.Error())
+		}
+	}
+
+	return report
+}
@@ -0,0 +1,135 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leansecurity-co/gwork/internal/config"
+	"github.com/leansecurity-co/gwork/internal/drive"
+	"github.com/leansecurity-co/gwork/internal/drive/drivetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunAudit(t *testing.T) {
+	files := []drive.FileInfo{
+		{ID: "doc", Name: "Roadmap", OwnerEmail: "bob@example.com", Size: 100},
+		{ID: "sheet", Name: "Budget", OwnerEmail: "alice@example.com", Size: 50},
+		{ID: "ignored", Name: "Template", OwnerEmail: "alice@example.com", Size: 10},
+		{ID: "broken", Name: "Broken", OwnerEmail: "alice@example.com"},
+	}
+
+	mockClient := new(MockDriveClient)
+	mockClient.On("ListAllFiles", mock.Anything).Return(files, nil)
+	mockClient.On("GetFilePermissions", mock.Anything, "doc").Return([]drive.Permission{
+		{Type: "user", Role: "reader", EmailAddress: "guest@rival.com"},
+	}, nil)
+	mockClient.On("GetFilePermissions", mock.Anything, "sheet").Return([]drive.Permission{
+		{Type: "anyone", Role: "reader"},
+	}, nil)
+	mockClient.On("GetFilePermissions", mock.Anything, "broken").Return([]drive.Permission(nil), errors.New("boom"))
+	mockClient.On("IsExternalShare", mock.Anything).Return(true)
+
+	cfg := &config.Config{
+		Google: config.GoogleConfig{Domain: "example.com"},
+		Audit:  config.AuditConfig{IgnoreFileIDs: []string{"ignored"}, FlaggedDomains: []string{"rival.com"}},
+	}
+
+	before := time.Now().UTC()
+	report, err := RunAudit(context.Background(), cfg, RunOptions{Client: mockClient, Explain: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, "example.com", report.Domain)
+	assert.False(t, report.Timestamp.Before(before))
+	assert.Equal(t, Totals{Files: 3, ExternalShares: 2, PublicShares: 1, Bytes: 150}, report.Summary)
+	assert.False(t, report.Partial)
+	assert.Equal(t, 1, report.ErrorCount)
+	require.Len(t, report.Errors, 1)
+	assert.Contains(t, report.Errors[0], "boom")
+
+	require.Len(t, report.Files, 3, "the ignore list from the config applies")
+	assert.Equal(t, "alice@example.com", report.Files[0].OwnerEmail, "records are sorted by owner")
+
+	require.Len(t, report.ExternalShares, 2)
+	byFile := make(map[string]ExternalShareRecord)
+	for _, rec := range report.ExternalShares {
+		byFile[rec.FileID] = rec
+	}
+	assert.True(t, byFile["doc"].Flagged, "flagged domains from the config apply")
+	assert.Equal(t, "guest@rival.com is outside example.com", byFile["doc"].Explanation)
+	assert.Equal(t, "shared to anyone with the link", byFile["sheet"].Explanation)
+
+	require.NotNil(t, report.FilesResult)
+	require.NotNil(t, report.SharingResult)
+	assert.Equal(t, 3, report.FilesResult.TotalFiles)
+
+	data, err := json.Marshal(report)
+	require.NoError(t, err)
+	var doc map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(data, &doc))
+	assert.Contains(t, doc, "external_sharing")
+	assert.NotContains(t, doc, "FilesResult")
+}
+
+func TestRunAudit_Empty(t *testing.T) {
+	mockClient := new(MockDriveClient)
+	mockClient.On("ListAllFiles", mock.Anything).Return([]drive.FileInfo{}, nil)
+
+	report, err := RunAudit(context.Background(), &config.Config{}, RunOptions{Client: mockClient})
+	require.NoError(t, err)
+
+	data, err := json.Marshal(report)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"files":[]`)
+	assert.Contains(t, string(data), `"external_sharing":[]`)
+	assert.Contains(t, string(data), `"errors":[]`)
+}
+
+func TestRunAudit_ListError(t *testing.T) {
+	mockClient := new(MockDriveClient)
+	mockClient.On("ListAllFiles", mock.Anything).Return([]drive.FileInfo(nil), errors.New("list failed"))
+
+	_, err := RunAudit(context.Background(), &config.Config{}, RunOptions{Client: mockClient})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "list failed")
+}
+
+func TestRunAudit_ActivityLog(t *testing.T) {
+	fake := &drivetest.FakeAPI{Files: 25, PageSize: 10, ExternalEvery: 5}
+	client := drive.NewClientWithAPI(fake, "example.com", 10, false)
+	cfg := &config.Config{Google: config.GoogleConfig{Domain: "example.com"}}
+
+	var log bytes.Buffer
+	report, err := RunAudit(context.Background(), cfg, RunOptions{Client: client, ActivityLog: &log})
+	require.NoError(t, err)
+	require.Len(t, report.ExternalShares, 5)
+
+	ops := make(map[string]int)
+	fileIDs := make(map[string]bool)
+	for line := range strings.Lines(log.String()) {
+		var entry drive.Activity
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		assert.Equal(t, "ok", entry.Result)
+		assert.False(t, entry.Time.IsZero())
+		ops[entry.Operation]++
+		if entry.Operation == "permissions.list" {
+			fileIDs[entry.FileID] = true
+		}
+	}
+
+	listFiles, listPermissions := fake.Calls()
+	assert.Equal(t, map[string]int{
+		"files.list":       int(listFiles),
+		"permissions.list": int(listPermissions),
+	}, ops, "one line per API call")
+	assert.Len(t, fileIDs, 25, "each file's permissions call names the file")
+}
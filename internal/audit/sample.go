@@ -0,0 +1,147 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/leansecurity-co/gwork/internal/drive"
+)
+
+// Reservoir keeps a uniform random sample of up to size items from a stream
+// of unknown length (Algorithm R). Every item added has the same probability
+// of being kept, regardless of when it arrives.
+type Reservoir[T any] struct {
+	size  int
+	seen  int
+	items []T
+	rng   *rand.Rand
+}
+
+// NewReservoir creates a reservoir of the given size. The same seed and
+// input always produce the same sample.
+func NewReservoir[T any](size int, seed int64) *Reservoir[T] {
+	return &Reservoir[T]{
+		size:  size,
+		items: make([]T, 0, size),
+		rng:   rand.New(rand.NewPCG(uint64(seed), 0)), //nolint:gosec // sampling, not security
+	}
+}
+
+// Add offers an item to the reservoir.
+func (r *Reservoir[T]) Add(item T) {
+	r.seen++
+	if len(r.items) < r.size {
+		r.items = append(r.items, item)
+		return
+	}
+	if j := r.rng.IntN(r.seen); j < r.size {
+		r.items[j] = item
+	}
+}
+
+// Items returns the sampled items.
+func (r *Reservoir[T]) Items() []T {
+	return r.items
+}
+
+// Seen returns the number of items offered so far.
+func (r *Reservoir[T]) Seen() int {
+	return r.seen
+}
+
+// SetSample enables sampling: audits keep a uniform random sample of size
+// files, selected with seed, instead of processing every file. A size of
+// zero or less disables sampling.
+func (a *Auditor) SetSample(size int, seed int64) {
+	a.sampleSize = size
+	a.sampleSeed = seed
+}
+
+// sampleFiles returns the sampled subset of files in listing order, or files
+// unchanged when sampling is disabled or there are not more files than the
+// sample size.
+func (a *Auditor) sampleFiles(files []drive.FileInfo) []drive.FileInfo {
+	if a.sampleSize <= 0 || len(files) <= a.sampleSize {
+		return files
+	}
+
+	reservoir := NewReservoir[int](a.sampleSize, a.sampleSeed)
+	for i := range files {
+		reservoir.Add(i)
+	}
+
+	indexes := reservoir.Items()
+	sort.Ints(indexes)
+
+	sampled := make([]drive.FileInfo, 0, len(indexes))
+	for _, i := range indexes {
+		sampled = append(sampled, files[i])
+	}
+	return sampled
+}
+
+// indexedFile is a listed file and its position among the files kept
+// after the ignore list.
+type indexedFile struct {
+	index int
+	file  drive.FileInfo
+}
+
+// listSample lists files a page at a time through a reservoir, so only the
+// sampled files are held rather than the whole listing. It selects the same
+// files, in listing order, as sampleFiles over the full listing; Drive is
+// still paged through in full to give every file a chance.
+func (a *Auditor) listSample(ctx context.Context, visitor FileVisitor) (*fileListing, error) {
+	start := time.Now()
+
+	reservoir := NewReservoir[indexedFile](a.sampleSize, a.sampleSeed)
+	var owners ownerTracker
+	suppressed := 0
+	err := visitor.VisitAllFiles(ctx, func(file drive.FileInfo) {
+		if _, ignored := a.ignoreFileIDs[file.ID]; ignored {
+			suppressed++
+			return
+		}
+		owners.add(file)
+		reservoir.Add(indexedFile{index: reservoir.Seen(), file: file})
+	})
+	budgetExceeded := errors.Is(err, drive.ErrBudgetExceeded)
+	if err != nil && !budgetExceeded {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	sampled := reservoir.Items()
+	slices.SortFunc(sampled, func(x, y indexedFile) int { return cmp.Compare(x.index, y.index) })
+	files := make([]drive.FileInfo, len(sampled))
+	for i, s := range sampled {
+		files[i] = s.file
+	}
+
+	return &fileListing{
+		files:          files,
+		total:          reservoir.Seen(),
+		suppressed:     suppressed,
+		ownersMissing:  owners.missing(),
+		budgetExceeded: budgetExceeded,
+		duration:       time.Since(start),
+	}, nil
+}
+
+// Extrapolate scales a count observed in the sampled files up to all files.
+// It returns count unchanged when the result was not sampled.
+func (r *AuditResult) Extrapolate(count int) int {
+	if r.SampledFiles == 0 || r.SampledFiles >= r.TotalFiles {
+		return count
+	}
+	return int(math.Round(float64(count) * float64(r.TotalFiles) / float64(r.SampledFiles)))
+}
@@ -0,0 +1,175 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/config"
+	"github.com/leansecurity-co/gwork/internal/drive"
+	"github.com/leansecurity-co/gwork/internal/drive/drivetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReservoir_Size(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     int
+		items    int
+		expected int
+	}{
+		{name: "more items than size", size: 10, items: 1000, expected: 10},
+		{name: "exactly size items", size: 10, items: 10, expected: 10},
+		{name: "fewer items than size", size: 10, items: 3, expected: 3},
+		{name: "no items", size: 10, items: 0, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewReservoir[int](tt.size, 1)
+			for i := 0; i < tt.items; i++ {
+				r.Add(i)
+			}
+			assert.Len(t, r.Items(), tt.expected)
+			assert.Equal(t, tt.items, r.Seen())
+		})
+	}
+}
+
+func TestReservoir_Deterministic(t *testing.T) {
+	sample := func(seed int64) []int {
+		r := NewReservoir[int](5, seed)
+		for i := 0; i < 500; i++ {
+			r.Add(i)
+		}
+		return append([]int(nil), r.Items()...)
+	}
+
+	assert.Equal(t, sample(42), sample(42), "the same seed selects the same items")
+	assert.NotEqual(t, sample(42), sample(43), "different seeds select different items")
+}
+
+func TestReservoir_Unbiased(t *testing.T) {
+	// Each of 10 items should be kept in about 3/10 of the trials.
+	const trials = 20000
+	counts := make([]int, 10)
+	for seed := int64(0); seed < trials; seed++ {
+		r := NewReservoir[int](3, seed)
+		for i := 0; i < 10; i++ {
+			r.Add(i)
+		}
+		for _, item := range r.Items() {
+			counts[item]++
+		}
+	}
+
+	expected := trials * 3 / 10
+	for item, count := range counts {
+		assert.InDelta(t, expected, count, float64(expected)*0.05, "item %d kept %d times", item, count)
+	}
+}
+
+func TestAuditExternalSharing_Sample(t *testing.T) {
+	mockClient := new(MockDriveClient)
+
+	files := make([]drive.FileInfo, 0, 100)
+	for i := 0; i < 100; i++ {
+		files = append(files, drive.FileInfo{ID: fmt.Sprintf("file%03d", i), Name: fmt.Sprintf("f%03d", i), OwnerEmail: "owner@example.com"})
+	}
+	mockClient.On("ListAllFiles", mock.Anything).Return(files, nil)
+	mockClient.On("GetFilePermissions", mock.Anything, mock.Anything).Return([]drive.Permission{
+		{ID: "p1", Type: "anyone", Role: "reader"},
+	}, nil)
+	mockClient.On("IsExternalShare", mock.Anything).Return(true)
+
+	run := func(seed int64) *AuditResult {
+		auditor := NewAuditorWithClient(&config.Config{}, mockClient)
+		auditor.SetSample(10, seed)
+		result, err := auditor.AuditExternalSharing(context.Background())
+		require.NoError(t, err)
+		return result
+	}
+
+	result := run(7)
+	assert.Equal(t, 100, result.TotalFiles)
+	assert.Equal(t, 10, result.SampledFiles)
+	assert.Equal(t, 10, result.FilesProcessed)
+	assert.Equal(t, 10, result.TotalExternalShares)
+	assert.Equal(t, 100, result.Extrapolate(result.TotalExternalShares))
+
+	assert.Equal(t, result.ExternalShares, run(7).ExternalShares, "a fixed seed selects the same files")
+}
+
+func TestAuditFiles_SampleLargerThanFiles(t *testing.T) {
+	mockClient := new(MockDriveClient)
+	files := []drive.FileInfo{{ID: "1"}, {ID: "2"}}
+	mockClient.On("ListAllFiles", mock.Anything).Return(files, nil)
+
+	auditor := NewAuditorWithClient(&config.Config{}, mockClient)
+	auditor.SetSample(10, 1)
+
+	result, err := auditor.AuditFiles(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, result.FileRecords, 2)
+	assert.Zero(t, result.SampledFiles, "no sampling when all files fit")
+	assert.Equal(t, 5, result.Extrapolate(5))
+}
+
+// countingVisitor is a FileVisitor over drive counting the files visited.
+type countingVisitor struct {
+	client  *drive.Client
+	visited int
+}
+
+func (v *countingVisitor) VisitAllFiles(ctx context.Context, visit func(drive.FileInfo)) error {
+	return v.client.VisitAllFiles(ctx, func(file drive.FileInfo) {
+		v.visited++
+		visit(file)
+	})
+}
+
+func TestListSample_StreamsPages(t *testing.T) {
+	newClient := func() *drive.Client {
+		fake := &drivetest.FakeAPI{Files: 95, PageSize: 10}
+		return drive.NewClientWithOptions(fake, drive.Options{Domain: "example.com"})
+	}
+	newAuditor := func(client DriveClient) *Auditor {
+		auditor := NewAuditorWithClient(&config.Config{}, client)
+		auditor.SetSample(10, 3)
+		auditor.SetIgnoreFileIDs(drivetest.FileID(4), drivetest.FileID(50))
+		return auditor
+	}
+
+	// The full listing, sampled afterwards.
+	want, err := newAuditor(newClient()).listFrom(context.Background(), newClient().ListAllFiles)
+	require.NoError(t, err)
+
+	visitor := &countingVisitor{client: newClient()}
+	got, err := newAuditor(nil).listSample(context.Background(), visitor)
+	require.NoError(t, err)
+
+	assert.Equal(t, 95, visitor.visited, "every page is visited")
+	assert.Len(t, got.files, 10)
+	assert.Equal(t, want.files, got.files, "the same files are sampled, in listing order")
+	assert.Equal(t, 93, got.total)
+	assert.Equal(t, 2, got.suppressed)
+	assert.Equal(t, want.ownersMissing, got.ownersMissing)
+}
+
+func TestAuditExternalSharing_SampleUsesVisitor(t *testing.T) {
+	fake := &drivetest.FakeAPI{Files: 40, PageSize: 7, ExternalEvery: 1}
+	client := drive.NewClientWithOptions(fake, drive.Options{Domain: "example.com"})
+
+	auditor := NewAuditorWithClient(&config.Config{}, client)
+	auditor.SetSample(5, 1)
+	result, err := auditor.AuditExternalSharing(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 40, result.TotalFiles)
+	assert.Equal(t, 5, result.SampledFiles)
+	assert.Equal(t, 5, result.FilesProcessed)
+}
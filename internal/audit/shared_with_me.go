@@ -0,0 +1,48 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrSharedWithMeUnsupported is returned by AuditSharedWithMe when the
+// drive client cannot list the files shared with the user.
+var ErrSharedWithMeUnsupported = errors.New("drive client does not support listing files shared with me")
+
+// AuditSharedWithMe reports the files in the authenticated user's "Shared
+// with me" collection whose owner is outside the primary domain and its
+// aliases: what outside accounts have shared into the organization. The
+// owner is classified like a share grantee, so this is the external
+// sharing audit seen from the recipient's side. It needs no domain-wide
+// delegation. TotalFiles counts every file shared with the user; files in
+// shared drives, which have no owner, are left out.
+func (a *Auditor) AuditSharedWithMe(ctx context.Context) (*AuditResult, error) {
+	lister, ok := a.driveClient.(SharedWithMeLister)
+	if !ok {
+		return nil, ErrSharedWithMeUnsupported
+	}
+
+	start, startStats := time.Now(), a.apiStats()
+
+	listing, err := a.listFrom(ctx, lister.ListSharedWithMe)
+	if err != nil {
+		return nil, err
+	}
+
+	result := a.auditListedFiles(ctx, listing)
+	external := make([]FileRecord, 0)
+	for _, rec := range result.FileRecords {
+		if a.isExternalOwner(rec.OwnerEmail) {
+			external = append(external, rec)
+		}
+	}
+	result.FileRecords = external
+
+	result.Timing.Total = time.Since(start)
+	result.Timing.API = a.apiStats().Sub(startStats)
+	return result, nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/config"
+	"github.com/leansecurity-co/gwork/internal/drive"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v3 "google.golang.org/api/drive/v3"
+)
+
+// sharedWithMeAPI serves its files only to "Shared with me" listings.
+type sharedWithMeAPI struct {
+	*pagedDriveAPI
+}
+
+func (s *sharedWithMeAPI) ListFiles(ctx context.Context, opts *drive.ListFilesOptions) (*drive.ListFilesResult, error) {
+	if opts.Corpora != "user" || !strings.Contains(opts.Query, "sharedWithMe = true") {
+		return &drive.ListFilesResult{}, nil
+	}
+	return s.pagedDriveAPI.ListFiles(ctx, opts)
+}
+
+func TestAuditSharedWithMe(t *testing.T) {
+	owned := func(id, owner string) *v3.File {
+		f := &v3.File{Id: id, Name: id + ".txt"}
+		if owner != "" {
+			f.Owners = []*v3.User{{EmailAddress: owner}}
+		}
+		return f
+	}
+	api := &sharedWithMeAPI{&pagedDriveAPI{
+		files: []*v3.File{
+			owned("from-colleague", "alice@example.com"),
+			owned("from-alias", "bob@example.org"),
+			owned("from-vendor", "vendor@partner.com"),
+			owned("from-gmail", "someone@gmail.com"),
+			owned("shared-drive", ""),
+		},
+		pageSize: 2,
+	}}
+	client := drive.NewClientWithAPI(api, "example.com", 2, false)
+	client.SetDomainAliases("example.org")
+
+	auditor := NewAuditorWithClient(&config.Config{}, client)
+	result, err := auditor.AuditSharedWithMe(context.Background())
+	require.NoError(t, err)
+
+	ids := make([]string, 0, len(result.FileRecords))
+	for _, rec := range result.FileRecords {
+		ids = append(ids, rec.FileID)
+	}
+	assert.ElementsMatch(t, []string{"from-vendor", "from-gmail"}, ids)
+	assert.Equal(t, 5, result.TotalFiles)
+}
+
+func TestAuditSharedWithMe_Unsupported(t *testing.T) {
+	auditor := NewAuditorWithClient(&config.Config{}, new(MockDriveClient))
+	_, err := auditor.AuditSharedWithMe(context.Background())
+	assert.ErrorIs(t, err, ErrSharedWithMeUnsupported)
+}
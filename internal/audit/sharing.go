@@ -5,66 +5,308 @@ package audit
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"sync"
+	"time"
 
+	"github.com/leansecurity-co/gwork/internal/config"
 	"github.com/leansecurity-co/gwork/internal/drive"
+	"github.com/leansecurity-co/gwork/internal/drivesyntax"
 )
 
 // AuditExternalSharing performs an external sharing audit.
+// Permissions are fetched concurrently according to audit.concurrency and
+// merged in file order, so the result is the same for any worker count.
 func (a *Auditor) AuditExternalSharing(ctx context.Context) (*AuditResult, error) {
-	files, err := a.driveClient.ListAllFiles(ctx)
+	return a.auditShares(ctx, a.isExternalShareOfInterest)
+}
+
+// SetExternalRolesOfInterest limits AuditExternalSharing to external
+// permissions with one of roles; permissions with other roles never become
+// records. No roles means every role.
+func (a *Auditor) SetExternalRolesOfInterest(roles ...string) {
+	if len(roles) == 0 {
+		a.externalRoles = nil
+		return
+	}
+	a.externalRoles = make(map[string]struct{}, len(roles))
+	for _, role := range roles {
+		a.externalRoles[role] = struct{}{}
+	}
+}
+
+// isExternalShareOfInterest reports whether perm is external and has a
+// role of interest.
+func (a *Auditor) isExternalShareOfInterest(perm drive.Permission) bool {
+	if a.externalRoles != nil {
+		if _, ok := a.externalRoles[perm.Role]; !ok {
+			return false
+		}
+	}
+	return a.driveClient.IsExternalShare(perm)
+}
+
+// AuditPublicShares performs a public exposure audit, reporting only
+// permissions that grant access to anyone.
+func (a *Auditor) AuditPublicShares(ctx context.Context) (*AuditResult, error) {
+	return a.auditShares(ctx, func(perm drive.Permission) bool {
+		return IsPublicPermissionType(perm.Type)
+	})
+}
+
+// AuditDomainShares reports files shared with everyone in the organization:
+// domain permissions granted to the primary domain or one of its aliases.
+// These shares are internal, so AuditExternalSharing leaves them out.
+func (a *Auditor) AuditDomainShares(ctx context.Context) (*AuditResult, error) {
+	return a.auditShares(ctx, a.isDomainWideShare)
+}
+
+// isDomainWideShare reports whether perm shares a file with the whole
+// internal domain.
+func (a *Auditor) isDomainWideShare(perm drive.Permission) bool {
+	return perm.Type == "domain" && !a.driveClient.IsExternalShare(perm)
+}
+
+// auditShares lists all files and records the permissions accepted by
+// include.
+func (a *Auditor) auditShares(ctx context.Context, include func(drive.Permission) bool) (*AuditResult, error) {
+	start, startStats := time.Now(), a.apiStats()
+
+	listing, err := a.listFiles(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list files: %w", err)
+		return nil, err
 	}
 
-	result := &AuditResult{
-		TotalFiles:     len(files),
-		ExternalShares: make([]ExternalShareRecord, 0),
-		Errors:         make([]error, 0),
+	result, err := a.auditListedShares(ctx, listing, include)
+	if err != nil {
+		return nil, err
 	}
+	result.Timing.Total = time.Since(start)
+	result.Timing.API = a.apiStats().Sub(startStats)
 
-	for _, file := range files {
-		select {
-		case <-ctx.Done():
-			return result, ctx.Err()
-		default:
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// auditListedShares fetches the permissions of the files in listing and
+// records those accepted by include. Total time and API stats are left to
+// the caller. The only error returned is that of appending to the
+// checkpoint report.
+func (a *Auditor) auditListedShares(ctx context.Context, listing *fileListing, include func(drive.Permission) bool) (*AuditResult, error) {
+	result := listing.newResult()
+	result.ExternalShares = make([]ExternalShareRecord, 0)
+	result.Errors = make([]error, 0)
+	files := listing.files
+
+	if a.checkpoint != nil {
+		if err := a.auditCheckpointed(ctx, result, files, include); err != nil {
+			return nil, err
 		}
+	} else {
+		fetchStart := time.Now()
+		outcomes := a.fetchPermissions(ctx, files)
+		result.Timing.FetchPermissions = time.Since(fetchStart)
+		a.recordSnapshots(result, files, outcomes)
+		a.mergeOutcomes(ctx, result, files, outcomes, include)
+		a.enrichShares(ctx, result, result.ExternalShares, make(map[string]groupMembers))
+	}
 
-		perms, err := a.driveClient.GetFilePermissions(ctx, file.ID)
-		if err != nil {
-			result.Errors = append(result.Errors, fmt.Errorf("file %s: %w", file.ID, err))
+	SortExternalShares(result.ExternalShares)
+	result.TotalExternalShares = len(result.ExternalShares)
+	return result, nil
+}
+
+// enrichShares sets the fields of records that their permissions do not
+// give: group members, flags, explanations and labels. groups caches the
+// groups resolved, as for expandGroups.
+func (a *Auditor) enrichShares(ctx context.Context, result *AuditResult, records []ExternalShareRecord, groups map[string]groupMembers) {
+	if a.groupResolver != nil {
+		a.expandGroups(ctx, result, records, groups)
+	}
+	MarkFlagged(records, a.flaggedDomains)
+	if a.explain {
+		a.explainShares(records)
+	}
+	a.classify(records)
+}
+
+// mergeOutcomes adds the permission outcomes of files to result, recording
+// those accepted by include. A single goroutine walks the outcomes in file
+// order.
+func (a *Auditor) mergeOutcomes(ctx context.Context, result *AuditResult, files []drive.FileInfo, outcomes []permissionOutcome, include func(drive.Permission) bool) {
+	for i, file := range files {
+		outcome := outcomes[i]
+		if !outcome.done {
 			continue
 		}
 
-		result.FilesProcessed++
+		// A failed fetch may still carry permissions from earlier pages;
+		// record the error but keep the partial shares. Running out of
+		// API call budget is reported once rather than per file.
+		switch {
+		case errors.Is(outcome.err, drive.ErrBudgetExceeded):
+			result.BudgetExceeded = true
+		case outcome.err != nil:
+			a.recordError(result, fmt.Errorf("file %s: %w", file.ID, outcome.err))
+		default:
+			result.FilesProcessed++
+		}
 
-		for _, perm := range perms {
-			if a.driveClient.IsExternalShare(perm) {
+		noteUnknownTypes(result, outcome.perms)
+		for _, perm := range outcome.perms {
+			if include(perm) {
 				record := permissionToRecord(file, perm)
+				record.DomainWide = a.isDomainWideShare(perm)
+				record.DriveName = a.driveName(ctx, file.DriveID)
 				result.ExternalShares = append(result.ExternalShares, record)
 			}
 		}
 	}
+}
 
-	result.TotalExternalShares = len(result.ExternalShares)
-	return result, nil
+// permissionOutcome holds the permission fetch result for a single file.
+type permissionOutcome struct {
+	perms  []drive.Permission
+	err    error
+	done   bool
+	cached bool // perms come from a file snapshot rather than the API
+}
+
+// fetchPermissions fetches permissions for all files using a worker pool.
+// Each outcome slot is written by exactly one worker and only read after all
+// workers have finished. Files not attempted before ctx is canceled are left
+// with done unset. Files unchanged since their snapshot reuse its
+// permissions without an API call.
+func (a *Auditor) fetchPermissions(ctx context.Context, files []drive.FileInfo) []permissionOutcome {
+	outcomes := make([]permissionOutcome, len(files))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < a.workers(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				perms, err := a.getFilePermissions(ctx, files[i].ID)
+				outcomes[i] = permissionOutcome{perms: perms, err: err, done: true}
+			}
+		}()
+	}
+
+feed:
+	for i := range files {
+		if perms, ok := a.cachedPermissions(files[i]); ok {
+			outcomes[i] = permissionOutcome{perms: perms, done: true, cached: true}
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			break feed
+		case jobs <- i:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return outcomes
+}
+
+// workers returns the number of permission fetch workers to run.
+func (a *Auditor) workers() int {
+	if a.config == nil || a.config.Audit.Concurrency < 1 {
+		return 1
+	}
+	return a.config.Audit.Concurrency
+}
+
+// google returns the Google settings of the audited domain, empty for an
+// auditor created without a config.
+func (a *Auditor) google() config.GoogleConfig {
+	if a.config == nil {
+		return config.GoogleConfig{}
+	}
+	return a.config.Google
+}
+
+// maxErrors returns the number of errors to keep in an AuditResult.
+func (a *Auditor) maxErrors() int {
+	if a.config == nil || a.config.Audit.MaxErrors < 1 {
+		return config.DefaultMaxErrors
+	}
+	return a.config.Audit.MaxErrors
+}
+
+// recordError adds err to result, or only counts it once result holds
+// maxErrors errors. Errors are recorded from a single goroutine.
+func (a *Auditor) recordError(result *AuditResult, err error) {
+	if len(result.Errors) >= a.maxErrors() {
+		result.DroppedErrorCount++
+		return
+	}
+	result.Errors = append(result.Errors, err)
+}
+
+// SortExternalShares sorts records by owner, then file name and file ID.
+// The sort is stable, so permissions of the same file keep their order.
+func SortExternalShares(records []ExternalShareRecord) {
+	sort.SliceStable(records, func(i, j int) bool {
+		if records[i].OwnerKey() != records[j].OwnerKey() {
+			return records[i].OwnerKey() < records[j].OwnerKey()
+		}
+		if records[i].FileName != records[j].FileName {
+			return records[i].FileName < records[j].FileName
+		}
+		return records[i].FileID < records[j].FileID
+	})
 }
 
 // permissionToRecord converts a file and permission to an ExternalShareRecord.
 func permissionToRecord(file drive.FileInfo, perm drive.Permission) ExternalShareRecord {
 	sharedWithDomain := perm.Domain
 	if sharedWithDomain == "" && perm.EmailAddress != "" {
-		sharedWithDomain = drive.ExtractDomain(perm.EmailAddress)
+		sharedWithDomain = drivesyntax.ExtractDomain(perm.EmailAddress)
 	}
 
 	return ExternalShareRecord{
 		OwnerEmail:       file.OwnerEmail,
+		OwnerName:        file.OwnerName,
 		FileID:           file.ID,
 		FileName:         file.Name,
 		SharedWithEmail:  perm.EmailAddress,
 		SharedWithDomain: sharedWithDomain,
 		PermissionType:   perm.Type,
 		PermissionRole:   perm.Role,
+		PermissionID:     perm.ID,
+		Trashed:          file.Trashed,
+		Inherited:        perm.Inherited,
+		InheritedFrom:    perm.InheritedFrom,
+		ExpirationTime:   perm.ExpirationTime,
+		GranteeDeleted:   perm.Deleted,
+		WebViewLink:      file.WebViewLink,
+		Location:         Location(file.DriveID),
 		// SharedDate is not available from Drive API
 	}
 }
+
+// IsPublicPermissionType reports whether a permission type grants access to
+// anyone. Drive uses the anyone type both for link sharing and for files
+// anyone can find, told apart by allowFileDiscovery.
+func IsPublicPermissionType(permType string) bool {
+	return permType == "anyone"
+}
+
+// CountPublicShares returns the number of records shared publicly.
+func CountPublicShares(records []ExternalShareRecord) int {
+	count := 0
+	for _, rec := range records {
+		if IsPublicPermissionType(rec.PermissionType) {
+			count++
+		}
+	}
+	return count
+}
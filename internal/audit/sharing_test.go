@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/leansecurity-co/gwork/internal/drive"
+	"github.com/leansecurity-co/gwork/internal/drivesyntax"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -25,6 +26,7 @@ func TestPermissionToRecord(t *testing.T) {
 				OwnerEmail: "owner@example.com",
 			},
 			permission: drive.Permission{
+				ID:           "12345678901234567890",
 				Type:         "user",
 				Role:         "reader",
 				EmailAddress: "external@other.com",
@@ -38,6 +40,7 @@ func TestPermissionToRecord(t *testing.T) {
 				SharedWithDomain: "other.com",
 				PermissionType:   "user",
 				PermissionRole:   "reader",
+				PermissionID:     "12345678901234567890",
 			},
 		},
 		{
@@ -172,7 +175,7 @@ func TestPermissionToRecord(t *testing.T) {
 }
 
 func TestExtractDomainFromEmail(t *testing.T) {
-	// This test verifies the drive.ExtractDomain function which is used by
+	// This test verifies the drivesyntax.ExtractDomain function which is used by
 	// permissionToRecord to extract domain from email addresses.
 	tests := []struct {
 		name     string
@@ -228,7 +231,7 @@ func TestExtractDomainFromEmail(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := drive.ExtractDomain(tt.email)
+			result := drivesyntax.ExtractDomain(tt.email)
 			assert.Equal(t, tt.expected, result)
 		})
 	}
@@ -290,3 +293,25 @@ func TestExternalShareRecord_Structure(t *testing.T) {
 	assert.Equal(t, "user", record.PermissionType)
 	assert.Equal(t, "reader", record.PermissionRole)
 }
+
+func TestCountPublicShares(t *testing.T) {
+	records := []ExternalShareRecord{
+		{PermissionType: "anyone"},
+		{PermissionType: "user"},
+		{PermissionType: "anyone"},
+		{PermissionType: "domain"},
+		{PermissionType: "group"},
+	}
+
+	assert.Equal(t, 2, CountPublicShares(records))
+	assert.Equal(t, 0, CountPublicShares(nil))
+}
+
+func TestPermissionToRecord_Trashed(t *testing.T) {
+	file := drive.FileInfo{ID: "file1", Name: "old.pdf", Trashed: true}
+	shareRecord := permissionToRecord(file, drive.Permission{Type: "anyone", Role: "reader"})
+	assert.True(t, shareRecord.Trashed)
+
+	fileRecord := fileInfoToRecord(file)
+	assert.True(t, fileRecord.Trashed)
+}
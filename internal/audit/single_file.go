@@ -0,0 +1,92 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrFileLookupUnsupported is returned by AuditSingleFile when the drive
+// client cannot fetch a file by ID.
+var ErrFileLookupUnsupported = errors.New("drive client does not support getting a file by ID")
+
+// SingleFileResult is the spot-check report of one file: its metadata and
+// every permission on it, internal ones included.
+type SingleFileResult struct {
+	File        FileRecord       `json:"file"`
+	Permissions []FilePermission `json:"permissions"`
+}
+
+// FilePermission is one permission in a SingleFileResult.
+type FilePermission struct {
+	ID             string    `json:"id"`
+	Type           string    `json:"type"`
+	Role           string    `json:"role"`
+	EmailAddress   string    `json:"email_address,omitempty"`
+	Domain         string    `json:"domain,omitempty"`
+	DisplayName    string    `json:"display_name,omitempty"`
+	Inherited      bool      `json:"inherited"`
+	InheritedFrom  string    `json:"inherited_from,omitempty"`
+	ExpirationTime time.Time `json:"expiration_time,omitzero"`
+
+	// External is set for permissions outside the primary domain and its
+	// aliases, including public ones.
+	External bool `json:"external"`
+	// Public is set for anyone permissions.
+	Public bool `json:"public"`
+	// Flagged is set when the grantee domain is in audit.flagged_domains.
+	Flagged bool `json:"flagged"`
+}
+
+// AuditSingleFile fetches the metadata and permissions of one file and
+// marks each permission as external, public or flagged. Ignored file IDs
+// and sampling do not apply.
+func (a *Auditor) AuditSingleFile(ctx context.Context, fileID string) (*SingleFileResult, error) {
+	getter, ok := a.driveClient.(FileGetter)
+	if !ok {
+		return nil, ErrFileLookupUnsupported
+	}
+
+	file, err := getter.GetFile(ctx, fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := parseFileInfo(file, a.config != nil && a.config.Audit.Strict)
+	if err != nil {
+		return nil, fmt.Errorf("file %s: %w", fileID, err)
+	}
+	record.DriveName = a.driveName(ctx, file.DriveID)
+
+	perms, err := a.driveClient.GetFilePermissions(ctx, fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SingleFileResult{File: record, Permissions: make([]FilePermission, 0, len(perms))}
+	for _, perm := range perms {
+		external := a.driveClient.IsExternalShare(perm)
+		domain := granteeDomain(permissionToRecord(file, perm))
+		_, flagged := a.flaggedDomains[domain]
+		result.Permissions = append(result.Permissions, FilePermission{
+			ID:             perm.ID,
+			Type:           perm.Type,
+			Role:           perm.Role,
+			EmailAddress:   perm.EmailAddress,
+			Domain:         perm.Domain,
+			DisplayName:    perm.DisplayName,
+			Inherited:      perm.Inherited,
+			InheritedFrom:  perm.InheritedFrom,
+			ExpirationTime: perm.ExpirationTime,
+			External:       external,
+			Public:         IsPublicPermissionType(perm.Type),
+			Flagged:        external && flagged && domain != "",
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/config"
+	"github.com/leansecurity-co/gwork/internal/drive"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v3 "google.golang.org/api/drive/v3"
+)
+
+func TestAuditSingleFile(t *testing.T) {
+	api := &pagedDriveAPI{
+		files: []*v3.File{
+			{Id: "other", Name: "other.txt"},
+			{Id: "target", Name: "plan.docx", Owners: []*v3.User{{EmailAddress: "alice@example.com"}}},
+		},
+		permissions: map[string][]*v3.Permission{
+			"target": {
+				{Id: "owner", Type: "user", Role: "owner", EmailAddress: "alice@example.com"},
+				{Id: "vendor", Type: "user", Role: "writer", EmailAddress: "bob@rival.com"},
+				{Id: "domain", Type: "domain", Role: "reader", Domain: "example.com"},
+				{Id: "link", Type: "anyone", Role: "reader"},
+			},
+		},
+		pageSize: 10,
+	}
+	client := drive.NewClientWithAPI(api, "example.com", 10, false)
+
+	auditor := NewAuditorWithClient(&config.Config{}, client)
+	auditor.SetFlaggedDomains("rival.com")
+	result, err := auditor.AuditSingleFile(context.Background(), "target")
+	require.NoError(t, err)
+
+	assert.Equal(t, "target", result.File.FileID)
+	assert.Equal(t, "plan.docx", result.File.FileName)
+	assert.Equal(t, "alice@example.com", result.File.OwnerEmail)
+
+	require.Len(t, result.Permissions, 4, "internal permissions are listed too")
+	flags := make(map[string][3]bool)
+	for _, p := range result.Permissions {
+		flags[p.ID] = [3]bool{p.External, p.Public, p.Flagged}
+	}
+	assert.Equal(t, map[string][3]bool{
+		"owner":  {false, false, false},
+		"vendor": {true, false, true},
+		"domain": {false, false, false},
+		"link":   {true, true, false},
+	}, flags)
+}
+
+func TestAuditSingleFile_NotFound(t *testing.T) {
+	client := drive.NewClientWithAPI(&pagedDriveAPI{pageSize: 10}, "example.com", 10, false)
+
+	auditor := NewAuditorWithClient(&config.Config{}, client)
+	_, err := auditor.AuditSingleFile(context.Background(), "missing")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing")
+}
+
+func TestAuditSingleFile_Unsupported(t *testing.T) {
+	auditor := NewAuditorWithClient(&config.Config{}, new(MockDriveClient))
+	_, err := auditor.AuditSingleFile(context.Background(), "target")
+	assert.ErrorIs(t, err, ErrFileLookupUnsupported)
+}
@@ -0,0 +1,61 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import "github.com/leansecurity-co/gwork/internal/drive"
+
+// FileSnapshot is the permissions of a file as of its modifiedTime, saved
+// by one run so the next can skip fetching them while the file is
+// unchanged.
+type FileSnapshot struct {
+	ModifiedTime string             `json:"modified_time"`
+	Permissions  []drive.Permission `json:"permissions"`
+}
+
+// SetFileSnapshots makes permission fetches reuse the snapshot of each file
+// whose modifiedTime equals the snapshot's, keyed by file ID. It also makes
+// share audits return the permissions they used in
+// AuditResult.FileSnapshots, to be saved for the next run. A nil map
+// disables both.
+func (a *Auditor) SetFileSnapshots(snapshots map[string]FileSnapshot) {
+	a.fileSnapshots = snapshots
+}
+
+// cachedPermissions returns the saved permissions of file, if it has not
+// been modified since they were saved.
+func (a *Auditor) cachedPermissions(file drive.FileInfo) ([]drive.Permission, bool) {
+	if file.ModifiedTime == "" {
+		return nil, false
+	}
+	snapshot, ok := a.fileSnapshots[file.ID]
+	if !ok || snapshot.ModifiedTime != file.ModifiedTime {
+		return nil, false
+	}
+	return snapshot.Permissions, true
+}
+
+// recordSnapshots adds the permissions of the files fetched in full or
+// reused from a snapshot to result.FileSnapshots, and counts the reused
+// ones in result.CachedFiles. It does nothing unless SetFileSnapshots was
+// called with a non-nil map.
+func (a *Auditor) recordSnapshots(result *AuditResult, files []drive.FileInfo, outcomes []permissionOutcome) {
+	if a.fileSnapshots == nil {
+		return
+	}
+	if result.FileSnapshots == nil {
+		result.FileSnapshots = make(map[string]FileSnapshot)
+	}
+	for i, outcome := range outcomes {
+		if outcome.cached {
+			result.CachedFiles++
+		}
+		if !outcome.done || outcome.err != nil || files[i].ModifiedTime == "" {
+			continue
+		}
+		result.FileSnapshots[files[i].ID] = FileSnapshot{
+			ModifiedTime: files[i].ModifiedTime,
+			Permissions:  outcome.perms,
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditExternalSharing_ReusesSnapshotsOfUnchangedFiles(t *testing.T) {
+	paged := newPagedDriveAPI(4, 5)
+	paged.failing = map[string]bool{}
+	for _, f := range paged.files {
+		f.ModifiedTime = "2025-01-01T00:00:00.000Z"
+	}
+	paged.files[3].ModifiedTime = ""
+
+	// First run: nothing to reuse, every file is fetched and saved.
+	first := newFlakyDriveAPI(paged, nil, nil)
+	auditor := newRetryTestAuditor(first, 0)
+	auditor.SetFileSnapshots(map[string]FileSnapshot{})
+	firstResult, err := auditor.AuditExternalSharing(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, first.calls, 4)
+	assert.Equal(t, 0, firstResult.CachedFiles)
+	assert.Len(t, firstResult.FileSnapshots, 3, "files without a modified time are not saved")
+
+	// Second run: file001 changed since the snapshot.
+	paged.files[1].ModifiedTime = "2025-02-01T00:00:00.000Z"
+	second := newFlakyDriveAPI(paged, nil, nil)
+	auditor = newRetryTestAuditor(second, 0)
+	auditor.SetFileSnapshots(firstResult.FileSnapshots)
+	secondResult, err := auditor.AuditExternalSharing(context.Background())
+	require.NoError(t, err)
+
+	assert.NotContains(t, second.calls, "file000")
+	assert.NotContains(t, second.calls, "file002")
+	assert.Contains(t, second.calls, "file001", "changed files are fetched")
+	assert.Contains(t, second.calls, "file003", "files without a modified time are fetched")
+	assert.Equal(t, 2, secondResult.CachedFiles)
+	assert.Equal(t, 4, secondResult.FilesProcessed)
+	assert.Equal(t, firstResult.ExternalShares, secondResult.ExternalShares)
+	assert.Equal(t, "2025-02-01T00:00:00.000Z", secondResult.FileSnapshots["file001"].ModifiedTime)
+}
+
+func TestAuditExternalSharing_NoSnapshotsWithoutCache(t *testing.T) {
+	paged := newPagedDriveAPI(2, 5)
+	paged.failing = map[string]bool{}
+	result, err := newRetryTestAuditor(paged, 0).AuditExternalSharing(context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, result.FileSnapshots)
+}
@@ -0,0 +1,176 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/leansecurity-co/gwork/internal/drive"
+)
+
+// AuditExternalSharingStream performs an external sharing audit like
+// AuditExternalSharing, but sends each record on out as soon as the
+// permissions of its file are fetched instead of collecting them, so memory
+// does not grow with the number of shares. Sends block while out is full,
+// which holds back the permission workers until the consumer catches up.
+//
+// Records are sent from several goroutines in no particular order. out is
+// closed once every worker has finished, so the consumer can range over
+// it. The returned result carries totals and errors but no ExternalShares.
+// File snapshots are reused but not recorded. If ctx is canceled, the
+// workers stop sending, drain and return, and the partial result is
+// returned with ctx.Err(); a consumer that stops reading must cancel ctx.
+func (a *Auditor) AuditExternalSharingStream(ctx context.Context, out chan<- ExternalShareRecord) (*AuditResult, error) {
+	defer close(out)
+	start, startStats := time.Now(), a.apiStats()
+
+	listing, err := a.listFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &shareStream{
+		auditor: a,
+		out:     out,
+		result:  listing.newResult(),
+		groups:  make(map[string]groupMembers),
+	}
+	s.result.Errors = make([]error, 0)
+
+	fetchStart := time.Now()
+	jobs := make(chan drive.FileInfo)
+	var wg sync.WaitGroup
+	for w := 0; w < a.workers(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for file := range jobs {
+				s.audit(ctx, file)
+			}
+		}()
+	}
+
+feed:
+	for _, file := range listing.files {
+		select {
+		case <-ctx.Done():
+			break feed
+		case jobs <- file:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	result := s.result
+	result.Timing.FetchPermissions = time.Since(fetchStart)
+	result.Timing.Total = time.Since(start)
+	result.Timing.API = a.apiStats().Sub(startStats)
+
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// shareStream holds the state shared by the workers of a streaming
+// sharing audit.
+type shareStream struct {
+	auditor *Auditor
+	out     chan<- ExternalShareRecord
+
+	// mu guards result and groups, and serializes classification.
+	mu     sync.Mutex
+	result *AuditResult
+	groups map[string]groupMembers
+}
+
+// audit fetches the permissions of file and sends a record for each
+// external share of interest. It returns early if ctx is canceled while a
+// send is blocked.
+func (s *shareStream) audit(ctx context.Context, file drive.FileInfo) {
+	a := s.auditor
+	perms, cached := a.cachedPermissions(file)
+	var err error
+	if !cached {
+		perms, err = a.getFilePermissions(ctx, file.ID)
+	}
+
+	// As in the batch audit, a failed fetch keeps the partial shares.
+	s.mu.Lock()
+	switch {
+	case errors.Is(err, drive.ErrBudgetExceeded):
+		s.result.BudgetExceeded = true
+	case err != nil:
+		a.recordError(s.result, fmt.Errorf("file %s: %w", file.ID, err))
+	default:
+		s.result.FilesProcessed++
+	}
+	if cached {
+		s.result.CachedFiles++
+	}
+	noteUnknownTypes(s.result, perms)
+	s.mu.Unlock()
+
+	for _, perm := range perms {
+		if !a.isExternalShareOfInterest(perm) {
+			continue
+		}
+		record := s.enrich(ctx, file, perm)
+		select {
+		case <-ctx.Done():
+			return
+		case s.out <- record:
+		}
+		s.mu.Lock()
+		s.result.TotalExternalShares++
+		s.mu.Unlock()
+	}
+}
+
+// enrich builds the record for perm on file and sets the fields the batch
+// audit sets on its whole result.
+func (s *shareStream) enrich(ctx context.Context, file drive.FileInfo, perm drive.Permission) ExternalShareRecord {
+	a := s.auditor
+	records := []ExternalShareRecord{permissionToRecord(file, perm)}
+	records[0].DomainWide = a.isDomainWideShare(perm)
+	records[0].DriveName = a.driveName(ctx, file.DriveID)
+	if a.groupResolver != nil && perm.Type == "group" && records[0].SharedWithEmail != "" {
+		s.group(ctx, records[0].SharedWithEmail).apply(&records[0])
+	}
+	MarkFlagged(records, a.flaggedDomains)
+	if a.explain {
+		a.explainShares(records)
+	}
+	// Classifiers need not be safe for concurrent use.
+	s.mu.Lock()
+	a.classify(records)
+	s.mu.Unlock()
+	return records[0]
+}
+
+// group returns the members of the group email, resolving each group once
+// per audit. Resolution is serialized, which keeps the calls to the
+// directory API to one per group.
+func (s *shareStream) group(ctx context.Context, email string) groupMembers {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if group, ok := s.groups[email]; ok {
+		return group
+	}
+	var group groupMembers
+	if s.auditor.isDirectoryGroup(email) {
+		var err error
+		if group, err = s.auditor.resolveGroup(ctx, email); err != nil {
+			s.auditor.recordError(s.result, err)
+		}
+	} else {
+		s.result.UnexpandedGroups++
+	}
+	s.groups[email] = group
+	return group
+}
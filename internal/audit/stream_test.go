@@ -0,0 +1,116 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/leansecurity-co/gwork/internal/config"
+	"github.com/leansecurity-co/gwork/internal/drive"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// assertNoGoroutineLeak fails t unless the number of goroutines drops back
+// to before within a second. It polls in the test goroutine, because
+// assert.Eventually runs its condition in a goroutine of its own.
+func assertNoGoroutineLeak(t *testing.T, before int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), before, "goroutines leaked")
+}
+
+func newStreamTestAuditor(concurrency int) *Auditor {
+	cfg := &config.Config{
+		Google: config.GoogleConfig{Domain: "example.com"},
+		Audit:  config.AuditConfig{Concurrency: concurrency},
+	}
+	client := drive.NewClientWithAPI(newPagedDriveAPI(120, 7), "example.com", 7, false)
+	return NewAuditorWithClient(cfg, client)
+}
+
+func TestAuditExternalSharingStream_EmitsEachRecordOnce(t *testing.T) {
+	batch, err := newStreamTestAuditor(1).AuditExternalSharing(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, batch.ExternalShares)
+
+	for _, concurrency := range []int{1, 8} {
+		before := runtime.NumGoroutine()
+
+		// A channel smaller than the worker pool makes the workers wait on
+		// the consumer.
+		out := make(chan ExternalShareRecord, 2)
+		done := make(chan []ExternalShareRecord)
+		go func() {
+			var records []ExternalShareRecord
+			for record := range out {
+				records = append(records, record)
+			}
+			done <- records
+		}()
+
+		result, err := newStreamTestAuditor(concurrency).AuditExternalSharingStream(context.Background(), out)
+		require.NoError(t, err)
+		records := <-done
+
+		SortExternalShares(records)
+		assert.Equal(t, batch.ExternalShares, records, "concurrency %d", concurrency)
+		assert.Equal(t, batch.TotalFiles, result.TotalFiles)
+		assert.Equal(t, batch.FilesProcessed, result.FilesProcessed)
+		assert.Equal(t, batch.TotalExternalShares, result.TotalExternalShares)
+		assert.ElementsMatch(t, batch.Errors, result.Errors)
+		assert.Nil(t, result.ExternalShares)
+		assertNoGoroutineLeak(t, before)
+	}
+}
+
+func TestAuditExternalSharingStream_CanceledConsumer(t *testing.T) {
+	before := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The consumer takes a few records, then cancels and stops reading,
+	// leaving the workers blocked on a full channel.
+	out := make(chan ExternalShareRecord)
+	received := make(chan int)
+	go func() {
+		n := 0
+		for range out {
+			n++
+			if n == 3 {
+				cancel()
+				break
+			}
+		}
+		received <- n
+	}()
+
+	result, err := newStreamTestAuditor(8).AuditExternalSharingStream(ctx, out)
+	assert.ErrorIs(t, err, context.Canceled)
+	require.NotNil(t, result)
+	assert.Equal(t, 3, <-received)
+	assert.Equal(t, 3, result.TotalExternalShares, "only records taken by the consumer are counted")
+
+	_, open := <-out
+	assert.False(t, open, "out is closed once the workers have drained")
+	assertNoGoroutineLeak(t, before)
+}
+
+func TestAuditExternalSharingStream_CanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	out := make(chan ExternalShareRecord, 10)
+	_, err := newStreamTestAuditor(4).AuditExternalSharingStream(ctx, out)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, open := <-out
+	assert.False(t, open)
+}
@@ -0,0 +1,29 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"time"
+
+	"github.com/leansecurity-co/gwork/internal/drive"
+)
+
+// Timing records where an audit spent its time.
+type Timing struct {
+	Total            time.Duration
+	ListFiles        time.Duration
+	FetchPermissions time.Duration // Zero for the files audit
+
+	// API holds the Drive API calls made during the audit. It is zero when
+	// the drive client does not implement StatsProvider.
+	API drive.Stats
+}
+
+// apiStats returns the drive client's API stats so far, if it counts them.
+func (a *Auditor) apiStats() drive.Stats {
+	if p, ok := a.driveClient.(StatsProvider); ok {
+		return p.Stats()
+	}
+	return drive.Stats{}
+}
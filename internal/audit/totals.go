@@ -0,0 +1,33 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+// Totals are the headline counts of an audit run.
+type Totals struct {
+	Files          int   `json:"files"`
+	ExternalShares int   `json:"external_shares"`
+	PublicShares   int   `json:"public_shares"`
+	Bytes          int64 `json:"bytes"`
+
+	// Filtered counts the records removed by each filter stage.
+	Filtered FilterStats `json:"filtered,omitempty"`
+}
+
+// Summarize returns the totals of results from one run. Audits in a run
+// list the same files, so Files is the largest TotalFiles rather than the
+// sum. Bytes is the size of the file records, so it is zero for sharing
+// audits. Filtered merges the filter stats of all results.
+func Summarize(results ...*AuditResult) Totals {
+	var totals Totals
+	for _, result := range results {
+		totals.Files = max(totals.Files, result.TotalFiles)
+		totals.ExternalShares += result.TotalExternalShares
+		totals.PublicShares += CountPublicShares(result.ExternalShares)
+		for _, rec := range result.FileRecords {
+			totals.Bytes += rec.SizeBytes
+		}
+		totals.Filtered.Merge(result.Filtered)
+	}
+	return totals
+}
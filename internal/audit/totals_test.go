@@ -0,0 +1,53 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/config"
+	"github.com/leansecurity-co/gwork/internal/drive"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarize(t *testing.T) {
+	mockClient := new(MockDriveClient)
+	mockClient.On("ListAllFiles", mock.Anything).Return([]drive.FileInfo{
+		{ID: "file1", Size: 100},
+		{ID: "file2", Size: 250},
+		{ID: "file3"},
+	}, nil)
+	mockClient.On("GetFilePermissions", mock.Anything, "file1").Return([]drive.Permission{
+		{ID: "p1", Type: "anyone", Role: "reader"},
+		{ID: "p2", Type: "user", Role: "writer", EmailAddress: "guest@partner.com"},
+	}, nil)
+	mockClient.On("GetFilePermissions", mock.Anything, "file2").Return([]drive.Permission{
+		{ID: "p3", Type: "user", Role: "reader", EmailAddress: "bob@example.com"},
+	}, nil)
+	mockClient.On("GetFilePermissions", mock.Anything, "file3").Return([]drive.Permission{
+		{ID: "anyoneWithLink", Type: "anyone", Role: "reader"},
+	}, nil)
+	mockClient.On("IsExternalShare", mock.MatchedBy(func(p drive.Permission) bool {
+		return p.EmailAddress == "bob@example.com"
+	})).Return(false)
+	mockClient.On("IsExternalShare", mock.Anything).Return(true)
+
+	auditor := NewAuditorWithClient(&config.Config{}, mockClient)
+	filesResult, err := auditor.AuditFiles(context.Background())
+	require.NoError(t, err)
+	sharingResult, err := auditor.AuditExternalSharing(context.Background())
+	require.NoError(t, err)
+
+	totals := Summarize(filesResult, sharingResult)
+	assert.Equal(t, Totals{Files: 3, ExternalShares: 3, PublicShares: 2, Bytes: 350}, totals)
+
+	// The counts match the records a full run writes.
+	assert.Equal(t, len(filesResult.FileRecords), totals.Files)
+	assert.Equal(t, len(sharingResult.ExternalShares), totals.ExternalShares)
+
+	assert.Equal(t, Totals{}, Summarize())
+}
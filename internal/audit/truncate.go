@@ -0,0 +1,98 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Truncate keeps at most n of the file and share records of r, so reports
+// stay within what downstream tools can load. Shares are kept before files,
+// highest risk first, and files largest first; ties keep report order, and
+// the records kept stay in report order. When records are dropped,
+// Truncated is set and UntruncatedRows holds the count before truncation.
+// Totals such as TotalExternalShares still count every record. n <= 0
+// means no limit.
+func (r *AuditResult) Truncate(n int) {
+	if n > 0 {
+		r.truncate(n)
+	}
+}
+
+// truncate keeps at most n records of r as Truncate does, but n = 0 keeps
+// none.
+func (r *AuditResult) truncate(n int) {
+	rows := len(r.FileRecords) + len(r.ExternalShares)
+	if rows <= n {
+		return
+	}
+
+	r.Truncated = true
+	r.UntruncatedRows = rows
+	r.ExternalShares = keepTop(r.ExternalShares, n, func(rec ExternalShareRecord) int64 {
+		return int64(rec.Risk)
+	})
+	r.FileRecords = keepTop(r.FileRecords, n-len(r.ExternalShares), func(rec FileRecord) int64 {
+		return rec.SizeBytes
+	})
+}
+
+// keepTop returns the n records with the highest rank, in their original
+// order. It returns records unchanged if there are no more than n.
+func keepTop[T any](records []T, n int, rank func(T) int64) []T {
+	if len(records) <= n {
+		return records
+	}
+	if n <= 0 {
+		return records[:0]
+	}
+
+	order := make([]int, len(records))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(rank(records[b]), rank(records[a]))
+	})
+	order = order[:n]
+	slices.Sort(order)
+
+	kept := make([]T, n)
+	for i, idx := range order {
+		kept[i] = records[idx]
+	}
+	return kept
+}
+
+// WithMaxRows returns a copy of r truncated to n records by Truncate, for
+// the record reports. r keeps every record, so history, baselines and
+// finding thresholds computed from it are unaffected by the limit.
+func (r *AuditResult) WithMaxRows(n int) *AuditResult {
+	truncated := *r
+	truncated.Truncate(n)
+	return &truncated
+}
+
+// WithMaxRowsTotal returns copies of results truncated to n records between
+// them, for reports written by one run. Each result is truncated as by
+// Truncate to the rows left by the results before it, so list first those
+// whose records matter most. Nil results stay nil, and n <= 0 means no
+// limit.
+func WithMaxRowsTotal(n int, results ...*AuditResult) []*AuditResult {
+	truncated := make([]*AuditResult, len(results))
+	left := n
+	for i, r := range results {
+		if r == nil {
+			continue
+		}
+		t := *r
+		if n > 0 {
+			t.truncate(left)
+			left -= len(t.FileRecords) + len(t.ExternalShares)
+		}
+		truncated[i] = &t
+	}
+	return truncated
+}
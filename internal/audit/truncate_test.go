@@ -0,0 +1,153 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditResult_Truncate(t *testing.T) {
+	shares := []ExternalShareRecord{
+		{FileID: "a", Risk: 10},
+		{FileID: "b", Risk: 90},
+		{FileID: "c", Risk: 50},
+		{FileID: "d", Risk: 90},
+	}
+	files := []FileRecord{
+		{FileID: "x", SizeBytes: 5},
+		{FileID: "y", SizeBytes: 500},
+		{FileID: "z", SizeBytes: 50},
+	}
+
+	tests := []struct {
+		name          string
+		result        AuditResult
+		limit         int
+		wantShares    []string
+		wantFiles     []string
+		wantTruncated bool
+		wantRows      int
+	}{
+		{
+			name:       "no limit",
+			result:     AuditResult{ExternalShares: shares},
+			limit:      0,
+			wantShares: []string{"a", "b", "c", "d"},
+		},
+		{
+			name:       "at the limit",
+			result:     AuditResult{ExternalShares: shares},
+			limit:      4,
+			wantShares: []string{"a", "b", "c", "d"},
+		},
+		{
+			name:          "keeps highest-risk shares in report order",
+			result:        AuditResult{ExternalShares: shares, TotalExternalShares: 4},
+			limit:         2,
+			wantShares:    []string{"b", "d"},
+			wantTruncated: true,
+			wantRows:      4,
+		},
+		{
+			name:          "keeps largest files in report order",
+			result:        AuditResult{FileRecords: files},
+			limit:         2,
+			wantFiles:     []string{"y", "z"},
+			wantTruncated: true,
+			wantRows:      3,
+		},
+		{
+			name:          "shares before files",
+			result:        AuditResult{ExternalShares: shares[:2], FileRecords: files},
+			limit:         3,
+			wantShares:    []string{"a", "b"},
+			wantFiles:     []string{"y"},
+			wantTruncated: true,
+			wantRows:      5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.result
+			result.Truncate(tt.limit)
+
+			var gotShares, gotFiles []string
+			for _, rec := range result.ExternalShares {
+				gotShares = append(gotShares, rec.FileID)
+			}
+			for _, rec := range result.FileRecords {
+				gotFiles = append(gotFiles, rec.FileID)
+			}
+			assert.Equal(t, tt.wantShares, gotShares)
+			assert.Equal(t, tt.wantFiles, gotFiles)
+			assert.Equal(t, tt.wantTruncated, result.Truncated)
+			assert.Equal(t, tt.wantRows, result.UntruncatedRows)
+			assert.Equal(t, tt.result.TotalExternalShares, result.TotalExternalShares, "totals still count every share")
+		})
+	}
+
+	assert.Equal(t, "a", shares[0].FileID, "the input slice is not reordered")
+}
+
+func TestAuditResult_WithMaxRows(t *testing.T) {
+	result := &AuditResult{
+		TotalExternalShares: 3,
+		ExternalShares:      []ExternalShareRecord{{FileID: "a", Risk: 10}, {FileID: "b", Risk: 90}, {FileID: "c", Risk: 50}},
+	}
+
+	report := result.WithMaxRows(1)
+	assert.True(t, report.Truncated)
+	assert.Equal(t, []ExternalShareRecord{{FileID: "b", Risk: 90}}, report.ExternalShares)
+	assert.Equal(t, 3, report.TotalExternalShares)
+
+	assert.False(t, result.Truncated, "the original result is not truncated")
+	assert.Len(t, result.ExternalShares, 3)
+}
+
+func TestWithMaxRowsTotal(t *testing.T) {
+	sharing := &AuditResult{
+		ExternalShares: []ExternalShareRecord{{FileID: "a", Risk: 10}, {FileID: "b", Risk: 90}},
+	}
+	files := &AuditResult{
+		FileRecords: []FileRecord{{FileID: "x", SizeBytes: 5}, {FileID: "y", SizeBytes: 500}, {FileID: "z", SizeBytes: 50}},
+	}
+
+	tests := []struct {
+		name       string
+		limit      int
+		wantShares int
+		wantFiles  []string
+	}{
+		{name: "no limit", limit: 0, wantShares: 2, wantFiles: []string{"x", "y", "z"}},
+		{name: "files get the rows left by shares", limit: 4, wantShares: 2, wantFiles: []string{"y", "z"}},
+		{name: "no rows left for files", limit: 2, wantShares: 2, wantFiles: []string{}},
+		{name: "shares alone over the limit", limit: 1, wantShares: 1, wantFiles: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reports := WithMaxRowsTotal(tt.limit, sharing, nil, files)
+			require.Len(t, reports, 3)
+			assert.Nil(t, reports[1])
+			assert.Len(t, reports[0].ExternalShares, tt.wantShares)
+
+			ids := []string{}
+			for _, rec := range reports[2].FileRecords {
+				ids = append(ids, rec.FileID)
+			}
+			assert.Equal(t, tt.wantFiles, ids)
+			if tt.limit > 0 {
+				assert.LessOrEqual(t, len(reports[0].ExternalShares)+len(reports[2].FileRecords), tt.limit)
+				assert.True(t, reports[2].Truncated)
+				assert.Equal(t, 3, reports[2].UntruncatedRows)
+			}
+		})
+	}
+
+	assert.Len(t, files.FileRecords, 3, "the original results are not truncated")
+}
@@ -6,35 +6,169 @@ package audit
 
 import "time"
 
+// OwnerDisplayPrefix marks owner keys derived from a display name because
+// the owner's email address was not returned.
+const OwnerDisplayPrefix = "display:"
+
 // FileRecord represents a file in the files-by-owner report.
 type FileRecord struct {
-	OwnerEmail   string
-	FileID       string
-	FileName     string
-	FileType     string
-	CreatedTime  time.Time
-	ModifiedTime time.Time
-	SizeBytes    int64
+	OwnerEmail   string    `json:"owner_email"`
+	OwnerName    string    `json:"owner_name,omitempty"`
+	FileID       string    `json:"file_id"`
+	FileName     string    `json:"file_name"`
+	FileType     string    `json:"file_type"`
+	CreatedTime  time.Time `json:"created_time,omitzero"`
+	ModifiedTime time.Time `json:"modified_time,omitzero"`
+	SizeBytes    int64     `json:"size_bytes"`
+	Trashed      bool      `json:"trashed"`
+	Location     string    `json:"location"`
+	// DriveName is the name of the shared drive the file lives in, empty
+	// for My Drive files and drives the admin cannot see.
+	DriveName string `json:"drive_name,omitempty"`
+
+	// ViewedByMeTime is when the impersonated admin last viewed the file,
+	// zero if never.
+	ViewedByMeTime time.Time `json:"viewed_by_me_time,omitzero"`
+
+	// LinkSharingEnabled reports whether the file has an anyone
+	// permission. It is nil unless audit.include_link_status is set, since
+	// it needs each file's permissions.
+	LinkSharingEnabled *bool `json:"link_sharing_enabled,omitempty"`
+
+	// SourceDomain is the audited domain the record came from. It is only
+	// set by multi-domain audits.
+	SourceDomain string `json:"source_domain,omitempty"`
 }
 
 // ExternalShareRecord represents an external sharing entry.
 type ExternalShareRecord struct {
-	OwnerEmail       string
-	FileID           string
-	FileName         string
-	SharedWithEmail  string
-	SharedWithDomain string
-	PermissionType   string
-	PermissionRole   string
-	SharedDate       time.Time // Note: Drive API doesn't provide this directly
+	OwnerEmail       string `json:"owner_email"`
+	OwnerName        string `json:"owner_name,omitempty"`
+	FileID           string `json:"file_id"`
+	FileName         string `json:"file_name"`
+	SharedWithEmail  string `json:"shared_with_email,omitempty"`
+	SharedWithDomain string `json:"shared_with_domain,omitempty"`
+	PermissionType   string `json:"permission_type"`
+	PermissionRole   string `json:"permission_role"`
+	// PermissionID is the Drive permission ID, for revoking the share with
+	// permissions.delete. Empty for records not built from a permission.
+	PermissionID   string    `json:"permission_id,omitempty"`
+	SharedDate     time.Time `json:"shared_date,omitzero"` // Note: Drive API doesn't provide this directly
+	Trashed        bool      `json:"trashed"`
+	Inherited      bool      `json:"inherited"`
+	InheritedFrom  string    `json:"inherited_from,omitempty"`
+	ExpirationTime time.Time `json:"expiration_time,omitzero"` // Zero when the share does not expire
+	WebViewLink    string    `json:"web_view_link,omitempty"`
+	Location       string    `json:"location"`
+	// DriveName is the name of the shared drive the file lives in, empty
+	// for My Drive files and drives the admin cannot see.
+	DriveName string `json:"drive_name,omitempty"`
+
+	// GranteeDeleted is set when Drive reports the grantee's account as
+	// deleted, leaving a stale share. Drive does not report this for every
+	// deleted account, so an unset flag does not prove the grantee exists.
+	GranteeDeleted bool `json:"grantee_deleted"`
+
+	// DomainWide is set for shares with everyone in the organization.
+	DomainWide bool `json:"domain_wide"`
+
+	// Flagged is set when the grantee domain is in audit.flagged_domains;
+	// FlagReason then names the matching domain.
+	Flagged    bool   `json:"flagged"`
+	FlagReason string `json:"flag_reason,omitempty"`
+
+	// Explanation says in plain words why the share was reported. It is
+	// only set when explanations are enabled.
+	Explanation string `json:"explanation,omitempty"`
+
+	// Label and Risk are set by the auditor's ShareClassifier.
+	Label string `json:"label,omitempty"`
+	Risk  int    `json:"risk"`
+
+	// GroupMemberCount and HasExternalMembers are only set for group shares
+	// when group expansion is enabled.
+	GroupMemberCount   int  `json:"group_member_count,omitempty"`
+	HasExternalMembers bool `json:"has_external_members,omitempty"`
+
+	// SourceDomain is the audited domain the record came from. It is only
+	// set by multi-domain audits.
+	SourceDomain string `json:"source_domain,omitempty"`
+}
+
+// OwnerKey returns the key used to group and sort the record by owner.
+func (r FileRecord) OwnerKey() string {
+	return ownerKey(r.OwnerEmail, r.OwnerName)
+}
+
+// OwnerKey returns the key used to group and sort the record by owner.
+func (r ExternalShareRecord) OwnerKey() string {
+	return ownerKey(r.OwnerEmail, r.OwnerName)
+}
+
+// ownerKey returns the owner email, falling back to a display-name sentinel
+// when the email is missing (e.g. deleted users) so such files stay
+// distinguishable from files with no owner at all.
+func ownerKey(email, name string) string {
+	if email == "" && name != "" {
+		return OwnerDisplayPrefix + name
+	}
+	return email
 }
 
 // AuditResult contains the results of an audit operation.
+//
+// For the sharing audit, ExternalShares is sorted by owner, file name and
+// file ID, and Errors follow file listing order. Both are independent of the
+// number of concurrent workers, so repeated runs over the same data produce
+// identical results.
+//
+// Errors holds at most audit.max_errors entries; further errors are only
+// counted in DroppedErrorCount. ErrorCount returns the total.
+//
+// When sampling is enabled, TotalFiles counts all listed files while records
+// only cover the SampledFiles in the sample; use Extrapolate to estimate
+// totals for all files.
 type AuditResult struct {
 	TotalFiles          int
+	SampledFiles        int // Zero unless sampling reduced the files audited
+	SuppressedCount     int // Files dropped by the ignore list, not in TotalFiles
 	TotalExternalShares int
 	FilesProcessed      int
 	Errors              []error
+	DroppedErrorCount   int  // Errors not kept in Errors once the cap was reached
+	BudgetExceeded      bool // The API call budget ran out; results are partial
+	OwnersMissing       int  // My Drive files listed, when Drive returned no owner for any of them
 	FileRecords         []FileRecord
 	ExternalShares      []ExternalShareRecord
+	Filtered            FilterStats // Records removed by filters after the audit
+	Truncated           bool        // Truncate dropped records; see UntruncatedRows
+	UntruncatedRows     int         // File and share records before Truncate
+	Timing              Timing
+
+	// UnexpandedGroups counts the groups of other domains shared with when
+	// audit.expand_groups is set. The Directory API only lists the members
+	// of groups in the organization's own Workspace, so they are skipped.
+	UnexpandedGroups int
+
+	// UnknownPermissionTypes lists, sorted, the permission types seen by a
+	// sharing audit that drive.IsKnownPermissionType does not recognize.
+	// Their shares are only reported with audit.strict_unknown.
+	UnknownPermissionTypes []string
+
+	// FileSnapshots holds the permissions used for each file, keyed by
+	// file ID, when the auditor has file snapshots set. CachedFiles counts
+	// the files whose permissions were reused rather than fetched.
+	FileSnapshots map[string]FileSnapshot
+	CachedFiles   int
+
+	// ResumedFiles counts the files skipped because a checkpointed audit
+	// resumed after them; see Auditor.SetCheckpoint. Their shares and
+	// errors are included from the checkpoint.
+	ResumedFiles int
+}
+
+// ErrorCount returns the number of errors encountered, including those
+// dropped once the error cap was reached.
+func (r *AuditResult) ErrorCount() int {
+	return len(r.Errors) + r.DroppedErrorCount
 }
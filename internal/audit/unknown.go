@@ -0,0 +1,29 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"slices"
+
+	"github.com/leansecurity-co/gwork/internal/drive"
+)
+
+// noteUnknownTypes adds the types of perms that drive.IsKnownPermissionType
+// does not recognize to result.UnknownPermissionTypes.
+func noteUnknownTypes(result *AuditResult, perms []drive.Permission) {
+	for _, perm := range perms {
+		if !drive.IsKnownPermissionType(perm.Type) {
+			result.UnknownPermissionTypes = addUnknownType(result.UnknownPermissionTypes, perm.Type)
+		}
+	}
+}
+
+// addUnknownType inserts t into the sorted types unless already present.
+func addUnknownType(types []string, t string) []string {
+	i, found := slices.BinarySearch(types, t)
+	if found {
+		return types
+	}
+	return slices.Insert(types, i, t)
+}
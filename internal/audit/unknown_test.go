@@ -0,0 +1,108 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/config"
+	"github.com/leansecurity-co/gwork/internal/drive"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v3 "google.golang.org/api/drive/v3"
+)
+
+// permissionsAPI is a DriveAPI serving fixed files and the permissions of
+// each file by ID.
+type permissionsAPI struct {
+	files []*v3.File
+	perms map[string][]*v3.Permission
+}
+
+func (p *permissionsAPI) ListFiles(_ context.Context, _ *drive.ListFilesOptions) (*drive.ListFilesResult, error) {
+	return &drive.ListFilesResult{Files: p.files}, nil
+}
+
+func (p *permissionsAPI) ListPermissions(_ context.Context, fileID string, _ *drive.ListPermissionsOptions) (*drive.ListPermissionsResult, error) {
+	return &drive.ListPermissionsResult{Permissions: p.perms[fileID]}, nil
+}
+
+func (p *permissionsAPI) GetFile(_ context.Context, _ string, _ *drive.GetFileOptions) (*v3.File, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (p *permissionsAPI) GetDrive(_ context.Context, _ string) (*v3.Drive, error) {
+	return nil, errors.New("not implemented")
+}
+
+// newUnknownTypeAPI returns files with a fabricated "audience" permission
+// type alongside known ones.
+func newUnknownTypeAPI() *permissionsAPI {
+	owner := []*v3.User{{EmailAddress: "alice@example.com"}}
+	return &permissionsAPI{
+		files: []*v3.File{
+			{Id: "f1", Name: "plan.doc", Owners: owner},
+			{Id: "f2", Name: "notes.doc", Owners: owner},
+		},
+		perms: map[string][]*v3.Permission{
+			"f1": {
+				{Id: "p1", Type: "user", Role: "reader", EmailAddress: "guest@partner.com"},
+				{Id: "p2", Type: "audience", Role: "reader", Domain: "partner.com"},
+			},
+			"f2": {
+				{Id: "p3", Type: "audience", Role: "writer"},
+				{Id: "p4", Type: "collection", Role: "reader"},
+			},
+		},
+	}
+}
+
+func TestAuditExternalSharing_UnknownPermissionTypes(t *testing.T) {
+	tests := []struct {
+		name      string
+		strict    bool
+		wantPerms []string
+	}{
+		{name: "default ignores unknown types", wantPerms: []string{"p1"}},
+		{name: "strict reports unknown types", strict: true, wantPerms: []string{"p1", "p2", "p3", "p4"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := drive.NewClientWithOptions(newUnknownTypeAPI(), drive.Options{Domain: "example.com", StrictUnknown: tt.strict})
+			cfg := &config.Config{Google: config.GoogleConfig{Domain: "example.com"}}
+
+			result, err := NewAuditorWithClient(cfg, client).AuditExternalSharing(context.Background())
+			require.NoError(t, err)
+
+			var got []string
+			for _, rec := range result.ExternalShares {
+				got = append(got, rec.PermissionID)
+			}
+			assert.ElementsMatch(t, tt.wantPerms, got)
+			assert.Equal(t, []string{"audience", "collection"}, result.UnknownPermissionTypes, "unknown types are surfaced either way")
+		})
+	}
+}
+
+func TestAuditExternalSharingStream_UnknownPermissionTypes(t *testing.T) {
+	client := drive.NewClientWithOptions(newUnknownTypeAPI(), drive.Options{Domain: "example.com"})
+	cfg := &config.Config{Google: config.GoogleConfig{Domain: "example.com"}, Audit: config.AuditConfig{Concurrency: 2}}
+
+	out := make(chan ExternalShareRecord, 10)
+	result, err := NewAuditorWithClient(cfg, client).AuditExternalSharingStream(context.Background(), out)
+	require.NoError(t, err)
+	assert.Len(t, out, 1)
+	assert.Equal(t, []string{"audience", "collection"}, result.UnknownPermissionTypes)
+}
+
+func TestMergeResults_UnknownPermissionTypes(t *testing.T) {
+	merged := mergeResults([]*AuditResult{
+		{UnknownPermissionTypes: []string{"audience", "collection"}},
+		{UnknownPermissionTypes: []string{"audience", "badge"}},
+	}, 0)
+	assert.Equal(t, []string{"audience", "badge", "collection"}, merged.UnknownPermissionTypes)
+}
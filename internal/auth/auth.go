@@ -6,12 +6,17 @@ package auth
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"os"
 
+	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
+	admin "google.golang.org/api/admin/directory/v1"
 	"google.golang.org/api/drive/v3"
 	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
 )
 
 // Scopes required for the audit tool.
@@ -21,12 +26,29 @@ var (
 		drive.DriveReadonlyScope,
 		drive.DriveMetadataReadonlyScope,
 	}
+
+	// DirectoryScopes are the OAuth scopes required to resolve group members.
+	DirectoryScopes = []string{
+		admin.AdminDirectoryGroupMemberReadonlyScope,
+	}
+
+	// SheetsScopes are the OAuth scopes required to write reports to
+	// Google Sheets.
+	SheetsScopes = []string{
+		sheets.SpreadsheetsScope,
+	}
 )
 
-// Authenticator handles service account authentication with domain-wide delegation.
+// Authenticator handles service account authentication with domain-wide
+// delegation. OAuth user credentials are also accepted in place of a
+// service account key; they act as the user who created them, without
+// delegation, which is enough for audit shared-with-me.
 type Authenticator struct {
 	serviceAccountFile string
 	adminEmail         string
+	quotaProject       string
+	driveEndpoint      string
+	transport          http.RoundTripper
 }
 
 // NewAuthenticator creates a new authenticator.
@@ -44,14 +66,133 @@ func NewAuthenticator(serviceAccountFile, adminEmail string) (*Authenticator, er
 	}, nil
 }
 
+// SetQuotaProject sets the Google Cloud project that API quota and billing
+// are attributed to. An empty project uses the service account's project.
+func (a *Authenticator) SetQuotaProject(project string) {
+	a.quotaProject = project
+}
+
+// SetDriveEndpoint points the Drive service at endpoint instead of
+// Google's, e.g. a local mock server for integration tests. Token requests
+// are not affected. An empty endpoint uses the default.
+func (a *Authenticator) SetDriveEndpoint(endpoint string) {
+	a.driveEndpoint = endpoint
+}
+
+// SetTransport routes API and token requests through a custom HTTP
+// transport, e.g. one built by NewTransport for a corporate proxy. A nil
+// transport uses the API client's default.
+func (a *Authenticator) SetTransport(transport http.RoundTripper) {
+	a.transport = transport
+}
+
 // GetDriveService creates an authenticated Drive service.
 func (a *Authenticator) GetDriveService(ctx context.Context) (*drive.Service, error) {
+	ts, err := a.tokenSource(ctx, DriveScopes...)
+	if err != nil {
+		return nil, err
+	}
+
+	service, err := drive.NewService(ctx, a.driveOptions(ts)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create drive service: %w", err)
+	}
+
+	return service, nil
+}
+
+// driveOptions returns the client options for the Drive service.
+func (a *Authenticator) driveOptions(ts oauth2.TokenSource) []option.ClientOption {
+	opts := a.clientOptions(ts)
+	if a.driveEndpoint != "" {
+		opts = append(opts, option.WithEndpoint(a.driveEndpoint))
+	}
+	return opts
+}
+
+// GetDirectoryService creates an authenticated Admin SDK Directory service.
+// The service account must also be authorized for DirectoryScopes.
+func (a *Authenticator) GetDirectoryService(ctx context.Context) (*admin.Service, error) {
+	ts, err := a.tokenSource(ctx, DirectoryScopes...)
+	if err != nil {
+		return nil, err
+	}
+
+	service, err := admin.NewService(ctx, a.clientOptions(ts)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create directory service: %w", err)
+	}
+
+	return service, nil
+}
+
+// GetSheetsService creates an authenticated Sheets service. Spreadsheets are
+// created in the admin's Drive. The service account must also be authorized
+// for SheetsScopes.
+func (a *Authenticator) GetSheetsService(ctx context.Context) (*sheets.Service, error) {
+	ts, err := a.tokenSource(ctx, SheetsScopes...)
+	if err != nil {
+		return nil, err
+	}
+
+	service, err := sheets.NewService(ctx, a.clientOptions(ts)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sheets service: %w", err)
+	}
+
+	return service, nil
+}
+
+// clientOptions returns the options used to construct API services. With a
+// custom transport the authenticated HTTP client is built here, since the
+// API client ignores its token source and quota project options when given
+// an HTTP client.
+func (a *Authenticator) clientOptions(ts oauth2.TokenSource) []option.ClientOption {
+	if a.transport != nil {
+		var base http.RoundTripper = a.transport
+		if a.quotaProject != "" {
+			base = &quotaProjectTransport{project: a.quotaProject, base: base}
+		}
+		client := &http.Client{Transport: &oauth2.Transport{Source: ts, Base: base}}
+		return []option.ClientOption{option.WithHTTPClient(client)}
+	}
+
+	opts := []option.ClientOption{option.WithTokenSource(ts)}
+	if a.quotaProject != "" {
+		opts = append(opts, option.WithQuotaProject(a.quotaProject))
+	}
+	return opts
+}
+
+// tokenSource creates a token source that impersonates the admin user, or
+// that acts as the user themselves for authorized_user credentials.
+// Tokens are fetched with ctx, so canceling it aborts pending fetches.
+func (a *Authenticator) tokenSource(ctx context.Context, scopes ...string) (oauth2.TokenSource, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	jsonCredentials, err := os.ReadFile(a.serviceAccountFile)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read service account file: %w", err)
 	}
 
-	config, err := google.JWTConfigFromJSON(jsonCredentials, DriveScopes...)
+	// Fetch tokens with ctx, through the custom transport if there is one.
+	base := a.transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: &contextTransport{ctx: ctx, base: base}})
+
+	if credentialsType(jsonCredentials) == authorizedUserType {
+		creds, err := google.CredentialsFromJSON(ctx, jsonCredentials, scopes...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse user credentials: %w", err)
+		}
+		return creds.TokenSource, nil
+	}
+
+	config, err := google.JWTConfigFromJSON(jsonCredentials, scopes...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse JWT config: %w", err)
 	}
@@ -59,12 +200,21 @@ func (a *Authenticator) GetDriveService(ctx context.Context) (*drive.Service, er
 	// Set Subject for domain-wide delegation impersonation
 	config.Subject = a.adminEmail
 
-	ts := config.TokenSource(ctx)
+	return config.TokenSource(ctx), nil
+}
 
-	service, err := drive.NewService(ctx, option.WithTokenSource(ts))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create drive service: %w", err)
-	}
+// authorizedUserType is the credentials type of OAuth user credentials,
+// such as those written by gcloud auth application-default login.
+const authorizedUserType = "authorized_user"
 
-	return service, nil
+// credentialsType returns the "type" field of a credentials file, or "" if
+// it cannot be read.
+func credentialsType(jsonCredentials []byte) string {
+	var f struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(jsonCredentials, &f); err != nil {
+		return ""
+	}
+	return f.Type
 }
@@ -0,0 +1,200 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/option"
+)
+
+func TestAuthenticator_ClientOptions(t *testing.T) {
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"})
+
+	tests := []struct {
+		name         string
+		quotaProject string
+		want         []option.ClientOption
+	}{
+		{
+			name: "no quota project",
+			want: []option.ClientOption{option.WithTokenSource(ts)},
+		},
+		{
+			name:         "quota project",
+			quotaProject: "billing-project",
+			want: []option.ClientOption{
+				option.WithTokenSource(ts),
+				option.WithQuotaProject("billing-project"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := NewAuthenticator("sa.json", "admin@example.com")
+			require.NoError(t, err)
+			a.SetQuotaProject(tt.quotaProject)
+
+			assert.Equal(t, tt.want, a.clientOptions(ts))
+		})
+	}
+}
+
+// writeServiceAccountFile writes a service account key whose tokens are
+// fetched from tokenURI.
+func writeServiceAccountFile(t *testing.T, tokenURI string) string {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	data, err := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"client_email": "gwork@project.iam.gserviceaccount.com",
+		"private_key":  string(keyPEM),
+		"token_uri":    tokenURI,
+	})
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "sa.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func TestAuthenticator_GetDriveService_CanceledContext(t *testing.T) {
+	a, err := NewAuthenticator(writeServiceAccountFile(t, "http://127.0.0.1:0/token"), "admin@example.com")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = a.GetDriveService(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestAuthenticator_TokenFetchHonorsContext(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	a, err := NewAuthenticator(writeServiceAccountFile(t, server.URL), "admin@example.com")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	ts, err := a.tokenSource(ctx, DriveScopes...)
+	require.NoError(t, err)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := ts.Token()
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		require.Error(t, err)
+		// oauth2 formats rather than wraps the transport error.
+		assert.Contains(t, err.Error(), context.DeadlineExceeded.Error())
+	case <-time.After(5 * time.Second):
+		t.Fatal("token fetch did not stop when the context expired")
+	}
+}
+
+func TestAuthenticator_AuthorizedUserCredentials(t *testing.T) {
+	var grantType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		grantType = r.PostForm.Get("grant_type")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"user-token","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	data, err := json.Marshal(map[string]string{
+		"type":          "authorized_user",
+		"client_id":     "client",
+		"client_secret": "secret",
+		"refresh_token": "refresh",
+		"token_uri":     server.URL,
+	})
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "user.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	a, err := NewAuthenticator(path, "me@example.com")
+	require.NoError(t, err)
+	ts, err := a.tokenSource(context.Background(), DriveScopes...)
+	require.NoError(t, err)
+
+	token, err := ts.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "user-token", token.AccessToken)
+	assert.Equal(t, "refresh_token", grantType, "user credentials are refreshed, not impersonated")
+}
+
+func TestAuthenticator_DriveOptions(t *testing.T) {
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"})
+
+	a, err := NewAuthenticator("sa.json", "admin@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, []option.ClientOption{option.WithTokenSource(ts)}, a.driveOptions(ts), "no endpoint by default")
+
+	a.SetDriveEndpoint("http://localhost:8080/drive/v3/")
+	assert.Equal(t, []option.ClientOption{
+		option.WithTokenSource(ts),
+		option.WithEndpoint("http://localhost:8080/drive/v3/"),
+	}, a.driveOptions(ts))
+	assert.Equal(t, []option.ClientOption{option.WithTokenSource(ts)}, a.clientOptions(ts), "other services keep their endpoint")
+}
+
+func TestAuthenticator_GetDriveService_Endpoint(t *testing.T) {
+	var apiPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/token" {
+			_, _ = w.Write([]byte(`{"access_token":"token","token_type":"Bearer","expires_in":3600}`))
+			return
+		}
+		apiPath = r.URL.Path
+		_, _ = w.Write([]byte(`{"files":[{"id":"file1"}]}`))
+	}))
+	defer server.Close()
+
+	a, err := NewAuthenticator(writeServiceAccountFile(t, server.URL+"/token"), "admin@example.com")
+	require.NoError(t, err)
+	a.SetDriveEndpoint(server.URL + "/drive/v3/")
+
+	service, err := a.GetDriveService(context.Background())
+	require.NoError(t, err)
+	list, err := service.Files.List().Do()
+	require.NoError(t, err)
+
+	assert.Equal(t, "/drive/v3/files", apiPath)
+	require.Len(t, list.Files, 1)
+	assert.Equal(t, "file1", list.Files[0].Id)
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// NewTransport returns an HTTP transport that sends requests through
+// proxyURL and trusts the certificates in caCertFile in addition to the
+// system roots. Either may be empty; the default transport's proxy settings
+// from the environment are kept when proxyURL is empty.
+func NewTransport(proxyURL, caCertFile string) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if proxyURL != "" {
+		u, err := url.Parse(proxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(u)
+	}
+
+	if caCertFile != "" {
+		pool, err := LoadCertPool(caCertFile)
+		if err != nil {
+			return nil, err
+		}
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		transport.TLSClientConfig.RootCAs = pool
+	}
+
+	return transport, nil
+}
+
+// LoadCertPool returns the system certificate pool with the PEM
+// certificates in file added. It fails if file contains no certificates.
+func LoadCertPool(file string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificate file: %w", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.New("CA certificate file contains no PEM certificates")
+	}
+	return pool, nil
+}
+
+// quotaProjectTransport sets the quota project header on each request. The
+// API client only adds it itself when it builds the HTTP client.
+type quotaProjectTransport struct {
+	project string
+	base    http.RoundTripper
+}
+
+// RoundTrip adds the X-Goog-User-Project header and sends the request.
+func (t *quotaProjectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("X-Goog-User-Project", t.project)
+	return t.base.RoundTrip(req)
+}
+
+// contextTransport sends each request with ctx. The JWT token source posts
+// without a request context, so token fetches would otherwise ignore
+// cancellation.
+type contextTransport struct {
+	ctx  context.Context
+	base http.RoundTripper
+}
+
+// RoundTrip sends req bound to the transport's context.
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.base.RoundTrip(req.WithContext(t.ctx))
+}
@@ -0,0 +1,129 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+// writeTestCA writes a self-signed CA certificate to a PEM file and returns
+// the file path and the parsed certificate.
+func writeTestCA(t *testing.T) (string, *x509.Certificate) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Corp Proxy CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:              []string{"proxy.corp.example"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	return path, cert
+}
+
+func TestNewTransport_Proxy(t *testing.T) {
+	transport, err := NewTransport("http://proxy.corp.example:3128", "")
+	require.NoError(t, err)
+	require.NotNil(t, transport.Proxy)
+
+	req, err := http.NewRequest(http.MethodGet, "https://www.googleapis.com/drive/v3/files", nil)
+	require.NoError(t, err)
+	proxy, err := transport.Proxy(req)
+	require.NoError(t, err)
+	assert.Equal(t, "http://proxy.corp.example:3128", proxy.String())
+	if transport.TLSClientConfig != nil {
+		assert.Nil(t, transport.TLSClientConfig.RootCAs, "system roots are used without a CA file")
+	}
+}
+
+func TestNewTransport_CACertFile(t *testing.T) {
+	path, cert := writeTestCA(t)
+
+	transport, err := NewTransport("", path)
+	require.NoError(t, err)
+	require.NotNil(t, transport.TLSClientConfig)
+	require.NotNil(t, transport.TLSClientConfig.RootCAs)
+
+	_, err = cert.Verify(x509.VerifyOptions{
+		Roots:   transport.TLSClientConfig.RootCAs,
+		DNSName: "proxy.corp.example",
+	})
+	assert.NoError(t, err, "the custom CA is trusted")
+}
+
+func TestNewTransport_InvalidCACertFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(path, []byte("not a certificate"), 0600))
+
+	_, err := NewTransport("", path)
+	assert.ErrorContains(t, err, "contains no PEM certificates")
+
+	_, err = NewTransport("", filepath.Join(t.TempDir(), "missing.pem"))
+	assert.ErrorContains(t, err, "failed to read CA certificate file")
+}
+
+// recordingTransport records the last request instead of sending it.
+type recordingTransport struct {
+	req *http.Request
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.req = req
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+}
+
+func TestAuthenticator_ClientOptions_Transport(t *testing.T) {
+	a, err := NewAuthenticator("sa.json", "admin@example.com")
+	require.NoError(t, err)
+	a.SetQuotaProject("billing-project")
+	a.SetTransport(&recordingTransport{})
+
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"})
+	assert.Len(t, a.clientOptions(ts), 1, "an HTTP client replaces the token source and quota project options")
+}
+
+func TestQuotaProjectTransport(t *testing.T) {
+	base := &recordingTransport{}
+	client := &http.Client{Transport: &oauth2.Transport{
+		Source: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"}),
+		Base:   &quotaProjectTransport{project: "billing-project", base: base},
+	}}
+
+	resp, err := client.Get("https://www.googleapis.com/drive/v3/files")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	require.NotNil(t, base.req)
+	assert.Equal(t, "billing-project", base.req.Header.Get("X-Goog-User-Project"))
+	assert.Equal(t, "Bearer token", base.req.Header.Get("Authorization"))
+}
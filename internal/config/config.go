@@ -6,9 +6,12 @@ package config
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/leansecurity-co/gwork/internal/drivesyntax"
 	"github.com/spf13/viper"
 	"gopkg.in/yaml.v3"
 )
@@ -18,38 +21,180 @@ type Config struct {
 	Google GoogleConfig `yaml:"google" mapstructure:"google"`
 	Audit  AuditConfig  `yaml:"audit" mapstructure:"audit"`
 	Output OutputConfig `yaml:"output" mapstructure:"output"`
+	Alert  AlertConfig  `yaml:"alert" mapstructure:"alert"`
+	// DefaultCommand is the audit subcommand, e.g. "all", run by gwork
+	// without a subcommand. Empty prints the help text.
+	DefaultCommand string `yaml:"default_command" mapstructure:"default_command"`
+
+	// source is the path of the config file the values were read from.
+	source string
+	// domainDerived is set when google.domain was taken from
+	// google.admin_email.
+	domainDerived bool
 }
 
 // GoogleConfig contains Google API configuration.
 type GoogleConfig struct {
-	ServiceAccountFile string `yaml:"service_account_file" mapstructure:"service_account_file"`
-	AdminEmail         string `yaml:"admin_email" mapstructure:"admin_email"`
-	Domain             string `yaml:"domain" mapstructure:"domain"`
+	ServiceAccountFile string   `yaml:"service_account_file" mapstructure:"service_account_file"`
+	AdminEmail         string   `yaml:"admin_email" mapstructure:"admin_email"`
+	Domain             string   `yaml:"domain" mapstructure:"domain"`
+	DomainAliases      []string `yaml:"domain_aliases" mapstructure:"domain_aliases"`
+	// InternalEmailRegex, when set, decides which user and group emails
+	// are internal instead of their domain: addresses matching it are
+	// internal and all others external, e.g. `-staff@example\.com$`.
+	InternalEmailRegex string `yaml:"internal_email_regex" mapstructure:"internal_email_regex"`
+	QuotaProject       string `yaml:"quota_project" mapstructure:"quota_project"`
+	// ProxyURL routes Google API traffic through an HTTP proxy, e.g.
+	// "http://proxy.corp:3128". Empty uses the HTTPS_PROXY environment.
+	ProxyURL string `yaml:"proxy_url" mapstructure:"proxy_url"`
+	// CACertFile is a PEM bundle of extra CAs to trust, for proxies that
+	// intercept TLS.
+	CACertFile string `yaml:"ca_cert_file" mapstructure:"ca_cert_file"`
+	// APIEndpoint replaces the Drive API base URL, e.g.
+	// "http://localhost:8080/drive/v3/" for a mock server or emulator.
+	// Empty uses Google's endpoint.
+	APIEndpoint string `yaml:"api_endpoint" mapstructure:"api_endpoint"`
+	// Domains lists several Workspace domains for gwork audit all to audit
+	// in parallel and merge into one report, each by impersonating its own
+	// admin with the shared service account. When set, it replaces Domain,
+	// AdminEmail and DomainAliases.
+	Domains []DomainConfig `yaml:"domains" mapstructure:"domains"`
+}
+
+// DomainConfig is one of the domains audited by a multi-domain run.
+type DomainConfig struct {
+	Domain        string   `yaml:"domain" mapstructure:"domain"`
+	AdminEmail    string   `yaml:"admin_email" mapstructure:"admin_email"`
+	DomainAliases []string `yaml:"domain_aliases" mapstructure:"domain_aliases"`
 }
 
 // AuditConfig contains audit-specific configuration.
 type AuditConfig struct {
-	IncludeSharedDrives bool  `yaml:"include_shared_drives" mapstructure:"include_shared_drives"`
-	PageSize            int64 `yaml:"page_size" mapstructure:"page_size"`
+	IncludeSharedDrives bool     `yaml:"include_shared_drives" mapstructure:"include_shared_drives"`
+	PageSize            int64    `yaml:"page_size" mapstructure:"page_size"`
+	Corpora             string   `yaml:"corpora" mapstructure:"corpora"`
+	DriveIDs            []string `yaml:"drive_ids" mapstructure:"drive_ids"`
+	IncludeTrashed      bool     `yaml:"include_trashed" mapstructure:"include_trashed"`
+	// IncludeLinkStatus fetches each file's permissions during a files
+	// audit to report whether link sharing is enabled.
+	IncludeLinkStatus bool `yaml:"include_link_status" mapstructure:"include_link_status"`
+	Concurrency       int  `yaml:"concurrency" mapstructure:"concurrency"`
+	// DomainConcurrency is the number of google.domains audited at once.
+	// Zero audits them one at a time.
+	DomainConcurrency int `yaml:"domain_concurrency" mapstructure:"domain_concurrency"`
+	MaxErrors         int `yaml:"max_errors" mapstructure:"max_errors"`
+	// MaxAPICalls caps the Drive API calls made per audit; 0 means no limit.
+	MaxAPICalls int64 `yaml:"max_api_calls" mapstructure:"max_api_calls"`
+	// MaxQPS caps the Drive API calls started per second; 0 means no cap.
+	// The rate is lowered further while Drive returns rate-limit errors.
+	MaxQPS           int      `yaml:"max_qps" mapstructure:"max_qps"`
+	FileFields       string   `yaml:"file_fields" mapstructure:"file_fields"`
+	PermissionFields string   `yaml:"permission_fields" mapstructure:"permission_fields"`
+	ExpandGroups     bool     `yaml:"expand_groups" mapstructure:"expand_groups"`
+	Strict           bool     `yaml:"strict" mapstructure:"strict"`
+	IgnoreFileIDs    []string `yaml:"ignore_file_ids" mapstructure:"ignore_file_ids"`
+	IgnoreFileList   string   `yaml:"ignore_file_list" mapstructure:"ignore_file_list"`
+	// StrictUnknown reports shares with permission types gwork does not
+	// recognize as external instead of ignoring them as internal.
+	StrictUnknown bool `yaml:"strict_unknown" mapstructure:"strict_unknown"`
+	// FlaggedDomains lists sensitive grantee domains, such as competitors,
+	// whose shares are marked as flagged.
+	FlaggedDomains []string `yaml:"flagged_domains" mapstructure:"flagged_domains"`
+	// ChunkByOwner lists and writes the files report one owner at a time,
+	// bounding memory by the largest owner. CSV only.
+	ChunkByOwner bool `yaml:"chunk_by_owner" mapstructure:"chunk_by_owner"`
+	// Query is an advanced Drive search query, e.g. "'<folder ID>' in
+	// parents", that restricts the files audited. It is combined with the
+	// built clauses such as "trashed = false".
+	Query string `yaml:"query" mapstructure:"query"`
+	// ExternalRolesOfInterest limits the sharing audit to external
+	// permissions with one of these roles, e.g. writer and owner when
+	// external readers are acceptable. Empty means every role.
+	ExternalRolesOfInterest []string `yaml:"external_roles_of_interest" mapstructure:"external_roles_of_interest"`
+	// RetryStatusCodes lists the HTTP status codes of Drive API errors that
+	// are retried with exponential backoff. Empty disables retries.
+	RetryStatusCodes []int `yaml:"retry_status_codes" mapstructure:"retry_status_codes"`
+	// Incremental reuses the permissions saved by the previous sharing
+	// audit for files whose modifiedTime has not changed, instead of
+	// fetching them again. It needs output.history_file, next to which the
+	// permissions are saved.
+	Incremental bool `yaml:"incremental" mapstructure:"incremental"`
 }
 
 // OutputConfig contains output formatting configuration.
 type OutputConfig struct {
-	Format    string `yaml:"format" mapstructure:"format"`
-	Directory string `yaml:"directory" mapstructure:"directory"`
+	Format      string `yaml:"format" mapstructure:"format"`
+	Directory   string `yaml:"directory" mapstructure:"directory"`
+	HistoryFile string `yaml:"history_file" mapstructure:"history_file"`
+	JSONIndent  bool   `yaml:"json_indent" mapstructure:"json_indent"`
+	// JSONFields limits each object in json and ndjson reports to these
+	// fields, in this order. Empty keeps every field.
+	JSONFields []string `yaml:"json_fields" mapstructure:"json_fields"`
+	// Delimiter separates CSV fields: a single character, or "tab" (also
+	// "\t") for tab-separated .tsv output. Empty means a comma.
+	Delimiter string `yaml:"delimiter" mapstructure:"delimiter"`
+	// SplitByOwner writes one files report per owner under files/ plus an
+	// index instead of a single files_by_owner report. CSV only.
+	SplitByOwner bool `yaml:"split_by_owner" mapstructure:"split_by_owner"`
+	// RoleDistribution writes a role_distribution report alongside sharing
+	// reports.
+	RoleDistribution bool `yaml:"role_distribution" mapstructure:"role_distribution"`
+	// File is the path of the report written by single-report commands,
+	// e.g. "reports/sharing.xlsx". It replaces Directory and the default
+	// report name; with format auto its extension picks the format.
+	File string `yaml:"file" mapstructure:"file"`
+	// MaxRowsPerFile splits CSV reports with more records into numbered
+	// segments, e.g. files_by_owner.001.csv, each with the header. Zero
+	// means no limit.
+	MaxRowsPerFile int `yaml:"max_rows_per_file" mapstructure:"max_rows_per_file"`
+	// HeaderLabels replaces the names of report columns in the header row
+	// of CSV, XLSX and Google Sheets reports, keyed by column name, e.g.
+	// owner_email: "Propriétaire". Columns without a label keep their name,
+	// and JSON keys are never changed.
+	HeaderLabels map[string]string `yaml:"header_labels" mapstructure:"header_labels"`
+	// LabelsFile is a YAML file of header labels, applied over HeaderLabels.
+	LabelsFile string `yaml:"labels_file" mapstructure:"labels_file"`
+	// SortOwnersBy orders owners in file reports, split outputs and the
+	// owners report: email, bytes or count, the largest first. Empty sorts
+	// file reports by email and the owners report by count.
+	SortOwnersBy string `yaml:"sort_owners_by" mapstructure:"sort_owners_by"`
+	// SortFilesBy orders the files of each owner in file reports: name,
+	// size (largest first) or modified (newest first). Empty means name.
+	SortFilesBy string `yaml:"sort_files_by" mapstructure:"sort_files_by"`
+}
+
+// AlertConfig sets thresholds on the findings of sharing audits. A count
+// above its threshold is a breach, highlighted in the run summary. Unset
+// thresholds are not checked; zero allows none.
+type AlertConfig struct {
+	MaxExternalShares *int `yaml:"max_external_shares,omitempty" mapstructure:"max_external_shares"`
+	MaxPublicShares   *int `yaml:"max_public_shares,omitempty" mapstructure:"max_public_shares"`
+	// MaxExternalWriters limits the external shares that can edit, i.e.
+	// with the writer role or above.
+	MaxExternalWriters *int `yaml:"max_external_writers,omitempty" mapstructure:"max_external_writers"`
+	// FailOnBreach exits with code 4, as --fail-above does, when any
+	// threshold is breached.
+	FailOnBreach bool `yaml:"fail_on_breach" mapstructure:"fail_on_breach"`
 }
 
-// Load reads and parses the configuration file.
+// StdinPath is the config path that reads the configuration from stdin.
+const StdinPath = "-"
+
+// Load reads and parses the configuration file. When configPath is empty,
+// .gwork.yaml is searched for in the current directory and then the home
+// directory, falling back to gwork/config.yaml in the user config directory.
+// When configPath is StdinPath, YAML is read from stdin instead.
 func Load(configPath string) (*Config, error) {
-	v := viper.New()
-	setDefaults(v)
+	if configPath == StdinPath {
+		return LoadReader(os.Stdin)
+	}
 
-	v.SetConfigName(".gwork")
-	v.SetConfigType("yaml")
+	v := newViper()
 
 	if configPath != "" {
 		v.SetConfigFile(configPath)
 	} else {
+		v.SetConfigName(".gwork")
 		v.AddConfigPath(".")
 		homeDir, err := os.UserHomeDir()
 		if err == nil {
@@ -61,12 +206,51 @@ func Load(configPath string) (*Config, error) {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
 			return nil, fmt.Errorf("failed to read config file: %w", err)
 		}
+
+		// Fall back to the user config directory, which uses a different
+		// file name than the search paths above.
+		if path := findUserConfigFile(); path != "" {
+			v.SetConfigFile(path)
+			if err := v.ReadInConfig(); err != nil {
+				return nil, fmt.Errorf("failed to read config file: %w", err)
+			}
+		}
+	}
+
+	return decode(v, v.ConfigFileUsed())
+}
+
+// LoadReader reads and parses YAML configuration from r, skipping the
+// config file search. Defaults and validation apply as in Load.
+func LoadReader(r io.Reader) (*Config, error) {
+	v := newViper()
+
+	if err := v.ReadConfig(r); err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
 	}
 
+	return decode(v, StdinPath)
+}
+
+// newViper creates a viper instance with defaults and environment
+// overrides for YAML config.
+func newViper() *viper.Viper {
+	v := viper.New()
+	setDefaults(v)
+	bindEnv(v)
+	v.SetConfigType("yaml")
+	return v
+}
+
+// decode unmarshals and validates the configuration held by v.
+func decode(v *viper.Viper, source string) (*Config, error) {
 	var cfg Config
 	if err := v.Unmarshal(&cfg); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
+	cfg.source = source
+	cfg.deriveDomain()
+	cfg.normalizeDomainLists()
 
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
@@ -75,6 +259,85 @@ func Load(configPath string) (*Config, error) {
 	return &cfg, nil
 }
 
+// userConfigFiles returns the candidate config files in the user config
+// directory: $XDG_CONFIG_HOME/gwork/config.yaml when XDG_CONFIG_HOME is set,
+// then the platform default (e.g. ~/.config on Linux, ~/Library/Application
+// Support on macOS, %AppData% on Windows).
+func userConfigFiles() []string {
+	var dirs []string
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		dirs = append(dirs, xdg)
+	}
+	if dir, err := os.UserConfigDir(); err == nil && (len(dirs) == 0 || dirs[0] != dir) {
+		dirs = append(dirs, dir)
+	}
+
+	files := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		files = append(files, filepath.Join(dir, "gwork", "config.yaml"))
+	}
+	return files
+}
+
+// findUserConfigFile returns the first existing user config file, or an
+// empty string if there is none.
+func findUserConfigFile() string {
+	for _, path := range userConfigFiles() {
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
+
+// Source returns the path of the config file that was loaded, StdinPath if
+// it was read from stdin, or an empty string if no config file was found.
+func (c *Config) Source() string {
+	return c.source
+}
+
+// DomainDerived reports whether google.domain was omitted and taken from
+// the domain of google.admin_email.
+func (c *Config) DomainDerived() bool {
+	return c.domainDerived
+}
+
+// AuditedDomain names the domains an audit covers: google.domains joined
+// by commas when set, else google.domain. It is the same on every run of
+// a config, so it keys the history, file snapshots and baseline.
+func (c *Config) AuditedDomain() string {
+	if len(c.Google.Domains) == 0 {
+		return c.Google.Domain
+	}
+	names := make([]string, len(c.Google.Domains))
+	for i, d := range c.Google.Domains {
+		names[i] = d.Domain
+	}
+	return strings.Join(names, ",")
+}
+
+// ForDomain returns a copy of the configuration that audits d alone, with
+// google.domain, admin_email and domain_aliases taken from d.
+func (c *Config) ForDomain(d DomainConfig) *Config {
+	cfg := *c
+	cfg.Google.Domain = d.Domain
+	cfg.Google.AdminEmail = d.AdminEmail
+	cfg.Google.DomainAliases = d.DomainAliases
+	cfg.Google.Domains = nil
+	cfg.domainDerived = false
+	return &cfg
+}
+
+// deriveDomain defaults google.domain to the domain of google.admin_email
+// when it is not set. An explicit domain is kept.
+func (c *Config) deriveDomain() {
+	if c.Google.Domain != "" {
+		return
+	}
+	c.Google.Domain = drivesyntax.ExtractDomain(c.Google.AdminEmail)
+	c.domainDerived = c.Google.Domain != ""
+}
+
 // Save writes the configuration to a file.
 func (c *Config) Save(path string) error {
 	dir := filepath.Dir(path)
@@ -84,13 +347,32 @@ func (c *Config) Save(path string) error {
 		}
 	}
 
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	if err := c.Write(file); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	return nil
+}
+
+// Write writes the configuration as YAML to w.
+func (c *Config) Write(w io.Writer) error {
 	data, err := yaml.Marshal(c)
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0600); err != nil {
-		return fmt.Errorf("failed to write config file: %w", err)
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
 	}
 
 	return nil
@@ -0,0 +1,303 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeConfigFile writes a minimal valid config for the given domain.
+func writeConfigFile(t *testing.T, path, domain string) {
+	t.Helper()
+
+	saFile := filepath.Join(t.TempDir(), "sa.json")
+	require.NoError(t, os.WriteFile(saFile, []byte("{}"), 0o600))
+
+	content := fmt.Sprintf(`google:
+  service_account_file: %q
+  admin_email: admin@%s
+  domain: %s
+`, saFile, domain, domain)
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+// isolateConfigSearch points the working, home and XDG config directories
+// at empty temp dirs and returns the XDG config dir.
+func isolateConfigSearch(t *testing.T) (workDir, xdgDir string) {
+	t.Helper()
+
+	workDir = t.TempDir()
+	xdgDir = t.TempDir()
+	t.Chdir(workDir)
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", xdgDir)
+	return workDir, xdgDir
+}
+
+func TestLoad_XDGConfigHome(t *testing.T) {
+	_, xdgDir := isolateConfigSearch(t)
+	path := filepath.Join(xdgDir, "gwork", "config.yaml")
+	writeConfigFile(t, path, "xdg.example.com")
+
+	cfg, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, "xdg.example.com", cfg.Google.Domain)
+	assert.Equal(t, path, cfg.Source())
+}
+
+func TestLoad_WorkingDirBeforeXDG(t *testing.T) {
+	workDir, xdgDir := isolateConfigSearch(t)
+	writeConfigFile(t, filepath.Join(xdgDir, "gwork", "config.yaml"), "xdg.example.com")
+	writeConfigFile(t, filepath.Join(workDir, ".gwork.yaml"), "local.example.com")
+
+	cfg, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, "local.example.com", cfg.Google.Domain)
+}
+
+func TestLoad_ExplicitPathBeforeXDG(t *testing.T) {
+	_, xdgDir := isolateConfigSearch(t)
+	writeConfigFile(t, filepath.Join(xdgDir, "gwork", "config.yaml"), "xdg.example.com")
+
+	explicit := filepath.Join(t.TempDir(), "custom.yaml")
+	writeConfigFile(t, explicit, "explicit.example.com")
+
+	cfg, err := Load(explicit)
+	require.NoError(t, err)
+	assert.Equal(t, "explicit.example.com", cfg.Google.Domain)
+}
+
+func TestUserConfigFiles(t *testing.T) {
+	xdgDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdgDir)
+
+	files := userConfigFiles()
+	require.NotEmpty(t, files)
+	assert.Equal(t, filepath.Join(xdgDir, "gwork", "config.yaml"), files[0])
+}
+
+func TestLoadReader(t *testing.T) {
+	saFile := filepath.Join(t.TempDir(), "sa.json")
+	require.NoError(t, os.WriteFile(saFile, []byte("{}"), 0o600))
+
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+		check   func(t *testing.T, cfg *Config)
+	}{
+		{
+			name: "valid config with defaults",
+			yaml: fmt.Sprintf(`google:
+  service_account_file: %q
+  admin_email: admin@example.com
+  domain: example.com
+audit:
+  page_size: 200
+`, saFile),
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, "example.com", cfg.Google.Domain)
+				assert.Equal(t, int64(200), cfg.Audit.PageSize)
+				assert.Equal(t, DefaultOutputFormat, cfg.Output.Format)
+				assert.Equal(t, StdinPath, cfg.Source())
+			},
+		},
+		{
+			name:    "malformed yaml",
+			yaml:    "google: [unclosed",
+			wantErr: "failed to read config",
+		},
+		{
+			name: "multiple domains",
+			yaml: fmt.Sprintf(`google:
+  service_account_file: %q
+  domains:
+    - domain: A.example
+      admin_email: admin@a.example
+    - domain: https://b.example/
+      admin_email: admin@b.example
+      domain_aliases: [B-Alias.example]
+`, saFile),
+			check: func(t *testing.T, cfg *Config) {
+				require.Len(t, cfg.Google.Domains, 2)
+				assert.Equal(t, DomainConfig{Domain: "a.example", AdminEmail: "admin@a.example"}, cfg.Google.Domains[0])
+				assert.Equal(t, "b.example", cfg.Google.Domains[1].Domain)
+				assert.Equal(t, []string{"b-alias.example"}, cfg.Google.Domains[1].DomainAliases)
+				assert.Equal(t, DefaultDomainConcurrency, cfg.Audit.DomainConcurrency)
+
+				b := cfg.ForDomain(cfg.Google.Domains[1])
+				assert.Equal(t, "b.example", b.Google.Domain)
+				assert.Equal(t, "admin@b.example", b.Google.AdminEmail)
+				assert.Equal(t, []string{"b-alias.example"}, b.Google.DomainAliases)
+				assert.Empty(t, b.Google.Domains)
+				assert.Len(t, cfg.Google.Domains, 2, "the original is unchanged")
+				assert.Equal(t, "a.example,b.example", cfg.AuditedDomain())
+				assert.Equal(t, "b.example", b.AuditedDomain())
+			},
+		},
+		{
+			name: "duplicate domain",
+			yaml: fmt.Sprintf(`google:
+  service_account_file: %q
+  domains:
+    - {domain: a.example, admin_email: admin@a.example}
+    - {domain: A.example, admin_email: other@a.example}
+`, saFile),
+			wantErr: "google.domains lists a.example more than once",
+		},
+		{
+			name: "domain without admin",
+			yaml: fmt.Sprintf(`google:
+  service_account_file: %q
+  domains:
+    - {domain: a.example}
+`, saFile),
+			wantErr: "google.domains[0].admin_email must be a valid email address",
+		},
+		{
+			name: "validation error",
+			yaml: fmt.Sprintf(`google:
+  service_account_file: %q
+  admin_email: not-an-email
+  domain: example.com
+`, saFile),
+			wantErr: "google.admin_email must be a valid email address",
+		},
+		{
+			name: "domain derived from admin email",
+			yaml: fmt.Sprintf(`google:
+  service_account_file: %q
+  admin_email: admin@corp.example.com
+`, saFile),
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, "corp.example.com", cfg.Google.Domain)
+				assert.True(t, cfg.DomainDerived())
+			},
+		},
+		{
+			name: "explicit domain overrides admin email",
+			yaml: fmt.Sprintf(`google:
+  service_account_file: %q
+  admin_email: admin@corp.example.com
+  domain: example.com
+`, saFile),
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, "example.com", cfg.Google.Domain)
+				assert.False(t, cfg.DomainDerived())
+			},
+		},
+		{
+			name: "domain not derivable",
+			yaml: fmt.Sprintf(`google:
+  service_account_file: %q
+  admin_email: admin
+`, saFile),
+			wantErr: "google.domain is required when it cannot be derived from google.admin_email",
+		},
+		{
+			name: "domain lists normalized",
+			yaml: fmt.Sprintf(`google:
+  service_account_file: %q
+  admin_email: admin@example.com
+  domain_aliases: [Example.ORG, " example.org ", https://example.net/]
+audit:
+  flagged_domains: [Gmail.com, gmail.com., rival.com]
+`, saFile),
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, []string{"example.org", "example.net"}, cfg.Google.DomainAliases)
+				assert.Equal(t, []string{"gmail.com", "rival.com"}, cfg.Audit.FlaggedDomains)
+			},
+		},
+		{
+			name: "invalid domain list entries",
+			yaml: fmt.Sprintf(`google:
+  service_account_file: %q
+  admin_email: admin@example.com
+  domain_aliases: [example.org, localhost]
+audit:
+  flagged_domains: ["rival .com"]
+`, saFile),
+			wantErr: `google.domain_aliases contains an invalid domain: "localhost": missing a top-level domain`,
+		},
+		{
+			name: "retry status codes default",
+			yaml: fmt.Sprintf(`google:
+  service_account_file: %q
+  admin_email: admin@example.com
+`, saFile),
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, []int{429, 500, 502, 503}, cfg.Audit.RetryStatusCodes)
+			},
+		},
+		{
+			name: "custom retry status codes",
+			yaml: fmt.Sprintf(`google:
+  service_account_file: %q
+  admin_email: admin@example.com
+audit:
+  retry_status_codes: [408, 429]
+`, saFile),
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, []int{408, 429}, cfg.Audit.RetryStatusCodes)
+			},
+		},
+		{
+			name:    "empty input",
+			yaml:    "",
+			wantErr: "google.service_account_file is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := LoadReader(strings.NewReader(tt.yaml))
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestLoad_EnvOverrides(t *testing.T) {
+	_, xdgDir := isolateConfigSearch(t)
+	path := filepath.Join(xdgDir, "gwork", "config.yaml")
+	writeConfigFile(t, path, "file.example.com")
+
+	t.Setenv("GWORK_GOOGLE_DOMAIN", "env.example.com")
+	t.Setenv("GWORK_AUDIT_PAGE_SIZE", "250")
+	t.Setenv("GWORK_AUDIT_DRIVE_IDS", "drive1,drive2")
+	t.Setenv("GWORK_OUTPUT_JSON_INDENT", "true")
+
+	cfg, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, "env.example.com", cfg.Google.Domain)
+	assert.Equal(t, int64(250), cfg.Audit.PageSize)
+	assert.Equal(t, []string{"drive1", "drive2"}, cfg.Audit.DriveIDs)
+	assert.True(t, cfg.Output.JSONIndent)
+	assert.Equal(t, DefaultConcurrency, cfg.Audit.Concurrency, "unset keys keep their defaults")
+}
+
+func TestLoad_EnvValidation(t *testing.T) {
+	_, xdgDir := isolateConfigSearch(t)
+	writeConfigFile(t, filepath.Join(xdgDir, "gwork", "config.yaml"), "example.com")
+	t.Setenv("GWORK_AUDIT_PAGE_SIZE", "5000")
+
+	_, err := Load("")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "audit.page_size must be between 1 and 1000")
+}
@@ -3,25 +3,64 @@
 
 package config
 
-import "github.com/spf13/viper"
+import (
+	"slices"
+
+	"github.com/spf13/viper"
+)
 
 const (
 	// DefaultPageSize is the default number of items per API page.
 	DefaultPageSize = 1000
 
+	// MinPageSize and MaxPageSize bound audit.page_size, as accepted by the
+	// Drive API.
+	MinPageSize = 1
+	MaxPageSize = 1000
+
+	// DefaultCorpora is the default Drive corpora to list files from.
+	DefaultCorpora = "domain"
+
+	// DefaultConcurrency is the default number of concurrent permission fetches.
+	DefaultConcurrency = 4
+
+	// MaxConcurrency is the maximum number of concurrent permission fetches.
+	MaxConcurrency = 64
+
+	// DefaultDomainConcurrency is the default number of google.domains
+	// audited at once.
+	DefaultDomainConcurrency = 2
+
+	// DefaultMaxErrors is the default number of per-file errors kept in
+	// memory during an audit.
+	DefaultMaxErrors = 1000
+
 	// DefaultOutputFormat is the default output format.
 	DefaultOutputFormat = "csv"
 
 	// DefaultOutputDirectory is the default output directory.
 	DefaultOutputDirectory = "./output"
+
+	// DefaultDelimiter is the default CSV field delimiter.
+	DefaultDelimiter = ","
 )
 
+// DefaultRetryStatusCodes are the HTTP status codes retried by default:
+// rate limiting and transient server errors.
+var DefaultRetryStatusCodes = []int{429, 500, 502, 503}
+
 // setDefaults sets default values in viper.
 func setDefaults(v *viper.Viper) {
 	v.SetDefault("audit.include_shared_drives", true)
 	v.SetDefault("audit.page_size", DefaultPageSize)
+	v.SetDefault("audit.corpora", DefaultCorpora)
+	v.SetDefault("audit.concurrency", DefaultConcurrency)
+	v.SetDefault("audit.domain_concurrency", DefaultDomainConcurrency)
+	v.SetDefault("audit.max_errors", DefaultMaxErrors)
+	v.SetDefault("audit.retry_status_codes", DefaultRetryStatusCodes)
 	v.SetDefault("output.format", DefaultOutputFormat)
 	v.SetDefault("output.directory", DefaultOutputDirectory)
+	v.SetDefault("output.delimiter", DefaultDelimiter)
 }
 
 // NewDefault creates a new Config with default values.
@@ -35,10 +74,16 @@ func NewDefault() *Config {
 		Audit: AuditConfig{
 			IncludeSharedDrives: true,
 			PageSize:            DefaultPageSize,
+			Corpora:             DefaultCorpora,
+			Concurrency:         DefaultConcurrency,
+			DomainConcurrency:   DefaultDomainConcurrency,
+			MaxErrors:           DefaultMaxErrors,
+			RetryStatusCodes:    slices.Clone(DefaultRetryStatusCodes),
 		},
 		Output: OutputConfig{
 			Format:    DefaultOutputFormat,
 			Directory: DefaultOutputDirectory,
+			Delimiter: DefaultDelimiter,
 		},
 	}
 }
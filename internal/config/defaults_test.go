@@ -21,6 +21,9 @@ func TestNewDefault(t *testing.T) {
 	// Test Audit config defaults
 	assert.Equal(t, true, cfg.Audit.IncludeSharedDrives, "IncludeSharedDrives should be true by default")
 	assert.Equal(t, int64(DefaultPageSize), cfg.Audit.PageSize, "PageSize should be DefaultPageSize")
+	assert.Equal(t, DefaultCorpora, cfg.Audit.Corpora, "Corpora should be DefaultCorpora")
+	assert.Equal(t, DefaultConcurrency, cfg.Audit.Concurrency, "Concurrency should be DefaultConcurrency")
+	assert.Equal(t, DefaultMaxErrors, cfg.Audit.MaxErrors, "MaxErrors should be DefaultMaxErrors")
 
 	// Test Output config defaults
 	assert.Equal(t, DefaultOutputFormat, cfg.Output.Format, "Format should be DefaultOutputFormat")
@@ -34,6 +37,9 @@ func TestSetDefaults(t *testing.T) {
 	// Test that defaults are set in viper
 	assert.Equal(t, true, v.GetBool("audit.include_shared_drives"))
 	assert.Equal(t, int64(DefaultPageSize), v.GetInt64("audit.page_size"))
+	assert.Equal(t, DefaultCorpora, v.GetString("audit.corpora"))
+	assert.Equal(t, DefaultConcurrency, v.GetInt("audit.concurrency"))
+	assert.Equal(t, DefaultMaxErrors, v.GetInt("audit.max_errors"))
 	assert.Equal(t, DefaultOutputFormat, v.GetString("output.format"))
 	assert.Equal(t, DefaultOutputDirectory, v.GetString("output.directory"))
 }
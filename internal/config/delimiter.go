@@ -0,0 +1,32 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// ParseDelimiter converts an output.delimiter value to the CSV field
+// delimiter. "tab" and the escape sequence "\t" select a tab; an empty value
+// selects a comma. Any other value must be a single character that is not a
+// quote or line break.
+func ParseDelimiter(s string) (rune, error) {
+	switch s {
+	case "":
+		return ',', nil
+	case "tab", `\t`:
+		return '\t', nil
+	}
+
+	if utf8.RuneCountInString(s) != 1 {
+		return 0, fmt.Errorf("must be a single character or \"tab\", got %q", s)
+	}
+	r, _ := utf8.DecodeRuneInString(s)
+	if r == '"' || r == '\r' || r == '\n' || r == utf8.RuneError {
+		return 0, errors.New("must not be a quote, line break or invalid character")
+	}
+	return r, nil
+}
@@ -0,0 +1,45 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDelimiter(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		want      rune
+		wantError bool
+	}{
+		{name: "empty defaults to comma", value: "", want: ','},
+		{name: "comma", value: ",", want: ','},
+		{name: "semicolon", value: ";", want: ';'},
+		{name: "tab keyword", value: "tab", want: '\t'},
+		{name: "escaped tab", value: `\t`, want: '\t'},
+		{name: "literal tab", value: "\t", want: '\t'},
+		{name: "pipe", value: "|", want: '|'},
+		{name: "multi-byte rune", value: "§", want: '§'},
+		{name: "multiple characters", value: ";;", wantError: true},
+		{name: "quote", value: `"`, wantError: true},
+		{name: "newline", value: "\n", wantError: true},
+		{name: "invalid utf-8", value: "\xff", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDelimiter(tt.value)
+			if tt.wantError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// domainPattern matches a lowercase DNS name with at least two labels.
+var domainPattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// InternalEmailPattern returns the compiled internal_email_regex, or nil
+// when it is not set.
+func (g GoogleConfig) InternalEmailPattern() (*regexp.Regexp, error) {
+	if g.InternalEmailRegex == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile(g.InternalEmailRegex)
+	if err != nil {
+		return nil, fmt.Errorf("google.internal_email_regex: %w", err)
+	}
+	return re, nil
+}
+
+// normalizeDomainList lowercases the domains, strips surrounding
+// whitespace, an http(s):// scheme and a trailing slash or dot, and drops
+// duplicates, keeping the first occurrence. Entries that are still not
+// domains are kept so that Validate reports them.
+func normalizeDomainList(domains []string) []string {
+	if len(domains) == 0 {
+		return domains
+	}
+
+	out := make([]string, 0, len(domains))
+	seen := make(map[string]struct{}, len(domains))
+	for _, d := range domains {
+		d = normalizeDomain(d)
+		if _, ok := seen[d]; ok {
+			continue
+		}
+		seen[d] = struct{}{}
+		out = append(out, d)
+	}
+	return out
+}
+
+// normalizeDomain normalizes a single domain list entry.
+func normalizeDomain(d string) string {
+	d = strings.ToLower(strings.TrimSpace(d))
+	for _, scheme := range []string{"https://", "http://"} {
+		if rest, ok := strings.CutPrefix(d, scheme); ok {
+			d = rest
+			break
+		}
+	}
+	return strings.TrimRight(d, "/.")
+}
+
+// checkDomain returns an error describing why d is not a valid domain, or
+// nil. d must already be normalized.
+func checkDomain(d string) error {
+	switch {
+	case d == "":
+		return errors.New("empty")
+	case strings.Contains(d, "@"):
+		return errors.New("an email address, not a domain")
+	case strings.ContainsAny(d, "/:?#"):
+		return errors.New("a URL, not a domain")
+	case !strings.Contains(d, "."):
+		return errors.New("missing a top-level domain")
+	case !domainPattern.MatchString(d):
+		return errors.New("not a valid DNS name")
+	}
+	return nil
+}
+
+// checkDomainList returns an error for each invalid entry of the domain
+// list named key.
+func checkDomainList(key string, domains []string) []error {
+	var errs []error
+	for _, d := range domains {
+		if err := checkDomain(normalizeDomain(d)); err != nil {
+			errs = append(errs, fmt.Errorf("%s contains an invalid domain: %q: %w", key, d, err))
+		}
+	}
+	return errs
+}
+
+// normalizeDomainLists normalizes every domain list in the configuration.
+func (c *Config) normalizeDomainLists() {
+	c.Google.DomainAliases = normalizeDomainList(c.Google.DomainAliases)
+	for i := range c.Google.Domains {
+		c.Google.Domains[i].Domain = normalizeDomain(c.Google.Domains[i].Domain)
+		c.Google.Domains[i].DomainAliases = normalizeDomainList(c.Google.Domains[i].DomainAliases)
+	}
+	c.Audit.FlaggedDomains = normalizeDomainList(c.Audit.FlaggedDomains)
+}
@@ -0,0 +1,86 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeDomainList(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []string
+		want []string
+	}{
+		{name: "nil", in: nil, want: nil},
+		{name: "lowercased", in: []string{"Gmail.COM"}, want: []string{"gmail.com"}},
+		{name: "whitespace", in: []string{"  example.org\t"}, want: []string{"example.org"}},
+		{name: "scheme and slash", in: []string{"https://example.org/", "http://example.net"}, want: []string{"example.org", "example.net"}},
+		{name: "trailing dot", in: []string{"example.org."}, want: []string{"example.org"}},
+		{name: "deduplicated in order", in: []string{"b.com", "A.com", "B.COM", "a.com"}, want: []string{"b.com", "a.com"}},
+		{name: "invalid entries kept", in: []string{"spy@rival.com", ""}, want: []string{"spy@rival.com", ""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, normalizeDomainList(tt.in))
+		})
+	}
+}
+
+func TestCheckDomainList(t *testing.T) {
+	tests := []struct {
+		domain  string
+		wantErr string
+	}{
+		{domain: "example.com"},
+		{domain: "EU.Example.co.uk"},
+		{domain: "xn--bcher-kva.example"},
+		{domain: "", wantErr: "empty"},
+		{domain: "spy@rival.com", wantErr: "an email address"},
+		{domain: "https://example.com/path", wantErr: "a URL"},
+		{domain: "example.com:443", wantErr: "a URL"},
+		{domain: "localhost", wantErr: "missing a top-level domain"},
+		{domain: "rival .com", wantErr: "not a valid DNS name"},
+		{domain: "-rival.com", wantErr: "not a valid DNS name"},
+		{domain: "*.rival.com", wantErr: "not a valid DNS name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			errs := checkDomainList("audit.flagged_domains", []string{tt.domain})
+			if tt.wantErr == "" {
+				assert.Empty(t, errs)
+				return
+			}
+			require.Len(t, errs, 1)
+			assert.Contains(t, errs[0].Error(), "audit.flagged_domains contains an invalid domain: "+`"`+tt.domain+`"`)
+			assert.Contains(t, errs[0].Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestCheckDomainList_ReportsEachEntry(t *testing.T) {
+	errs := checkDomainList("google.domain_aliases", []string{"example.org", "localhost", "a@b.com"})
+	require.Len(t, errs, 2)
+	assert.Contains(t, errs[0].Error(), `"localhost"`)
+	assert.Contains(t, errs[1].Error(), `"a@b.com"`)
+}
+
+func TestGoogleConfig_InternalEmailPattern(t *testing.T) {
+	re, err := GoogleConfig{}.InternalEmailPattern()
+	require.NoError(t, err)
+	assert.Nil(t, re, "no pattern keeps the domain comparison")
+
+	re, err = GoogleConfig{InternalEmailRegex: `-staff@example\.com$`}.InternalEmailPattern()
+	require.NoError(t, err)
+	assert.True(t, re.MatchString("alice-staff@example.com"))
+	assert.False(t, re.MatchString("bob-contractor@example.com"))
+
+	_, err = GoogleConfig{InternalEmailRegex: `(`}.InternalEmailPattern()
+	assert.ErrorContains(t, err, "google.internal_email_regex")
+}
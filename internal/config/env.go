@@ -0,0 +1,46 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// EnvPrefix is the prefix of environment variables that override config
+// file values. Keys are upper-cased with dots replaced by underscores, e.g.
+// GWORK_AUDIT_PAGE_SIZE overrides audit.page_size.
+const EnvPrefix = "GWORK"
+
+// bindEnv makes every config key overridable from the environment.
+func bindEnv(v *viper.Viper) {
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	for _, key := range configKeys(reflect.TypeOf(Config{}), "") {
+		// BindEnv only fails when no key is given.
+		_ = v.BindEnv(key)
+	}
+}
+
+// configKeys returns the dotted mapstructure keys of all leaf fields of t.
+func configKeys(t reflect.Type, prefix string) []string {
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("mapstructure")
+		if !field.IsExported() || tag == "" {
+			continue
+		}
+
+		key := prefix + tag
+		if field.Type.Kind() == reflect.Struct {
+			keys = append(keys, configKeys(field.Type, key+".")...)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
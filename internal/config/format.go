@@ -0,0 +1,63 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// FormatAuto selects the output format from the extension of output.file.
+const FormatAuto = "auto"
+
+// formatsByExtension maps output file extensions to output formats.
+var formatsByExtension = map[string]string{
+	".csv":    "csv",
+	".json":   "json",
+	".ndjson": "ndjson",
+	".xlsx":   "xlsx",
+	".db":     "sqlite",
+	".sqlite": "sqlite",
+}
+
+// InferFormat returns the output format for a report file from its
+// extension, compared case-insensitively.
+func InferFormat(path string) (string, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if format, ok := formatsByExtension[ext]; ok {
+		return format, nil
+	}
+	if ext == "" {
+		return "", fmt.Errorf("cannot infer output format from %q: no file extension (use .csv, .json, .ndjson, .xlsx or .db)", path)
+	}
+	return "", fmt.Errorf("cannot infer output format from extension %q (use .csv, .json, .ndjson, .xlsx or .db)", ext)
+}
+
+// SplitFormats returns the trimmed, comma-separated entries of an
+// output.format value, e.g. ["csv", "json"] for "csv, json".
+func SplitFormats(format string) []string {
+	formats := strings.Split(format, ",")
+	for i, f := range formats {
+		formats[i] = strings.TrimSpace(f)
+	}
+	return formats
+}
+
+// EffectiveFormats returns the output formats to write: the entries of
+// Format, or the format inferred from File when Format is FormatAuto.
+func (o OutputConfig) EffectiveFormats() ([]string, error) {
+	formats := SplitFormats(o.Format)
+	if len(formats) != 1 || formats[0] != FormatAuto {
+		return formats, nil
+	}
+	if o.File == "" {
+		return nil, fmt.Errorf("output.format %s requires output.file", FormatAuto)
+	}
+	format, err := InferFormat(o.File)
+	if err != nil {
+		return nil, err
+	}
+	return []string{format}, nil
+}
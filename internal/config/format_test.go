@@ -0,0 +1,58 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInferFormat(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+		errMsg   string
+	}{
+		{path: "report.csv", expected: "csv"},
+		{path: "out/sharing.json", expected: "json"},
+		{path: "sharing.ndjson", expected: "ndjson"},
+		{path: "Sharing.XLSX", expected: "xlsx"},
+		{path: "audits.db", expected: "sqlite"},
+		{path: "audits.sqlite", expected: "sqlite"},
+		{path: "report.pdf", errMsg: `cannot infer output format from extension ".pdf"`},
+		{path: "report", errMsg: "no file extension"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			format, err := InferFormat(tt.path)
+			if tt.errMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, format)
+		})
+	}
+}
+
+func TestOutputConfig_EffectiveFormats(t *testing.T) {
+	formats, err := OutputConfig{Format: "json", File: "report.csv"}.EffectiveFormats()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"json"}, formats, "an explicit format wins over the extension")
+
+	formats, err = OutputConfig{Format: FormatAuto, File: "report.xlsx"}.EffectiveFormats()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"xlsx"}, formats)
+
+	formats, err = OutputConfig{Format: "csv, json,ndjson"}.EffectiveFormats()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"csv", "json", "ndjson"}, formats)
+
+	_, err = OutputConfig{Format: FormatAuto}.EffectiveFormats()
+	assert.EqualError(t, err, "output.format auto requires output.file")
+}
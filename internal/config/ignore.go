@@ -0,0 +1,57 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// IgnoredFileIDs returns the file IDs to leave out of audits: those listed
+// in ignore_file_ids followed by those read from ignore_file_list.
+func (c AuditConfig) IgnoredFileIDs() ([]string, error) {
+	ids := append([]string(nil), c.IgnoreFileIDs...)
+	if c.IgnoreFileList == "" {
+		return ids, nil
+	}
+
+	f, err := os.Open(c.IgnoreFileList)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ignore file list: %w", err)
+	}
+	defer f.Close() //nolint:errcheck // read-only
+
+	listed, err := ReadIDList(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ignore file list %s: %w", c.IgnoreFileList, err)
+	}
+	return append(ids, listed...), nil
+}
+
+// ReadIDList reads one file ID per line. Blank lines and lines starting with
+// "#" are skipped, as is anything after whitespace on a line, so IDs can be
+// annotated ("1AbC... quarterly template").
+func ReadIDList(r io.Reader) ([]string, error) {
+	var ids []string
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if !fileIDPattern.MatchString(fields[0]) {
+			return nil, fmt.Errorf("line %d: invalid file ID %q", line, fields[0])
+		}
+		ids = append(ids, fields[0])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
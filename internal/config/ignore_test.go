@@ -0,0 +1,44 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadIDList(t *testing.T) {
+	input := `# intentionally public
+1AbC_def-123   quarterly template
+
+  2XyZ
+#3Commented
+`
+	ids, err := ReadIDList(strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1AbC_def-123", "2XyZ"}, ids)
+
+	_, err = ReadIDList(strings.NewReader("good\nbad/id\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `line 2: invalid file ID "bad/id"`)
+}
+
+func TestAuditConfig_IgnoredFileIDs(t *testing.T) {
+	list := filepath.Join(t.TempDir(), "ignore.txt")
+	require.NoError(t, os.WriteFile(list, []byte("fromList1\nfromList2\n"), 0o600))
+
+	cfg := AuditConfig{IgnoreFileIDs: []string{"inline"}, IgnoreFileList: list}
+	ids, err := cfg.IgnoredFileIDs()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"inline", "fromList1", "fromList2"}, ids)
+	assert.Equal(t, []string{"inline"}, cfg.IgnoreFileIDs, "config is not modified")
+
+	_, err = AuditConfig{IgnoreFileList: filepath.Join(t.TempDir(), "missing.txt")}.IgnoredFileIDs()
+	assert.Error(t, err)
+}
@@ -0,0 +1,65 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Labels returns the report header labels keyed by column name: those in
+// header_labels, overridden by those read from labels_file. It returns nil
+// when neither is set.
+func (c OutputConfig) Labels() (map[string]string, error) {
+	if len(c.HeaderLabels) == 0 && c.LabelsFile == "" {
+		return nil, nil
+	}
+
+	labels := make(map[string]string, len(c.HeaderLabels))
+	for column, label := range c.HeaderLabels {
+		labels[column] = label
+	}
+	if c.LabelsFile == "" {
+		return labels, checkLabels(labels)
+	}
+
+	f, err := os.Open(c.LabelsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open labels file: %w", err)
+	}
+	defer f.Close() //nolint:errcheck // read-only
+
+	read, err := ReadLabels(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read labels file %s: %w", c.LabelsFile, err)
+	}
+	for column, label := range read {
+		labels[column] = label
+	}
+	return labels, checkLabels(labels)
+}
+
+// ReadLabels reads a YAML mapping of column names to header labels, e.g.
+// "owner_email: Propriétaire". An empty document has no labels.
+func ReadLabels(r io.Reader) (map[string]string, error) {
+	var labels map[string]string
+	if err := yaml.NewDecoder(r).Decode(&labels); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return labels, nil
+}
+
+// checkLabels rejects empty labels, which would leave a column unnamed.
+func checkLabels(labels map[string]string) error {
+	for column, label := range labels {
+		if label == "" {
+			return fmt.Errorf("header label for column %s must not be empty", column)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadLabels(t *testing.T) {
+	labels, err := ReadLabels(strings.NewReader("owner_email: Propriétaire\nfile_name: \"Nom du fichier\"\n"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"owner_email": "Propriétaire", "file_name": "Nom du fichier"}, labels)
+
+	labels, err = ReadLabels(strings.NewReader(""))
+	require.NoError(t, err)
+	assert.Empty(t, labels)
+
+	_, err = ReadLabels(strings.NewReader("- not\n- a map\n"))
+	assert.Error(t, err)
+}
+
+func TestOutputConfig_Labels(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "labels.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("file_name: Nom du fichier\nowner_email: Propriétaire\n"), 0o600))
+
+	tests := []struct {
+		name    string
+		cfg     OutputConfig
+		want    map[string]string
+		wantErr string
+	}{
+		{name: "none"},
+		{
+			name: "inline",
+			cfg:  OutputConfig{HeaderLabels: map[string]string{"owner_email": "Owner"}},
+			want: map[string]string{"owner_email": "Owner"},
+		},
+		{
+			name: "file overrides inline",
+			cfg:  OutputConfig{HeaderLabels: map[string]string{"owner_email": "Owner", "risk": "Risque"}, LabelsFile: file},
+			want: map[string]string{"owner_email": "Propriétaire", "file_name": "Nom du fichier", "risk": "Risque"},
+		},
+		{
+			name:    "missing file",
+			cfg:     OutputConfig{LabelsFile: filepath.Join(t.TempDir(), "missing.yaml")},
+			wantErr: "failed to open labels file",
+		},
+		{
+			name:    "empty label",
+			cfg:     OutputConfig{HeaderLabels: map[string]string{"risk": ""}},
+			wantErr: "header label for column risk must not be empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			labels, err := tt.cfg.Labels()
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, labels)
+		})
+	}
+}
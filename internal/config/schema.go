@@ -0,0 +1,131 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+// SchemaDraft is the JSON Schema dialect of Schema.
+const SchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// schemaConstraints holds the validation rules of Validate that a JSON
+// Schema can express, keyed by dotted config path. They are built from the
+// same variables and constants as Validate so the two stay in sync.
+var schemaConstraints = map[string]map[string]any{
+	"google.domain_aliases":            {"items": map[string]any{"type": "string", "minLength": 1, "not": map[string]any{"pattern": "@"}}},
+	"audit.page_size":                  {"minimum": MinPageSize, "maximum": MaxPageSize},
+	"audit.concurrency":                {"minimum": 0, "maximum": MaxConcurrency},
+	"audit.domain_concurrency":         {"minimum": 0, "maximum": MaxConcurrency},
+	"audit.max_errors":                 {"minimum": 0},
+	"audit.max_api_calls":              {"minimum": 0},
+	"audit.max_qps":                    {"minimum": 0},
+	"audit.corpora":                    {"enum": append([]string{""}, ValidCorpora...)},
+	"audit.drive_ids":                  {"items": map[string]any{"type": "string", "pattern": driveIDPattern.String()}},
+	"audit.ignore_file_ids":            {"items": map[string]any{"type": "string", "pattern": fileIDPattern.String()}},
+	"audit.flagged_domains":            {"items": map[string]any{"type": "string", "minLength": 1, "not": map[string]any{"pattern": "@"}}},
+	"audit.external_roles_of_interest": {"items": map[string]any{"type": "string", "enum": ValidRoles}},
+	"audit.retry_status_codes":         {"items": map[string]any{"type": "integer", "minimum": 400, "maximum": 599}},
+	"output.format":                    {"anyOf": []any{map[string]any{"enum": ValidOutputFormats}, map[string]any{"pattern": formatListPattern()}}},
+	"default_command":                  {"enum": append([]string{""}, DefaultCommands...)},
+	"output.max_rows_per_file":         {"minimum": 0},
+	"output.sort_owners_by":            {"enum": append([]string{""}, ValidOwnerOrders...)},
+	"output.sort_files_by":             {"enum": append([]string{""}, ValidFileOrders...)},
+	"alert.max_external_shares":        {"minimum": 0},
+	"alert.max_public_shares":          {"minimum": 0},
+	"alert.max_external_writers":       {"minimum": 0},
+	"google.admin_email":               {"pattern": "@"},
+	"google.service_account_file":      {"minLength": 1},
+}
+
+// formatListPattern matches an output.format listing several of the formats
+// that can be written together.
+func formatListPattern() string {
+	var combinable []string
+	for _, f := range ValidOutputFormats {
+		if f != FormatAuto && f != "sheets" {
+			combinable = append(combinable, f)
+		}
+	}
+	one := "(" + strings.Join(combinable, "|") + ")"
+	return `^ *` + one + `( *, *` + one + `)+ *$`
+}
+
+// Schema returns a JSON Schema describing the config file. Properties are
+// generated from the yaml tags of Config, with defaults from NewDefault and
+// the enums and ranges enforced by Validate. No key is required, since any
+// value may come from GWORK_* environment variables instead; rules that
+// span several fields or touch the file system are only checked by
+// Validate.
+func Schema() map[string]any {
+	schema := objectSchema("", reflect.TypeOf(Config{}), reflect.ValueOf(*NewDefault()))
+	schema["$schema"] = SchemaDraft
+	schema["title"] = "gwork configuration"
+	return schema
+}
+
+// objectSchema returns the schema of struct type t at the dotted path
+// prefix, with defaults taken from the non-zero fields of def.
+func objectSchema(prefix string, t reflect.Type, def reflect.Value) map[string]any {
+	properties := make(map[string]any)
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if !field.IsExported() || name == "" || name == "-" {
+			continue
+		}
+
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+		properties[name] = fieldSchema(path, field.Type, def.Field(i))
+	}
+
+	return map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+}
+
+// fieldSchema returns the schema of a single config field.
+func fieldSchema(path string, t reflect.Type, def reflect.Value) map[string]any {
+	var schema map[string]any
+	switch t.Kind() {
+	case reflect.Struct:
+		return objectSchema(path, t, def)
+	case reflect.Pointer:
+		// Optional values are described by their element; nil has no default.
+		if def.IsNil() {
+			def = reflect.Zero(t.Elem())
+		} else {
+			def = def.Elem()
+		}
+		return fieldSchema(path, t.Elem(), def)
+	case reflect.Bool:
+		schema = map[string]any{"type": "boolean"}
+	case reflect.Map:
+		schema = map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}}
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		schema = map[string]any{"type": "integer"}
+	case reflect.Slice:
+		items := map[string]any{"type": "string"}
+		if t.Elem().Kind() == reflect.Struct {
+			items = objectSchema(path, t.Elem(), reflect.Zero(t.Elem()))
+		}
+		schema = map[string]any{"type": "array", "items": items}
+	default:
+		schema = map[string]any{"type": "string"}
+	}
+
+	if !def.IsZero() {
+		schema["default"] = def.Interface()
+	}
+	for key, value := range schemaConstraints[path] {
+		schema[key] = value
+	}
+	return schema
+}
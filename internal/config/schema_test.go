@@ -0,0 +1,85 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// schemaProperty returns the schema of the dotted config path.
+func schemaProperty(t *testing.T, schema map[string]any, section, key string) map[string]any {
+	t.Helper()
+
+	sections, ok := schema["properties"].(map[string]any)
+	require.True(t, ok)
+	sec, ok := sections[section].(map[string]any)
+	require.True(t, ok, "section %s", section)
+	props, ok := sec["properties"].(map[string]any)
+	require.True(t, ok)
+	prop, ok := props[key].(map[string]any)
+	require.True(t, ok, "property %s.%s", section, key)
+	return prop
+}
+
+func TestSchema_Constraints(t *testing.T) {
+	schema := Schema()
+	assert.Equal(t, SchemaDraft, schema["$schema"])
+
+	format := schemaProperty(t, schema, "output", "format")
+	assert.Equal(t, "string", format["type"])
+	require.Len(t, format["anyOf"], 2)
+	formatChoices := format["anyOf"].([]any)
+	assert.Equal(t, ValidOutputFormats, formatChoices[0].(map[string]any)["enum"])
+	listPattern := regexp.MustCompile(formatChoices[1].(map[string]any)["pattern"].(string))
+	assert.True(t, listPattern.MatchString("csv,json"))
+	assert.True(t, listPattern.MatchString("csv, ndjson, sqlite"))
+	assert.False(t, listPattern.MatchString("csv"))
+	assert.False(t, listPattern.MatchString("csv,sheets"))
+	assert.Equal(t, DefaultOutputFormat, format["default"])
+
+	pageSize := schemaProperty(t, schema, "audit", "page_size")
+	assert.Equal(t, "integer", pageSize["type"])
+	assert.Equal(t, MinPageSize, pageSize["minimum"])
+	assert.Equal(t, MaxPageSize, pageSize["maximum"])
+
+	concurrency := schemaProperty(t, schema, "audit", "concurrency")
+	assert.Equal(t, MaxConcurrency, concurrency["maximum"])
+
+	assert.Equal(t, "boolean", schemaProperty(t, schema, "audit", "chunk_by_owner")["type"])
+	assert.Equal(t, "array", schemaProperty(t, schema, "google", "domain_aliases")["type"])
+
+	properties, _ := schema["properties"].(map[string]any)
+	defaultCommand, _ := properties["default_command"].(map[string]any)
+	assert.Equal(t, append([]string{""}, DefaultCommands...), defaultCommand["enum"])
+}
+
+func TestSchema_CoversConfig(t *testing.T) {
+	// Every key written by config show must be described by the schema.
+	data, err := yaml.Marshal(NewDefault())
+	require.NoError(t, err)
+	var doc map[string]any
+	require.NoError(t, yaml.Unmarshal(data, &doc))
+
+	schema := Schema()
+	for name, value := range doc {
+		keys, ok := value.(map[string]any)
+		if !ok {
+			properties, _ := schema["properties"].(map[string]any)
+			assert.Contains(t, properties, name, "property %s", name)
+			continue
+		}
+		for key := range keys {
+			schemaProperty(t, schema, name, key)
+		}
+	}
+
+	_, err = json.Marshal(schema)
+	assert.NoError(t, err, "schema is valid JSON")
+}
@@ -4,14 +4,55 @@
 package config
 
 import (
+	"crypto/x509"
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
+	"regexp"
+	"slices"
 	"strings"
+
+	"github.com/leansecurity-co/gwork/internal/drivesyntax"
 )
 
 // ValidOutputFormats lists the supported output formats.
-var ValidOutputFormats = []string{"csv", "json"}
+var ValidOutputFormats = []string{"csv", "json", "ndjson", "xlsx", "sqlite", "sheets", FormatAuto}
+
+// driveIDPattern matches the characters allowed in a shared drive ID.
+var driveIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// fileIDPattern matches the characters allowed in a Drive file ID.
+var fileIDPattern = driveIDPattern
+
+// ValidCorpora lists the supported Drive corpora.
+var ValidCorpora = []string{"user", "domain", "drive", "allDrives"}
+
+// ValidRoles lists the Drive permission roles, most privileged first.
+var ValidRoles = []string{"owner", "organizer", "fileOrganizer", "writer", "commenter", "reader"}
+
+// ValidOwnerOrders lists the values of output.sort_owners_by.
+var ValidOwnerOrders = []string{"email", "bytes", "count"}
+
+// ValidFileOrders lists the values of output.sort_files_by.
+var ValidFileOrders = []string{"name", "size", "modified"}
+
+// ValidJSONFields lists the keys output.json_fields may name: the top-level
+// fields of the objects in any JSON report.
+var ValidJSONFields = []string{
+	"owner_email", "owner_name", "file_id", "file_name", "file_type",
+	"created_time", "modified_time", "size_bytes", "trashed", "location",
+	"drive_name", "viewed_by_me_time", "link_sharing_enabled", "source_domain",
+	"shared_with_email", "shared_with_domain", "permission_type", "permission_role",
+	"permission_id", "shared_date", "inherited", "inherited_from", "expiration_time",
+	"web_view_link", "grantee_deleted", "domain_wide", "flagged", "flag_reason",
+	"explanation", "label", "risk", "group_member_count", "has_external_members",
+	"file_ids", "file_count", "total_bytes", "scope", "role", "count",
+}
+
+// DefaultCommands lists the audit subcommands default_command may name:
+// those that take no arguments.
+var DefaultCommands = []string{"files", "sharing", "public", "domain-shares", "external-owners", "shared-with-me", "owners", "duplicates", "all"}
 
 // Validate checks if the configuration is valid.
 func (c *Config) Validate() error {
@@ -24,24 +65,241 @@ func (c *Config) Validate() error {
 		errs = append(errs, fmt.Errorf("service account file not found: %s", c.Google.ServiceAccountFile))
 	}
 
-	if c.Google.AdminEmail == "" {
-		errs = append(errs, errors.New("google.admin_email is required for domain-wide delegation"))
-	} else if !strings.Contains(c.Google.AdminEmail, "@") {
-		errs = append(errs, errors.New("google.admin_email must be a valid email address"))
+	// Each of google.domains names its own admin, so the single-domain
+	// settings are not needed.
+	if len(c.Google.Domains) == 0 {
+		if c.Google.AdminEmail == "" {
+			errs = append(errs, errors.New("google.admin_email is required for domain-wide delegation"))
+		} else if !strings.Contains(c.Google.AdminEmail, "@") {
+			errs = append(errs, errors.New("google.admin_email must be a valid email address"))
+		}
+
+		// The domain defaults to that of the admin email when omitted.
+		if c.Google.Domain == "" && drivesyntax.ExtractDomain(c.Google.AdminEmail) == "" {
+			errs = append(errs, errors.New("google.domain is required when it cannot be derived from google.admin_email"))
+		}
+	}
+
+	errs = append(errs, checkDomainList("google.domain_aliases", c.Google.DomainAliases)...)
+	if _, err := c.Google.InternalEmailPattern(); err != nil {
+		errs = append(errs, err)
+	}
+	errs = append(errs, c.validateDomains()...)
+
+	if c.Google.APIEndpoint != "" {
+		if err := validateAPIEndpoint(c.Google.APIEndpoint); err != nil {
+			errs = append(errs, fmt.Errorf("google.api_endpoint: %w", err))
+		}
+	}
+
+	if c.Google.ProxyURL != "" {
+		if err := validateProxyURL(c.Google.ProxyURL); err != nil {
+			errs = append(errs, fmt.Errorf("google.proxy_url: %w", err))
+		}
 	}
 
-	if c.Google.Domain == "" {
-		errs = append(errs, errors.New("google.domain is required"))
+	if c.Google.CACertFile != "" {
+		if err := validateCACertFile(c.Google.CACertFile); err != nil {
+			errs = append(errs, fmt.Errorf("google.ca_cert_file: %w", err))
+		}
 	}
 
 	// Validate audit config
-	if c.Audit.PageSize < 1 || c.Audit.PageSize > 1000 {
-		errs = append(errs, errors.New("audit.page_size must be between 1 and 1000"))
+	if c.Audit.PageSize < MinPageSize || c.Audit.PageSize > MaxPageSize {
+		errs = append(errs, fmt.Errorf("audit.page_size must be between %d and %d", MinPageSize, MaxPageSize))
+	}
+
+	// Zero concurrency falls back to sequential processing.
+	if c.Audit.Concurrency < 0 || c.Audit.Concurrency > MaxConcurrency {
+		errs = append(errs, fmt.Errorf("audit.concurrency must be between 0 and %d", MaxConcurrency))
+	}
+
+	// Zero domain concurrency audits one domain at a time.
+	if c.Audit.DomainConcurrency < 0 || c.Audit.DomainConcurrency > MaxConcurrency {
+		errs = append(errs, fmt.Errorf("audit.domain_concurrency must be between 0 and %d", MaxConcurrency))
+	}
+
+	// Zero max errors falls back to the default.
+	if c.Audit.MaxErrors < 0 {
+		errs = append(errs, errors.New("audit.max_errors must not be negative"))
+	}
+
+	// Zero max API calls means no limit.
+	if c.Audit.MaxAPICalls < 0 {
+		errs = append(errs, errors.New("audit.max_api_calls must not be negative"))
+	}
+
+	if c.Audit.MaxQPS < 0 {
+		errs = append(errs, errors.New("audit.max_qps must not be negative"))
+	}
+
+	// An empty corpora falls back to the default.
+	if c.Audit.Corpora != "" && !contains(ValidCorpora, c.Audit.Corpora) {
+		errs = append(errs, fmt.Errorf("audit.corpora must be one of: %s", strings.Join(ValidCorpora, ", ")))
+	}
+
+	if c.Audit.Corpora == "drive" && len(c.Audit.DriveIDs) == 0 {
+		errs = append(errs, errors.New("audit.drive_ids is required when audit.corpora is drive"))
+	}
+
+	for _, id := range c.Audit.DriveIDs {
+		if !driveIDPattern.MatchString(id) {
+			errs = append(errs, fmt.Errorf("audit.drive_ids contains an invalid drive ID: %q", id))
+		}
+	}
+
+	for _, id := range c.Audit.IgnoreFileIDs {
+		if !fileIDPattern.MatchString(id) {
+			errs = append(errs, fmt.Errorf("audit.ignore_file_ids contains an invalid file ID: %q", id))
+		}
+	}
+
+	errs = append(errs, checkDomainList("audit.flagged_domains", c.Audit.FlaggedDomains)...)
+
+	for _, role := range c.Audit.ExternalRolesOfInterest {
+		if !contains(ValidRoles, role) {
+			errs = append(errs, fmt.Errorf("audit.external_roles_of_interest contains an unknown role %q (must be one of: %s)", role, strings.Join(ValidRoles, ", ")))
+		}
+	}
+
+	for _, code := range c.Audit.RetryStatusCodes {
+		if code < 400 || code > 599 {
+			errs = append(errs, fmt.Errorf("audit.retry_status_codes contains %d, which is not an HTTP error status (400-599)", code))
+		}
+	}
+
+	if c.Audit.IgnoreFileList != "" {
+		if _, err := os.Stat(c.Audit.IgnoreFileList); err != nil {
+			errs = append(errs, fmt.Errorf("audit.ignore_file_list: %w", err))
+		}
+	}
+
+	if err := drivesyntax.ValidateQuery(c.Audit.Query); err != nil {
+		errs = append(errs, fmt.Errorf("audit.query: %w", err))
+	}
+
+	if err := validateFieldMask(c.Audit.FileFields); err != nil {
+		errs = append(errs, fmt.Errorf("audit.file_fields: %w", err))
+	}
+
+	if err := validateFieldMask(c.Audit.PermissionFields); err != nil {
+		errs = append(errs, fmt.Errorf("audit.permission_fields: %w", err))
+	}
+
+	if c.DefaultCommand != "" && !contains(DefaultCommands, c.DefaultCommand) {
+		errs = append(errs, fmt.Errorf("default_command must be one of: %s", strings.Join(DefaultCommands, ", ")))
 	}
 
 	// Validate output config
-	if !isValidFormat(c.Output.Format) {
-		errs = append(errs, fmt.Errorf("output.format must be one of: %s", strings.Join(ValidOutputFormats, ", ")))
+	formats := SplitFormats(c.Output.Format)
+	for _, f := range formats {
+		if !isValidFormat(f) {
+			errs = append(errs, fmt.Errorf("output.format must be one of: %s", strings.Join(ValidOutputFormats, ", ")))
+			break
+		}
+	}
+	if len(formats) > 1 {
+		errs = append(errs, validateFormatList(formats, c.Output.File)...)
+	}
+
+	if _, err := ParseDelimiter(c.Output.Delimiter); err != nil {
+		errs = append(errs, fmt.Errorf("output.delimiter: %w", err))
+	}
+
+	effective, err := c.Output.EffectiveFormats()
+	if err != nil {
+		errs = append(errs, err)
+	}
+	format := strings.Join(effective, ",")
+
+	if c.Output.SplitByOwner && format != "" && format != "csv" {
+		errs = append(errs, errors.New("output.split_by_owner requires output.format csv"))
+	}
+
+	if len(c.Output.JSONFields) > 0 {
+		if !slices.Contains(effective, "json") && !slices.Contains(effective, "ndjson") {
+			errs = append(errs, errors.New("output.json_fields requires output.format json or ndjson"))
+		}
+		for _, field := range c.Output.JSONFields {
+			if strings.TrimSpace(field) == "" {
+				errs = append(errs, errors.New("output.json_fields must not contain empty field names"))
+				break
+			}
+			if !slices.Contains(ValidJSONFields, field) {
+				errs = append(errs, fmt.Errorf("output.json_fields has unknown field %q", field))
+			}
+		}
+	}
+
+	if c.Audit.ChunkByOwner {
+		if format != "" && format != "csv" {
+			errs = append(errs, errors.New("audit.chunk_by_owner requires output.format csv"))
+		}
+		if c.Output.SplitByOwner {
+			errs = append(errs, errors.New("audit.chunk_by_owner cannot be combined with output.split_by_owner"))
+		}
+		// Shared drive files have no owner, so a shared drive corpora would
+		// produce an empty report.
+		if len(c.Audit.DriveIDs) > 0 || c.Audit.Corpora == "drive" || c.Audit.Corpora == "allDrives" {
+			errs = append(errs, errors.New("audit.chunk_by_owner cannot be combined with audit.drive_ids or the drive and allDrives corpora"))
+		}
+		// Chunks are written one owner at a time in email order.
+		if c.Output.SortOwnersBy != "" && c.Output.SortOwnersBy != "email" {
+			errs = append(errs, errors.New("audit.chunk_by_owner only supports output.sort_owners_by email"))
+		}
+	}
+
+	if c.Output.SortOwnersBy != "" && !contains(ValidOwnerOrders, c.Output.SortOwnersBy) {
+		errs = append(errs, fmt.Errorf("output.sort_owners_by must be one of: %s", strings.Join(ValidOwnerOrders, ", ")))
+	}
+	if c.Output.SortFilesBy != "" && !contains(ValidFileOrders, c.Output.SortFilesBy) {
+		errs = append(errs, fmt.Errorf("output.sort_files_by must be one of: %s", strings.Join(ValidFileOrders, ", ")))
+	}
+
+	if c.Audit.Incremental && c.Output.HistoryFile == "" {
+		errs = append(errs, errors.New("audit.incremental requires output.history_file"))
+	}
+
+	if _, err := c.Output.Labels(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if c.Output.MaxRowsPerFile < 0 {
+		errs = append(errs, errors.New("output.max_rows_per_file must not be negative"))
+	}
+
+	for _, threshold := range []struct {
+		name  string
+		limit *int
+	}{
+		{"alert.max_external_shares", c.Alert.MaxExternalShares},
+		{"alert.max_public_shares", c.Alert.MaxPublicShares},
+		{"alert.max_external_writers", c.Alert.MaxExternalWriters},
+	} {
+		if threshold.limit != nil && *threshold.limit < 0 {
+			errs = append(errs, fmt.Errorf("%s must not be negative", threshold.name))
+		}
+	}
+
+	if c.Output.MaxRowsPerFile > 0 {
+		if format != "" && format != "csv" {
+			errs = append(errs, errors.New("output.max_rows_per_file requires output.format csv"))
+		}
+		if c.Output.SplitByOwner {
+			errs = append(errs, errors.New("output.max_rows_per_file cannot be combined with output.split_by_owner"))
+		}
+		if c.Audit.ChunkByOwner {
+			errs = append(errs, errors.New("output.max_rows_per_file cannot be combined with audit.chunk_by_owner"))
+		}
+	}
+
+	if c.Output.File != "" {
+		if c.Output.SplitByOwner {
+			errs = append(errs, errors.New("output.file cannot be combined with output.split_by_owner"))
+		}
+		if format == "sheets" {
+			errs = append(errs, errors.New("output.file is not supported with output.format sheets"))
+		}
 	}
 
 	if len(errs) > 0 {
@@ -51,11 +309,124 @@ func (c *Config) Validate() error {
 	return nil
 }
 
+// validateFormatList checks an output.format listing several formats, all
+// of which are written in one run.
+func validateFormatList(formats []string, file string) []error {
+	var errs []error
+	seen := make(map[string]bool, len(formats))
+	for _, f := range formats {
+		switch {
+		case f == FormatAuto || f == "sheets":
+			errs = append(errs, fmt.Errorf("output.format %s cannot be combined with other formats", f))
+		case seen[f] && f != "":
+			errs = append(errs, fmt.Errorf("output.format lists %s more than once", f))
+		}
+		seen[f] = true
+	}
+	if file != "" {
+		errs = append(errs, errors.New("output.file requires a single output.format"))
+	}
+	return errs
+}
+
+// validateFieldMask checks that a field mask is a comma-separated list of
+// non-empty fields with balanced parentheses. An empty mask is valid.
+func validateFieldMask(mask string) error {
+	if strings.TrimSpace(mask) == "" {
+		return nil
+	}
+
+	if strings.Count(mask, "(") != strings.Count(mask, ")") {
+		return errors.New("unbalanced parentheses")
+	}
+
+	for _, field := range drivesyntax.SplitFields(mask) {
+		if field == "" {
+			return errors.New("empty field in mask")
+		}
+		if strings.HasPrefix(field, "nextPageToken") || strings.HasPrefix(field, "files(") || strings.HasPrefix(field, "permissions(") {
+			return fmt.Errorf("field %q must not be wrapped; list the per-item fields only", field)
+		}
+	}
+
+	return nil
+}
+
 func isValidFormat(format string) bool {
-	for _, f := range ValidOutputFormats {
-		if f == format {
+	return contains(ValidOutputFormats, format)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
 			return true
 		}
 	}
 	return false
 }
+
+// validateProxyURL checks that s is an absolute http, https or socks5 URL.
+func validateProxyURL(s string) error {
+	u, err := url.Parse(s)
+	if err != nil {
+		return err
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return fmt.Errorf("unsupported scheme %q, use http, https or socks5", u.Scheme)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("missing host in %q", s)
+	}
+	return nil
+}
+
+// validateAPIEndpoint checks that s is an absolute http or https URL.
+func validateAPIEndpoint(s string) error {
+	u, err := url.Parse(s)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported scheme %q, use http or https", u.Scheme)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("missing host in %q", s)
+	}
+	return nil
+}
+
+// validateCACertFile checks that file can be read and contains at least one
+// PEM certificate.
+func validateCACertFile(file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	if !x509.NewCertPool().AppendCertsFromPEM(data) {
+		return fmt.Errorf("no PEM certificates found in %s", file)
+	}
+	return nil
+}
+
+// validateDomains checks the entries of google.domains.
+func (c *Config) validateDomains() []error {
+	var errs []error
+	seen := make(map[string]bool, len(c.Google.Domains))
+	for i, d := range c.Google.Domains {
+		key := fmt.Sprintf("google.domains[%d]", i)
+		if err := checkDomain(normalizeDomain(d.Domain)); err != nil {
+			errs = append(errs, fmt.Errorf("%s.domain is invalid: %q: %w", key, d.Domain, err))
+		} else if seen[d.Domain] {
+			errs = append(errs, fmt.Errorf("google.domains lists %s more than once", d.Domain))
+		}
+		seen[d.Domain] = true
+
+		if !strings.Contains(d.AdminEmail, "@") {
+			errs = append(errs, fmt.Errorf("%s.admin_email must be a valid email address", key))
+		}
+		errs = append(errs, checkDomainList(key+".domain_aliases", d.DomainAliases)...)
+	}
+	return errs
+}
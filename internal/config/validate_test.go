@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestConfig_Validate(t *testing.T) {
@@ -17,6 +18,8 @@ func TestConfig_Validate(t *testing.T) {
 	validServiceAccountFile := filepath.Join(tmpDir, "service-account.json")
 	err := os.WriteFile(validServiceAccountFile, []byte(`{"type":"service_account"}`), 0600)
 	assert.NoError(t, err)
+	invalidCACertFile := filepath.Join(tmpDir, "ca.pem")
+	require.NoError(t, os.WriteFile(invalidCACertFile, []byte("not a certificate"), 0600))
 
 	tests := []struct {
 		name      string
@@ -114,7 +117,7 @@ func TestConfig_Validate(t *testing.T) {
 			errorMsg:  "must be a valid email address",
 		},
 		{
-			name: "missing domain",
+			name: "missing domain derivable from admin email",
 			config: Config{
 				Google: GoogleConfig{
 					ServiceAccountFile: validServiceAccountFile,
@@ -128,11 +131,840 @@ func TestConfig_Validate(t *testing.T) {
 					Format: "csv",
 				},
 			},
+			wantError: false,
+		},
+		{
+			name: "missing domain and admin email without @",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin",
+					Domain:             "",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+				},
+				Output: OutputConfig{
+					Format: "csv",
+				},
+			},
+			wantError: true,
+			errorMsg:  "google.domain is required when it cannot be derived from google.admin_email",
+		},
+		{
+			name: "page size zero",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: 0,
+				},
+				Output: OutputConfig{
+					Format: "csv",
+				},
+			},
+			wantError: true,
+			errorMsg:  "page_size must be between 1 and 1000",
+		},
+		{
+			name: "page size negative",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: -10,
+				},
+				Output: OutputConfig{
+					Format: "csv",
+				},
+			},
+			wantError: true,
+			errorMsg:  "page_size must be between 1 and 1000",
+		},
+		{
+			name: "page size too large",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: 1001,
+				},
+				Output: OutputConfig{
+					Format: "csv",
+				},
+			},
+			wantError: true,
+			errorMsg:  "page_size must be between 1 and 1000",
+		},
+		{
+			name: "page size at minimum boundary",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: 1,
+				},
+				Output: OutputConfig{
+					Format: "csv",
+				},
+			},
+			wantError: false,
+		},
+		{
+			name: "page size at maximum boundary",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: 1000,
+				},
+				Output: OutputConfig{
+					Format: "csv",
+				},
+			},
+			wantError: false,
+		},
+		{
+			name: "invalid output format",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+				},
+				Output: OutputConfig{
+					Format: "xml",
+				},
+			},
+			wantError: true,
+			errorMsg:  "output.format must be one of",
+		},
+		{
+			name: "valid json format",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+				},
+				Output: OutputConfig{
+					Format: "json",
+				},
+			},
+			wantError: false,
+		},
+		{
+			name: "format list",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+				},
+				Output: OutputConfig{
+					Format: "csv, json,sqlite",
+				},
+			},
+			wantError: false,
+		},
+		{
+			name: "format list with invalid entry",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+				},
+				Output: OutputConfig{
+					Format: "csv,html",
+				},
+			},
+			wantError: true,
+			errorMsg:  "output.format must be one of",
+		},
+		{
+			name: "format list with empty entry",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+				},
+				Output: OutputConfig{
+					Format: "csv,,json",
+				},
+			},
+			wantError: true,
+			errorMsg:  "output.format must be one of",
+		},
+		{
+			name: "format list with sheets",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+				},
+				Output: OutputConfig{
+					Format: "csv,sheets",
+				},
+			},
+			wantError: true,
+			errorMsg:  "output.format sheets cannot be combined with other formats",
+		},
+		{
+			name: "format list with auto",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+				},
+				Output: OutputConfig{
+					Format: "auto,json",
+					File:   "report.json",
+				},
+			},
+			wantError: true,
+			errorMsg:  "output.format auto cannot be combined with other formats",
+		},
+		{
+			name: "format list with duplicate",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+				},
+				Output: OutputConfig{
+					Format: "json,csv,json",
+				},
+			},
+			wantError: true,
+			errorMsg:  "output.format lists json more than once",
+		},
+		{
+			name: "format list with output file",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+				},
+				Output: OutputConfig{
+					Format: "csv,json",
+					File:   "report.csv",
+				},
+			},
+			wantError: true,
+			errorMsg:  "output.file requires a single output.format",
+		},
+		{
+			name: "json fields with format list",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+				},
+				Output: OutputConfig{
+					Format:     "csv,json",
+					JSONFields: []string{"file_id"},
+				},
+			},
+			wantError: false,
+		},
+		{
+			name: "split by owner with csv format",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+				},
+				Output: OutputConfig{
+					Format:       "csv",
+					SplitByOwner: true,
+				},
+			},
+			wantError: false,
+		},
+		{
+			name: "split by owner with json format",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+				},
+				Output: OutputConfig{
+					Format:       "json",
+					SplitByOwner: true,
+				},
+			},
+			wantError: true,
+			errorMsg:  "output.split_by_owner requires output.format csv",
+		},
+		{
+			name: "json fields with ndjson format",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+				},
+				Output: OutputConfig{
+					Format:     "ndjson",
+					JSONFields: []string{"file_id", "owner_email"},
+				},
+			},
+			wantError: false,
+		},
+		{
+			name: "json fields with csv format",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+				},
+				Output: OutputConfig{
+					Format:     "csv",
+					JSONFields: []string{"file_id"},
+				},
+			},
+			wantError: true,
+			errorMsg:  "output.json_fields requires output.format json or ndjson",
+		},
+		{
+			name: "json fields with empty name",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+				},
+				Output: OutputConfig{
+					Format:     "json",
+					JSONFields: []string{"file_id", " "},
+				},
+			},
+			wantError: true,
+			errorMsg:  "output.json_fields must not contain empty field names",
+		},
+		{
+			name: "json fields with unknown name",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+				},
+				Output: OutputConfig{
+					Format:     "json",
+					JSONFields: []string{"file_id", "fileid"},
+				},
+			},
+			wantError: true,
+			errorMsg:  `output.json_fields has unknown field "fileid"`,
+		},
+		{
+			name: "auto format without output file",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+				},
+				Output: OutputConfig{
+					Format: "auto",
+				},
+			},
+			wantError: true,
+			errorMsg:  "output.format auto requires output.file",
+		},
+		{
+			name: "auto format with unknown extension",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+				},
+				Output: OutputConfig{
+					Format: "auto",
+					File:   "report.pdf",
+				},
+			},
+			wantError: true,
+			errorMsg:  `cannot infer output format from extension ".pdf"`,
+		},
+		{
+			name: "output file with split by owner",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+				},
+				Output: OutputConfig{
+					Format:       "csv",
+					File:         "report.csv",
+					SplitByOwner: true,
+				},
+			},
+			wantError: true,
+			errorMsg:  "output.file cannot be combined with output.split_by_owner",
+		},
+		{
+			name: "chunk by owner with json",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize:     100,
+					ChunkByOwner: true,
+				},
+				Output: OutputConfig{
+					Format: "json",
+				},
+			},
+			wantError: true,
+			errorMsg:  "audit.chunk_by_owner requires output.format csv",
+		},
+		{
+			name: "chunk by owner with drive ids",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize:     100,
+					Corpora:      "drive",
+					DriveIDs:     []string{"0AAbc"},
+					ChunkByOwner: true,
+				},
+			},
+			wantError: true,
+			errorMsg:  "audit.chunk_by_owner cannot be combined with audit.drive_ids or the drive and allDrives corpora",
+		},
+		{
+			name: "valid auto format",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+				},
+				Output: OutputConfig{
+					Format: "auto",
+					File:   "reports/sharing.xlsx",
+				},
+			},
+			wantError: false,
+		},
+		{
+			name: "valid corpora",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+					Corpora:  "allDrives",
+				},
+				Output: OutputConfig{
+					Format: "csv",
+				},
+			},
+			wantError: false,
+		},
+		{
+			name: "valid domain aliases",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+					DomainAliases:      []string{"example.org", "example.net"},
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+				},
+				Output: OutputConfig{
+					Format: "csv",
+				},
+			},
+			wantError: false,
+		},
+		{
+			name: "domain alias that is an email address",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+					DomainAliases:      []string{"admin@example.org"},
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+				},
+				Output: OutputConfig{
+					Format: "csv",
+				},
+			},
+			wantError: true,
+			errorMsg:  "google.domain_aliases contains an invalid domain",
+		},
+		{
+			name: "valid internal email regex",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+					InternalEmailRegex: `-staff@example\.com$`,
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+				},
+				Output: OutputConfig{
+					Format: "csv",
+				},
+			},
+			wantError: false,
+		},
+		{
+			name: "invalid internal email regex",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+					InternalEmailRegex: `-staff@(example\.com$`,
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+				},
+				Output: OutputConfig{
+					Format: "csv",
+				},
+			},
+			wantError: true,
+			errorMsg:  "google.internal_email_regex: error parsing regexp",
+		},
+		{
+			name: "invalid corpora",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+					Corpora:  "everything",
+				},
+				Output: OutputConfig{
+					Format: "csv",
+				},
+			},
+			wantError: true,
+			errorMsg:  "audit.corpora must be one of",
+		},
+		{
+			name: "drive corpora without drive ID",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+					Corpora:  "drive",
+				},
+				Output: OutputConfig{
+					Format: "csv",
+				},
+			},
+			wantError: true,
+			errorMsg:  "audit.drive_ids is required",
+		},
+		{
+			name: "drive corpora with drive ID",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+					Corpora:  "drive",
+					DriveIDs: []string{"0ABCdefGHIjkl", "0AXyz_-123"},
+				},
+				Output: OutputConfig{
+					Format: "csv",
+				},
+			},
+			wantError: false,
+		},
+		{
+			name: "empty drive ID",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+					Corpora:  "drive",
+					DriveIDs: []string{"0ABCdefGHIjkl", ""},
+				},
+				Output: OutputConfig{
+					Format: "csv",
+				},
+			},
+			wantError: true,
+			errorMsg:  "invalid drive ID",
+		},
+		{
+			name: "malformed drive ID",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+					DriveIDs: []string{"drive/../other"},
+				},
+				Output: OutputConfig{
+					Format: "csv",
+				},
+			},
+			wantError: true,
+			errorMsg:  "invalid drive ID",
+		},
+		{
+			name: "concurrency too large",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize:    100,
+					Concurrency: MaxConcurrency + 1,
+				},
+				Output: OutputConfig{
+					Format: "csv",
+				},
+			},
+			wantError: true,
+			errorMsg:  "audit.concurrency must be between 0 and",
+		},
+		{
+			name: "negative concurrency",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize:    100,
+					Concurrency: -1,
+				},
+				Output: OutputConfig{
+					Format: "csv",
+				},
+			},
+			wantError: true,
+			errorMsg:  "audit.concurrency must be between 0 and",
+		},
+		{
+			name: "negative max errors",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize:  100,
+					MaxErrors: -1,
+				},
+				Output: OutputConfig{
+					Format: "csv",
+				},
+			},
+			wantError: true,
+			errorMsg:  "audit.max_errors must not be negative",
+		},
+		{
+			name: "invalid ignored file ID",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize:      100,
+					IgnoreFileIDs: []string{"1AbC", "../etc"},
+				},
+				Output: OutputConfig{
+					Format: "csv",
+				},
+			},
+			wantError: true,
+			errorMsg:  `audit.ignore_file_ids contains an invalid file ID: "../etc"`,
+		},
+		{
+			name: "invalid flagged domain",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize:       100,
+					FlaggedDomains: []string{"rival.com", "spy@rival.com"},
+				},
+				Output: OutputConfig{
+					Format: "csv",
+				},
+			},
+			wantError: true,
+			errorMsg:  `audit.flagged_domains contains an invalid domain: "spy@rival.com"`,
+		},
+		{
+			name: "valid external roles of interest",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize:                100,
+					ExternalRolesOfInterest: []string{"writer", "owner"},
+				},
+				Output: OutputConfig{
+					Format: "csv",
+				},
+			},
+			wantError: false,
+		},
+		{
+			name: "unknown external role of interest",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize:                100,
+					ExternalRolesOfInterest: []string{"writer", "editor"},
+				},
+				Output: OutputConfig{
+					Format: "csv",
+				},
+			},
 			wantError: true,
-			errorMsg:  "domain is required",
+			errorMsg:  `audit.external_roles_of_interest contains an unknown role "editor"`,
 		},
 		{
-			name: "page size zero",
+			name: "retry status code outside 400-599",
 			config: Config{
 				Google: GoogleConfig{
 					ServiceAccountFile: validServiceAccountFile,
@@ -140,17 +972,18 @@ func TestConfig_Validate(t *testing.T) {
 					Domain:             "example.com",
 				},
 				Audit: AuditConfig{
-					PageSize: 0,
+					PageSize:         100,
+					RetryStatusCodes: []int{429, 302},
 				},
 				Output: OutputConfig{
 					Format: "csv",
 				},
 			},
 			wantError: true,
-			errorMsg:  "page_size must be between 1 and 1000",
+			errorMsg:  "audit.retry_status_codes contains 302, which is not an HTTP error status (400-599)",
 		},
 		{
-			name: "page size negative",
+			name: "incremental without history file",
 			config: Config{
 				Google: GoogleConfig{
 					ServiceAccountFile: validServiceAccountFile,
@@ -158,17 +991,18 @@ func TestConfig_Validate(t *testing.T) {
 					Domain:             "example.com",
 				},
 				Audit: AuditConfig{
-					PageSize: -10,
+					PageSize:    100,
+					Incremental: true,
 				},
 				Output: OutputConfig{
 					Format: "csv",
 				},
 			},
 			wantError: true,
-			errorMsg:  "page_size must be between 1 and 1000",
+			errorMsg:  "audit.incremental requires output.history_file",
 		},
 		{
-			name: "page size too large",
+			name: "unknown default command",
 			config: Config{
 				Google: GoogleConfig{
 					ServiceAccountFile: validServiceAccountFile,
@@ -176,17 +1010,18 @@ func TestConfig_Validate(t *testing.T) {
 					Domain:             "example.com",
 				},
 				Audit: AuditConfig{
-					PageSize: 1001,
+					PageSize: 100,
 				},
 				Output: OutputConfig{
 					Format: "csv",
 				},
+				DefaultCommand: "file",
 			},
 			wantError: true,
-			errorMsg:  "page_size must be between 1 and 1000",
+			errorMsg:  "default_command must be one of: files, sharing, public, domain-shares, external-owners, shared-with-me, owners, duplicates, all",
 		},
 		{
-			name: "page size at minimum boundary",
+			name: "missing ignore file list",
 			config: Config{
 				Google: GoogleConfig{
 					ServiceAccountFile: validServiceAccountFile,
@@ -194,7 +1029,65 @@ func TestConfig_Validate(t *testing.T) {
 					Domain:             "example.com",
 				},
 				Audit: AuditConfig{
-					PageSize: 1,
+					PageSize:       100,
+					IgnoreFileList: "/nonexistent/ignore.txt",
+				},
+				Output: OutputConfig{
+					Format: "csv",
+				},
+			},
+			wantError: true,
+			errorMsg:  "audit.ignore_file_list",
+		},
+		{
+			name: "proxy URL with unsupported scheme",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+					ProxyURL:           "ftp://proxy.corp:21",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+				},
+				Output: OutputConfig{
+					Format: "csv",
+				},
+			},
+			wantError: true,
+			errorMsg:  `google.proxy_url: unsupported scheme "ftp"`,
+		},
+		{
+			name: "proxy URL without host",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+					ProxyURL:           "proxy.corp:3128",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+				},
+				Output: OutputConfig{
+					Format: "csv",
+				},
+			},
+			wantError: true,
+			errorMsg:  "google.proxy_url",
+		},
+		{
+			name: "API endpoint",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+					APIEndpoint:        "http://localhost:8080/drive/v3/",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
 				},
 				Output: OutputConfig{
 					Format: "csv",
@@ -203,15 +1096,54 @@ func TestConfig_Validate(t *testing.T) {
 			wantError: false,
 		},
 		{
-			name: "page size at maximum boundary",
+			name: "API endpoint without scheme",
 			config: Config{
 				Google: GoogleConfig{
 					ServiceAccountFile: validServiceAccountFile,
 					AdminEmail:         "admin@example.com",
 					Domain:             "example.com",
+					APIEndpoint:        "localhost:8080",
 				},
 				Audit: AuditConfig{
-					PageSize: 1000,
+					PageSize: 100,
+				},
+				Output: OutputConfig{
+					Format: "csv",
+				},
+			},
+			wantError: true,
+			errorMsg:  "google.api_endpoint",
+		},
+		{
+			name: "CA cert file without certificates",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+					CACertFile:         invalidCACertFile,
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+				},
+				Output: OutputConfig{
+					Format: "csv",
+				},
+			},
+			wantError: true,
+			errorMsg:  "google.ca_cert_file: no PEM certificates found",
+		},
+		{
+			name: "valid proxy URL",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+					ProxyURL:           "http://proxy.corp:3128",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
 				},
 				Output: OutputConfig{
 					Format: "csv",
@@ -220,7 +1152,7 @@ func TestConfig_Validate(t *testing.T) {
 			wantError: false,
 		},
 		{
-			name: "invalid output format",
+			name: "valid query",
 			config: Config{
 				Google: GoogleConfig{
 					ServiceAccountFile: validServiceAccountFile,
@@ -229,16 +1161,35 @@ func TestConfig_Validate(t *testing.T) {
 				},
 				Audit: AuditConfig{
 					PageSize: 100,
+					Query:    "'folder1' in parents",
 				},
 				Output: OutputConfig{
-					Format: "xml",
+					Format: "csv",
+				},
+			},
+			wantError: false,
+		},
+		{
+			name: "query that closes its group",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+					Query:    "name = 'a') or (trashed = true",
+				},
+				Output: OutputConfig{
+					Format: "csv",
 				},
 			},
 			wantError: true,
-			errorMsg:  "output.format must be one of",
+			errorMsg:  "audit.query: unbalanced parentheses",
 		},
 		{
-			name: "valid json format",
+			name: "max rows per file requires csv",
 			config: Config{
 				Google: GoogleConfig{
 					ServiceAccountFile: validServiceAccountFile,
@@ -249,11 +1200,150 @@ func TestConfig_Validate(t *testing.T) {
 					PageSize: 100,
 				},
 				Output: OutputConfig{
-					Format: "json",
+					Format:         "json",
+					MaxRowsPerFile: 1000,
+				},
+			},
+			wantError: true,
+			errorMsg:  "output.max_rows_per_file requires output.format csv",
+		},
+		{
+			name: "negative max rows per file",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+				},
+				Output: OutputConfig{
+					Format:         "csv",
+					MaxRowsPerFile: -1,
+				},
+			},
+			wantError: true,
+			errorMsg:  "output.max_rows_per_file must not be negative",
+		},
+		{
+			name: "zero alert threshold",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+				},
+				Output: OutputConfig{
+					Format: "csv",
+				},
+				Alert: AlertConfig{
+					MaxPublicShares: intPtr(0),
+				},
+			},
+			wantError: false,
+		},
+		{
+			name: "negative alert threshold",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+				},
+				Output: OutputConfig{
+					Format: "csv",
+				},
+				Alert: AlertConfig{
+					MaxExternalWriters: intPtr(-1),
+				},
+			},
+			wantError: true,
+			errorMsg:  "alert.max_external_writers must not be negative",
+		},
+		{
+			name: "valid sort orders",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+				},
+				Output: OutputConfig{
+					Format:       "csv",
+					SortOwnersBy: "bytes",
+					SortFilesBy:  "modified",
 				},
 			},
 			wantError: false,
 		},
+		{
+			name: "unknown owner order",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+				},
+				Output: OutputConfig{
+					Format:       "csv",
+					SortOwnersBy: "risk",
+				},
+			},
+			wantError: true,
+			errorMsg:  "output.sort_owners_by must be one of: email, bytes, count",
+		},
+		{
+			name: "unknown file order",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize: 100,
+				},
+				Output: OutputConfig{
+					Format:      "csv",
+					SortFilesBy: "created",
+				},
+			},
+			wantError: true,
+			errorMsg:  "output.sort_files_by must be one of: name, size, modified",
+		},
+		{
+			name: "chunk by owner with owner order",
+			config: Config{
+				Google: GoogleConfig{
+					ServiceAccountFile: validServiceAccountFile,
+					AdminEmail:         "admin@example.com",
+					Domain:             "example.com",
+				},
+				Audit: AuditConfig{
+					PageSize:     100,
+					ChunkByOwner: true,
+				},
+				Output: OutputConfig{
+					Format:       "csv",
+					SortOwnersBy: "count",
+				},
+			},
+			wantError: true,
+			errorMsg:  "audit.chunk_by_owner only supports output.sort_owners_by email",
+		},
 		{
 			name: "multiple validation errors",
 			config: Config{
@@ -288,6 +1378,11 @@ func TestConfig_Validate(t *testing.T) {
 	}
 }
 
+// intPtr returns a pointer to n, for optional config values.
+func intPtr(n int) *int {
+	return &n
+}
+
 func TestIsValidFormat(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -304,6 +1399,11 @@ func TestIsValidFormat(t *testing.T) {
 			format:   "json",
 			expected: true,
 		},
+		{
+			name:     "ndjson is valid",
+			format:   "ndjson",
+			expected: true,
+		},
 		{
 			name:     "xml is invalid",
 			format:   "xml",
@@ -333,5 +1433,42 @@ func TestValidOutputFormats(t *testing.T) {
 	// Ensure ValidOutputFormats contains expected formats
 	assert.Contains(t, ValidOutputFormats, "csv")
 	assert.Contains(t, ValidOutputFormats, "json")
-	assert.Len(t, ValidOutputFormats, 2)
+	assert.Contains(t, ValidOutputFormats, "ndjson")
+	assert.Contains(t, ValidOutputFormats, "xlsx")
+	assert.Contains(t, ValidOutputFormats, "sqlite")
+	assert.Contains(t, ValidOutputFormats, "sheets")
+	assert.Contains(t, ValidOutputFormats, "auto")
+	assert.Len(t, ValidOutputFormats, 7)
+}
+
+func TestValidCorpora(t *testing.T) {
+	assert.Equal(t, []string{"user", "domain", "drive", "allDrives"}, ValidCorpora)
+	assert.Contains(t, ValidCorpora, DefaultCorpora)
+}
+
+func TestValidateFieldMask(t *testing.T) {
+	tests := []struct {
+		name      string
+		mask      string
+		wantError bool
+	}{
+		{name: "empty mask", mask: "", wantError: false},
+		{name: "simple fields", mask: "id, name, description", wantError: false},
+		{name: "nested selection", mask: "id, owners(emailAddress, displayName)", wantError: false},
+		{name: "unbalanced parentheses", mask: "id, owners(emailAddress", wantError: true},
+		{name: "empty entry", mask: "id,,name", wantError: true},
+		{name: "wrapped mask", mask: "files(id, name)", wantError: true},
+		{name: "page token included", mask: "nextPageToken, id", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateFieldMask(tt.mask)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
 }
@@ -0,0 +1,61 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package directory resolves Google Workspace group membership using the
+// Admin SDK Directory API.
+package directory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/leansecurity-co/gwork/pkg/exitcode"
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+// GroupResolver resolves group members using the Admin SDK Directory API.
+type GroupResolver struct {
+	service *admin.Service
+}
+
+// NewGroupResolver creates a new GroupResolver.
+func NewGroupResolver(service *admin.Service) *GroupResolver {
+	return &GroupResolver{service: service}
+}
+
+// GroupMembers returns the email addresses of all members of a group,
+// including members of nested groups.
+func (g *GroupResolver) GroupMembers(ctx context.Context, groupEmail string) ([]string, error) {
+	var members []string
+	pageToken := ""
+
+	for {
+		call := g.service.Members.List(groupEmail).
+			IncludeDerivedMembership(true).
+			Fields("nextPageToken, members(email, type)")
+
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+
+		result, err := call.Context(ctx).Do()
+		if err != nil {
+			return nil, exitcode.Wrap(exitcode.APIError, fmt.Errorf("failed to list members of group %s: %w", groupEmail, err))
+		}
+
+		for _, m := range result.Members {
+			// Nested groups are expanded through derived membership.
+			if m.Type == "GROUP" || m.Email == "" {
+				continue
+			}
+			members = append(members, m.Email)
+		}
+
+		pageToken = result.NextPageToken
+		if pageToken == "" {
+			break
+		}
+	}
+
+	return members, nil
+}
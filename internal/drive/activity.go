@@ -0,0 +1,128 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package drive
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+)
+
+// Activity is one Drive API call recorded by an ActivityLog.
+type Activity struct {
+	Time time.Time `json:"time"`
+	// Operation is the API method called: files.list, files.get,
+	// permissions.list or drives.get.
+	Operation string `json:"operation"`
+	// FileID and DriveID identify the target of the call. files.list only
+	// has a DriveID when listing a single shared drive.
+	FileID     string `json:"file_id,omitempty"`
+	DriveID    string `json:"drive_id,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+	// Result is "ok" or "error"; failed calls also have the error and, for
+	// API errors, the HTTP status.
+	Result string `json:"result"`
+	Status int    `json:"status,omitempty"`
+	Error  string `json:"error,omitempty"`
+	// Items is the number of files or permissions a list call returned.
+	Items int `json:"items,omitempty"`
+}
+
+// ActivityLog is a DriveAPI that records every call made through it as a
+// line of JSON, giving an auditable trail of what gwork read from Drive.
+// Retries are recorded as separate calls. Errors writing the log do not
+// fail the calls, so w should remember them for the caller to report. It
+// is safe for concurrent use.
+type ActivityLog struct {
+	api DriveAPI
+	now func() time.Time
+
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewActivityLog returns a DriveAPI calling api that writes an Activity
+// line to w for each call.
+func NewActivityLog(api DriveAPI, w io.Writer) *ActivityLog {
+	return &ActivityLog{api: api, now: time.Now, enc: json.NewEncoder(w)}
+}
+
+// SetActivityLog makes the client record each Drive API call as a line of
+// JSON in w; see ActivityLog. A nil w stops recording. Call it before the
+// client is used.
+func (c *Client) SetActivityLog(w io.Writer) {
+	if log, ok := c.api.(*ActivityLog); ok {
+		c.api = log.api
+	}
+	if w != nil {
+		c.api = NewActivityLog(c.api, w)
+	}
+}
+
+// ListFiles lists files and records a files.list call.
+func (l *ActivityLog) ListFiles(ctx context.Context, opts *ListFilesOptions) (*ListFilesResult, error) {
+	start := l.now()
+	result, err := l.api.ListFiles(ctx, opts)
+	entry := Activity{Operation: "files.list", DriveID: opts.DriveID}
+	if result != nil {
+		entry.Items = len(result.Files)
+	}
+	l.record(start, entry, err)
+	return result, err
+}
+
+// ListPermissions lists permissions and records a permissions.list call.
+func (l *ActivityLog) ListPermissions(ctx context.Context, fileID string, opts *ListPermissionsOptions) (*ListPermissionsResult, error) {
+	start := l.now()
+	result, err := l.api.ListPermissions(ctx, fileID, opts)
+	entry := Activity{Operation: "permissions.list", FileID: fileID}
+	if result != nil {
+		entry.Items = len(result.Permissions)
+	}
+	l.record(start, entry, err)
+	return result, err
+}
+
+// GetFile gets a file and records a files.get call.
+func (l *ActivityLog) GetFile(ctx context.Context, fileID string, opts *GetFileOptions) (*drive.File, error) {
+	start := l.now()
+	file, err := l.api.GetFile(ctx, fileID, opts)
+	l.record(start, Activity{Operation: "files.get", FileID: fileID}, err)
+	return file, err
+}
+
+// GetDrive gets a shared drive and records a drives.get call.
+func (l *ActivityLog) GetDrive(ctx context.Context, driveID string) (*drive.Drive, error) {
+	start := l.now()
+	d, err := l.api.GetDrive(ctx, driveID)
+	l.record(start, Activity{Operation: "drives.get", DriveID: driveID}, err)
+	return d, err
+}
+
+// record completes entry with the timing and outcome of a call that
+// started at start and writes it.
+func (l *ActivityLog) record(start time.Time, entry Activity, err error) {
+	entry.Time = start.UTC()
+	entry.DurationMS = l.now().Sub(start).Milliseconds()
+	entry.Result = "ok"
+	if err != nil {
+		entry.Result = "error"
+		entry.Error = strings.TrimSpace(err.Error())
+		var apiErr *googleapi.Error
+		if errors.As(err, &apiErr) {
+			entry.Status = apiErr.Code
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_ = l.enc.Encode(entry)
+}
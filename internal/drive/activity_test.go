@@ -0,0 +1,104 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package drive
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	v3 "google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+)
+
+// readActivity decodes the lines of an activity log.
+func readActivity(t *testing.T, log *bytes.Buffer) []Activity {
+	t.Helper()
+	var entries []Activity
+	for line := range strings.Lines(log.String()) {
+		var entry Activity
+		require.NoError(t, json.Unmarshal([]byte(line), &entry), "line %q", line)
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestActivityLog_RecordsEachCall(t *testing.T) {
+	mockAPI := new(MockDriveAPI)
+	mockAPI.On("ListFiles", mock.Anything, mock.Anything).Return(&ListFilesResult{
+		Files: []*v3.File{{Id: "file1"}, {Id: "file2"}},
+	}, nil).Once()
+	mockAPI.On("ListPermissions", mock.Anything, "file1", mock.Anything).Return(&ListPermissionsResult{
+		Permissions: []*v3.Permission{{Id: "perm1"}},
+	}, nil).Once()
+	mockAPI.On("GetFile", mock.Anything, "file2", mock.Anything).Return(nil, &googleapi.Error{Code: http.StatusNotFound, Message: "File not found"}).Once()
+	mockAPI.On("GetDrive", mock.Anything, "drive1").Return(&v3.Drive{Id: "drive1", Name: "Finance"}, nil).Once()
+
+	var log bytes.Buffer
+	api := NewActivityLog(mockAPI, &log)
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	clock := &fakeClock{now: start}
+	api.now = func() time.Time {
+		now := clock.Now()
+		clock.Advance(50 * time.Millisecond)
+		return now
+	}
+
+	ctx := context.Background()
+	_, err := api.ListFiles(ctx, &ListFilesOptions{Corpora: "drive", DriveID: "drive1"})
+	require.NoError(t, err)
+	_, err = api.ListPermissions(ctx, "file1", &ListPermissionsOptions{})
+	require.NoError(t, err)
+	_, err = api.GetFile(ctx, "file2", &GetFileOptions{})
+	require.Error(t, err)
+	_, err = api.GetDrive(ctx, "drive1")
+	require.NoError(t, err)
+	mockAPI.AssertExpectations(t)
+
+	entries := readActivity(t, &log)
+	require.Len(t, entries, 4, "one line per call")
+	want := []Activity{
+		{Operation: "files.list", DriveID: "drive1", Result: "ok", Items: 2},
+		{Operation: "permissions.list", FileID: "file1", Result: "ok", Items: 1},
+		{Operation: "files.get", FileID: "file2", Result: "error", Status: http.StatusNotFound},
+		{Operation: "drives.get", DriveID: "drive1", Result: "ok"},
+	}
+	for i := range want {
+		got := entries[i]
+		assert.Equal(t, start.Add(time.Duration(i)*100*time.Millisecond), got.Time, "entry %d", i)
+		assert.Equal(t, int64(50), got.DurationMS, "entry %d", i)
+		if want[i].Result == "error" {
+			assert.Contains(t, got.Error, "File not found")
+		}
+		got.Time, got.DurationMS, got.Error = time.Time{}, 0, ""
+		assert.Equal(t, want[i], got, "entry %d", i)
+	}
+}
+
+func TestClient_SetActivityLog(t *testing.T) {
+	mockAPI := new(MockDriveAPI)
+	mockAPI.On("GetDrive", mock.Anything, "drive1").Return(&v3.Drive{Id: "drive1", Name: "Finance"}, nil).Once()
+	mockAPI.On("GetDrive", mock.Anything, "drive2").Return(&v3.Drive{Id: "drive2", Name: "Legal"}, nil).Once()
+
+	var first, second bytes.Buffer
+	client := NewClientWithOptions(mockAPI, Options{ActivityLog: &first})
+	client.SetActivityLog(&second)
+	client.DriveName(context.Background(), "drive1")
+	client.SetActivityLog(nil)
+	client.DriveName(context.Background(), "drive2")
+
+	assert.Same(t, mockAPI, client.api, "a nil writer removes the log")
+	assert.Empty(t, first.String(), "a new writer replaces the old one")
+	entries := readActivity(t, &second)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "drives.get", entries[0].Operation)
+	assert.Equal(t, "drive1", entries[0].DriveID)
+}
@@ -0,0 +1,25 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package drive_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/drive"
+	"github.com/leansecurity-co/gwork/internal/drive/drivetest"
+)
+
+func BenchmarkClient_ListAllFiles(b *testing.B) {
+	fake := &drivetest.FakeAPI{Files: 10000, Owners: 50}
+	client := drive.NewClientWithAPI(fake, "example.com", 1000, false)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := client.ListAllFiles(ctx); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package drive
+
+import "errors"
+
+// ErrBudgetExceeded is returned, along with any results collected so far,
+// once a client has made the number of API calls set by SetMaxAPICalls.
+var ErrBudgetExceeded = errors.New("API call budget exceeded")
+
+// SetMaxAPICalls caps the number of files.list and permissions.list calls
+// the client makes. Zero or less means no limit.
+func (c *Client) SetMaxAPICalls(n int64) {
+	c.maxAPICalls = n
+}
+
+// reserveCall claims one call from the budget before an API call is made.
+// It is safe for concurrent use.
+func (c *Client) reserveCall() error {
+	if c.maxAPICalls <= 0 {
+		return nil
+	}
+	if c.reservedCalls.Add(1) > c.maxAPICalls {
+		return ErrBudgetExceeded
+	}
+	return nil
+}
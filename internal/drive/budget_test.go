@@ -0,0 +1,88 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package drive
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	v3 "google.golang.org/api/drive/v3"
+)
+
+// endlessFilesAPI is a DriveAPI that lists one file per page without end
+// and counts the calls made to it.
+type endlessFilesAPI struct {
+	listFilesCalls int
+}
+
+func (f *endlessFilesAPI) ListFiles(_ context.Context, _ *ListFilesOptions) (*ListFilesResult, error) {
+	f.listFilesCalls++
+	return &ListFilesResult{
+		Files:         []*v3.File{{Id: fmt.Sprintf("file%d", f.listFilesCalls)}},
+		NextPageToken: fmt.Sprintf("page%d", f.listFilesCalls+1),
+	}, nil
+}
+
+func (f *endlessFilesAPI) ListPermissions(_ context.Context, _ string, _ *ListPermissionsOptions) (*ListPermissionsResult, error) {
+	return &ListPermissionsResult{}, nil
+}
+
+func (f *endlessFilesAPI) GetFile(_ context.Context, fileID string, _ *GetFileOptions) (*v3.File, error) {
+	return &v3.File{Id: fileID}, nil
+}
+
+func (f *endlessFilesAPI) GetDrive(_ context.Context, driveID string) (*v3.Drive, error) {
+	return nil, fmt.Errorf("shared drive not found: %s", driveID)
+}
+
+func TestClient_MaxAPICalls_ListFiles(t *testing.T) {
+	api := &endlessFilesAPI{}
+	client := NewClientWithAPI(api, "example.com", 100, false)
+	client.SetMaxAPICalls(3)
+
+	files, err := client.ListAllFiles(context.Background())
+	require.ErrorIs(t, err, ErrBudgetExceeded)
+	assert.Len(t, files, 3, "files from pages within the budget are kept")
+	assert.Equal(t, 3, api.listFilesCalls)
+	assert.Equal(t, int64(3), client.Stats().Total().Calls)
+}
+
+func TestClient_MaxAPICalls_SharedAcrossMethods(t *testing.T) {
+	mockAPI := new(MockDriveAPI)
+	mockAPI.On("ListFiles", mock.Anything, mock.Anything).
+		Return(&ListFilesResult{Files: []*v3.File{{Id: "file1"}}}, nil)
+	mockAPI.On("ListPermissions", mock.Anything, mock.Anything, mock.Anything).
+		Return(&ListPermissionsResult{Permissions: []*v3.Permission{{Id: "p1", Type: "anyone"}}}, nil)
+
+	client := NewClientWithAPI(mockAPI, "example.com", 100, false)
+	client.SetMaxAPICalls(2)
+
+	_, err := client.ListAllFiles(context.Background())
+	require.NoError(t, err)
+	perms, err := client.GetFilePermissions(context.Background(), "file1")
+	require.NoError(t, err)
+	assert.Len(t, perms, 1)
+
+	perms, err = client.GetFilePermissions(context.Background(), "file2")
+	require.ErrorIs(t, err, ErrBudgetExceeded)
+	assert.Empty(t, perms)
+	mockAPI.AssertNumberOfCalls(t, "ListPermissions", 1)
+}
+
+func TestClient_MaxAPICalls_Unlimited(t *testing.T) {
+	mockAPI := new(MockDriveAPI)
+	mockAPI.On("ListPermissions", mock.Anything, mock.Anything, mock.Anything).
+		Return(&ListPermissionsResult{}, nil)
+
+	client := NewClientWithAPI(mockAPI, "example.com", 100, false)
+	for i := 0; i < 10; i++ {
+		_, err := client.GetFilePermissions(context.Background(), "file1")
+		require.NoError(t, err)
+	}
+	mockAPI.AssertNumberOfCalls(t, "ListPermissions", 10)
+}
@@ -4,6 +4,11 @@
 package drive
 
 import (
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+
 	"google.golang.org/api/drive/v3"
 )
 
@@ -11,29 +16,91 @@ import (
 type Client struct {
 	api                 DriveAPI
 	domain              string
+	domainAliases       []string
+	internalEmails      *regexp.Regexp
 	pageSize            int64
 	includeSharedDrives bool
+	corpora             string
+	driveIDs            []string
+	includeTrashed      bool
+	query               string
+	fileFields          string
+	permissionFields    string
+	strictUnknown       bool
+
+	listFilesCalls       callCounter
+	listPermissionsCalls callCounter
+
+	maxAPICalls   int64
+	reservedCalls atomic.Int64
+
+	retryCodes map[int]struct{}
+	retryDelay time.Duration
+
+	throttle throttle
+
+	driveNames driveNameCache
 }
 
 // NewClient creates a new Drive client with the real Google Drive service.
+// It is equivalent to NewClientWithOptions with NewGoogleDriveAPI(service)
+// and these options set.
 func NewClient(service *drive.Service, domain string, pageSize int64, includeSharedDrives bool) *Client {
-	return &Client{
-		api:                 NewGoogleDriveAPI(service),
-		domain:              domain,
-		pageSize:            pageSize,
-		includeSharedDrives: includeSharedDrives,
-	}
+	return NewClientWithAPI(NewGoogleDriveAPI(service), domain, pageSize, includeSharedDrives)
 }
 
 // NewClientWithAPI creates a new Drive client with a custom DriveAPI implementation.
-// This is primarily used for testing.
+// This is primarily used for testing; NewClientWithOptions takes the other
+// settings as well.
 func NewClientWithAPI(api DriveAPI, domain string, pageSize int64, includeSharedDrives bool) *Client {
-	return &Client{
-		api:                 api,
-		domain:              domain,
-		pageSize:            pageSize,
-		includeSharedDrives: includeSharedDrives,
-	}
+	return NewClientWithOptions(api, Options{
+		Domain:              domain,
+		PageSize:            pageSize,
+		IncludeSharedDrives: includeSharedDrives,
+	})
+}
+
+// SetCorpora sets the corpora files are listed from. An empty corpora
+// selects "domain". When drive IDs are given, listing is restricted to
+// those shared drives using the "drive" corpora and corpora is ignored.
+func (c *Client) SetCorpora(corpora string, driveIDs ...string) {
+	c.corpora = corpora
+	c.driveIDs = driveIDs
+}
+
+// SetDomainAliases sets alias domains of the primary domain. Shares to an
+// alias domain are treated as internal.
+func (c *Client) SetDomainAliases(aliases ...string) {
+	c.domainAliases = aliases
+}
+
+// SetInternalEmailPattern makes user and group emails matching re internal
+// and all others external, whatever their domain. A nil re restores the
+// domain comparison. Shares with a whole domain are still compared by
+// domain.
+func (c *Client) SetInternalEmailPattern(re *regexp.Regexp) {
+	c.internalEmails = re
+}
+
+// SetIncludeTrashed controls whether trashed files are listed.
+func (c *Client) SetIncludeTrashed(includeTrashed bool) {
+	c.includeTrashed = includeTrashed
+}
+
+// SetQuery restricts file listing to files matching a Drive search query,
+// e.g. "'<folder ID>' in parents". The query is combined with the built
+// clauses as a parenthesized group; check it with drivesyntax.ValidateQuery first. An
+// empty query lists all files.
+func (c *Client) SetQuery(query string) {
+	c.query = strings.TrimSpace(query)
+}
+
+// SetFieldMasks overrides the per-file and per-permission field masks, e.g.
+// "id, name, owners, description". Fields needed internally are added when
+// missing. An empty mask keeps the default.
+func (c *Client) SetFieldMasks(fileFields, permissionFields string) {
+	c.fileFields = fileFields
+	c.permissionFields = permissionFields
 }
 
 // Domain returns the configured domain.
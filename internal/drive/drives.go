@@ -0,0 +1,69 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package drive
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"google.golang.org/api/drive/v3"
+)
+
+// driveNameCache holds the outcome of each shared drive lookup, so every
+// drive is looked up at most once per client.
+type driveNameCache struct {
+	mu      sync.Mutex
+	entries map[string]driveNameEntry
+}
+
+type driveNameEntry struct {
+	name string
+	err  error
+}
+
+// DriveName returns the name of the shared drive driveID. Lookups are
+// cached, failures included, so each drive costs at most one API call
+// however many files live in it. It is safe for concurrent use; lookups
+// are serialized.
+func (c *Client) DriveName(ctx context.Context, driveID string) (string, error) {
+	c.driveNames.mu.Lock()
+	defer c.driveNames.mu.Unlock()
+
+	if entry, ok := c.driveNames.entries[driveID]; ok {
+		return entry.name, entry.err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	name, err := c.getDriveName(ctx, driveID)
+	if err != nil && ctx.Err() != nil {
+		// Not cached: the lookup did not fail on its own.
+		return "", err
+	}
+	if c.driveNames.entries == nil {
+		c.driveNames.entries = make(map[string]driveNameEntry)
+	}
+	c.driveNames.entries[driveID] = driveNameEntry{name: name, err: err}
+	return name, err
+}
+
+// getDriveName fetches the name of the shared drive driveID.
+func (c *Client) getDriveName(ctx context.Context, driveID string) (string, error) {
+	if err := c.reserveCall(); err != nil {
+		return "", err
+	}
+
+	var d *drive.Drive
+	err := c.retry(ctx, func() error {
+		var err error
+		d, err = c.api.GetDrive(ctx, driveID)
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get shared drive %s: %w", driveID, err)
+	}
+	return d.Name, nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package drive
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	v3 "google.golang.org/api/drive/v3"
+)
+
+func TestClient_DriveName_CachesLookups(t *testing.T) {
+	mockAPI := new(MockDriveAPI)
+	mockAPI.On("GetDrive", mock.Anything, "drive1").Return(&v3.Drive{Id: "drive1", Name: "Finance"}, nil).Once()
+	mockAPI.On("GetDrive", mock.Anything, "drive2").Return(&v3.Drive{Id: "drive2", Name: "Legal"}, nil).Once()
+	client := NewClientWithAPI(mockAPI, "example.com", 100, true)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			name, err := client.DriveName(context.Background(), "drive1")
+			assert.NoError(t, err)
+			assert.Equal(t, "Finance", name)
+		}()
+	}
+	wg.Wait()
+
+	name, err := client.DriveName(context.Background(), "drive2")
+	require.NoError(t, err)
+	assert.Equal(t, "Legal", name)
+
+	mockAPI.AssertExpectations(t)
+	mockAPI.AssertNumberOfCalls(t, "GetDrive", 2)
+}
+
+func TestClient_DriveName_CachesFailures(t *testing.T) {
+	mockAPI := new(MockDriveAPI)
+	mockAPI.On("GetDrive", mock.Anything, "drive1").Return(nil, errors.New("not a member")).Once()
+	client := NewClientWithAPI(mockAPI, "example.com", 100, true)
+
+	for range 3 {
+		name, err := client.DriveName(context.Background(), "drive1")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to get shared drive drive1: not a member")
+		assert.Empty(t, name)
+	}
+	mockAPI.AssertNumberOfCalls(t, "GetDrive", 1)
+}
+
+func TestClient_DriveName_CanceledNotCached(t *testing.T) {
+	mockAPI := new(MockDriveAPI)
+	mockAPI.On("GetDrive", mock.Anything, "drive1").Return(&v3.Drive{Id: "drive1", Name: "Finance"}, nil)
+	client := NewClientWithAPI(mockAPI, "example.com", 100, true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := client.DriveName(ctx, "drive1")
+	require.ErrorIs(t, err, context.Canceled)
+
+	name, err := client.DriveName(context.Background(), "drive1")
+	require.NoError(t, err)
+	assert.Equal(t, "Finance", name)
+}
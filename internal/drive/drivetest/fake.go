@@ -0,0 +1,277 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package drivetest provides an in-memory drive.DriveAPI serving synthetic
+// files and permissions, for benchmarks and integration-style tests.
+package drivetest
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/leansecurity-co/gwork/internal/drive"
+	v3 "google.golang.org/api/drive/v3"
+)
+
+// DefaultPageSize is the number of files per page when neither the fake nor
+// the request sets one.
+const DefaultPageSize = 100
+
+// Page token prefixes. Tokens carry the offset of the next item.
+const (
+	filesTokenPrefix       = "files-"
+	permissionsTokenPrefix = "perms-"
+)
+
+// FakeAPI is an in-memory drive.DriveAPI. Files are generated from their
+// index on each call, so large file counts cost no memory up front. The
+// zero value lists no files. It is safe for concurrent use.
+type FakeAPI struct {
+	// Files is the number of files listed.
+	Files int
+	// PageSize caps the files per page. Zero uses the page size of the
+	// request, or DefaultPageSize.
+	PageSize int
+	// Owners is the number of distinct owners files are spread across,
+	// round robin. Zero means one owner.
+	Owners int
+	// Domain is the domain of owners and internal grantees. Empty means
+	// "example.com".
+	Domain string
+
+	// InternalPermissions is the number of internal user permissions on
+	// every file, in addition to the owner.
+	InternalPermissions int
+	// ExternalEvery shares every Nth file with an external user. Zero
+	// never does.
+	ExternalEvery int
+	// PublicEvery shares every Nth file with anyone with the link. Zero
+	// never does.
+	PublicEvery int
+	// PermissionsPageSize splits each file's permissions into pages of
+	// this size. Zero returns them in one page.
+	PermissionsPageSize int
+
+	// DriveNames maps shared drive IDs to the names GetDrive serves.
+	// Files are never placed in shared drives.
+	DriveNames map[string]string
+
+	// ListFilesError, when set, is called with the zero-based page number
+	// before each files page is served; a non-nil error is returned instead
+	// of the page.
+	ListFilesError func(page int) error
+	// ListPermissionsError, when set, is called with the file ID before
+	// permissions are served; a non-nil error is returned instead.
+	ListPermissionsError func(fileID string) error
+
+	listFilesCalls       atomic.Int64
+	listPermissionsCalls atomic.Int64
+}
+
+// Interface guard.
+var _ drive.DriveAPI = (*FakeAPI)(nil)
+
+// FileID returns the ID of the file at index i.
+func FileID(i int) string {
+	return fmt.Sprintf("file%07d", i)
+}
+
+// ListFiles serves a page of synthetic files. The query is ignored.
+func (f *FakeAPI) ListFiles(ctx context.Context, opts *drive.ListFilesOptions) (*drive.ListFilesResult, error) {
+	f.listFilesCalls.Add(1)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	start, err := parseToken(opts.PageToken, filesTokenPrefix)
+	if err != nil {
+		return nil, err
+	}
+	size := f.pageSize(opts.PageSize)
+	if f.ListFilesError != nil {
+		if err := f.ListFilesError(start / size); err != nil {
+			return nil, err
+		}
+	}
+
+	end := min(start+size, f.Files)
+	result := &drive.ListFilesResult{Files: make([]*v3.File, 0, max(end-start, 0))}
+	for i := start; i < end; i++ {
+		result.Files = append(result.Files, f.file(i))
+	}
+	if end < f.Files {
+		result.NextPageToken = filesTokenPrefix + strconv.Itoa(end)
+	}
+	return result, nil
+}
+
+// ListPermissions serves the synthetic permissions of a file.
+func (f *FakeAPI) ListPermissions(ctx context.Context, fileID string, opts *drive.ListPermissionsOptions) (*drive.ListPermissionsResult, error) {
+	f.listPermissionsCalls.Add(1)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if f.ListPermissionsError != nil {
+		if err := f.ListPermissionsError(fileID); err != nil {
+			return nil, err
+		}
+	}
+
+	i, err := f.fileIndex(fileID)
+	if err != nil {
+		return nil, err
+	}
+	start, err := parseToken(opts.PageToken, permissionsTokenPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	perms := f.permissions(i)
+	end := len(perms)
+	if f.PermissionsPageSize > 0 {
+		end = min(start+f.PermissionsPageSize, len(perms))
+	}
+	result := &drive.ListPermissionsResult{Permissions: perms[min(start, end):end]}
+	if end < len(perms) {
+		result.NextPageToken = permissionsTokenPrefix + strconv.Itoa(end)
+	}
+	return result, nil
+}
+
+// GetFile serves the metadata of a single synthetic file.
+func (f *FakeAPI) GetFile(ctx context.Context, fileID string, _ *drive.GetFileOptions) (*v3.File, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	i, err := f.fileIndex(fileID)
+	if err != nil {
+		return nil, err
+	}
+	return f.file(i), nil
+}
+
+// GetDrive serves the name of a shared drive in DriveNames.
+func (f *FakeAPI) GetDrive(ctx context.Context, driveID string) (*v3.Drive, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	name, ok := f.DriveNames[driveID]
+	if !ok {
+		return nil, fmt.Errorf("shared drive not found: %s", driveID)
+	}
+	return &v3.Drive{Id: driveID, Name: name}, nil
+}
+
+// Calls returns the number of ListFiles and ListPermissions calls made.
+func (f *FakeAPI) Calls() (listFiles, listPermissions int64) {
+	return f.listFilesCalls.Load(), f.listPermissionsCalls.Load()
+}
+
+// ExternalShares returns the number of external permissions, including
+// public ones, across all files.
+func (f *FakeAPI) ExternalShares() int {
+	return every(f.Files, f.ExternalEvery) + every(f.Files, f.PublicEvery)
+}
+
+// PublicShares returns the number of anyone permissions across all files.
+func (f *FakeAPI) PublicShares() int {
+	return every(f.Files, f.PublicEvery)
+}
+
+// every returns how many of n indexes are multiples of step, counting from
+// index step-1.
+func every(n, step int) int {
+	if step <= 0 {
+		return 0
+	}
+	return n / step
+}
+
+// pageSize returns the files page size for a request.
+func (f *FakeAPI) pageSize(requested int64) int {
+	switch {
+	case f.PageSize > 0:
+		return f.PageSize
+	case requested > 0:
+		return int(requested)
+	default:
+		return DefaultPageSize
+	}
+}
+
+// domain returns the configured domain or the default.
+func (f *FakeAPI) domain() string {
+	if f.Domain == "" {
+		return "example.com"
+	}
+	return f.Domain
+}
+
+// owner returns the owner email of the file at index i.
+func (f *FakeAPI) owner(i int) string {
+	owners := max(f.Owners, 1)
+	return fmt.Sprintf("user%d@%s", i%owners, f.domain())
+}
+
+// file builds the file at index i.
+func (f *FakeAPI) file(i int) *v3.File {
+	owner := f.owner(i)
+	return &v3.File{
+		Id:           FileID(i),
+		Name:         fmt.Sprintf("Document %d", i),
+		MimeType:     "application/vnd.google-apps.document",
+		Owners:       []*v3.User{{EmailAddress: owner, DisplayName: strings.SplitN(owner, "@", 2)[0]}},
+		CreatedTime:  "2025-01-01T00:00:00Z",
+		ModifiedTime: "2025-02-01T00:00:00Z",
+		Size:         int64(1024 * (i%10 + 1)),
+	}
+}
+
+// permissions builds the permissions of the file at index i.
+func (f *FakeAPI) permissions(i int) []*v3.Permission {
+	perms := []*v3.Permission{
+		{Id: "owner", Type: "user", Role: "owner", EmailAddress: f.owner(i)},
+	}
+	for n := range f.InternalPermissions {
+		perms = append(perms, &v3.Permission{
+			Id: fmt.Sprintf("internal%d", n), Type: "user", Role: "reader",
+			EmailAddress: fmt.Sprintf("colleague%d@%s", n, f.domain()),
+		})
+	}
+	if f.ExternalEvery > 0 && (i+1)%f.ExternalEvery == 0 {
+		perms = append(perms, &v3.Permission{
+			Id: "external", Type: "user", Role: "writer", EmailAddress: fmt.Sprintf("guest%d@partner.example", i),
+		})
+	}
+	if f.PublicEvery > 0 && (i+1)%f.PublicEvery == 0 {
+		// Drive gives link sharing the permission ID anyoneWithLink; the
+		// type is anyone, and allowFileDiscovery is unset.
+		perms = append(perms, &v3.Permission{Id: "anyoneWithLink", Type: "anyone", Role: "reader"})
+	}
+	return perms
+}
+
+// fileIndex returns the index of a file ID served by the fake.
+func (f *FakeAPI) fileIndex(fileID string) (int, error) {
+	i, err := strconv.Atoi(strings.TrimPrefix(fileID, "file"))
+	if err != nil || !strings.HasPrefix(fileID, "file") || i < 0 || i >= f.Files {
+		return 0, fmt.Errorf("file not found: %s", fileID)
+	}
+	return i, nil
+}
+
+// parseToken returns the offset encoded in a page token, or zero for the
+// first page.
+func parseToken(token, prefix string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+	offset, err := strconv.Atoi(strings.TrimPrefix(token, prefix))
+	if err != nil || !strings.HasPrefix(token, prefix) || offset < 0 {
+		return 0, fmt.Errorf("invalid page token %q", token)
+	}
+	return offset, nil
+}
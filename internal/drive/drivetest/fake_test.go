@@ -0,0 +1,143 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package drivetest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/drive"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeAPI_ListFiles_Pagination(t *testing.T) {
+	tests := []struct {
+		name      string
+		files     int
+		pageSize  int
+		wantPages int64
+	}{
+		{name: "no files", files: 0, pageSize: 10, wantPages: 1},
+		{name: "single partial page", files: 7, pageSize: 10, wantPages: 1},
+		{name: "exact pages", files: 30, pageSize: 10, wantPages: 3},
+		{name: "trailing partial page", files: 25, pageSize: 10, wantPages: 3},
+		{name: "request page size", files: 250, pageSize: 0, wantPages: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &FakeAPI{Files: tt.files, PageSize: tt.pageSize}
+			client := drive.NewClientWithAPI(fake, "example.com", 100, false)
+
+			files, err := client.ListAllFiles(context.Background())
+			require.NoError(t, err)
+			require.Len(t, files, tt.files)
+
+			seen := make(map[string]bool, len(files))
+			for i, f := range files {
+				assert.Equal(t, FileID(i), f.ID)
+				assert.False(t, seen[f.ID], "duplicate file %s", f.ID)
+				seen[f.ID] = true
+			}
+
+			pages, _ := fake.Calls()
+			assert.Equal(t, tt.wantPages, pages)
+		})
+	}
+}
+
+func TestFakeAPI_ListFiles_InvalidToken(t *testing.T) {
+	fake := &FakeAPI{Files: 10}
+
+	_, err := fake.ListFiles(context.Background(), &drive.ListFilesOptions{PageToken: "bogus"})
+	assert.ErrorContains(t, err, "invalid page token")
+}
+
+func TestFakeAPI_ListPermissions_Distribution(t *testing.T) {
+	fake := &FakeAPI{
+		Files:               12,
+		InternalPermissions: 2,
+		ExternalEvery:       3,
+		PublicEvery:         4,
+		PermissionsPageSize: 2,
+	}
+	client := drive.NewClientWithAPI(fake, "example.com", 100, false)
+
+	var external, public int
+	for i := range fake.Files {
+		perms, err := client.GetFilePermissions(context.Background(), FileID(i))
+		require.NoError(t, err)
+		for _, p := range perms {
+			if client.IsExternalShare(p) {
+				external++
+			}
+			if p.Type == "anyone" {
+				public++
+			}
+		}
+	}
+
+	assert.Equal(t, 7, fake.ExternalShares())
+	assert.Equal(t, fake.ExternalShares(), external)
+	assert.Equal(t, fake.PublicShares(), public)
+}
+
+func TestFakeAPI_ListPermissions_UnknownFile(t *testing.T) {
+	fake := &FakeAPI{Files: 1}
+
+	_, err := fake.ListPermissions(context.Background(), "file9999999", &drive.ListPermissionsOptions{})
+	assert.ErrorContains(t, err, "file not found")
+}
+
+func TestFakeAPI_ErrorInjection(t *testing.T) {
+	errQuota := errors.New("quota exceeded")
+
+	t.Run("files page", func(t *testing.T) {
+		fake := &FakeAPI{
+			Files:    50,
+			PageSize: 10,
+			ListFilesError: func(page int) error {
+				if page == 2 {
+					return errQuota
+				}
+				return nil
+			},
+		}
+		client := drive.NewClientWithAPI(fake, "example.com", 100, false)
+
+		_, err := client.ListAllFiles(context.Background())
+		require.ErrorIs(t, err, errQuota)
+
+		pages, _ := fake.Calls()
+		assert.Equal(t, int64(3), pages)
+	})
+
+	t.Run("permissions", func(t *testing.T) {
+		fake := &FakeAPI{
+			Files: 3,
+			ListPermissionsError: func(fileID string) error {
+				if fileID == FileID(1) {
+					return errQuota
+				}
+				return nil
+			},
+		}
+		client := drive.NewClientWithAPI(fake, "example.com", 100, false)
+
+		_, err := client.GetFilePermissions(context.Background(), FileID(0))
+		require.NoError(t, err)
+		_, err = client.GetFilePermissions(context.Background(), FileID(1))
+		require.ErrorIs(t, err, errQuota)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := (&FakeAPI{Files: 1}).ListFiles(ctx, &drive.ListFilesOptions{})
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -0,0 +1,55 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package drive
+
+import (
+	"strings"
+
+	"github.com/leansecurity-co/gwork/internal/drivesyntax"
+)
+
+// DefaultFileFields is the default field mask for each listed file.
+const DefaultFileFields = "id, name, mimeType, owners, createdTime, modifiedTime, size, trashed, webViewLink, driveId, viewedByMeTime"
+
+// DefaultPermissionFields is the default field mask for each permission.
+const DefaultPermissionFields = "id, type, role, emailAddress, domain, displayName, permissionDetails(inherited, inheritedFrom), expirationTime, deleted"
+
+// requiredFileFields are always requested: files are tracked by ID, file
+// snapshots and stale-file checks need modifiedTime and viewedByMeTime, and
+// driveId places files in their shared drive.
+var requiredFileFields = []string{"id", "modifiedTime", "driveId", "viewedByMeTime"}
+
+// requiredPermissionFields are always requested since they drive the
+// external share classification and --deleted-grantees-only.
+var requiredPermissionFields = []string{"id", "type", "emailAddress", "domain", "deleted"}
+
+// withRequiredFields returns the mask with any missing required fields
+// appended. An empty mask selects the default.
+func withRequiredFields(mask, defaultMask string, required []string) string {
+	if strings.TrimSpace(mask) == "" {
+		return defaultMask
+	}
+
+	fields := drivesyntax.SplitFields(mask)
+	present := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		present[fieldName(f)] = true
+	}
+
+	for _, r := range required {
+		if !present[r] {
+			fields = append(fields, r)
+		}
+	}
+
+	return strings.Join(fields, ", ")
+}
+
+// fieldName returns the top-level name of a field selection.
+func fieldName(field string) string {
+	if idx := strings.Index(field, "("); idx >= 0 {
+		field = field[:idx]
+	}
+	return strings.TrimSpace(field)
+}
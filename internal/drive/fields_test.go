@@ -0,0 +1,98 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package drive
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRequiredFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		mask     string
+		required []string
+		expected string
+	}{
+		{
+			name:     "empty mask uses default",
+			mask:     "",
+			required: requiredFileFields,
+			expected: DefaultFileFields,
+		},
+		{
+			name:     "required fields already present",
+			mask:     "id, name, modifiedTime, driveId, viewedByMeTime",
+			required: requiredFileFields,
+			expected: "id, name, modifiedTime, driveId, viewedByMeTime",
+		},
+		{
+			name:     "missing required fields appended",
+			mask:     "name, description, driveId",
+			required: requiredFileFields,
+			expected: "name, description, driveId, id, modifiedTime, viewedByMeTime",
+		},
+		{
+			name:     "nested selection counts as present",
+			mask:     "id, type, emailAddress, domain, deleted, permissionDetails(inherited)",
+			required: requiredPermissionFields,
+			expected: "id, type, emailAddress, domain, deleted, permissionDetails(inherited)",
+		},
+		{
+			name:     "multiple missing permission fields",
+			mask:     "role",
+			required: requiredPermissionFields,
+			expected: "role, id, type, emailAddress, domain, deleted",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, withRequiredFields(tt.mask, DefaultFileFields, tt.required))
+		})
+	}
+}
+
+func TestClient_FieldMaskOverrides(t *testing.T) {
+	mockAPI := new(MockDriveAPI)
+	mockAPI.On("ListFiles", mock.Anything, mock.MatchedBy(func(opts *ListFilesOptions) bool {
+		return opts.Fields == "nextPageToken, files(name, description, id, modifiedTime, driveId, viewedByMeTime)"
+	})).Return(&ListFilesResult{}, nil)
+	mockAPI.On("ListPermissions", mock.Anything, "file1", mock.MatchedBy(func(opts *ListPermissionsOptions) bool {
+		return opts.Fields == "nextPageToken, permissions(role, expirationTime, id, type, emailAddress, domain, deleted)"
+	})).Return(&ListPermissionsResult{}, nil)
+
+	client := NewClientWithAPI(mockAPI, "example.com", 100, false)
+	client.SetFieldMasks("name, description", "role, expirationTime")
+
+	_, err := client.ListAllFiles(context.Background())
+	require.NoError(t, err)
+	_, err = client.GetFilePermissions(context.Background(), "file1")
+	require.NoError(t, err)
+
+	mockAPI.AssertExpectations(t)
+}
+
+func TestClient_DefaultFieldMasks(t *testing.T) {
+	mockAPI := new(MockDriveAPI)
+	mockAPI.On("ListFiles", mock.Anything, mock.MatchedBy(func(opts *ListFilesOptions) bool {
+		return opts.Fields == "nextPageToken, files("+DefaultFileFields+")"
+	})).Return(&ListFilesResult{}, nil)
+	mockAPI.On("ListPermissions", mock.Anything, "file1", mock.MatchedBy(func(opts *ListPermissionsOptions) bool {
+		return opts.Fields == "nextPageToken, permissions("+DefaultPermissionFields+")"
+	})).Return(&ListPermissionsResult{}, nil)
+
+	client := NewClientWithAPI(mockAPI, "example.com", 100, false)
+
+	_, err := client.ListAllFiles(context.Background())
+	require.NoError(t, err)
+	_, err = client.GetFilePermissions(context.Background(), "file1")
+	require.NoError(t, err)
+
+	mockAPI.AssertExpectations(t)
+}
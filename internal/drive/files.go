@@ -6,56 +6,221 @@ package drive
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/api/drive/v3"
 )
 
-// ListAllFiles retrieves all files in the domain.
+// ListAllFiles retrieves all files in the domain. When drive IDs are
+// configured, only those shared drives are listed, one after another.
 func (c *Client) ListAllFiles(ctx context.Context) ([]FileInfo, error) {
-	var allFiles []FileInfo
+	return collectFiles(func(visit func(FileInfo)) error {
+		return c.visitAllFiles(ctx, "", visit)
+	})
+}
+
+// VisitAllFiles calls visit for each file ListAllFiles would return, in the
+// same order, a page at a time, so callers that keep only some files never
+// hold the whole listing. Files visited before an error stay visited.
+func (c *Client) VisitAllFiles(ctx context.Context, visit func(FileInfo)) error {
+	return c.visitAllFiles(ctx, "", visit)
+}
+
+// visitAllFiles visits files across the configured corpora or shared
+// drives, restricted to files owned by owner unless it is empty.
+func (c *Client) visitAllFiles(ctx context.Context, owner string, visit func(FileInfo)) error {
+	clause := ownerClause(owner)
+	if len(c.driveIDs) == 0 {
+		return c.listFiles(ctx, c.corpora, "", clause, visit)
+	}
+
+	for _, driveID := range c.driveIDs {
+		if err := c.listFiles(ctx, "drive", driveID, clause, visit); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// collectFiles returns the files visited by list, including those visited
+// before it failed.
+func collectFiles(list func(visit func(FileInfo)) error) ([]FileInfo, error) {
+	var files []FileInfo
+	err := list(func(file FileInfo) {
+		files = append(files, file)
+	})
+	return files, err
+}
+
+// ListSharedWithMe retrieves the files in the "Shared with me" collection
+// of the authenticated user, listed from the user corpora whatever the
+// configured corpora and drive IDs. It needs no domain-wide delegation.
+func (c *Client) ListSharedWithMe(ctx context.Context) ([]FileInfo, error) {
+	return collectFiles(func(visit func(FileInfo)) error {
+		return c.listFiles(ctx, "user", "", sharedWithMeClause, visit)
+	})
+}
+
+// listFiles pages through a single corpora, calling visit for each file.
+// clause is an extra query clause, such as ownerClause, or empty.
+func (c *Client) listFiles(ctx context.Context, corpora, driveID, clause string, visit func(FileInfo)) error {
 	pageToken := ""
 
 	for {
 		select {
 		case <-ctx.Done():
-			return allFiles, ctx.Err()
+			return ctx.Err()
 		default:
 		}
 
-		opts := &ListFilesOptions{
-			Corpora:                   "domain",
-			PageSize:                  c.pageSize,
-			PageToken:                 pageToken,
-			Fields:                    "nextPageToken, files(id, name, mimeType, owners, createdTime, modifiedTime, size)",
-			SupportsAllDrives:         c.includeSharedDrives,
-			IncludeItemsFromAllDrives: c.includeSharedDrives,
+		opts := c.listFilesOptions(corpora, driveID, clause, pageToken)
+
+		if err := c.reserveCall(); err != nil {
+			return err
 		}
 
-		result, err := c.api.ListFiles(ctx, opts)
+		var result *ListFilesResult
+		err := c.retry(ctx, func() error {
+			start := time.Now()
+			var err error
+			result, err = c.api.ListFiles(ctx, opts)
+			c.listFilesCalls.record(start)
+			return err
+		})
 		if err != nil {
-			return nil, fmt.Errorf("failed to list files: %w", err)
+			if driveID != "" {
+				return fmt.Errorf("failed to list files in drive %s: %w", driveID, err)
+			}
+			return fmt.Errorf("failed to list files: %w", err)
 		}
 
 		for _, file := range result.Files {
-			ownerEmail := ""
-			if len(file.Owners) > 0 {
-				ownerEmail = file.Owners[0].EmailAddress
-			}
-
-			allFiles = append(allFiles, FileInfo{
-				ID:           file.Id,
-				Name:         file.Name,
-				MimeType:     file.MimeType,
-				OwnerEmail:   ownerEmail,
-				CreatedTime:  file.CreatedTime,
-				ModifiedTime: file.ModifiedTime,
-				Size:         file.Size,
-			})
+			visit(toFileInfo(file))
 		}
 
 		pageToken = result.NextPageToken
 		if pageToken == "" {
-			break
+			return nil
 		}
 	}
+}
+
+// GetFile retrieves the metadata of a single file by ID, with the same
+// fields as listed files. Trashed files are returned too.
+func (c *Client) GetFile(ctx context.Context, fileID string) (FileInfo, error) {
+	opts := &GetFileOptions{
+		Fields:            withRequiredFields(c.fileFields, DefaultFileFields, requiredFileFields),
+		SupportsAllDrives: c.supportsAllDrives(),
+	}
+
+	if err := c.reserveCall(); err != nil {
+		return FileInfo{}, err
+	}
+
+	var file *drive.File
+	err := c.retry(ctx, func() error {
+		var err error
+		file, err = c.api.GetFile(ctx, fileID, opts)
+		return err
+	})
+	if err != nil {
+		return FileInfo{}, fmt.Errorf("failed to get file %s: %w", fileID, err)
+	}
+
+	return toFileInfo(file), nil
+}
+
+// toFileInfo converts a Drive API file. The first owner is reported; files
+// in shared drives have none.
+func toFileInfo(file *drive.File) FileInfo {
+	ownerEmail, ownerName := "", ""
+	if len(file.Owners) > 0 {
+		ownerEmail = file.Owners[0].EmailAddress
+		ownerName = file.Owners[0].DisplayName
+	}
+
+	return FileInfo{
+		ID:           file.Id,
+		Name:         file.Name,
+		MimeType:     file.MimeType,
+		OwnerEmail:   ownerEmail,
+		OwnerName:    ownerName,
+		CreatedTime:  file.CreatedTime,
+		ModifiedTime: file.ModifiedTime,
+		Size:         file.Size,
+		Trashed:      file.Trashed,
+		WebViewLink:  file.WebViewLink,
+		DriveID:      file.DriveId,
+
+		ViewedByMeTime: file.ViewedByMeTime,
+	}
+}
+
+// supportsAllDrives reports whether single-file requests declare shared
+// drive support: with includeSharedDrives, or when the files are listed
+// from shared drives. GetFile and GetFilePermissions use the same setting,
+// so a file that can be fetched can also have its permissions listed.
+func (c *Client) supportsAllDrives() bool {
+	return c.includeSharedDrives || len(c.driveIDs) > 0 || c.corpora == "drive" || c.corpora == "allDrives"
+}
+
+// listFilesOptions builds the list options for a corpora.
+// The "drive" and "allDrives" corpora require shared drive support, so
+// it is forced on for them regardless of includeSharedDrives.
+func (c *Client) listFilesOptions(corpora, driveID, clause, pageToken string) *ListFilesOptions {
+	if corpora == "" {
+		corpora = "domain"
+	}
+
+	allDrives := c.includeSharedDrives || corpora == "drive" || corpora == "allDrives"
+
+	opts := &ListFilesOptions{
+		Corpora:                   corpora,
+		PageSize:                  c.pageSize,
+		PageToken:                 pageToken,
+		Fields:                    "nextPageToken, files(" + withRequiredFields(c.fileFields, DefaultFileFields, requiredFileFields) + ")",
+		Query:                     c.buildQuery(clause),
+		SupportsAllDrives:         allDrives,
+		IncludeItemsFromAllDrives: allDrives,
+	}
+
+	if corpora == "drive" {
+		opts.DriveID = driveID
+	}
+
+	return opts
+}
+
+// sharedWithMeClause restricts a file listing to the "Shared with me"
+// collection of the authenticated user.
+const sharedWithMeClause = "sharedWithMe = true"
+
+// ownerClause returns the query clause restricting a file listing to files
+// owned by owner, or "" for an empty owner.
+func ownerClause(owner string) string {
+	if owner == "" {
+		return ""
+	}
+	return fmt.Sprintf("'%s' in owners", escapeQueryValue(owner))
+}
+
+// buildQuery builds the Drive search query for listing files, optionally
+// narrowed by clause. The query set with SetQuery is parenthesized so an
+// "or" in it cannot widen the other clauses.
+func (c *Client) buildQuery(clause string) string {
+	var clauses []string
+
+	if !c.includeTrashed {
+		clauses = append(clauses, "trashed = false")
+	}
+	if clause != "" {
+		clauses = append(clauses, clause)
+	}
+	if c.query != "" {
+		clauses = append(clauses, "("+c.query+")")
+	}
 
-	return allFiles, nil
+	return strings.Join(clauses, " and ")
 }
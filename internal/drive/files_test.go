@@ -0,0 +1,379 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package drive
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	v3 "google.golang.org/api/drive/v3"
+)
+
+func TestClient_ListAllFiles_Corpora(t *testing.T) {
+	tests := []struct {
+		name                string
+		corpora             string
+		driveID             string
+		includeSharedDrives bool
+		expectedCorpora     string
+		expectedDriveID     string
+		expectedAllDrives   bool
+	}{
+		{
+			name:                "empty corpora defaults to domain",
+			corpora:             "",
+			includeSharedDrives: false,
+			expectedCorpora:     "domain",
+			expectedAllDrives:   false,
+		},
+		{
+			name:                "user corpora",
+			corpora:             "user",
+			includeSharedDrives: true,
+			expectedCorpora:     "user",
+			expectedAllDrives:   true,
+		},
+		{
+			name:                "drive corpora passes drive ID",
+			corpora:             "drive",
+			driveID:             "drive123",
+			includeSharedDrives: false,
+			expectedCorpora:     "drive",
+			expectedDriveID:     "drive123",
+			expectedAllDrives:   true,
+		},
+		{
+			name:                "drive ID overrides other corpora",
+			corpora:             "domain",
+			driveID:             "drive123",
+			includeSharedDrives: false,
+			expectedCorpora:     "drive",
+			expectedDriveID:     "drive123",
+			expectedAllDrives:   true,
+		},
+		{
+			name:                "allDrives corpora forces shared drive support",
+			corpora:             "allDrives",
+			includeSharedDrives: false,
+			expectedCorpora:     "allDrives",
+			expectedAllDrives:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI := new(MockDriveAPI)
+			mockAPI.On("ListFiles", mock.Anything, mock.MatchedBy(func(opts *ListFilesOptions) bool {
+				return opts.Corpora == tt.expectedCorpora &&
+					opts.DriveID == tt.expectedDriveID &&
+					opts.SupportsAllDrives == tt.expectedAllDrives &&
+					opts.IncludeItemsFromAllDrives == tt.expectedAllDrives
+			})).Return(&ListFilesResult{}, nil)
+
+			client := NewClientWithAPI(mockAPI, "example.com", 100, tt.includeSharedDrives)
+			if tt.driveID != "" {
+				client.SetCorpora(tt.corpora, tt.driveID)
+			} else {
+				client.SetCorpora(tt.corpora)
+			}
+
+			_, err := client.ListAllFiles(context.Background())
+			require.NoError(t, err)
+			mockAPI.AssertExpectations(t)
+		})
+	}
+}
+
+func TestClient_ListAllFiles_Pagination(t *testing.T) {
+	mockAPI := new(MockDriveAPI)
+	mockAPI.On("ListFiles", mock.Anything, mock.MatchedBy(func(opts *ListFilesOptions) bool {
+		return opts.PageToken == ""
+	})).Return(&ListFilesResult{
+		Files: []*v3.File{
+			{Id: "file1", Name: "one.txt", Owners: []*v3.User{{EmailAddress: "alice@example.com"}}},
+		},
+		NextPageToken: "page2",
+	}, nil)
+	mockAPI.On("ListFiles", mock.Anything, mock.MatchedBy(func(opts *ListFilesOptions) bool {
+		return opts.PageToken == "page2"
+	})).Return(&ListFilesResult{
+		Files: []*v3.File{
+			{Id: "file2", Name: "two.txt"},
+		},
+	}, nil)
+
+	client := NewClientWithAPI(mockAPI, "example.com", 100, true)
+	files, err := client.ListAllFiles(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	assert.Equal(t, "file1", files[0].ID)
+	assert.Equal(t, "alice@example.com", files[0].OwnerEmail)
+	assert.Equal(t, "file2", files[1].ID)
+	assert.Equal(t, "", files[1].OwnerEmail)
+	mockAPI.AssertExpectations(t)
+}
+
+func TestClient_ListAllFiles_OwnerName(t *testing.T) {
+	mockAPI := new(MockDriveAPI)
+	mockAPI.On("ListFiles", mock.Anything, mock.Anything).Return(&ListFilesResult{
+		Files: []*v3.File{
+			{Id: "file1", Owners: []*v3.User{{EmailAddress: "alice@example.com", DisplayName: "Alice"}}},
+			{Id: "file2", Owners: []*v3.User{{DisplayName: "Deleted User"}}},
+			{Id: "file3"},
+		},
+	}, nil)
+
+	client := NewClientWithAPI(mockAPI, "example.com", 100, true)
+	files, err := client.ListAllFiles(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+	assert.Equal(t, "alice@example.com", files[0].OwnerEmail)
+	assert.Equal(t, "Alice", files[0].OwnerName)
+	assert.Equal(t, "", files[1].OwnerEmail)
+	assert.Equal(t, "Deleted User", files[1].OwnerName)
+	assert.Equal(t, "", files[2].OwnerName)
+}
+
+func TestClient_ListAllFiles_DriveIDField(t *testing.T) {
+	mockAPI := new(MockDriveAPI)
+	mockAPI.On("ListFiles", mock.Anything, mock.MatchedBy(func(opts *ListFilesOptions) bool {
+		return strings.Contains(opts.Fields, "driveId")
+	})).Return(&ListFilesResult{
+		Files: []*v3.File{
+			{Id: "file1"},
+			{Id: "file2", DriveId: "0Ashared"},
+		},
+	}, nil)
+
+	client := NewClientWithAPI(mockAPI, "example.com", 100, true)
+	files, err := client.ListAllFiles(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	assert.Equal(t, "", files[0].DriveID)
+	assert.Equal(t, "0Ashared", files[1].DriveID)
+}
+
+func TestClient_ListAllFiles_TrashedQuery(t *testing.T) {
+	tests := []struct {
+		name           string
+		includeTrashed bool
+		expectedQuery  string
+	}{
+		{
+			name:           "trashed files excluded by default",
+			includeTrashed: false,
+			expectedQuery:  "trashed = false",
+		},
+		{
+			name:           "trashed files included",
+			includeTrashed: true,
+			expectedQuery:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI := new(MockDriveAPI)
+			mockAPI.On("ListFiles", mock.Anything, mock.MatchedBy(func(opts *ListFilesOptions) bool {
+				return opts.Query == tt.expectedQuery && strings.Contains(opts.Fields, "trashed")
+			})).Return(&ListFilesResult{
+				Files: []*v3.File{
+					{Id: "file1", Trashed: true},
+					{Id: "file2"},
+				},
+			}, nil)
+
+			client := NewClientWithAPI(mockAPI, "example.com", 100, true)
+			client.SetIncludeTrashed(tt.includeTrashed)
+
+			files, err := client.ListAllFiles(context.Background())
+			require.NoError(t, err)
+			require.Len(t, files, 2)
+			assert.True(t, files[0].Trashed)
+			assert.False(t, files[1].Trashed)
+			mockAPI.AssertExpectations(t)
+		})
+	}
+}
+
+func TestClient_BuildQuery_Passthrough(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		includeTrashed bool
+		owner          string
+		want           string
+	}{
+		{
+			name:  "no query",
+			query: "",
+			want:  "trashed = false",
+		},
+		{
+			name:  "combined with trashed clause",
+			query: "'folder1' in parents",
+			want:  "trashed = false and ('folder1' in parents)",
+		},
+		{
+			name:  "or is grouped so it cannot widen other clauses",
+			query: "name contains 'budget' or name contains 'payroll'",
+			want:  "trashed = false and (name contains 'budget' or name contains 'payroll')",
+		},
+		{
+			name:           "only clause when trashed files are included",
+			query:          "  mimeType = 'application/pdf' ",
+			includeTrashed: true,
+			want:           "(mimeType = 'application/pdf')",
+		},
+		{
+			name:  "combined with escaped owner clause",
+			query: "'folder1' in parents",
+			owner: "o'brien@example.com",
+			want:  `trashed = false and 'o\'brien@example.com' in owners and ('folder1' in parents)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClientWithAPI(new(MockDriveAPI), "example.com", 100, false)
+			client.SetIncludeTrashed(tt.includeTrashed)
+			client.SetQuery(tt.query)
+
+			assert.Equal(t, tt.want, client.buildQuery(ownerClause(tt.owner)))
+		})
+	}
+}
+
+func TestClient_ListAllFiles_DriveIDs(t *testing.T) {
+	mockAPI := new(MockDriveAPI)
+	mockAPI.On("ListFiles", mock.Anything, mock.MatchedBy(func(opts *ListFilesOptions) bool {
+		return opts.Corpora == "drive" && opts.DriveID == "driveA" && opts.PageToken == ""
+	})).Return(&ListFilesResult{
+		Files:         []*v3.File{{Id: "a1"}},
+		NextPageToken: "next",
+	}, nil).Once()
+	mockAPI.On("ListFiles", mock.Anything, mock.MatchedBy(func(opts *ListFilesOptions) bool {
+		return opts.Corpora == "drive" && opts.DriveID == "driveA" && opts.PageToken == "next"
+	})).Return(&ListFilesResult{
+		Files: []*v3.File{{Id: "a2"}},
+	}, nil).Once()
+	mockAPI.On("ListFiles", mock.Anything, mock.MatchedBy(func(opts *ListFilesOptions) bool {
+		return opts.Corpora == "drive" && opts.DriveID == "driveB"
+	})).Return(&ListFilesResult{
+		Files: []*v3.File{{Id: "b1"}},
+	}, nil).Once()
+
+	client := NewClientWithAPI(mockAPI, "example.com", 100, true)
+	client.SetCorpora("domain", "driveA", "driveB")
+
+	files, err := client.ListAllFiles(context.Background())
+	require.NoError(t, err)
+
+	ids := make([]string, 0, len(files))
+	for _, f := range files {
+		ids = append(ids, f.ID)
+	}
+	assert.Equal(t, []string{"a1", "a2", "b1"}, ids)
+
+	// Only the specified drives were queried
+	mockAPI.AssertExpectations(t)
+	mockAPI.AssertNumberOfCalls(t, "ListFiles", 3)
+	for _, call := range mockAPI.Calls {
+		opts := call.Arguments.Get(1).(*ListFilesOptions)
+		assert.Contains(t, []string{"driveA", "driveB"}, opts.DriveID)
+	}
+}
+
+func TestClient_ListAllFiles_DriveIDError(t *testing.T) {
+	mockAPI := new(MockDriveAPI)
+	mockAPI.On("ListFiles", mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+	client := NewClientWithAPI(mockAPI, "example.com", 100, true)
+	client.SetCorpora("drive", "missingDrive")
+
+	_, err := client.ListAllFiles(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missingDrive")
+}
+
+func TestClient_GetFile(t *testing.T) {
+	mockAPI := new(MockDriveAPI)
+	mockAPI.On("GetFile", mock.Anything, "file1", mock.MatchedBy(func(opts *GetFileOptions) bool {
+		return opts.SupportsAllDrives && strings.Contains(opts.Fields, "owners") && !strings.HasPrefix(opts.Fields, "files(")
+	})).Return(&v3.File{
+		Id:     "file1",
+		Name:   "plan.docx",
+		Owners: []*v3.User{{EmailAddress: "alice@example.com", DisplayName: "Alice"}},
+	}, nil)
+	mockAPI.On("GetFile", mock.Anything, "missing", mock.Anything).Return(nil, assert.AnError)
+
+	client := NewClientWithAPI(mockAPI, "example.com", 100, true)
+
+	file, err := client.GetFile(context.Background(), "file1")
+	require.NoError(t, err)
+	assert.Equal(t, "file1", file.ID)
+	assert.Equal(t, "plan.docx", file.Name)
+	assert.Equal(t, "alice@example.com", file.OwnerEmail)
+
+	_, err = client.GetFile(context.Background(), "missing")
+	require.ErrorIs(t, err, assert.AnError)
+	assert.Contains(t, err.Error(), "missing")
+}
+
+func TestClient_SingleFileSupportsAllDrives(t *testing.T) {
+	tests := []struct {
+		name   string
+		client func(api DriveAPI) *Client
+		want   bool
+	}{
+		{name: "shared drives included", client: func(api DriveAPI) *Client { return NewClientWithAPI(api, "example.com", 100, true) }, want: true},
+		{name: "shared drives excluded", client: func(api DriveAPI) *Client { return NewClientWithAPI(api, "example.com", 100, false) }},
+		{name: "shared drive corpora", want: true, client: func(api DriveAPI) *Client {
+			return NewClientWithOptions(api, Options{Domain: "example.com", Corpora: "drive", DriveIDs: []string{"drive1"}})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI := new(MockDriveAPI)
+			mockAPI.On("GetFile", mock.Anything, "file1", mock.MatchedBy(func(opts *GetFileOptions) bool {
+				return opts.SupportsAllDrives == tt.want
+			})).Return(&v3.File{Id: "file1"}, nil)
+			mockAPI.On("ListPermissions", mock.Anything, "file1", mock.MatchedBy(func(opts *ListPermissionsOptions) bool {
+				return opts.SupportsAllDrives == tt.want
+			})).Return(&ListPermissionsResult{}, nil)
+
+			client := tt.client(mockAPI)
+			_, err := client.GetFile(context.Background(), "file1")
+			require.NoError(t, err)
+			_, err = client.GetFilePermissions(context.Background(), "file1")
+			require.NoError(t, err)
+			mockAPI.AssertExpectations(t)
+		})
+	}
+}
+
+func TestClient_ListSharedWithMe(t *testing.T) {
+	mockAPI := new(MockDriveAPI)
+	mockAPI.On("ListFiles", mock.Anything, mock.MatchedBy(func(opts *ListFilesOptions) bool {
+		return opts.Corpora == "user" && opts.DriveID == "" &&
+			opts.Query == "trashed = false and sharedWithMe = true and ('folder1' in parents)"
+	})).Return(&ListFilesResult{Files: []*v3.File{{Id: "file1"}}}, nil).Once()
+
+	client := NewClientWithAPI(mockAPI, "example.com", 100, false)
+	client.SetCorpora("drive", "drive1")
+	client.SetQuery("'folder1' in parents")
+	files, err := client.ListSharedWithMe(context.Background())
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "file1", files[0].ID)
+	mockAPI.AssertExpectations(t)
+}
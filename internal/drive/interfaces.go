@@ -14,14 +14,18 @@ import (
 type DriveAPI interface {
 	ListFiles(ctx context.Context, opts *ListFilesOptions) (*ListFilesResult, error)
 	ListPermissions(ctx context.Context, fileID string, opts *ListPermissionsOptions) (*ListPermissionsResult, error)
+	GetFile(ctx context.Context, fileID string, opts *GetFileOptions) (*drive.File, error)
+	GetDrive(ctx context.Context, driveID string) (*drive.Drive, error)
 }
 
 // ListFilesOptions contains options for listing files.
 type ListFilesOptions struct {
 	Corpora                   string
+	DriveID                   string
 	PageSize                  int64
 	PageToken                 string
 	Fields                    string
+	Query                     string
 	SupportsAllDrives         bool
 	IncludeItemsFromAllDrives bool
 }
@@ -45,6 +49,12 @@ type ListPermissionsResult struct {
 	NextPageToken string
 }
 
+// GetFileOptions contains options for getting a single file.
+type GetFileOptions struct {
+	Fields            string
+	SupportsAllDrives bool
+}
+
 // GoogleDriveAPI implements DriveAPI using the real Google Drive service.
 type GoogleDriveAPI struct {
 	service *drive.Service
@@ -64,6 +74,14 @@ func (g *GoogleDriveAPI) ListFiles(ctx context.Context, opts *ListFilesOptions)
 		SupportsAllDrives(opts.SupportsAllDrives).
 		IncludeItemsFromAllDrives(opts.IncludeItemsFromAllDrives)
 
+	if opts.DriveID != "" {
+		call = call.DriveId(opts.DriveID)
+	}
+
+	if opts.Query != "" {
+		call = call.Q(opts.Query)
+	}
+
 	if opts.PageToken != "" {
 		call = call.PageToken(opts.PageToken)
 	}
@@ -99,3 +117,20 @@ func (g *GoogleDriveAPI) ListPermissions(ctx context.Context, fileID string, opt
 		NextPageToken: result.NextPageToken,
 	}, nil
 }
+
+// GetFile gets the metadata of a single file.
+func (g *GoogleDriveAPI) GetFile(ctx context.Context, fileID string, opts *GetFileOptions) (*drive.File, error) {
+	return g.service.Files.Get(fileID).
+		Fields(googleapi.Field(opts.Fields)).
+		SupportsAllDrives(opts.SupportsAllDrives).
+		Context(ctx).
+		Do()
+}
+
+// GetDrive gets the ID and name of a shared drive.
+func (g *GoogleDriveAPI) GetDrive(ctx context.Context, driveID string) (*drive.Drive, error) {
+	return g.service.Drives.Get(driveID).
+		Fields("id", "name").
+		Context(ctx).
+		Do()
+}
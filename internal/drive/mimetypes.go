@@ -0,0 +1,95 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package drive
+
+import "strings"
+
+// File categories returned by CategorizeMimeType.
+const (
+	CategoryDocuments     = "Documents"
+	CategorySpreadsheets  = "Spreadsheets"
+	CategoryPresentations = "Presentations"
+	CategoryPDFs          = "PDFs"
+	CategoryImages        = "Images"
+	CategoryVideo         = "Video"
+	CategoryAudio         = "Audio"
+	CategoryArchives      = "Archives"
+	CategoryFolders       = "Folders"
+	CategoryOther         = "Other"
+)
+
+// mimeCategories maps exact MIME types to categories.
+var mimeCategories = map[string]string{
+	// Google-native types
+	"application/vnd.google-apps.document":     CategoryDocuments,
+	"application/vnd.google-apps.spreadsheet":  CategorySpreadsheets,
+	"application/vnd.google-apps.presentation": CategoryPresentations,
+	"application/vnd.google-apps.drawing":      CategoryImages,
+	"application/vnd.google-apps.photo":        CategoryImages,
+	"application/vnd.google-apps.video":        CategoryVideo,
+	"application/vnd.google-apps.audio":        CategoryAudio,
+	"application/vnd.google-apps.folder":       CategoryFolders,
+
+	// Office and OpenDocument types
+	"application/msword": CategoryDocuments,
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document": CategoryDocuments,
+	"application/vnd.oasis.opendocument.text":                                 CategoryDocuments,
+	"application/rtf":          CategoryDocuments,
+	"text/plain":               CategoryDocuments,
+	"text/markdown":            CategoryDocuments,
+	"text/html":                CategoryDocuments,
+	"application/vnd.ms-excel": CategorySpreadsheets,
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": CategorySpreadsheets,
+	"application/vnd.oasis.opendocument.spreadsheet":                    CategorySpreadsheets,
+	"text/csv":                      CategorySpreadsheets,
+	"text/tab-separated-values":     CategorySpreadsheets,
+	"application/vnd.ms-powerpoint": CategoryPresentations,
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation": CategoryPresentations,
+	"application/vnd.oasis.opendocument.presentation":                           CategoryPresentations,
+	"application/pdf": CategoryPDFs,
+
+	// Archives
+	"application/zip":              CategoryArchives,
+	"application/x-zip-compressed": CategoryArchives,
+	"application/x-7z-compressed":  CategoryArchives,
+	"application/x-rar-compressed": CategoryArchives,
+	"application/vnd.rar":          CategoryArchives,
+	"application/x-tar":            CategoryArchives,
+	"application/gzip":             CategoryArchives,
+	"application/x-gzip":           CategoryArchives,
+	"application/x-bzip2":          CategoryArchives,
+}
+
+// mimePrefixCategories maps MIME type prefixes to categories for types not
+// listed in mimeCategories.
+var mimePrefixCategories = []struct {
+	prefix   string
+	category string
+}{
+	{"image/", CategoryImages},
+	{"video/", CategoryVideo},
+	{"audio/", CategoryAudio},
+}
+
+// CategorizeMimeType maps a MIME type to a coarse file category such as
+// Documents or Images. Unknown types are categorized as Other.
+func CategorizeMimeType(mimeType string) string {
+	m := strings.ToLower(strings.TrimSpace(mimeType))
+	// Drop parameters such as "; charset=utf-8".
+	if idx := strings.Index(m, ";"); idx >= 0 {
+		m = strings.TrimSpace(m[:idx])
+	}
+
+	if category, ok := mimeCategories[m]; ok {
+		return category
+	}
+
+	for _, p := range mimePrefixCategories {
+		if strings.HasPrefix(m, p.prefix) {
+			return p.category
+		}
+	}
+
+	return CategoryOther
+}
@@ -0,0 +1,53 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package drive
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCategorizeMimeType(t *testing.T) {
+	tests := []struct {
+		mimeType string
+		expected string
+	}{
+		{"application/vnd.google-apps.document", CategoryDocuments},
+		{"application/vnd.google-apps.spreadsheet", CategorySpreadsheets},
+		{"application/vnd.google-apps.presentation", CategoryPresentations},
+		{"application/vnd.google-apps.drawing", CategoryImages},
+		{"application/vnd.google-apps.folder", CategoryFolders},
+		{"application/vnd.google-apps.video", CategoryVideo},
+		{"application/msword", CategoryDocuments},
+		{"application/vnd.openxmlformats-officedocument.wordprocessingml.document", CategoryDocuments},
+		{"text/plain", CategoryDocuments},
+		{"text/plain; charset=utf-8", CategoryDocuments},
+		{"application/vnd.ms-excel", CategorySpreadsheets},
+		{"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", CategorySpreadsheets},
+		{"text/csv", CategorySpreadsheets},
+		{"application/vnd.ms-powerpoint", CategoryPresentations},
+		{"application/vnd.openxmlformats-officedocument.presentationml.presentation", CategoryPresentations},
+		{"application/pdf", CategoryPDFs},
+		{"APPLICATION/PDF", CategoryPDFs},
+		{"image/png", CategoryImages},
+		{"image/jpeg", CategoryImages},
+		{"image/svg+xml", CategoryImages},
+		{"video/mp4", CategoryVideo},
+		{"audio/mpeg", CategoryAudio},
+		{"application/zip", CategoryArchives},
+		{"application/x-7z-compressed", CategoryArchives},
+		{"application/gzip", CategoryArchives},
+		{"application/vnd.google-apps.form", CategoryOther},
+		{"application/octet-stream", CategoryOther},
+		{"application/x-unknown", CategoryOther},
+		{"", CategoryOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mimeType, func(t *testing.T) {
+			assert.Equal(t, tt.expected, CategorizeMimeType(tt.mimeType))
+		})
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package drive
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/api/drive/v3"
+)
+
+// MockDriveAPI is a mock implementation of the DriveAPI interface.
+type MockDriveAPI struct {
+	mock.Mock
+}
+
+// ListFiles mocks the ListFiles method.
+func (m *MockDriveAPI) ListFiles(ctx context.Context, opts *ListFilesOptions) (*ListFilesResult, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ListFilesResult), args.Error(1)
+}
+
+// ListPermissions mocks the ListPermissions method.
+func (m *MockDriveAPI) ListPermissions(ctx context.Context, fileID string, opts *ListPermissionsOptions) (*ListPermissionsResult, error) {
+	args := m.Called(ctx, fileID, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ListPermissionsResult), args.Error(1)
+}
+
+// GetFile mocks the GetFile method.
+func (m *MockDriveAPI) GetFile(ctx context.Context, fileID string, opts *GetFileOptions) (*drive.File, error) {
+	args := m.Called(ctx, fileID, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*drive.File), args.Error(1)
+}
+
+// GetDrive mocks the GetDrive method.
+func (m *MockDriveAPI) GetDrive(ctx context.Context, driveID string) (*drive.Drive, error) {
+	args := m.Called(ctx, driveID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*drive.Drive), args.Error(1)
+}
@@ -0,0 +1,63 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package drive
+
+import (
+	"io"
+	"regexp"
+)
+
+// Options configures a Client created with NewClientWithOptions. Each
+// field matches a Set method of Client, which can still change it later.
+// The zero value lists files from the "domain" corpora with the API's
+// default page size and field masks, without a call budget, rate cap or
+// retries.
+type Options struct {
+	// Domain is the primary Workspace domain; shares outside it, its
+	// DomainAliases and InternalEmails are external.
+	Domain         string
+	DomainAliases  []string
+	InternalEmails *regexp.Regexp
+
+	PageSize            int64
+	IncludeSharedDrives bool
+	Corpora             string
+	DriveIDs            []string
+	IncludeTrashed      bool
+	Query               string
+
+	FileFields       string
+	PermissionFields string
+	StrictUnknown    bool
+
+	MaxAPICalls      int64
+	MaxQPS           int
+	RetryStatusCodes []int
+	ThrottleLog      io.Writer
+	ActivityLog      io.Writer
+}
+
+// NewClientWithOptions creates a Drive client calling api, configured by
+// opts. Use NewGoogleDriveAPI to call the real Drive service.
+func NewClientWithOptions(api DriveAPI, opts Options) *Client {
+	c := &Client{
+		api:                 api,
+		domain:              opts.Domain,
+		pageSize:            opts.PageSize,
+		includeSharedDrives: opts.IncludeSharedDrives,
+	}
+	c.SetDomainAliases(opts.DomainAliases...)
+	c.SetInternalEmailPattern(opts.InternalEmails)
+	c.SetCorpora(opts.Corpora, opts.DriveIDs...)
+	c.SetIncludeTrashed(opts.IncludeTrashed)
+	c.SetQuery(opts.Query)
+	c.SetFieldMasks(opts.FileFields, opts.PermissionFields)
+	c.SetStrictUnknown(opts.StrictUnknown)
+	c.SetMaxAPICalls(opts.MaxAPICalls)
+	c.SetMaxQPS(opts.MaxQPS)
+	c.SetRetryStatusCodes(opts.RetryStatusCodes...)
+	c.SetThrottleLog(opts.ThrottleLog)
+	c.SetActivityLog(opts.ActivityLog)
+	return c
+}
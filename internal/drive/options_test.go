@@ -0,0 +1,66 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package drive
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewClientWithOptions(t *testing.T) {
+	api := new(MockDriveAPI)
+	internal := regexp.MustCompile(`-staff@example\.com$`)
+	var log bytes.Buffer
+
+	client := NewClientWithOptions(api, Options{
+		Domain:              "example.com",
+		DomainAliases:       []string{"example.org"},
+		InternalEmails:      internal,
+		PageSize:            250,
+		IncludeSharedDrives: true,
+		Corpora:             "allDrives",
+		DriveIDs:            []string{"0AAbc"},
+		IncludeTrashed:      true,
+		Query:               "  'folder1' in parents ",
+		FileFields:          "id, name",
+		PermissionFields:    "id, type, role",
+		MaxAPICalls:         1000,
+		MaxQPS:              20,
+		RetryStatusCodes:    []int{429, 503},
+		ThrottleLog:         &log,
+	})
+
+	assert.Same(t, api, client.api)
+	assert.Equal(t, "example.com", client.Domain())
+	assert.Equal(t, []string{"example.org"}, client.domainAliases)
+	assert.Same(t, internal, client.internalEmails)
+	assert.Equal(t, int64(250), client.pageSize)
+	assert.True(t, client.includeSharedDrives)
+	assert.Equal(t, "allDrives", client.corpora)
+	assert.Equal(t, []string{"0AAbc"}, client.driveIDs)
+	assert.True(t, client.includeTrashed)
+	assert.Equal(t, "'folder1' in parents", client.query)
+	assert.Equal(t, "id, name", client.fileFields)
+	assert.Equal(t, "id, type, role", client.permissionFields)
+	assert.Equal(t, int64(1000), client.maxAPICalls)
+	assert.Equal(t, float64(20), client.EffectiveQPS())
+	assert.Equal(t, map[int]struct{}{429: {}, 503: {}}, client.retryCodes)
+	assert.Same(t, &log, client.throttle.log)
+}
+
+func TestNewClientWithOptions_Wrappers(t *testing.T) {
+	api := new(MockDriveAPI)
+	want := NewClientWithOptions(api, Options{Domain: "example.com", PageSize: 100, IncludeSharedDrives: true})
+	got := NewClientWithAPI(api, "example.com", 100, true)
+
+	assert.Equal(t, want.domain, got.domain)
+	assert.Equal(t, want.pageSize, got.pageSize)
+	assert.Equal(t, want.includeSharedDrives, got.includeSharedDrives)
+	assert.Nil(t, got.retryCodes, "no retries unless configured")
+	assert.Zero(t, got.EffectiveQPS(), "no rate cap unless configured")
+	assert.Zero(t, got.maxAPICalls, "no call budget unless configured")
+}
@@ -0,0 +1,65 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package drive
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	v3 "google.golang.org/api/drive/v3"
+)
+
+func TestClient_ListOwners(t *testing.T) {
+	mockAPI := new(MockDriveAPI)
+	owned := func(email string) *v3.File {
+		return &v3.File{Owners: []*v3.User{{EmailAddress: email}}}
+	}
+
+	mockAPI.On("ListFiles", mock.Anything, mock.MatchedBy(func(opts *ListFilesOptions) bool {
+		return opts.PageToken == "" && opts.Fields == ownerFields
+	})).Return(&ListFilesResult{
+		Files:         []*v3.File{owned("carol@example.com"), owned("alice@example.com"), {}},
+		NextPageToken: "page2",
+	}, nil).Once()
+	mockAPI.On("ListFiles", mock.Anything, mock.MatchedBy(func(opts *ListFilesOptions) bool {
+		return opts.PageToken == "page2" && opts.Fields == ownerFields
+	})).Return(&ListFilesResult{
+		Files: []*v3.File{owned("alice@example.com"), owned("bob@example.com")},
+	}, nil).Once()
+
+	client := NewClientWithAPI(mockAPI, "example.com", 100, false)
+	owners, err := client.ListOwners(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice@example.com", "bob@example.com", "carol@example.com"}, owners)
+	mockAPI.AssertExpectations(t)
+}
+
+func TestClient_ListOwnerFiles_Query(t *testing.T) {
+	tests := []struct {
+		name          string
+		owner         string
+		expectedQuery string
+	}{
+		{name: "plain email", owner: "alice@example.com", expectedQuery: "trashed = false and 'alice@example.com' in owners"},
+		{name: "quote escaped", owner: "o'brien@example.com", expectedQuery: `trashed = false and 'o\'brien@example.com' in owners`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI := new(MockDriveAPI)
+			mockAPI.On("ListFiles", mock.Anything, mock.MatchedBy(func(opts *ListFilesOptions) bool {
+				return opts.Query == tt.expectedQuery
+			})).Return(&ListFilesResult{Files: []*v3.File{{Id: "file1"}}}, nil)
+
+			client := NewClientWithAPI(mockAPI, "example.com", 100, false)
+			files, err := client.ListOwnerFiles(context.Background(), tt.owner)
+			require.NoError(t, err)
+			require.Len(t, files, 1)
+			mockAPI.AssertExpectations(t)
+		})
+	}
+}
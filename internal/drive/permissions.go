@@ -6,10 +6,16 @@ package drive
 import (
 	"context"
 	"fmt"
-	"strings"
+	"slices"
+	"time"
+
+	"github.com/leansecurity-co/gwork/internal/drivesyntax"
+	"google.golang.org/api/drive/v3"
 )
 
-// GetFilePermissions retrieves all permissions for a file.
+// GetFilePermissions retrieves all permissions for a file. If a page fails,
+// the permissions collected from earlier pages are returned along with the
+// error so partial results are not lost.
 func (c *Client) GetFilePermissions(ctx context.Context, fileID string) ([]Permission, error) {
 	var allPerms []Permission
 	pageToken := ""
@@ -22,24 +28,42 @@ func (c *Client) GetFilePermissions(ctx context.Context, fileID string) ([]Permi
 		}
 
 		opts := &ListPermissionsOptions{
-			Fields:            "nextPageToken, permissions(id, type, role, emailAddress, domain, displayName)",
+			Fields:            "nextPageToken, permissions(" + withRequiredFields(c.permissionFields, DefaultPermissionFields, requiredPermissionFields) + ")",
 			PageToken:         pageToken,
-			SupportsAllDrives: c.includeSharedDrives,
+			SupportsAllDrives: c.supportsAllDrives(),
+		}
+
+		if err := c.reserveCall(); err != nil {
+			return allPerms, err
 		}
 
-		result, err := c.api.ListPermissions(ctx, fileID, opts)
+		var result *ListPermissionsResult
+		err := c.retry(ctx, func() error {
+			start := time.Now()
+			var err error
+			result, err = c.api.ListPermissions(ctx, fileID, opts)
+			c.listPermissionsCalls.record(start)
+			return err
+		})
 		if err != nil {
-			return nil, fmt.Errorf("failed to list permissions for file %s: %w", fileID, err)
+			return allPerms, fmt.Errorf("failed to list permissions for file %s: %w", fileID, err)
 		}
 
 		for _, perm := range result.Permissions {
+			inherited, inheritedFrom := inheritance(perm.PermissionDetails)
+			// Permissions without an expiration keep the zero time.
+			expirationTime, _ := time.Parse(time.RFC3339, perm.ExpirationTime)
 			allPerms = append(allPerms, Permission{
-				ID:           perm.Id,
-				Type:         perm.Type,
-				Role:         perm.Role,
-				EmailAddress: perm.EmailAddress,
-				Domain:       perm.Domain,
-				DisplayName:  perm.DisplayName,
+				ID:             perm.Id,
+				Type:           perm.Type,
+				Role:           perm.Role,
+				EmailAddress:   perm.EmailAddress,
+				Domain:         perm.Domain,
+				DisplayName:    perm.DisplayName,
+				Inherited:      inherited,
+				InheritedFrom:  inheritedFrom,
+				ExpirationTime: expirationTime,
+				Deleted:        perm.Deleted,
 			})
 		}
 
@@ -52,29 +76,80 @@ func (c *Client) GetFilePermissions(ctx context.Context, fileID string) ([]Permi
 	return allPerms, nil
 }
 
+// inheritance reports whether a permission is inherited and from which item.
+// A permission is only considered inherited when none of its details grant
+// it directly. Drive only returns details for shared drive items, so
+// permissions without details are treated as direct.
+func inheritance(details []*drive.PermissionPermissionDetails) (bool, string) {
+	if len(details) == 0 {
+		return false, ""
+	}
+
+	inheritedFrom := ""
+	for _, d := range details {
+		if !d.Inherited {
+			return false, ""
+		}
+		if inheritedFrom == "" {
+			inheritedFrom = d.InheritedFrom
+		}
+	}
+
+	return true, inheritedFrom
+}
+
+// KnownPermissionTypes lists the permission types IsExternalShare
+// recognizes.
+var KnownPermissionTypes = []string{"anyone", "domain", "user", "group"}
+
 // IsExternalShare checks if a permission is external to the domain.
+// Permission types it does not recognize are internal unless
+// SetStrictUnknown is set; see IsKnownPermissionType.
 func (c *Client) IsExternalShare(perm Permission) bool {
+	if !IsKnownPermissionType(perm.Type) {
+		return c.strictUnknown
+	}
+
 	switch perm.Type {
 	case "anyone":
 		return true
 	case "domain":
-		return perm.Domain != c.domain
-	case "user", "group":
+		return !c.isInternalDomain(perm.Domain)
+	default: // user or group
 		if perm.EmailAddress == "" {
 			return false
 		}
-		emailDomain := ExtractDomain(perm.EmailAddress)
-		return emailDomain != c.domain
-	default:
-		return false
+		if c.internalEmails != nil {
+			return !c.internalEmails.MatchString(perm.EmailAddress)
+		}
+		emailDomain := drivesyntax.ExtractDomain(perm.EmailAddress)
+		return !c.isInternalDomain(emailDomain)
 	}
 }
 
-// ExtractDomain extracts the domain part from an email address.
-func ExtractDomain(email string) string {
-	idx := strings.LastIndex(email, "@")
-	if idx < 0 {
-		return ""
+// IsKnownPermissionType reports whether permission type t is in
+// KnownPermissionTypes. Drive occasionally adds types, which would
+// otherwise go unreported.
+func IsKnownPermissionType(t string) bool {
+	return slices.Contains(KnownPermissionTypes, t)
+}
+
+// SetStrictUnknown makes IsExternalShare treat permission types it does
+// not recognize as external rather than internal.
+func (c *Client) SetStrictUnknown(strict bool) {
+	c.strictUnknown = strict
+}
+
+// isInternalDomain reports whether domain is the primary domain or one of
+// its aliases.
+func (c *Client) isInternalDomain(domain string) bool {
+	if domain == c.domain {
+		return true
+	}
+	for _, alias := range c.domainAliases {
+		if domain == alias {
+			return true
+		}
 	}
-	return email[idx+1:]
+	return false
 }
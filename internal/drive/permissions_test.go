@@ -4,9 +4,17 @@
 package drive
 
 import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	v3 "google.golang.org/api/drive/v3"
 )
 
 func TestClient_IsExternalShare(t *testing.T) {
@@ -131,58 +139,225 @@ func TestClient_IsExternalShare(t *testing.T) {
 	}
 }
 
-func TestExtractDomain(t *testing.T) {
+func TestClient_GetFilePermissions_Inheritance(t *testing.T) {
+	mockAPI := new(MockDriveAPI)
+	mockAPI.On("ListPermissions", mock.Anything, "file1", mock.Anything).Return(&ListPermissionsResult{
+		Permissions: []*v3.Permission{
+			{
+				Id: "direct", Type: "user", EmailAddress: "a@other.com",
+			},
+			{
+				Id: "inherited", Type: "user", EmailAddress: "b@other.com",
+				PermissionDetails: []*v3.PermissionPermissionDetails{
+					{Inherited: true, InheritedFrom: "folder123"},
+				},
+			},
+			{
+				Id: "both", Type: "user", EmailAddress: "c@other.com",
+				PermissionDetails: []*v3.PermissionPermissionDetails{
+					{Inherited: true, InheritedFrom: "folder123"},
+					{Inherited: false},
+				},
+			},
+		},
+	}, nil)
+
+	client := NewClientWithAPI(mockAPI, "example.com", 100, true)
+	perms, err := client.GetFilePermissions(context.Background(), "file1")
+
+	require.NoError(t, err)
+	require.Len(t, perms, 3)
+	assert.False(t, perms[0].Inherited)
+	assert.Equal(t, "", perms[0].InheritedFrom)
+	assert.True(t, perms[1].Inherited)
+	assert.Equal(t, "folder123", perms[1].InheritedFrom)
+	assert.False(t, perms[2].Inherited, "a direct grant wins over an inherited one")
+}
+
+func TestClient_GetFilePermissions_ExpirationTime(t *testing.T) {
+	mockAPI := new(MockDriveAPI)
+	mockAPI.On("ListPermissions", mock.Anything, "file1", mock.Anything).Return(&ListPermissionsResult{
+		Permissions: []*v3.Permission{
+			{Id: "expiring", Type: "user", EmailAddress: "a@other.com", ExpirationTime: "2025-03-01T12:00:00.000Z"},
+			{Id: "permanent", Type: "anyone"},
+			{Id: "malformed", Type: "user", EmailAddress: "b@other.com", ExpirationTime: "not-a-time"},
+		},
+	}, nil)
+
+	client := NewClientWithAPI(mockAPI, "example.com", 100, true)
+	perms, err := client.GetFilePermissions(context.Background(), "file1")
+
+	require.NoError(t, err)
+	require.Len(t, perms, 3)
+	assert.Equal(t, time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC), perms[0].ExpirationTime.UTC())
+	assert.True(t, perms[1].ExpirationTime.IsZero(), "no expiration keeps the zero time")
+	assert.True(t, perms[2].ExpirationTime.IsZero())
+}
+
+func TestClient_GetFilePermissions_Deleted(t *testing.T) {
+	mockAPI := new(MockDriveAPI)
+	mockAPI.On("ListPermissions", mock.Anything, "file1", mock.MatchedBy(func(opts *ListPermissionsOptions) bool {
+		return strings.Contains(opts.Fields, "deleted")
+	})).Return(&ListPermissionsResult{
+		Permissions: []*v3.Permission{
+			{Id: "gone", Type: "user", EmailAddress: "former@other.com", Deleted: true},
+			{Id: "active", Type: "user", EmailAddress: "current@other.com"},
+		},
+	}, nil)
+
+	client := NewClientWithAPI(mockAPI, "example.com", 100, true)
+	perms, err := client.GetFilePermissions(context.Background(), "file1")
+
+	require.NoError(t, err)
+	require.Len(t, perms, 2)
+	assert.True(t, perms[0].Deleted)
+	assert.False(t, perms[1].Deleted)
+	mockAPI.AssertExpectations(t)
+}
+
+func TestClient_GetFilePermissions_PartialOnPageError(t *testing.T) {
+	mockAPI := new(MockDriveAPI)
+	mockAPI.On("ListPermissions", mock.Anything, "file1", mock.MatchedBy(func(opts *ListPermissionsOptions) bool {
+		return opts.PageToken == ""
+	})).Return(&ListPermissionsResult{
+		Permissions:   []*v3.Permission{{Id: "p1", Type: "anyone", Role: "reader"}},
+		NextPageToken: "page2",
+	}, nil)
+	mockAPI.On("ListPermissions", mock.Anything, "file1", mock.MatchedBy(func(opts *ListPermissionsOptions) bool {
+		return opts.PageToken == "page2"
+	})).Return(nil, errors.New("backend error"))
+
+	client := NewClientWithAPI(mockAPI, "example.com", 100, true)
+	perms, err := client.GetFilePermissions(context.Background(), "file1")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "backend error")
+	require.Len(t, perms, 1, "permissions from the first page are kept")
+	assert.Equal(t, "p1", perms[0].ID)
+}
+
+func TestClient_IsExternalShare_StrictUnknown(t *testing.T) {
 	tests := []struct {
-		name     string
-		email    string
-		expected string
+		name       string
+		strict     bool
+		permission Permission
+		expected   bool
 	}{
+		{name: "unknown type is internal by default", permission: Permission{Type: "audience", Domain: "partner.com"}, expected: false},
+		{name: "unknown type is external when strict", strict: true, permission: Permission{Type: "audience", Domain: "partner.com"}, expected: true},
+		{name: "known internal type stays internal when strict", strict: true, permission: Permission{Type: "user", EmailAddress: "bob@example.com"}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClientWithOptions(nil, Options{Domain: "example.com", StrictUnknown: tt.strict})
+			assert.Equal(t, tt.expected, client.IsExternalShare(tt.permission))
+			assert.Equal(t, tt.permission.Type != "audience", IsKnownPermissionType(tt.permission.Type))
+		})
+	}
+}
+
+func TestClient_IsExternalShare_DomainAliases(t *testing.T) {
+	client := NewClientWithAPI(nil, "example.com", 100, false)
+	client.SetDomainAliases("example.org", "corp.example.net")
+
+	tests := []struct {
+		name       string
+		permission Permission
+		expected   bool
+	}{
+		{
+			name:       "user in alias domain is internal",
+			permission: Permission{Type: "user", EmailAddress: "alice@example.org"},
+			expected:   false,
+		},
+		{
+			name:       "group in alias domain is internal",
+			permission: Permission{Type: "group", EmailAddress: "team@corp.example.net"},
+			expected:   false,
+		},
+		{
+			name:       "domain share to alias is internal",
+			permission: Permission{Type: "domain", Domain: "example.org"},
+			expected:   false,
+		},
+		{
+			name:       "user in primary domain is still internal",
+			permission: Permission{Type: "user", EmailAddress: "bob@example.com"},
+			expected:   false,
+		},
+		{
+			name:       "user in non-alias domain is external",
+			permission: Permission{Type: "user", EmailAddress: "guest@partner.com"},
+			expected:   true,
+		},
 		{
-			name:     "standard email",
-			email:    "user@example.com",
-			expected: "example.com",
+			name:       "subdomain of an alias is external",
+			permission: Permission{Type: "user", EmailAddress: "guest@sub.example.org"},
+			expected:   true,
 		},
 		{
-			name:     "email with subdomain",
-			email:    "user@mail.example.com",
-			expected: "mail.example.com",
+			name:       "anyone is external regardless of aliases",
+			permission: Permission{Type: "anyone"},
+			expected:   true,
 		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, client.IsExternalShare(tt.permission))
+		})
+	}
+}
+
+func TestClient_IsExternalShare_InternalEmailPattern(t *testing.T) {
+	client := NewClientWithAPI(nil, "example.com", 100, false)
+	client.SetInternalEmailPattern(regexp.MustCompile(`-staff@example\.com$`))
+
+	tests := []struct {
+		name       string
+		permission Permission
+		expected   bool
+	}{
 		{
-			name:     "email with multiple @ symbols uses last one",
-			email:    "user@name@example.com",
-			expected: "example.com",
+			name:       "staff user is internal",
+			permission: Permission{Type: "user", EmailAddress: "alice-staff@example.com"},
+			expected:   false,
 		},
 		{
-			name:     "email without @ symbol",
-			email:    "notanemail",
-			expected: "",
+			name:       "contractor in the same domain is external",
+			permission: Permission{Type: "user", EmailAddress: "bob-contractor@example.com"},
+			expected:   true,
 		},
 		{
-			name:     "empty email",
-			email:    "",
-			expected: "",
+			name:       "staff group is internal",
+			permission: Permission{Type: "group", EmailAddress: "eng-staff@example.com"},
+			expected:   false,
 		},
 		{
-			name:     "email with @ at end",
-			email:    "user@",
-			expected: "",
+			name:       "matching address in another domain is external",
+			permission: Permission{Type: "user", EmailAddress: "carol-staff@example.com.evil.io"},
+			expected:   true,
 		},
 		{
-			name:     "email with @ at start",
-			email:    "@example.com",
-			expected: "example.com",
+			name:       "domain share is still compared by domain",
+			permission: Permission{Type: "domain", Domain: "example.com"},
+			expected:   false,
 		},
 		{
-			name:     "complex email address",
-			email:    "user+tag@example.co.uk",
-			expected: "example.co.uk",
+			name:       "anyone is external",
+			permission: Permission{Type: "anyone"},
+			expected:   true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := ExtractDomain(tt.email)
-			assert.Equal(t, tt.expected, result)
+			assert.Equal(t, tt.expected, client.IsExternalShare(tt.permission))
 		})
 	}
+
+	client.SetInternalEmailPattern(nil)
+	assert.False(t, client.IsExternalShare(Permission{Type: "user", EmailAddress: "bob-contractor@example.com"}),
+		"a nil pattern restores the domain comparison")
 }
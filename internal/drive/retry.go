@@ -0,0 +1,132 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package drive
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/leansecurity-co/gwork/pkg/exitcode"
+	"google.golang.org/api/googleapi"
+)
+
+const (
+	// maxRetries is the number of times a failed API call is retried.
+	maxRetries = 5
+
+	// defaultRetryDelay is the backoff before the first retry; it doubles
+	// with each further retry up to maxRetryDelay.
+	defaultRetryDelay = time.Second
+	maxRetryDelay     = 30 * time.Second
+)
+
+// SetRetryStatusCodes sets the HTTP status codes of API errors that are
+// retried with exponential backoff, e.g. 429 and 503. No codes means
+// failed calls are not retried.
+func (c *Client) SetRetryStatusCodes(codes ...int) {
+	if len(codes) == 0 {
+		c.retryCodes = nil
+		return
+	}
+	c.retryCodes = make(map[int]struct{}, len(codes))
+	for _, code := range codes {
+		c.retryCodes[code] = struct{}{}
+	}
+}
+
+// IsRetryable reports whether err is an API error with one of the status
+// codes set with SetRetryStatusCodes.
+func (c *Client) IsRetryable(err error) bool {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	_, ok := c.retryCodes[apiErr.Code]
+	return ok
+}
+
+// rateLimitReasons are the error reasons Drive gives with 403 responses
+// when a rate limit, rather than access, refused the request.
+var rateLimitReasons = map[string]bool{
+	"rateLimitExceeded":     true,
+	"userRateLimitExceeded": true,
+}
+
+// isRateLimit reports whether apiErr refused a request because of a rate
+// limit: a 429, or a 403 with a rate limit reason.
+func isRateLimit(apiErr *googleapi.Error) bool {
+	switch apiErr.Code {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusForbidden:
+		for _, item := range apiErr.Errors {
+			if rateLimitReasons[item.Reason] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// observe feeds the outcome of an API call to the throttle.
+func (c *Client) observe(err error) {
+	var apiErr *googleapi.Error
+	switch {
+	case err == nil:
+		c.throttle.succeeded()
+	case errors.As(err, &apiErr) && isRateLimit(apiErr):
+		c.throttle.rateLimited()
+	}
+}
+
+// apiError marks err, returned by a Drive API call, with the
+// exitcode.APIError exit code. Canceled calls are left unmarked.
+func apiError(err error) error {
+	if errors.Is(err, context.Canceled) {
+		return err
+	}
+	return exitcode.Wrap(exitcode.APIError, err)
+}
+
+// retry runs call, retrying it with exponential backoff and jitter while
+// it fails with a retryable error. Each attempt waits for the throttle,
+// which learns from rate-limit errors, and each retry is an API call of
+// its own, so it claims a call from the budget first. Waiting stops when
+// ctx is canceled.
+func (c *Client) retry(ctx context.Context, call func() error) error {
+	delay := c.retryDelay
+	if delay == 0 {
+		delay = defaultRetryDelay
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err := c.throttle.wait(ctx); err != nil {
+			return err
+		}
+		err := call()
+		c.observe(err)
+		if err == nil || attempt == maxRetries || !c.IsRetryable(err) {
+			return apiError(err)
+		}
+
+		// Sleep between half and the full delay so concurrent workers
+		// hitting the same quota do not retry in lockstep.
+		wait := delay/2 + rand.N(delay/2+1)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		delay = min(delay*2, maxRetryDelay)
+
+		if err := c.reserveCall(); err != nil {
+			return err
+		}
+	}
+}
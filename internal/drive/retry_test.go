@@ -0,0 +1,134 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package drive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	v3 "google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+)
+
+// newRetryClient returns a client over api that retries codes without
+// waiting between attempts.
+func newRetryClient(api DriveAPI, codes ...int) *Client {
+	client := NewClientWithAPI(api, "example.com", 100, false)
+	client.SetRetryStatusCodes(codes...)
+	client.retryDelay = time.Nanosecond
+	return client
+}
+
+func TestClient_Retry(t *testing.T) {
+	permissions := &ListPermissionsResult{Permissions: []*v3.Permission{{Id: "p1", Type: "anyone", Role: "reader"}}}
+
+	tests := []struct {
+		name      string
+		codes     []int
+		status    int
+		wantCalls int
+		wantErr   bool
+	}{
+		{name: "configured code is retried", codes: []int{429, 500, 502, 503}, status: http.StatusServiceUnavailable, wantCalls: 2},
+		{name: "custom code is retried", codes: []int{408}, status: http.StatusRequestTimeout, wantCalls: 2},
+		{name: "code outside the set is not retried", codes: []int{429, 500, 502, 503}, status: http.StatusForbidden, wantCalls: 1, wantErr: true},
+		{name: "default code dropped from the set is not retried", codes: []int{429}, status: http.StatusServiceUnavailable, wantCalls: 1, wantErr: true},
+		{name: "no codes disables retries", status: http.StatusTooManyRequests, wantCalls: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI := new(MockDriveAPI)
+			mockAPI.On("ListPermissions", mock.Anything, "file1", mock.Anything).
+				Return(nil, &googleapi.Error{Code: tt.status}).Once()
+			mockAPI.On("ListPermissions", mock.Anything, "file1", mock.Anything).
+				Return(permissions, nil).Once()
+
+			client := newRetryClient(mockAPI, tt.codes...)
+			perms, err := client.GetFilePermissions(context.Background(), "file1")
+
+			if tt.wantErr {
+				var apiErr *googleapi.Error
+				require.ErrorAs(t, err, &apiErr)
+				assert.Equal(t, tt.status, apiErr.Code)
+			} else {
+				require.NoError(t, err)
+				assert.Len(t, perms, 1)
+			}
+			mockAPI.AssertNumberOfCalls(t, "ListPermissions", tt.wantCalls)
+			assert.Equal(t, int64(tt.wantCalls), client.Stats().ListPermissions.Calls)
+		})
+	}
+}
+
+func TestClient_Retry_GivesUp(t *testing.T) {
+	mockAPI := new(MockDriveAPI)
+	mockAPI.On("ListFiles", mock.Anything, mock.Anything).
+		Return(nil, &googleapi.Error{Code: http.StatusInternalServerError})
+
+	client := newRetryClient(mockAPI, http.StatusInternalServerError)
+	_, err := client.ListAllFiles(context.Background())
+
+	require.Error(t, err)
+	mockAPI.AssertNumberOfCalls(t, "ListFiles", maxRetries+1)
+}
+
+func TestClient_Retry_NonAPIError(t *testing.T) {
+	mockAPI := new(MockDriveAPI)
+	mockAPI.On("GetFile", mock.Anything, "file1", mock.Anything).
+		Return(nil, errors.New("connection reset"))
+
+	client := newRetryClient(mockAPI, http.StatusServiceUnavailable)
+	_, err := client.GetFile(context.Background(), "file1")
+
+	require.Error(t, err)
+	mockAPI.AssertNumberOfCalls(t, "GetFile", 1)
+}
+
+func TestClient_Retry_CountsAgainstBudget(t *testing.T) {
+	mockAPI := new(MockDriveAPI)
+	mockAPI.On("ListPermissions", mock.Anything, "file1", mock.Anything).
+		Return(nil, &googleapi.Error{Code: http.StatusTooManyRequests})
+
+	client := newRetryClient(mockAPI, http.StatusTooManyRequests)
+	client.SetMaxAPICalls(3)
+	_, err := client.GetFilePermissions(context.Background(), "file1")
+
+	require.ErrorIs(t, err, ErrBudgetExceeded)
+	mockAPI.AssertNumberOfCalls(t, "ListPermissions", 3)
+}
+
+func TestClient_Retry_CanceledWhileWaiting(t *testing.T) {
+	mockAPI := new(MockDriveAPI)
+	mockAPI.On("ListPermissions", mock.Anything, "file1", mock.Anything).
+		Return(nil, &googleapi.Error{Code: http.StatusServiceUnavailable})
+
+	client := NewClientWithAPI(mockAPI, "example.com", 100, false)
+	client.SetRetryStatusCodes(http.StatusServiceUnavailable)
+	client.retryDelay = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := client.GetFilePermissions(ctx, "file1")
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	mockAPI.AssertNumberOfCalls(t, "ListPermissions", 1)
+}
+
+func TestClient_IsRetryable(t *testing.T) {
+	client := newRetryClient(nil, http.StatusTooManyRequests, http.StatusBadGateway)
+
+	assert.True(t, client.IsRetryable(&googleapi.Error{Code: http.StatusTooManyRequests}))
+	assert.True(t, client.IsRetryable(fmt.Errorf("failed to list permissions: %w", &googleapi.Error{Code: http.StatusBadGateway})))
+	assert.False(t, client.IsRetryable(&googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "userRateLimitExceeded"}}}))
+	assert.False(t, client.IsRetryable(ErrBudgetExceeded))
+	assert.False(t, newRetryClient(nil).IsRetryable(&googleapi.Error{Code: http.StatusTooManyRequests}), "no codes retries nothing")
+}
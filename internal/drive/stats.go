@@ -0,0 +1,93 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package drive
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// CallStats counts the calls made to one API method and the time spent in
+// them, including failed calls.
+type CallStats struct {
+	Calls    int64
+	Duration time.Duration
+}
+
+// Average returns the mean latency per call, or zero without calls.
+func (s CallStats) Average() time.Duration {
+	if s.Calls == 0 {
+		return 0
+	}
+	return s.Duration / time.Duration(s.Calls)
+}
+
+// Stats summarizes the Drive API calls made by a Client.
+type Stats struct {
+	ListFiles       CallStats
+	ListPermissions CallStats
+}
+
+// Total returns the combined stats of all API methods.
+func (s Stats) Total() CallStats {
+	return CallStats{
+		Calls:    s.ListFiles.Calls + s.ListPermissions.Calls,
+		Duration: s.ListFiles.Duration + s.ListPermissions.Duration,
+	}
+}
+
+// Add returns the combined stats of two clients, e.g. those of the domains
+// of a multi-domain audit.
+func (s Stats) Add(other Stats) Stats {
+	return Stats{
+		ListFiles: CallStats{
+			Calls:    s.ListFiles.Calls + other.ListFiles.Calls,
+			Duration: s.ListFiles.Duration + other.ListFiles.Duration,
+		},
+		ListPermissions: CallStats{
+			Calls:    s.ListPermissions.Calls + other.ListPermissions.Calls,
+			Duration: s.ListPermissions.Duration + other.ListPermissions.Duration,
+		},
+	}
+}
+
+// Sub returns the calls made since an earlier snapshot of the same client.
+func (s Stats) Sub(earlier Stats) Stats {
+	return Stats{
+		ListFiles: CallStats{
+			Calls:    s.ListFiles.Calls - earlier.ListFiles.Calls,
+			Duration: s.ListFiles.Duration - earlier.ListFiles.Duration,
+		},
+		ListPermissions: CallStats{
+			Calls:    s.ListPermissions.Calls - earlier.ListPermissions.Calls,
+			Duration: s.ListPermissions.Duration - earlier.ListPermissions.Duration,
+		},
+	}
+}
+
+// callCounter accumulates CallStats and is safe for concurrent use.
+type callCounter struct {
+	calls atomic.Int64
+	nanos atomic.Int64
+}
+
+// record counts a call that started at start.
+func (c *callCounter) record(start time.Time) {
+	c.calls.Add(1)
+	c.nanos.Add(int64(time.Since(start)))
+}
+
+// snapshot returns the current counts.
+func (c *callCounter) snapshot() CallStats {
+	return CallStats{Calls: c.calls.Load(), Duration: time.Duration(c.nanos.Load())}
+}
+
+// Stats returns the API calls made by the client so far. It is safe to call
+// while permissions are fetched concurrently.
+func (c *Client) Stats() Stats {
+	return Stats{
+		ListFiles:       c.listFilesCalls.snapshot(),
+		ListPermissions: c.listPermissionsCalls.snapshot(),
+	}
+}
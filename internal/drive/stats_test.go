@@ -0,0 +1,84 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package drive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	v3 "google.golang.org/api/drive/v3"
+)
+
+func TestClient_Stats(t *testing.T) {
+	mockAPI := new(MockDriveAPI)
+	mockAPI.On("ListFiles", mock.Anything, mock.MatchedBy(func(opts *ListFilesOptions) bool {
+		return opts.PageToken == ""
+	})).Return(&ListFilesResult{Files: []*v3.File{{Id: "file1"}}, NextPageToken: "page2"}, nil)
+	mockAPI.On("ListFiles", mock.Anything, mock.MatchedBy(func(opts *ListFilesOptions) bool {
+		return opts.PageToken == "page2"
+	})).Return(&ListFilesResult{Files: []*v3.File{{Id: "file2"}}}, nil)
+	mockAPI.On("ListPermissions", mock.Anything, "file1", mock.Anything).
+		Return(&ListPermissionsResult{}, nil)
+	mockAPI.On("ListPermissions", mock.Anything, "file2", mock.Anything).
+		Return(nil, errors.New("backend error"))
+
+	client := NewClientWithAPI(mockAPI, "example.com", 100, false)
+	assert.Equal(t, Stats{}, client.Stats())
+
+	_, err := client.ListAllFiles(context.Background())
+	require.NoError(t, err)
+	_, err = client.GetFilePermissions(context.Background(), "file1")
+	require.NoError(t, err)
+	_, err = client.GetFilePermissions(context.Background(), "file2")
+	require.Error(t, err)
+
+	stats := client.Stats()
+	assert.Equal(t, int64(2), stats.ListFiles.Calls, "one call per page")
+	assert.Equal(t, int64(2), stats.ListPermissions.Calls, "failed calls are counted")
+	assert.Equal(t, int64(4), stats.Total().Calls)
+	assert.GreaterOrEqual(t, stats.Total().Duration, time.Duration(0))
+}
+
+func TestClient_Stats_Concurrent(t *testing.T) {
+	mockAPI := new(MockDriveAPI)
+	mockAPI.On("ListPermissions", mock.Anything, mock.Anything, mock.Anything).
+		Return(&ListPermissionsResult{}, nil)
+
+	client := NewClientWithAPI(mockAPI, "example.com", 100, false)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				_, _ = client.GetFilePermissions(context.Background(), fmt.Sprintf("file%d-%d", i, j))
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(200), client.Stats().ListPermissions.Calls)
+}
+
+func TestStats_SubAndAverage(t *testing.T) {
+	earlier := Stats{ListFiles: CallStats{Calls: 1, Duration: time.Second}}
+	later := Stats{
+		ListFiles:       CallStats{Calls: 3, Duration: 5 * time.Second},
+		ListPermissions: CallStats{Calls: 4, Duration: 2 * time.Second},
+	}
+
+	delta := later.Sub(earlier)
+	assert.Equal(t, CallStats{Calls: 2, Duration: 4 * time.Second}, delta.ListFiles)
+	assert.Equal(t, 2*time.Second, delta.ListFiles.Average())
+	assert.Equal(t, 500*time.Millisecond, delta.ListPermissions.Average())
+	assert.Equal(t, time.Duration(0), CallStats{}.Average())
+}
@@ -0,0 +1,193 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package drive
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	// rateLimitStrikes is the number of rate-limit errors in a row that
+	// lower the request rate.
+	rateLimitStrikes = 3
+
+	// adaptiveStartQPS is the rate imposed when rate-limit errors arrive
+	// while no maximum rate is configured.
+	adaptiveStartQPS = 10.0
+
+	// minQPS is the lowest rate the throttle slows down to.
+	minQPS = 0.5
+
+	// throttleCooldown is the minimum time between a change of rate and a
+	// reduction, so that calls already in flight at the old rate do not
+	// lower it again.
+	throttleCooldown = 2 * time.Second
+
+	// throttleRecovery is how long a reduced rate is kept before a
+	// successful call raises it by a quarter, up to the configured maximum.
+	throttleRecovery = 10 * time.Second
+)
+
+// throttle paces API calls to a maximum rate, which it lowers when calls
+// keep failing with rate-limit errors and slowly raises again while they
+// succeed. Its zero value does not limit calls.
+type throttle struct {
+	mu sync.Mutex
+
+	// max is the configured rate in calls per second; 0 means none.
+	max float64
+	// limit is the current rate; 0 means calls are not paced.
+	limit float64
+	// next is the earliest time the next call may start.
+	next time.Time
+
+	strikes    int
+	lastChange time.Time
+
+	log io.Writer
+
+	// now and sleep are replaced in tests.
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// SetMaxQPS caps the API calls started per second; 0 means no cap. The
+// effective rate is lowered below it while Drive keeps returning
+// rate-limit errors.
+func (c *Client) SetMaxQPS(qps int) {
+	c.throttle.mu.Lock()
+	defer c.throttle.mu.Unlock()
+	c.throttle.max = float64(qps)
+	c.throttle.limit = float64(qps)
+}
+
+// SetThrottleLog makes the client write a line to w whenever it changes
+// its request rate. A nil w discards them.
+func (c *Client) SetThrottleLog(w io.Writer) {
+	c.throttle.mu.Lock()
+	defer c.throttle.mu.Unlock()
+	c.throttle.log = w
+}
+
+// EffectiveQPS returns the current limit on API calls per second, or 0
+// when calls are not limited.
+func (c *Client) EffectiveQPS() float64 {
+	c.throttle.mu.Lock()
+	defer c.throttle.mu.Unlock()
+	return c.throttle.limit
+}
+
+// wait blocks until the next call may start at the current rate.
+func (t *throttle) wait(ctx context.Context) error {
+	t.mu.Lock()
+	if t.limit == 0 {
+		t.mu.Unlock()
+		return nil
+	}
+	now := t.clock()
+	start := t.next
+	if start.Before(now) {
+		start = now
+	}
+	t.next = start.Add(time.Duration(float64(time.Second) / t.limit))
+	sleep := t.sleep
+	t.mu.Unlock()
+
+	delay := start.Sub(now)
+	if delay <= 0 {
+		return nil
+	}
+	if sleep != nil {
+		return sleep(ctx, delay)
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// rateLimited records a rate-limit error, halving the rate after
+// rateLimitStrikes of them in a row.
+func (t *throttle) rateLimited() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.strikes++
+	now := t.clock()
+	if t.strikes < rateLimitStrikes || (!t.lastChange.IsZero() && now.Sub(t.lastChange) < throttleCooldown) {
+		return
+	}
+
+	if t.limit == 0 {
+		t.limit = adaptiveStartQPS
+	} else {
+		t.limit = max(t.limit/2, minQPS)
+	}
+	t.strikes = 0
+	t.lastChange = now
+	t.logf("Drive API rate limit exceeded, slowing down to %.1f calls per second\n", t.limit)
+}
+
+// succeeded records a successful call, raising a reduced rate by a quarter
+// once throttleRecovery has passed since the last change.
+func (t *throttle) succeeded() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.strikes = 0
+	if !t.reduced() {
+		return
+	}
+	now := t.clock()
+	if now.Sub(t.lastChange) < throttleRecovery {
+		return
+	}
+
+	t.limit *= 1.25
+	t.lastChange = now
+	ceiling := t.max
+	if ceiling == 0 {
+		ceiling = adaptiveStartQPS
+	}
+	if t.limit < ceiling {
+		t.logf("Drive API calls succeeding, speeding up to %.1f calls per second\n", t.limit)
+		return
+	}
+
+	t.limit = t.max
+	if t.limit == 0 {
+		t.logf("Drive API calls succeeding, no longer limiting the request rate\n")
+	} else {
+		t.logf("Drive API calls succeeding, back to %.1f calls per second\n", t.limit)
+	}
+}
+
+// reduced reports whether the rate is below the configured one.
+func (t *throttle) reduced() bool {
+	if t.max == 0 {
+		return t.limit > 0
+	}
+	return t.limit < t.max
+}
+
+func (t *throttle) clock() time.Time {
+	if t.now != nil {
+		return t.now()
+	}
+	return time.Now()
+}
+
+func (t *throttle) logf(format string, args ...any) {
+	if t.log != nil {
+		fmt.Fprintf(t.log, format, args...)
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package drive
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	v3 "google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+)
+
+// fakeClock is a manually advanced clock for throttle tests.
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time          { return c.now }
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+// newTestThrottle returns a throttle capped at maxQPS on a fake clock,
+// logging to the returned buffer.
+func newTestThrottle(maxQPS float64) (*throttle, *fakeClock, *bytes.Buffer) {
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	var log bytes.Buffer
+	return &throttle{max: maxQPS, limit: maxQPS, now: clock.Now, log: &log}, clock, &log
+}
+
+func TestThrottle_BurstReducesRate(t *testing.T) {
+	th, clock, log := newTestThrottle(10)
+
+	th.rateLimited()
+	th.rateLimited()
+	assert.Equal(t, 10.0, th.limit, "isolated rate-limit errors keep the rate")
+
+	th.rateLimited()
+	assert.Equal(t, 5.0, th.limit)
+	assert.Contains(t, log.String(), "slowing down to 5.0 calls per second")
+
+	for range rateLimitStrikes {
+		th.rateLimited()
+	}
+	assert.Equal(t, 5.0, th.limit, "errors from calls in flight during the cooldown do not lower the rate again")
+
+	clock.Advance(throttleCooldown)
+	th.rateLimited()
+	assert.Equal(t, 2.5, th.limit, "errors that continue after the cooldown do")
+
+	for range 10 {
+		clock.Advance(throttleCooldown)
+		for range rateLimitStrikes {
+			th.rateLimited()
+		}
+	}
+	assert.Equal(t, minQPS, th.limit, "the rate never drops below the floor")
+}
+
+func TestThrottle_SuccessResetsStrikes(t *testing.T) {
+	th, _, _ := newTestThrottle(10)
+
+	for range 5 {
+		th.rateLimited()
+		th.rateLimited()
+		th.succeeded()
+	}
+	assert.Equal(t, 10.0, th.limit)
+}
+
+func TestThrottle_Recovers(t *testing.T) {
+	th, clock, log := newTestThrottle(8)
+	for range rateLimitStrikes {
+		th.rateLimited()
+	}
+	require.Equal(t, 4.0, th.limit)
+
+	th.succeeded()
+	assert.Equal(t, 4.0, th.limit, "the reduced rate is kept for a while")
+
+	clock.Advance(throttleRecovery)
+	th.succeeded()
+	assert.Equal(t, 5.0, th.limit)
+	assert.Contains(t, log.String(), "speeding up to 5.0 calls per second")
+
+	for range 5 {
+		clock.Advance(throttleRecovery)
+		th.succeeded()
+	}
+	assert.Equal(t, 8.0, th.limit, "the rate recovers to the configured maximum and no further")
+	assert.Contains(t, log.String(), "back to 8.0 calls per second")
+}
+
+func TestThrottle_Unlimited(t *testing.T) {
+	th, clock, log := newTestThrottle(0)
+
+	require.NoError(t, th.wait(context.Background()))
+	for range rateLimitStrikes {
+		th.rateLimited()
+	}
+	assert.Equal(t, adaptiveStartQPS, th.limit, "rate-limit errors impose a rate when none is configured")
+
+	clock.Advance(throttleCooldown)
+	for range rateLimitStrikes {
+		th.rateLimited()
+	}
+	assert.Equal(t, adaptiveStartQPS/2, th.limit)
+
+	for range 10 {
+		clock.Advance(throttleRecovery)
+		th.succeeded()
+	}
+	assert.Zero(t, th.limit)
+	assert.Contains(t, log.String(), "no longer limiting the request rate")
+}
+
+func TestThrottle_WaitPacesCalls(t *testing.T) {
+	th, clock, _ := newTestThrottle(4)
+	var delays []time.Duration
+	th.sleep = func(_ context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+
+	for range 3 {
+		require.NoError(t, th.wait(context.Background()))
+	}
+	assert.Equal(t, []time.Duration{250 * time.Millisecond, 500 * time.Millisecond}, delays,
+		"the first call starts at once and the others are spaced at the rate")
+
+	clock.Advance(time.Second)
+	delays = nil
+	require.NoError(t, th.wait(context.Background()))
+	assert.Empty(t, delays, "an idle throttle does not delay the next call")
+}
+
+func TestClient_RateLimitBurstLowersRate(t *testing.T) {
+	permissions := &ListPermissionsResult{Permissions: []*v3.Permission{{Id: "p1", Type: "anyone", Role: "reader"}}}
+	rateLimited := &googleapi.Error{
+		Code:   http.StatusForbidden,
+		Errors: []googleapi.ErrorItem{{Reason: "userRateLimitExceeded"}},
+	}
+
+	mockAPI := new(MockDriveAPI)
+	mockAPI.On("ListPermissions", mock.Anything, "file1", mock.Anything).
+		Return(nil, &googleapi.Error{Code: http.StatusTooManyRequests}).Times(2)
+	mockAPI.On("ListPermissions", mock.Anything, "file1", mock.Anything).
+		Return(nil, rateLimited).Once()
+	mockAPI.On("ListPermissions", mock.Anything, "file1", mock.Anything).
+		Return(permissions, nil).Once()
+
+	client := newRetryClient(mockAPI, http.StatusTooManyRequests, http.StatusForbidden)
+	client.SetMaxQPS(20)
+	client.throttle.sleep = func(context.Context, time.Duration) error { return nil }
+	var log bytes.Buffer
+	client.SetThrottleLog(&log)
+
+	perms, err := client.GetFilePermissions(context.Background(), "file1")
+	require.NoError(t, err)
+	assert.Len(t, perms, 1)
+	assert.Equal(t, 10.0, client.EffectiveQPS())
+	assert.Equal(t, "Drive API rate limit exceeded, slowing down to 10.0 calls per second\n", log.String())
+}
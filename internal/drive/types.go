@@ -4,15 +4,26 @@
 // Package drive provides a client for Google Drive API operations.
 package drive
 
+import "time"
+
 // FileInfo represents relevant file metadata.
 type FileInfo struct {
 	ID           string
 	Name         string
 	MimeType     string
 	OwnerEmail   string
+	OwnerName    string
 	CreatedTime  string
 	ModifiedTime string
 	Size         int64
+	Trashed      bool
+	WebViewLink  string
+	DriveID      string // Empty for files in a user's My Drive
+
+	// ViewedByMeTime is when the impersonated admin last viewed the file.
+	// It is empty if the admin never opened it, so it only approximates
+	// last access for files the admin works with.
+	ViewedByMeTime string
 }
 
 // Permission represents a file permission.
@@ -23,4 +34,16 @@ type Permission struct {
 	EmailAddress string
 	Domain       string
 	DisplayName  string
+
+	// Inherited is set when the permission comes from a parent folder or
+	// shared drive rather than being granted on the file itself.
+	Inherited     bool
+	InheritedFrom string
+
+	// ExpirationTime is zero when the permission does not expire.
+	ExpirationTime time.Time
+
+	// Deleted is set when the grantee's account has been deleted. Drive
+	// only reports it for user and group permissions, and not always.
+	Deleted bool
 }
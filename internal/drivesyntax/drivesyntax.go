@@ -0,0 +1,83 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package drivesyntax parses the text formats shared by the configuration
+// and the Drive client: email addresses, field masks and search queries. It
+// imports no other gwork package, so config can validate settings without
+// depending on the Drive client.
+package drivesyntax
+
+import (
+	"errors"
+	"strings"
+)
+
+// ExtractDomain extracts the domain part from an email address.
+func ExtractDomain(email string) string {
+	idx := strings.LastIndex(email, "@")
+	if idx < 0 {
+		return ""
+	}
+	return email[idx+1:]
+}
+
+// SplitFields splits a field mask on top-level commas, keeping nested
+// selections such as "owners(emailAddress, displayName)" intact.
+func SplitFields(mask string) []string {
+	var fields []string
+	depth := 0
+	start := 0
+
+	for i, r := range mask {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				fields = append(fields, strings.TrimSpace(mask[start:i]))
+				start = i + 1
+			}
+		}
+	}
+
+	if last := strings.TrimSpace(mask[start:]); last != "" || len(fields) > 0 {
+		fields = append(fields, last)
+	}
+
+	return fields
+}
+
+// ValidateQuery checks that a Drive search query passed to drive.Client.SetQuery has
+// terminated string literals and balanced parentheses, so that it cannot
+// close the group it is wrapped in. It does not check the query grammar;
+// the Drive API rejects invalid queries when listing.
+func ValidateQuery(query string) error {
+	depth := 0
+	inString := false
+	for i := 0; i < len(query); i++ {
+		switch ch := query[i]; {
+		case inString && ch == '\\':
+			i++ // skip the escaped character
+		case ch == '\'':
+			inString = !inString
+		case inString:
+		case ch == '(':
+			depth++
+		case ch == ')':
+			depth--
+			if depth < 0 {
+				return errors.New("unbalanced parentheses")
+			}
+		}
+	}
+
+	if inString {
+		return errors.New("unterminated string literal")
+	}
+	if depth != 0 {
+		return errors.New("unbalanced parentheses")
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package drivesyntax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractDomain(t *testing.T) {
+	tests := []struct {
+		name     string
+		email    string
+		expected string
+	}{
+		{
+			name:     "standard email",
+			email:    "user@example.com",
+			expected: "example.com",
+		},
+		{
+			name:     "email with subdomain",
+			email:    "user@mail.example.com",
+			expected: "mail.example.com",
+		},
+		{
+			name:     "email with multiple @ symbols uses last one",
+			email:    "user@name@example.com",
+			expected: "example.com",
+		},
+		{
+			name:     "email without @ symbol",
+			email:    "notanemail",
+			expected: "",
+		},
+		{
+			name:     "empty email",
+			email:    "",
+			expected: "",
+		},
+		{
+			name:     "email with @ at end",
+			email:    "user@",
+			expected: "",
+		},
+		{
+			name:     "email with @ at start",
+			email:    "@example.com",
+			expected: "example.com",
+		},
+		{
+			name:     "complex email address",
+			email:    "user+tag@example.co.uk",
+			expected: "example.co.uk",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ExtractDomain(tt.email)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestSplitFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		mask     string
+		expected []string
+	}{
+		{name: "empty mask", mask: "", expected: nil},
+		{name: "single field", mask: "id", expected: []string{"id"}},
+		{name: "multiple fields", mask: "id, name,size", expected: []string{"id", "name", "size"}},
+		{
+			name:     "nested selection",
+			mask:     "id, owners(emailAddress, displayName), size",
+			expected: []string{"id", "owners(emailAddress, displayName)", "size"},
+		},
+		{name: "trailing comma", mask: "id,", expected: []string{"id", ""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, SplitFields(tt.mask))
+		})
+	}
+}
+
+func TestValidateQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		wantErr string
+	}{
+		{name: "empty", query: ""},
+		{name: "parent folder", query: "'folder1' in parents"},
+		{name: "nested groups", query: "(name contains 'a' or (name contains 'b'))"},
+		{name: "parentheses inside string", query: "name = 'Q3 (draft'"},
+		{name: "escaped quote", query: `name = 'O\'Brien'`},
+		{name: "unterminated string", query: "name = 'budget", wantErr: "unterminated string literal"},
+		{name: "stray quote", query: "x') or (trashed = true", wantErr: "unterminated string literal"},
+		{name: "closes the wrapping group", query: "name = 'a') or (name = 'b'", wantErr: "unbalanced parentheses"},
+		{name: "unclosed parenthesis", query: "(name = 'a'", wantErr: "unbalanced parentheses"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateQuery(tt.query)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
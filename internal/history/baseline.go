@@ -0,0 +1,75 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Baseline is the snapshot of the external shares found by the last run,
+// stored as share keys so the next run can tell which shares are new.
+type Baseline struct {
+	Timestamp time.Time `json:"timestamp"`
+	Domain    string    `json:"domain"`
+	Keys      []string  `json:"keys"`
+}
+
+// BaselinePath returns the baseline file kept next to historyFile: the
+// history file name with its extension replaced by ".baseline.json".
+func BaselinePath(historyFile string) string {
+	return strings.TrimSuffix(historyFile, filepath.Ext(historyFile)) + ".baseline.json"
+}
+
+// ReadBaseline reads the baseline at path. A missing file yields a nil
+// baseline, meaning there is no previous run to compare against.
+func ReadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+
+	var baseline Baseline
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+	return &baseline, nil
+}
+
+// WriteBaseline replaces the baseline at path. The snapshot is written to
+// a temporary file first so an interrupted run leaves the old one intact.
+func WriteBaseline(path string, baseline Baseline) error {
+	return writeJSONFile(path, baseline, "baseline")
+}
+
+// writeJSONFile replaces the file at path with v encoded as JSON, writing
+// a temporary file first and renaming it into place. what names the file
+// in errors.
+func writeJSONFile(path string, v any, what string) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", what, err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", what, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", what, err)
+	}
+	return nil
+}
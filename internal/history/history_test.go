@@ -0,0 +1,139 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+	"github.com/leansecurity-co/gwork/internal/drive"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendAndRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "history.jsonl")
+
+	entries := []Entry{
+		{
+			Timestamp:      time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+			Domain:         "example.com",
+			TotalFiles:     100,
+			ExternalShares: 10,
+			PublicShares:   2,
+		},
+		{
+			Timestamp:      time.Date(2025, 1, 8, 10, 0, 0, 0, time.UTC),
+			Domain:         "example.com",
+			TotalFiles:     120,
+			ExternalShares: 8,
+			PublicShares:   1,
+		},
+		{
+			Timestamp:      time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC),
+			Domain:         "example.com",
+			TotalFiles:     130,
+			ExternalShares: 5,
+			PublicShares:   0,
+		},
+	}
+
+	for _, e := range entries {
+		require.NoError(t, Append(path, e))
+	}
+
+	got, err := Read(path)
+	require.NoError(t, err)
+	assert.Equal(t, entries, got)
+}
+
+func TestAppend_PreservesExistingEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	first := Entry{Timestamp: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), Domain: "example.com", TotalFiles: 1}
+	second := Entry{Timestamp: time.Date(2025, 2, 2, 0, 0, 0, 0, time.UTC), Domain: "example.com", TotalFiles: 2}
+
+	require.NoError(t, Append(path, first))
+	require.NoError(t, Append(path, second))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"total_files":1`)
+	assert.Contains(t, string(data), `"total_files":2`)
+}
+
+func TestRead_MissingFile(t *testing.T) {
+	entries, err := Read(filepath.Join(t.TempDir(), "missing.jsonl"))
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestRead_InvalidLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("{\"domain\":\"example.com\"}\nnot-json\n"), 0600))
+
+	_, err := Read(path)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "line 2")
+}
+
+func TestBaselinePath(t *testing.T) {
+	assert.Equal(t, filepath.Join("out", "history.baseline.json"), BaselinePath(filepath.Join("out", "history.jsonl")))
+	assert.Equal(t, "runs.baseline.json", BaselinePath("runs"))
+}
+
+func TestWriteAndReadBaseline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "history.baseline.json")
+	want := Baseline{
+		Timestamp: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		Domain:    "example.com",
+		Keys:      []string{"a", "b"},
+	}
+
+	require.NoError(t, WriteBaseline(path, want))
+	got, err := ReadBaseline(path)
+	require.NoError(t, err)
+	assert.Equal(t, &want, got)
+
+	want.Keys = []string{"c"}
+	require.NoError(t, WriteBaseline(path, want))
+	got, err = ReadBaseline(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c"}, got.Keys)
+}
+
+func TestReadBaseline_MissingFile(t *testing.T) {
+	baseline, err := ReadBaseline(filepath.Join(t.TempDir(), "missing.json"))
+	assert.NoError(t, err)
+	assert.Nil(t, baseline)
+}
+
+func TestWriteAndReadFileSnapshots(t *testing.T) {
+	path := FileSnapshotsPath(filepath.Join(t.TempDir(), "history.jsonl"))
+	assert.Equal(t, "history.files.json", filepath.Base(path))
+
+	missing, err := ReadFileSnapshots(path)
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	want := FileSnapshots{
+		Timestamp: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		Domain:    "example.com",
+		Files: map[string]audit.FileSnapshot{
+			"f1": {
+				ModifiedTime: "2025-02-01T00:00:00.000Z",
+				Permissions: []drive.Permission{
+					{ID: "p1", Type: "user", Role: "reader", EmailAddress: "guest@partner.com",
+						ExpirationTime: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)},
+				},
+			},
+		},
+	}
+	require.NoError(t, WriteFileSnapshots(path, want))
+	got, err := ReadFileSnapshots(path)
+	require.NoError(t, err)
+	assert.Equal(t, &want, got)
+}
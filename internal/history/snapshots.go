@@ -0,0 +1,55 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+)
+
+// FileSnapshots is the permissions of every file seen by the last
+// incremental run, keyed by file ID, so the next run can reuse those of
+// unchanged files.
+type FileSnapshots struct {
+	Timestamp time.Time                     `json:"timestamp"`
+	Domain    string                        `json:"domain"`
+	Files     map[string]audit.FileSnapshot `json:"files"`
+}
+
+// FileSnapshotsPath returns the file snapshots file kept next to
+// historyFile: the history file name with its extension replaced by
+// ".files.json".
+func FileSnapshotsPath(historyFile string) string {
+	return strings.TrimSuffix(historyFile, filepath.Ext(historyFile)) + ".files.json"
+}
+
+// ReadFileSnapshots reads the file snapshots at path. A missing file yields
+// nil, meaning there is nothing to reuse.
+func ReadFileSnapshots(path string) (*FileSnapshots, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read file snapshots: %w", err)
+	}
+
+	var snapshots FileSnapshots
+	if err := json.Unmarshal(data, &snapshots); err != nil {
+		return nil, fmt.Errorf("failed to parse file snapshots %s: %w", path, err)
+	}
+	return &snapshots, nil
+}
+
+// WriteFileSnapshots replaces the file snapshots at path, leaving the old
+// ones intact if the write is interrupted.
+func WriteFileSnapshots(path string, snapshots FileSnapshots) error {
+	return writeJSONFile(path, snapshots, "file snapshots")
+}
@@ -0,0 +1,86 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package reporter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// flushEvery is the number of records after which CSV writers flush their
+// buffered output to the temporary report file.
+const flushEvery = 1000
+
+// atomicFile is a report file written to a temporary file in the target
+// directory and renamed into place by Commit. Until Commit succeeds, an
+// existing file at the target path is left untouched. Everything written is
+// also fed to a SHA-256 hash, so the checksum is known without re-reading
+// the file.
+type atomicFile struct {
+	*os.File
+	path      string
+	hash      hash.Hash
+	committed bool
+}
+
+// createAtomic creates a temporary file for a report at path.
+func createAtomic(path string) (*atomicFile, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return nil, err
+	}
+	return &atomicFile{File: tmp, path: path, hash: sha256.New()}, nil
+}
+
+// Write writes p to the temporary file and the checksum.
+func (f *atomicFile) Write(p []byte) (int, error) {
+	n, err := f.File.Write(p)
+	f.hash.Write(p[:n])
+	return n, err
+}
+
+// WriteString writes s to the temporary file and the checksum.
+func (f *atomicFile) WriteString(s string) (int, error) {
+	return f.Write([]byte(s))
+}
+
+// ReadFrom copies r to the temporary file through Write, so io.Copy cannot
+// bypass the checksum via os.File.ReadFrom.
+func (f *atomicFile) ReadFrom(r io.Reader) (int64, error) {
+	return io.Copy(struct{ io.Writer }{f}, r)
+}
+
+// Checksum returns the hex-encoded SHA-256 of everything written so far.
+func (f *atomicFile) Checksum() string {
+	return hex.EncodeToString(f.hash.Sum(nil))
+}
+
+// Commit closes the temporary file and renames it to the final path.
+func (f *atomicFile) Commit() error {
+	if err := f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+	if err := os.Rename(f.Name(), f.path); err != nil {
+		_ = os.Remove(f.Name())
+		return fmt.Errorf("failed to move report into place: %w", err)
+	}
+	f.committed = true
+	return nil
+}
+
+// Abort closes and removes the temporary file unless it was committed. It is
+// safe to defer Abort right after createAtomic.
+func (f *atomicFile) Abort() {
+	if f.committed {
+		return
+	}
+	_ = f.Close()
+	_ = os.Remove(f.Name())
+}
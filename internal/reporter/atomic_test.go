@@ -0,0 +1,93 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package reporter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAtomicFile(t *testing.T) {
+	t.Run("final file only appears on commit", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "report.csv")
+
+		file, err := createAtomic(path)
+		require.NoError(t, err)
+		defer file.Abort()
+
+		_, err = file.WriteString("new\n")
+		require.NoError(t, err)
+		assert.NoFileExists(t, path)
+
+		require.NoError(t, file.Commit())
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "new\n", string(data))
+		assertNoTempFiles(t, filepath.Dir(path))
+	})
+
+	t.Run("abort keeps existing report", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "report.csv")
+		require.NoError(t, os.WriteFile(path, []byte("old\n"), 0o600))
+
+		file, err := createAtomic(path)
+		require.NoError(t, err)
+		_, err = file.WriteString("partial")
+		require.NoError(t, err)
+		file.Abort()
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "old\n", string(data))
+		assertNoTempFiles(t, filepath.Dir(path))
+	})
+
+	t.Run("failed rename removes temp file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "report.csv")
+		require.NoError(t, os.MkdirAll(filepath.Join(path, "child"), 0o750))
+
+		file, err := createAtomic(path)
+		require.NoError(t, err)
+		defer file.Abort()
+
+		err = file.Commit()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to move report into place")
+		assertNoTempFiles(t, filepath.Dir(path))
+	})
+}
+
+func TestCSVReporter_AtomicWrite(t *testing.T) {
+	dir := t.TempDir()
+	rep, err := NewCSVReporter(dir)
+	require.NoError(t, err)
+
+	records := make([]audit.FileRecord, flushEvery*2+1)
+	for i := range records {
+		records[i] = audit.FileRecord{
+			OwnerEmail: "alice@example.com",
+			FileID:     fmt.Sprintf("file%d", i),
+			FileName:   fmt.Sprintf("doc%05d.txt", i),
+		}
+	}
+
+	require.NoError(t, rep.WriteFilesByOwner(records))
+	assertNoTempFiles(t, dir)
+
+	rows := readCSVFile(t, filepath.Join(dir, "files_by_owner.csv"))
+	assert.Len(t, rows, len(records)+1)
+}
+
+func assertNoTempFiles(t *testing.T, dir string) {
+	t.Helper()
+	matches, err := filepath.Glob(filepath.Join(dir, ".*.tmp-*"))
+	require.NoError(t, err)
+	assert.Empty(t, matches, "temporary report files should be removed")
+}
@@ -0,0 +1,149 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package reporter
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ChecksumsFileName is the name of the checksum list written alongside the
+// manifest, in the format read by sha256sum -c.
+const ChecksumsFileName = "SHA256SUMS"
+
+// checksums maps the path of each committed report file to its hex-encoded
+// SHA-256.
+type checksums map[string]string
+
+// commit commits f and records its checksum.
+func (c *checksums) commit(f *atomicFile) error {
+	if err := f.Commit(); err != nil {
+		return err
+	}
+	if *c == nil {
+		*c = make(checksums)
+	}
+	(*c)[f.path] = f.Checksum()
+	return nil
+}
+
+// writeChecksums writes SHA256SUMS listing the given files, whose paths are
+// relative to outputDir. Files committed without a recorded checksum, such
+// as the SQLite database and the manifest, are hashed from disk.
+func writeChecksums(outputDir string, files []string, sums checksums) error {
+	file, err := createAtomic(filepath.Join(outputDir, ChecksumsFileName))
+	if err != nil {
+		return fmt.Errorf("failed to create checksums file: %w", err)
+	}
+	defer file.Abort()
+
+	w := bufio.NewWriter(file)
+	for _, rel := range files {
+		path := filepath.Join(outputDir, rel)
+		sum, ok := sums[path]
+		if !ok {
+			if sum, err = fileChecksum(path); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s  %s\n", sum, filepath.ToSlash(rel)); err != nil {
+			return fmt.Errorf("failed to write checksums file: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write checksums file: %w", err)
+	}
+	return file.Commit()
+}
+
+// fileChecksum returns the hex-encoded SHA-256 of the file at path.
+func fileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open report file: %w", err)
+	}
+	defer f.Close() //nolint:errcheck // read-only
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to hash report file: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ErrChecksumMismatch is reported by VerifyChecksums for a file whose
+// content no longer matches its recorded checksum.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// ChecksumResult is the outcome of checking one file listed in SHA256SUMS.
+type ChecksumResult struct {
+	// Path is the file's path as listed, relative to the output directory.
+	Path string
+	// Err is nil when the file matches, ErrChecksumMismatch when its
+	// content differs, or the error reading it, e.g. os.ErrNotExist.
+	Err error
+}
+
+// VerifyChecksums recomputes the checksum of every file listed in the
+// SHA256SUMS file of outputDir and compares it with the recorded one.
+// It returns one result per listed file, in order, and an error only if
+// SHA256SUMS cannot be read or parsed.
+func VerifyChecksums(outputDir string) ([]ChecksumResult, error) {
+	f, err := os.Open(filepath.Join(outputDir, ChecksumsFileName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open checksums file: %w", err)
+	}
+	defer f.Close() //nolint:errcheck // read-only
+
+	var results []ChecksumResult
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		want, rel, err := parseChecksumLine(scanner.Text())
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", ChecksumsFileName, line, err)
+		}
+
+		result := ChecksumResult{Path: rel}
+		got, err := fileChecksum(filepath.Join(outputDir, filepath.FromSlash(rel)))
+		switch {
+		case err != nil:
+			result.Err = err
+		case got != want:
+			result.Err = ErrChecksumMismatch
+		}
+		results = append(results, result)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read checksums file: %w", err)
+	}
+	return results, nil
+}
+
+// parseChecksumLine splits a SHA256SUMS line, "<hex>  <path>" or
+// "<hex> *<path>" as written by sha256sum, into the lowercase checksum and
+// the path, which must stay inside the output directory.
+func parseChecksumLine(line string) (string, string, error) {
+	sum, rel, ok := strings.Cut(line, " ")
+	if !ok || len(rel) < 2 || (rel[0] != ' ' && rel[0] != '*') {
+		return "", "", errors.New("expected \"<sha256>  <path>\"")
+	}
+	rel = rel[1:]
+	if _, err := hex.DecodeString(sum); err != nil || len(sum) != 2*sha256.Size {
+		return "", "", fmt.Errorf("invalid SHA-256 %q", sum)
+	}
+	if !filepath.IsLocal(filepath.FromSlash(rel)) {
+		return "", "", fmt.Errorf("path %q is outside the output directory", rel)
+	}
+	return strings.ToLower(sum), rel, nil
+}
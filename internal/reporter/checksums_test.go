@@ -0,0 +1,199 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package reporter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readChecksums parses the SHA256SUMS file in dir into a map from path to
+// checksum, in the order listed.
+func readChecksums(t *testing.T, dir string) ([]string, map[string]string) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, ChecksumsFileName))
+	require.NoError(t, err)
+
+	var paths []string
+	sums := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+		sum, path, ok := strings.Cut(line, "  ")
+		require.True(t, ok, "malformed line %q", line)
+		paths = append(paths, path)
+		sums[path] = sum
+	}
+	return paths, sums
+}
+
+func TestWriteManifest_Checksums(t *testing.T) {
+	files := []audit.FileRecord{
+		{OwnerEmail: "alice@example.com", FileID: "1", FileName: "a.txt"},
+		{OwnerEmail: "bob@example.com", FileID: "2", FileName: "b.txt"},
+	}
+	shares := []audit.ExternalShareRecord{
+		{OwnerEmail: "alice@example.com", FileID: "1", FileName: "a.txt", PermissionType: "anyone"},
+	}
+
+	tests := []struct {
+		format string
+		opts   Options
+		want   []string
+	}{
+		{format: FormatCSV, want: []string{"files_by_owner.csv", "external_sharing.csv", "owners.csv"}},
+		{format: FormatCSV, opts: Options{SplitByOwner: true}, want: []string{
+			"files/alice@example.com.csv", "files/bob@example.com.csv", "files_index.csv", "external_sharing.csv", "owners.csv",
+		}},
+		{format: FormatCSV, opts: Options{MaxRowsPerFile: 1}, want: []string{
+			"files_by_owner.001.csv", "files_by_owner.002.csv", "external_sharing.csv", "owners.csv",
+		}},
+		{format: FormatJSON, want: []string{"files_by_owner.json", "external_sharing.json", "owners.json"}},
+		{format: FormatNDJSON, want: []string{"files_by_owner.ndjson", "external_sharing.ndjson", "owners.ndjson"}},
+		{format: FormatXLSX, want: []string{"files_by_owner.xlsx", "external_sharing.xlsx", "owners.xlsx"}},
+		{format: FormatSQLite, want: []string{"gwork.db"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			dir := t.TempDir()
+			rep, err := New(tt.format, dir, tt.opts)
+			require.NoError(t, err)
+
+			require.NoError(t, rep.WriteFilesByOwner(files))
+			require.NoError(t, rep.WriteExternalSharing(shares))
+			require.NoError(t, rep.WriteOwners(audit.SummarizeByOwner(files)))
+			require.NoError(t, rep.WriteManifest(RunMeta{Domain: "example.com"}))
+
+			paths, sums := readChecksums(t, dir)
+			assert.Equal(t, append(tt.want, ManifestFileName), paths)
+			for _, path := range paths {
+				data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(path)))
+				require.NoError(t, err)
+				want := sha256.Sum256(data)
+				assert.Equal(t, hex.EncodeToString(want[:]), sums[path], path)
+			}
+		})
+	}
+}
+
+func TestAtomicFile_ChecksumCoversAllWrites(t *testing.T) {
+	file, err := createAtomic(filepath.Join(t.TempDir(), "report.txt"))
+	require.NoError(t, err)
+	defer file.Abort()
+
+	_, err = file.Write([]byte("one,"))
+	require.NoError(t, err)
+	_, err = file.WriteString("two,")
+	require.NoError(t, err)
+	_, err = file.ReadFrom(strings.NewReader("three"))
+	require.NoError(t, err)
+	require.NoError(t, file.Commit())
+
+	want := sha256.Sum256([]byte("one,two,three"))
+	assert.Equal(t, hex.EncodeToString(want[:]), file.Checksum())
+}
+
+func TestVerifyChecksums(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(t *testing.T, dir string)
+		want   map[string]error
+	}{
+		{
+			name: "matching",
+			want: map[string]error{"external_sharing.csv": nil, ManifestFileName: nil},
+		},
+		{
+			name: "tampered",
+			modify: func(t *testing.T, dir string) {
+				path := filepath.Join(dir, "external_sharing.csv")
+				data, err := os.ReadFile(path)
+				require.NoError(t, err)
+				require.NoError(t, os.WriteFile(path, append(data, "x\n"...), 0o600))
+			},
+			want: map[string]error{"external_sharing.csv": ErrChecksumMismatch, ManifestFileName: nil},
+		},
+		{
+			name: "missing",
+			modify: func(t *testing.T, dir string) {
+				require.NoError(t, os.Remove(filepath.Join(dir, "external_sharing.csv")))
+			},
+			want: map[string]error{"external_sharing.csv": os.ErrNotExist, ManifestFileName: nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			rep, err := New(FormatCSV, dir, Options{})
+			require.NoError(t, err)
+			require.NoError(t, rep.WriteExternalSharing([]audit.ExternalShareRecord{
+				{OwnerEmail: "alice@example.com", FileID: "1", FileName: "a.txt", PermissionType: "anyone"},
+			}))
+			require.NoError(t, rep.WriteManifest(RunMeta{Version: "1.0.0"}))
+			if tt.modify != nil {
+				tt.modify(t, dir)
+			}
+
+			results, err := VerifyChecksums(dir)
+			require.NoError(t, err)
+			require.Len(t, results, len(tt.want))
+			for _, result := range results {
+				want, ok := tt.want[result.Path]
+				require.True(t, ok, "unexpected path %s", result.Path)
+				if want == nil {
+					assert.NoError(t, result.Err, result.Path)
+				} else {
+					assert.ErrorIs(t, result.Err, want, result.Path)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyChecksums_InvalidFile(t *testing.T) {
+	sum := strings.Repeat("a", 64)
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "no separator", content: sum + "\n", wantErr: "line 1: expected"},
+		{name: "short checksum", content: "abc  report.csv\n", wantErr: `line 1: invalid SHA-256 "abc"`},
+		{name: "path outside the directory", content: "\n" + sum + "  ../secret.csv\n", wantErr: `line 2: path "../secret.csv" is outside the output directory`},
+		{name: "absolute path", content: sum + "  /etc/passwd\n", wantErr: "is outside the output directory"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, ChecksumsFileName), []byte(tt.content), 0o600))
+
+			_, err := VerifyChecksums(dir)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+
+	_, err := VerifyChecksums(t.TempDir())
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestVerifyChecksums_BinaryMarker(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "report.csv"), []byte("a,b\n"), 0o600))
+	sum := sha256.Sum256([]byte("a,b\n"))
+	content := strings.ToUpper(hex.EncodeToString(sum[:])) + " *report.csv\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ChecksumsFileName), []byte(content), 0o600))
+
+	results, err := VerifyChecksums(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []ChecksumResult{{Path: "report.csv"}}, results)
+}
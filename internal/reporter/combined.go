@@ -0,0 +1,53 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+)
+
+// CombinedReport is a single JSON document holding the files and sharing
+// results of a run together with their totals, for piping into tools such
+// as jq.
+type CombinedReport struct {
+	Version        string                      `json:"version"`
+	Timestamp      time.Time                   `json:"timestamp"`
+	Domain         string                      `json:"domain"`
+	Summary        audit.Totals                `json:"summary"`
+	Files          []audit.FileRecord          `json:"files"`
+	ExternalShares []audit.ExternalShareRecord `json:"external_sharing"`
+}
+
+// NewCombinedReport builds the combined document from the files and sharing
+// results of a run. Records are sorted as in the individual reports.
+func NewCombinedReport(meta RunMeta, files, sharing *audit.AuditResult) CombinedReport {
+	report := audit.NewAuditReport(meta.Domain, meta.Timestamp, files, sharing)
+	return CombinedReport{
+		Version:        meta.Version,
+		Timestamp:      report.Timestamp,
+		Domain:         report.Domain,
+		Summary:        report.Summary,
+		Files:          report.Files,
+		ExternalShares: report.ExternalShares,
+	}
+}
+
+// WriteCombinedJSON writes report to w as one JSON document, indented when
+// indent is set.
+func WriteCombinedJSON(w io.Writer, report CombinedReport, indent bool) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	if indent {
+		enc.SetIndent("", "  ")
+	}
+	if err := enc.Encode(report); err != nil {
+		return fmt.Errorf("failed to write combined report: %w", err)
+	}
+	return nil
+}
@@ -6,9 +6,9 @@ package reporter
 import (
 	"encoding/csv"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
-	"sort"
 	"strconv"
 
 	"github.com/leansecurity-co/gwork/internal/audit"
@@ -17,71 +17,140 @@ import (
 // CSVReporter generates CSV reports.
 type CSVReporter struct {
 	outputDir string
+	opts      Options
+	written   []string
+	checksums checksums
+	// segments counts the files written for each report base name split
+	// by opts.MaxRowsPerFile.
+	segments map[string]int
 }
 
 // NewCSVReporter creates a new CSV reporter.
 func NewCSVReporter(outputDir string) (*CSVReporter, error) {
+	return NewCSVReporterWithOptions(outputDir, Options{})
+}
+
+// NewCSVReporterWithOptions creates a new CSV reporter with optional columns.
+func NewCSVReporterWithOptions(outputDir string, opts Options) (*CSVReporter, error) {
 	if err := os.MkdirAll(outputDir, 0750); err != nil {
 		return nil, fmt.Errorf("failed to create output directory: %w", err)
 	}
-	return &CSVReporter{outputDir: outputDir}, nil
+	return &CSVReporter{outputDir: outputDir, opts: opts, segments: make(map[string]int)}, nil
 }
 
-// WriteFilesByOwner generates the files-by-owner CSV.
-func (r *CSVReporter) WriteFilesByOwner(records []audit.FileRecord) (err error) {
-	// Sort by owner email
-	sort.Slice(records, func(i, j int) bool {
-		if records[i].OwnerEmail != records[j].OwnerEmail {
-			return records[i].OwnerEmail < records[j].OwnerEmail
-		}
-		return records[i].FileName < records[j].FileName
+// WriteFilesByOwner generates the files-by-owner CSV, or one CSV per owner
+// and an index when opts.SplitByOwner is set.
+func (r *CSVReporter) WriteFilesByOwner(records []audit.FileRecord) error {
+	if r.opts.SplitByOwner {
+		return r.writeSplitByOwner(records)
+	}
+
+	// Sort by owner, then file
+	r.opts.Order.SortFileRecords(records)
+	return writeRecords(r, "files_by_owner", fileRecordHeader(r.opts), records, r.fileRecordRow)
+}
+
+// writeFileRecords writes file records to name, relative to the output
+// directory, in the order given.
+func (r *CSVReporter) writeFileRecords(name string, records []audit.FileRecord) error {
+	return writeRows(r, name, fileRecordHeader(r.opts), records, r.fileRecordRow)
+}
+
+// fileRecordRow formats a file record with the reporter's options.
+func (r *CSVReporter) fileRecordRow(rec audit.FileRecord) []string {
+	return fileRecordRow(rec, r.opts)
+}
+
+// WriteExternalSharing generates the external-sharing CSV.
+func (r *CSVReporter) WriteExternalSharing(records []audit.ExternalShareRecord) error {
+	// Sort by owner, then file
+	audit.SortExternalShares(records)
+	return writeRecords(r, "external_sharing", externalShareHeader(r.opts), records, func(rec audit.ExternalShareRecord) []string {
+		return externalShareRow(rec, r.opts)
+	})
+}
+
+// WriteManifest writes manifest.json listing the reports written by this reporter.
+func (r *CSVReporter) WriteManifest(meta RunMeta) error {
+	return writeManifest(r.outputDir, meta, r.written, r.checksums)
+}
+
+// manifestFiles returns the reports written so far and their checksums.
+func (r *CSVReporter) manifestFiles(RunMeta) ([]string, checksums, error) {
+	return r.written, r.checksums, nil
+}
+
+// WritePublicShares generates the public-shares CSV.
+func (r *CSVReporter) WritePublicShares(records []audit.ExternalShareRecord) error {
+	// Sort by owner, then file
+	audit.SortExternalShares(records)
+	return writeRecords(r, "public_shares", publicShareHeader(r.opts), records, func(rec audit.ExternalShareRecord) []string {
+		return publicShareRow(rec, r.opts)
+	})
+}
+
+// WriteDomainShares generates the domain-wide shares CSV.
+func (r *CSVReporter) WriteDomainShares(records []audit.ExternalShareRecord) error {
+	// Sort by owner, then file
+	audit.SortExternalShares(records)
+	return writeRecords(r, "domain_shares", domainShareHeader(r.opts), records, func(rec audit.ExternalShareRecord) []string {
+		return domainShareRow(rec, r.opts)
+	})
+}
+
+// WriteDuplicates generates the duplicates CSV, one row per group.
+func (r *CSVReporter) WriteDuplicates(groups []audit.DuplicateGroup) error {
+	return writeRecords(r, "duplicates", duplicateGroupHeader, groups, func(g audit.DuplicateGroup) []string {
+		return duplicateGroupRow(g, r.opts)
 	})
+}
 
-	path := filepath.Join(r.outputDir, "files_by_owner.csv")
-	file, err := os.Create(path)
+// WriteNewShares generates the new-shares CSV, with the same columns as
+// the external-sharing report.
+func (r *CSVReporter) WriteNewShares(records []audit.ExternalShareRecord) error {
+	audit.SortExternalShares(records)
+	return writeRecords(r, "new_shares", externalShareHeader(r.opts), records, func(rec audit.ExternalShareRecord) []string {
+		return externalShareRow(rec, r.opts)
+	})
+}
+
+// WriteExternalOwners generates the external-owners CSV, with the same
+// columns as the files-by-owner report.
+func (r *CSVReporter) WriteExternalOwners(records []audit.FileRecord) error {
+	r.opts.Order.SortFileRecords(records)
+	return writeRecords(r, "external_owners", fileRecordHeader(r.opts), records, r.fileRecordRow)
+}
+
+// WriteSharedWithMe generates the shared-with-me CSV, with the same columns
+// as the files-by-owner report.
+func (r *CSVReporter) WriteSharedWithMe(records []audit.FileRecord) error {
+	r.opts.Order.SortFileRecords(records)
+	return writeRecords(r, "shared_with_me", fileRecordHeader(r.opts), records, r.fileRecordRow)
+}
+
+// WriteOwners generates the owners CSV. Summaries are written in the order
+// given, which for audit.SummarizeByOwner is by file count descending.
+func (r *CSVReporter) WriteOwners(summaries []audit.OwnerSummary) (err error) {
+	path := filepath.Join(r.outputDir, r.FileName("owners"))
+	file, err := createAtomic(path)
 	if err != nil {
 		return fmt.Errorf("failed to create file: %w", err)
 	}
-	defer func() {
-		if cerr := file.Close(); cerr != nil && err == nil {
-			err = fmt.Errorf("failed to close file: %w", cerr)
-		}
-	}()
+	defer file.Abort()
 
-	writer := csv.NewWriter(file)
+	writer := r.newWriter(file)
 
-	// Write header
-	header := []string{
-		"owner_email", "file_id", "file_name", "file_type",
-		"created_time", "modified_time", "size_bytes",
-	}
-	if err := writer.Write(header); err != nil {
+	if err := writer.Write(r.opts.labelHeader(ownerSummaryHeader)); err != nil {
 		return fmt.Errorf("failed to write header: %w", err)
 	}
 
-	// Write records
-	for _, rec := range records {
-		createdTime := ""
-		if !rec.CreatedTime.IsZero() {
-			createdTime = rec.CreatedTime.Format("2006-01-02T15:04:05Z")
-		}
-		modifiedTime := ""
-		if !rec.ModifiedTime.IsZero() {
-			modifiedTime = rec.ModifiedTime.Format("2006-01-02T15:04:05Z")
-		}
-
-		row := []string{
-			rec.OwnerEmail,
-			rec.FileID,
-			rec.FileName,
-			rec.FileType,
-			createdTime,
-			modifiedTime,
-			strconv.FormatInt(rec.SizeBytes, 10),
-		}
-		if err := writer.Write(row); err != nil {
+	for i, s := range summaries {
+		if err := writer.Write(ownerSummaryRow(s, r.opts)); err != nil {
 			return fmt.Errorf("failed to write record: %w", err)
 		}
+		if err := flushPeriodically(writer, i); err != nil {
+			return err
+		}
 	}
 
 	writer.Flush()
@@ -89,58 +158,30 @@ func (r *CSVReporter) WriteFilesByOwner(records []audit.FileRecord) (err error)
 		return fmt.Errorf("failed to flush writer: %w", err)
 	}
 
+	if err := r.checksums.commit(file); err != nil {
+		return err
+	}
+
+	r.track(r.FileName("owners"))
 	return nil
 }
 
-// WriteExternalSharing generates the external-sharing CSV.
-func (r *CSVReporter) WriteExternalSharing(records []audit.ExternalShareRecord) (err error) {
-	// Sort by owner email
-	sort.Slice(records, func(i, j int) bool {
-		if records[i].OwnerEmail != records[j].OwnerEmail {
-			return records[i].OwnerEmail < records[j].OwnerEmail
-		}
-		return records[i].FileName < records[j].FileName
-	})
-
-	path := filepath.Join(r.outputDir, "external_sharing.csv")
-	file, err := os.Create(path)
+// WriteRoleDistribution generates the role distribution CSV in the order
+// given.
+func (r *CSVReporter) WriteRoleDistribution(counts []audit.RoleCount) error {
+	file, err := createAtomic(filepath.Join(r.outputDir, r.FileName("role_distribution")))
 	if err != nil {
 		return fmt.Errorf("failed to create file: %w", err)
 	}
-	defer func() {
-		if cerr := file.Close(); cerr != nil && err == nil {
-			err = fmt.Errorf("failed to close file: %w", cerr)
-		}
-	}()
+	defer file.Abort()
 
-	writer := csv.NewWriter(file)
+	writer := r.newWriter(file)
 
-	// Write header
-	header := []string{
-		"owner_email", "file_id", "file_name", "shared_with_email",
-		"shared_with_domain", "permission_type", "permission_role", "shared_date",
-	}
-	if err := writer.Write(header); err != nil {
+	if err := writer.Write(r.opts.labelHeader(roleCountHeader)); err != nil {
 		return fmt.Errorf("failed to write header: %w", err)
 	}
-
-	// Write records
-	for _, rec := range records {
-		sharedDate := ""
-		if !rec.SharedDate.IsZero() {
-			sharedDate = rec.SharedDate.Format("2006-01-02T15:04:05Z")
-		}
-		row := []string{
-			rec.OwnerEmail,
-			rec.FileID,
-			rec.FileName,
-			rec.SharedWithEmail,
-			rec.SharedWithDomain,
-			rec.PermissionType,
-			rec.PermissionRole,
-			sharedDate,
-		}
-		if err := writer.Write(row); err != nil {
+	for _, c := range counts {
+		if err := writer.Write(roleCountRow(c, r.opts)); err != nil {
 			return fmt.Errorf("failed to write record: %w", err)
 		}
 	}
@@ -150,10 +191,83 @@ func (r *CSVReporter) WriteExternalSharing(records []audit.ExternalShareRecord)
 		return fmt.Errorf("failed to flush writer: %w", err)
 	}
 
+	if err := r.checksums.commit(file); err != nil {
+		return err
+	}
+
+	r.track(r.FileName("role_distribution"))
 	return nil
 }
 
+// track records a report file, relative to the output directory, for the manifest.
+func (r *CSVReporter) track(name string) {
+	for _, w := range r.written {
+		if w == name {
+			return
+		}
+	}
+	r.written = append(r.written, name)
+}
+
+// Segments returns the number of segment files the report named base was
+// split into, or zero when it was written to a single file.
+func (r *CSVReporter) Segments(base string) int {
+	return r.segments[base]
+}
+
 // OutputDir returns the output directory path.
 func (r *CSVReporter) OutputDir() string {
 	return r.outputDir
 }
+
+// FileName returns the report file name for base: base.tsv when the
+// delimiter is a tab, otherwise base.csv.
+func (r *CSVReporter) FileName(base string) string {
+	if name, ok := r.opts.FileNames[base]; ok {
+		return name
+	}
+	if r.opts.Delimiter == '\t' {
+		return base + ".tsv"
+	}
+	return FileName(FormatCSV, base)
+}
+
+// newWriter creates a CSV writer using the configured delimiter.
+func (r *CSVReporter) newWriter(file io.Writer) *csv.Writer {
+	writer := csv.NewWriter(file)
+	if r.opts.Delimiter != 0 {
+		writer.Comma = r.opts.Delimiter
+	}
+	return writer
+}
+
+// flushPeriodically flushes the writer after every flushEvery records, so
+// buffered rows reach the file during long writes.
+func flushPeriodically(writer *csv.Writer, index int) error {
+	if (index+1)%flushEvery != 0 {
+		return nil
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to flush writer: %w", err)
+	}
+	return nil
+}
+
+// groupMemberCount formats the member count for group shares and leaves the
+// column empty for other permission types.
+func groupMemberCount(rec audit.ExternalShareRecord) string {
+	if rec.PermissionType != "group" {
+		return ""
+	}
+	return strconv.Itoa(rec.GroupMemberCount)
+}
+
+// groupHasExternalMembers formats HasExternalMembers for group shares and
+// leaves the column empty for other permission types.
+func groupHasExternalMembers(rec audit.ExternalShareRecord) string {
+	if rec.PermissionType != "group" {
+		return ""
+	}
+	return strconv.FormatBool(rec.HasExternalMembers)
+}
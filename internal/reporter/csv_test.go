@@ -5,8 +5,10 @@ package reporter
 
 import (
 	"encoding/csv"
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 	"time"
 
@@ -162,7 +164,8 @@ func TestCSVReporter_WriteFilesByOwner(t *testing.T) {
 			require.GreaterOrEqual(t, len(rows), 1, "CSV should have at least a header")
 			expectedHeader := []string{
 				"owner_email", "file_id", "file_name", "file_type",
-				"created_time", "modified_time", "size_bytes",
+				"created_time", "modified_time", "size_bytes", "owner_name", "location",
+				"drive_name", "viewed_by_me_time",
 			}
 			assert.Equal(t, expectedHeader, rows[0])
 
@@ -280,6 +283,9 @@ func TestCSVReporter_WriteExternalSharing(t *testing.T) {
 			expectedHeader := []string{
 				"owner_email", "file_id", "file_name", "shared_with_email",
 				"shared_with_domain", "permission_type", "permission_role", "shared_date",
+				"owner_name", "inherited", "inherited_from", "expiration_time", "location",
+				"drive_name", "flagged", "flag_reason", "label", "risk", "permission_id",
+				"grantee_deleted",
 			}
 			assert.Equal(t, expectedHeader, rows[0])
 
@@ -382,3 +388,548 @@ func TestCSVReporter_TimestampFormatting(t *testing.T) {
 	assert.Equal(t, "2024-05-15T14:30:45Z", rows[1][4]) // created_time
 	assert.Equal(t, "2024-05-20T16:45:30Z", rows[1][5]) // modified_time
 }
+
+func TestCSVReporter_OwnerNameFallback(t *testing.T) {
+	tmpDir := t.TempDir()
+	reporter, err := NewCSVReporter(tmpDir)
+	require.NoError(t, err)
+
+	records := []audit.FileRecord{
+		{OwnerEmail: "bob@example.com", OwnerName: "Bob", FileID: "1", FileName: "b.txt"},
+		{OwnerEmail: "", OwnerName: "", FileID: "2", FileName: "orphan.txt"},
+		{OwnerEmail: "", OwnerName: "Deleted User", FileID: "3", FileName: "d.txt"},
+	}
+
+	err = reporter.WriteFilesByOwner(records)
+	require.NoError(t, err)
+
+	file, err := os.Open(filepath.Join(tmpDir, "files_by_owner.csv"))
+	require.NoError(t, err)
+	defer file.Close() //nolint:errcheck // test cleanup
+
+	rows, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+
+	// Fully-empty owners sort first, then email owners, then display-name owners
+	require.Equal(t, 4, len(rows))
+	assert.Equal(t, "2", rows[1][1])
+	assert.Equal(t, "1", rows[2][1])
+	assert.Equal(t, "Bob", rows[2][7])
+	assert.Equal(t, "3", rows[3][1])
+	assert.Equal(t, "", rows[3][0])
+	assert.Equal(t, "Deleted User", rows[3][7])
+}
+
+func TestCSVReporter_IncludeTrashed(t *testing.T) {
+	tests := []struct {
+		name           string
+		includeTrashed bool
+		wantColumns    int
+	}{
+		{name: "trashed column omitted by default", includeTrashed: false, wantColumns: 11},
+		{name: "trashed column included", includeTrashed: true, wantColumns: 12},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			reporter, err := NewCSVReporterWithOptions(tmpDir, Options{IncludeTrashed: tt.includeTrashed})
+			require.NoError(t, err)
+
+			records := []audit.FileRecord{
+				{OwnerEmail: "a@example.com", FileID: "1", FileName: "a.txt", Trashed: true},
+				{OwnerEmail: "b@example.com", FileID: "2", FileName: "b.txt"},
+			}
+			require.NoError(t, reporter.WriteFilesByOwner(records))
+
+			file, err := os.Open(filepath.Join(tmpDir, "files_by_owner.csv"))
+			require.NoError(t, err)
+			defer file.Close() //nolint:errcheck // test cleanup
+
+			rows, err := csv.NewReader(file).ReadAll()
+			require.NoError(t, err)
+			require.Len(t, rows, 3)
+			assert.Len(t, rows[0], tt.wantColumns)
+
+			if tt.includeTrashed {
+				assert.Equal(t, "trashed", rows[0][11])
+				assert.Equal(t, "true", rows[1][11])
+				assert.Equal(t, "false", rows[2][11])
+			}
+		})
+	}
+}
+
+func TestCSVReporter_IncludeLinkStatus(t *testing.T) {
+	tmpDir := t.TempDir()
+	reporter, err := NewCSVReporterWithOptions(tmpDir, Options{IncludeLinkStatus: true})
+	require.NoError(t, err)
+
+	enabled, disabled := true, false
+	records := []audit.FileRecord{
+		{OwnerEmail: "a@example.com", FileID: "1", FileName: "a.txt", LinkSharingEnabled: &enabled},
+		{OwnerEmail: "b@example.com", FileID: "2", FileName: "b.txt", LinkSharingEnabled: &disabled},
+		{OwnerEmail: "c@example.com", FileID: "3", FileName: "c.txt"},
+	}
+	require.NoError(t, reporter.WriteFilesByOwner(records))
+
+	file, err := os.Open(filepath.Join(tmpDir, "files_by_owner.csv"))
+	require.NoError(t, err)
+	defer file.Close() //nolint:errcheck // test cleanup
+
+	rows, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 4)
+	require.Len(t, rows[0], 12)
+	assert.Equal(t, "link_sharing_enabled", rows[0][11])
+	assert.Equal(t, "true", rows[1][11])
+	assert.Equal(t, "false", rows[2][11])
+	assert.Equal(t, "", rows[3][11], "empty when the status is unknown")
+}
+
+func TestAgeDays(t *testing.T) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		t    time.Time
+		want string
+	}{
+		{name: "same moment", t: now, want: "0"},
+		{name: "just under a day", t: now.Add(-23 * time.Hour), want: "0"},
+		{name: "exactly one day", t: now.Add(-24 * time.Hour), want: "1"},
+		{name: "412 days", t: time.Date(2024, 4, 29, 12, 0, 0, 0, time.UTC), want: "412"},
+		{name: "across a leap day", t: time.Date(2024, 2, 28, 18, 0, 0, 0, time.UTC), want: "472"},
+		{name: "other time zone", t: time.Date(2025, 6, 14, 14, 0, 0, 0, time.FixedZone("CEST", 2*60*60)), want: "1"},
+		{name: "zero time", t: time.Time{}, want: ""},
+		{name: "in the future", t: now.Add(time.Hour), want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ageDays(tt.t, now))
+		})
+	}
+}
+
+func TestCSVReporter_AgeColumns(t *testing.T) {
+	tmpDir := t.TempDir()
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	reporter, err := NewCSVReporterWithOptions(tmpDir, Options{AgeAt: now})
+	require.NoError(t, err)
+
+	records := []audit.FileRecord{
+		{OwnerEmail: "a@example.com", FileID: "1", FileName: "a.txt", CreatedTime: now.AddDate(0, 0, -412), ModifiedTime: now.AddDate(0, 0, -3)},
+		{OwnerEmail: "b@example.com", FileID: "2", FileName: "b.txt"},
+	}
+	require.NoError(t, reporter.WriteFilesByOwner(records))
+
+	file, err := os.Open(filepath.Join(tmpDir, "files_by_owner.csv"))
+	require.NoError(t, err)
+	defer file.Close() //nolint:errcheck // test cleanup
+
+	rows, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, []string{"created_age_days", "modified_age_days"}, rows[0][11:])
+	assert.Equal(t, []string{"412", "3"}, rows[1][11:])
+	assert.Equal(t, []string{"", ""}, rows[2][11:], "blank when the times are unknown")
+}
+
+func TestCSVReporter_PermissionID(t *testing.T) {
+	tmpDir := t.TempDir()
+	reporter, err := NewCSVReporter(tmpDir)
+	require.NoError(t, err)
+
+	records := []audit.ExternalShareRecord{
+		{OwnerEmail: "a@example.com", FileID: "file1", FileName: "a.txt", PermissionType: "user",
+			PermissionRole: "writer", SharedWithEmail: "guest@partner.com", PermissionID: "06693587"},
+		{OwnerEmail: "b@example.com", FileID: "file2", FileName: "b.txt", PermissionType: "anyone",
+			PermissionRole: "reader", PermissionID: "anyoneWithLink"},
+	}
+	require.NoError(t, reporter.WriteExternalSharing(records))
+	require.NoError(t, reporter.WritePublicShares(records[1:]))
+
+	for name, want := range map[string][]string{
+		"external_sharing.csv": {"06693587", "anyoneWithLink"},
+		"public_shares.csv":    {"anyoneWithLink"},
+	} {
+		file, err := os.Open(filepath.Join(tmpDir, name))
+		require.NoError(t, err)
+		defer file.Close() //nolint:errcheck // test cleanup
+
+		rows, err := csv.NewReader(file).ReadAll()
+		require.NoError(t, err)
+		column := slices.Index(rows[0], "permission_id")
+		require.NotEqual(t, -1, column, name)
+
+		var got []string
+		for _, row := range rows[1:] {
+			got = append(got, row[column])
+		}
+		assert.Equal(t, want, got, name)
+	}
+}
+
+func TestCSVReporter_ExpandGroups(t *testing.T) {
+	tests := []struct {
+		name         string
+		expandGroups bool
+		wantColumns  int
+	}{
+		{name: "group columns omitted by default", expandGroups: false, wantColumns: 20},
+		{name: "group columns included", expandGroups: true, wantColumns: 22},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			reporter, err := NewCSVReporterWithOptions(tmpDir, Options{ExpandGroups: tt.expandGroups})
+			require.NoError(t, err)
+
+			records := []audit.ExternalShareRecord{
+				{OwnerEmail: "a@example.com", FileID: "1", FileName: "a.txt", PermissionType: "group",
+					SharedWithEmail: "team@partner.com", GroupMemberCount: 5, HasExternalMembers: true},
+				{OwnerEmail: "b@example.com", FileID: "2", FileName: "b.txt", PermissionType: "user",
+					SharedWithEmail: "guest@partner.com"},
+			}
+			require.NoError(t, reporter.WriteExternalSharing(records))
+
+			file, err := os.Open(filepath.Join(tmpDir, "external_sharing.csv"))
+			require.NoError(t, err)
+			defer file.Close() //nolint:errcheck // test cleanup
+
+			rows, err := csv.NewReader(file).ReadAll()
+			require.NoError(t, err)
+			require.Len(t, rows, 3)
+			assert.Len(t, rows[0], tt.wantColumns)
+
+			if tt.expandGroups {
+				assert.Equal(t, []string{"group_member_count", "has_external_members"}, rows[0][20:])
+				assert.Equal(t, []string{"5", "true"}, rows[1][20:])
+				assert.Equal(t, []string{"", ""}, rows[2][20:])
+			}
+		})
+	}
+}
+
+func TestCSVReporter_ExplanationColumn(t *testing.T) {
+	records := []audit.ExternalShareRecord{
+		{OwnerEmail: "a@example.com", FileID: "1", FileName: "a.txt", PermissionType: "anyone",
+			Explanation: "shared to anyone with the link"},
+	}
+
+	for _, explain := range []bool{false, true} {
+		tmpDir := t.TempDir()
+		reporter, err := NewCSVReporterWithOptions(tmpDir, Options{Explain: explain})
+		require.NoError(t, err)
+		require.NoError(t, reporter.WriteExternalSharing(records))
+
+		file, err := os.Open(filepath.Join(tmpDir, "external_sharing.csv"))
+		require.NoError(t, err)
+		defer file.Close() //nolint:errcheck // test cleanup
+
+		rows, err := csv.NewReader(file).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		if !explain {
+			assert.NotContains(t, rows[0], "explanation")
+			continue
+		}
+		assert.Equal(t, "explanation", rows[0][len(rows[0])-1])
+		assert.Equal(t, "shared to anyone with the link", rows[1][len(rows[1])-1])
+	}
+}
+
+func TestCSVReporter_SourceDomainColumn(t *testing.T) {
+	tmpDir := t.TempDir()
+	reporter, err := NewCSVReporterWithOptions(tmpDir, Options{SourceDomain: true})
+	require.NoError(t, err)
+	require.NoError(t, reporter.WriteFilesByOwner([]audit.FileRecord{
+		{OwnerEmail: "a@a.example", FileID: "1", FileName: "a.txt", SourceDomain: "a.example"},
+	}))
+	require.NoError(t, reporter.WritePublicShares([]audit.ExternalShareRecord{
+		{OwnerEmail: "b@b.example", FileID: "2", FileName: "b.txt", PermissionType: "anyone", SourceDomain: "b.example"},
+	}))
+
+	for name, want := range map[string]string{"files_by_owner.csv": "a.example", "public_shares.csv": "b.example"} {
+		file, err := os.Open(filepath.Join(tmpDir, name))
+		require.NoError(t, err)
+		defer file.Close() //nolint:errcheck // test cleanup
+
+		rows, err := csv.NewReader(file).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		assert.Equal(t, "source_domain", rows[0][len(rows[0])-1], name)
+		assert.Equal(t, want, rows[1][len(rows[1])-1], name)
+	}
+}
+
+func TestCSVReporter_ExpirationTime(t *testing.T) {
+	tmpDir := t.TempDir()
+	reporter, err := NewCSVReporter(tmpDir)
+	require.NoError(t, err)
+
+	records := []audit.ExternalShareRecord{
+		{OwnerEmail: "a@example.com", FileID: "1", FileName: "a.txt",
+			ExpirationTime: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)},
+		{OwnerEmail: "b@example.com", FileID: "2", FileName: "b.txt"},
+	}
+	require.NoError(t, reporter.WriteExternalSharing(records))
+
+	file, err := os.Open(filepath.Join(tmpDir, "external_sharing.csv"))
+	require.NoError(t, err)
+	defer file.Close() //nolint:errcheck // test cleanup
+
+	rows, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, "expiration_time", rows[0][11])
+	assert.Equal(t, "2025-03-01T12:00:00Z", rows[1][11])
+	assert.Equal(t, "", rows[2][11], "shares without expiration leave the column blank")
+}
+
+func TestCSVReporter_WritePublicShares(t *testing.T) {
+	tmpDir := t.TempDir()
+	reporter, err := NewCSVReporter(tmpDir)
+	require.NoError(t, err)
+
+	records := []audit.ExternalShareRecord{
+		{OwnerEmail: "b@example.com", FileID: "2", FileName: "b.pdf", PermissionType: "anyone", PermissionRole: "reader"},
+		{OwnerEmail: "a@example.com", FileID: "1", FileName: "a.pdf", PermissionType: "anyone", PermissionRole: "writer",
+			WebViewLink: "https://drive.google.com/file/d/1/view"},
+	}
+	require.NoError(t, reporter.WritePublicShares(records))
+
+	file, err := os.Open(filepath.Join(tmpDir, "public_shares.csv"))
+	require.NoError(t, err)
+	defer file.Close() //nolint:errcheck // test cleanup
+
+	rows, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, []string{
+		"owner_email", "file_id", "file_name", "permission_type", "permission_role",
+		"web_view_link", "owner_name", "inherited", "inherited_from", "expiration_time",
+		"location", "drive_name", "label", "risk", "permission_id",
+	}, rows[0])
+	assert.Equal(t, "a@example.com", rows[1][0])
+	assert.Equal(t, "https://drive.google.com/file/d/1/view", rows[1][5])
+	assert.Equal(t, "b@example.com", rows[2][0])
+	assert.Equal(t, "", rows[2][5])
+}
+
+func TestCSVReporter_WriteDomainShares(t *testing.T) {
+	tmpDir := t.TempDir()
+	reporter, err := NewCSVReporter(tmpDir)
+	require.NoError(t, err)
+
+	records := []audit.ExternalShareRecord{
+		{OwnerEmail: "hr@example.com", FileID: "1", FileName: "handbook.pdf", PermissionType: "domain",
+			PermissionRole: "reader", PermissionID: "p1", SharedWithDomain: "example.com", DomainWide: true, Label: "internal", Risk: 1},
+	}
+	require.NoError(t, reporter.WriteDomainShares(records))
+
+	file, err := os.Open(filepath.Join(tmpDir, "domain_shares.csv"))
+	require.NoError(t, err)
+	defer file.Close() //nolint:errcheck // test cleanup
+
+	rows, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, []string{
+		"owner_email", "file_id", "file_name", "shared_with_domain", "permission_role",
+		"web_view_link", "owner_name", "inherited", "inherited_from", "expiration_time",
+		"location", "drive_name", "label", "risk", "permission_id",
+	}, rows[0])
+	assert.Equal(t, []string{
+		"hr@example.com", "1", "handbook.pdf", "example.com", "reader",
+		"", "", "false", "", "", "", "", "internal", "1", "p1",
+	}, rows[1])
+}
+
+func TestCSVReporter_WriteExternalOwners(t *testing.T) {
+	tmpDir := t.TempDir()
+	reporter, err := NewCSVReporter(tmpDir)
+	require.NoError(t, err)
+
+	records := []audit.FileRecord{
+		{OwnerEmail: "z@partner.com", FileID: "2", FileName: "b.txt"},
+		{OwnerEmail: "a@vendor.com", FileID: "1", FileName: "a.txt"},
+	}
+	require.NoError(t, reporter.WriteExternalOwners(records))
+	require.NoError(t, reporter.WriteManifest(RunMeta{}))
+
+	file, err := os.Open(filepath.Join(tmpDir, "external_owners.csv"))
+	require.NoError(t, err)
+	defer file.Close() //nolint:errcheck // test cleanup
+
+	rows, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, fileRecordHeader(Options{}), rows[0])
+	assert.Equal(t, "a@vendor.com", rows[1][0], "sorted by owner")
+	assert.Equal(t, "z@partner.com", rows[2][0])
+
+	manifest, err := os.ReadFile(filepath.Join(tmpDir, ManifestFileName))
+	require.NoError(t, err)
+	assert.Contains(t, string(manifest), "external_owners.csv")
+}
+
+func TestCSVReporter_WriteSharedWithMe(t *testing.T) {
+	tmpDir := t.TempDir()
+	reporter, err := NewCSVReporter(tmpDir)
+	require.NoError(t, err)
+
+	require.NoError(t, reporter.WriteSharedWithMe([]audit.FileRecord{
+		{OwnerEmail: "z@partner.com", FileID: "2", FileName: "b.txt"},
+		{OwnerEmail: "a@vendor.com", FileID: "1", FileName: "a.txt"},
+	}))
+
+	file, err := os.Open(filepath.Join(tmpDir, "shared_with_me.csv"))
+	require.NoError(t, err)
+	defer file.Close() //nolint:errcheck // test cleanup
+
+	rows, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, fileRecordHeader(Options{}), rows[0])
+	assert.Equal(t, "a@vendor.com", rows[1][0], "sorted by owner")
+}
+
+func TestCSVReporter_WriteOwners(t *testing.T) {
+	tmpDir := t.TempDir()
+	reporter, err := NewCSVReporter(tmpDir)
+	require.NoError(t, err)
+
+	summaries := []audit.OwnerSummary{
+		{OwnerEmail: "bob@example.com", OwnerName: "Bob", FileCount: 3, TotalBytes: 350},
+		{OwnerEmail: "alice@example.com", FileCount: 1, TotalBytes: 10},
+	}
+	require.NoError(t, reporter.WriteOwners(summaries))
+
+	file, err := os.Open(filepath.Join(tmpDir, "owners.csv"))
+	require.NoError(t, err)
+	defer file.Close() //nolint:errcheck // test cleanup
+
+	rows, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"owner_email", "owner_name", "file_count", "total_bytes"},
+		{"bob@example.com", "Bob", "3", "350"},
+		{"alice@example.com", "", "1", "10"},
+	}, rows)
+}
+
+func TestCheckHeaderLabels(t *testing.T) {
+	require.NoError(t, CheckHeaderLabels(nil))
+	require.NoError(t, CheckHeaderLabels(map[string]string{"owner_email": "Propriétaire", "explanation": "Raison", "file": "Fichier"}))
+
+	err := CheckHeaderLabels(map[string]string{"owner_email": "Propriétaire", "owner_emial": "Propriétaire"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown column "owner_emial"`)
+}
+
+func TestCSVReporter_HeaderLabels(t *testing.T) {
+	dir := t.TempDir()
+	labels := map[string]string{"owner_email": "Propriétaire", "file_count": "Nombre de fichiers"}
+	rep, err := NewCSVReporterWithOptions(dir, Options{HeaderLabels: labels})
+	require.NoError(t, err)
+
+	require.NoError(t, rep.WriteOwners([]audit.OwnerSummary{{OwnerEmail: "bob@example.com", FileCount: 3}}))
+	assert.Equal(t, [][]string{
+		{"Propriétaire", "owner_name", "Nombre de fichiers", "total_bytes"},
+		{"bob@example.com", "", "3", "0"},
+	}, readCSVFile(t, filepath.Join(dir, "owners.csv")))
+
+	require.NoError(t, rep.WriteExternalSharing([]audit.ExternalShareRecord{{OwnerEmail: "bob@example.com", FileID: "1"}}))
+	rows := readCSVFile(t, filepath.Join(dir, "external_sharing.csv"))
+	assert.Equal(t, "Propriétaire", rows[0][0])
+	assert.Equal(t, externalShareHeader(Options{})[1:], rows[0][1:], "columns without a label keep their name")
+
+	jsonRep, err := NewJSONReporter(dir, Options{HeaderLabels: labels})
+	require.NoError(t, err)
+	require.NoError(t, jsonRep.WriteExternalSharing([]audit.ExternalShareRecord{{OwnerEmail: "bob@example.com", FileID: "1"}}))
+	data, err := os.ReadFile(filepath.Join(dir, "external_sharing.json"))
+	require.NoError(t, err)
+	var got []map[string]any
+	require.NoError(t, json.Unmarshal(data, &got))
+	require.Len(t, got, 1)
+	assert.Equal(t, "bob@example.com", got[0]["owner_email"], "JSON keys stay stable")
+	assert.NotContains(t, got[0], "Propriétaire")
+}
+
+func TestCSVReporter_Order(t *testing.T) {
+	records := []audit.FileRecord{
+		{OwnerEmail: "alice@example.com", FileID: "a1", FileName: "a.txt", SizeBytes: 10},
+		{OwnerEmail: "bob@example.com", FileID: "b1", FileName: "b.txt", SizeBytes: 100},
+		{OwnerEmail: "alice@example.com", FileID: "a2", FileName: "z.txt", SizeBytes: 50},
+	}
+	order, err := audit.NewRecordOrder("bytes", "size")
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	rep, err := NewCSVReporterWithOptions(dir, Options{Order: order})
+	require.NoError(t, err)
+	require.NoError(t, rep.WriteFilesByOwner(records))
+
+	rows := readCSVFile(t, filepath.Join(dir, "files_by_owner.csv"))
+	var names []string
+	for _, row := range rows[1:] {
+		names = append(names, row[2])
+	}
+	assert.Equal(t, []string{"b.txt", "z.txt", "a.txt"}, names)
+
+	splitDir := t.TempDir()
+	rep, err = NewCSVReporterWithOptions(splitDir, Options{Order: order, SplitByOwner: true})
+	require.NoError(t, err)
+	require.NoError(t, rep.WriteFilesByOwner(records))
+
+	index := readCSVFile(t, filepath.Join(splitDir, "files_index.csv"))
+	require.Len(t, index, 3)
+	assert.Equal(t, "bob@example.com", index[1][0], "split outputs list owners in the same order")
+	assert.Equal(t, "alice@example.com", index[2][0])
+}
+
+func TestCSVReporter_WriteDuplicates(t *testing.T) {
+	tmpDir := t.TempDir()
+	reporter, err := NewCSVReporter(tmpDir)
+	require.NoError(t, err)
+
+	groups := []audit.DuplicateGroup{
+		{OwnerEmail: "alice@example.com", FileName: "notes.txt", SizeBytes: 10, FileIDs: []string{"a1", "a2"}},
+		{OwnerEmail: "bob@example.com", OwnerName: "Bob", FileName: "report.pdf", SizeBytes: 2048, FileIDs: []string{"b1", "b2", "b3"}},
+	}
+	require.NoError(t, reporter.WriteDuplicates(groups))
+
+	file, err := os.Open(filepath.Join(tmpDir, "duplicates.csv"))
+	require.NoError(t, err)
+	defer file.Close() //nolint:errcheck // test cleanup
+
+	rows, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"owner_email", "owner_name", "file_name", "size_bytes", "file_count", "file_ids"},
+		{"alice@example.com", "", "notes.txt", "10", "2", "a1;a2"},
+		{"bob@example.com", "Bob", "report.pdf", "2048", "3", "b1;b2;b3"},
+	}, rows)
+}
+
+func TestCSVReporter_WriteRoleDistribution(t *testing.T) {
+	tmpDir := t.TempDir()
+	reporter, err := NewCSVReporter(tmpDir)
+	require.NoError(t, err)
+
+	counts := []audit.RoleCount{
+		{Scope: audit.ScopePublic, Role: "reader", Count: 2},
+		{Scope: audit.ScopeExternal, Role: "writer", Count: 1},
+	}
+	require.NoError(t, reporter.WriteRoleDistribution(counts))
+
+	assert.Equal(t, [][]string{
+		{"scope", "role", "count"},
+		{"public", "reader", "2"},
+		{"external", "writer", "1"},
+	}, readCSVFile(t, filepath.Join(tmpDir, "role_distribution.csv")))
+	assert.Contains(t, reporter.written, "role_distribution.csv")
+}
@@ -0,0 +1,82 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package reporter
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSVReporter_Delimiter(t *testing.T) {
+	tests := []struct {
+		name      string
+		delimiter rune
+		wantFile  string
+	}{
+		{name: "default comma", wantFile: "external_sharing.csv"},
+		{name: "semicolon", delimiter: ';', wantFile: "external_sharing.csv"},
+		{name: "tab", delimiter: '\t', wantFile: "external_sharing.tsv"},
+	}
+
+	records := []audit.ExternalShareRecord{
+		{OwnerEmail: "a@example.com", FileID: "1", FileName: "budget; final, v2\t.xlsx",
+			SharedWithEmail: "guest@partner.com", SharedWithDomain: "partner.com",
+			PermissionType: "user", PermissionRole: "reader"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			rep, err := NewCSVReporterWithOptions(dir, Options{Delimiter: tt.delimiter})
+			require.NoError(t, err)
+			require.NoError(t, rep.WriteExternalSharing(records))
+
+			assert.Equal(t, tt.wantFile, rep.FileName("external_sharing"))
+			assert.Equal(t, []string{tt.wantFile}, rep.written)
+
+			file, err := os.Open(filepath.Join(dir, tt.wantFile))
+			require.NoError(t, err)
+			defer file.Close() //nolint:errcheck // test cleanup
+
+			reader := csv.NewReader(file)
+			if tt.delimiter != 0 {
+				reader.Comma = tt.delimiter
+			}
+			rows, err := reader.ReadAll()
+			require.NoError(t, err)
+			require.Len(t, rows, 2)
+			assert.Equal(t, "owner_email", rows[0][0])
+			assert.Equal(t, "budget; final, v2\t.xlsx", rows[1][2], "fields containing delimiters round-trip")
+			assert.Equal(t, "partner.com", rows[1][4])
+		})
+	}
+}
+
+func TestCSVReporter_SplitByOwnerTSV(t *testing.T) {
+	dir := t.TempDir()
+	rep, err := NewCSVReporterWithOptions(dir, Options{SplitByOwner: true, Delimiter: '\t'})
+	require.NoError(t, err)
+
+	records := []audit.FileRecord{{OwnerEmail: "alice@example.com", FileID: "1", FileName: "a.txt"}}
+	require.NoError(t, rep.WriteFilesByOwner(records))
+
+	assert.FileExists(t, filepath.Join(dir, "files", "alice@example.com.tsv"))
+
+	file, err := os.Open(filepath.Join(dir, "files_index.tsv"))
+	require.NoError(t, err)
+	defer file.Close() //nolint:errcheck // test cleanup
+
+	reader := csv.NewReader(file)
+	reader.Comma = '\t'
+	rows, err := reader.ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "files/alice@example.com.tsv", rows[1][4])
+}
@@ -0,0 +1,336 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package reporter
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+)
+
+// JSONReporter generates JSON reports. Each report is written either as a
+// single JSON array or, in NDJSON mode, as one JSON object per line.
+type JSONReporter struct {
+	outputDir string
+	opts      Options
+	ndjson    bool
+	written   []string
+	checksums checksums
+}
+
+// NewJSONReporter creates a new reporter that writes JSON arrays, indented
+// when opts.JSONIndent is set.
+func NewJSONReporter(outputDir string, opts Options) (*JSONReporter, error) {
+	if err := os.MkdirAll(outputDir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	return &JSONReporter{outputDir: outputDir, opts: opts}, nil
+}
+
+// NewNDJSONReporter creates a new reporter that writes newline-delimited
+// JSON, one compact object per line. opts.JSONIndent is ignored.
+func NewNDJSONReporter(outputDir string, opts Options) (*JSONReporter, error) {
+	r, err := NewJSONReporter(outputDir, opts)
+	if err != nil {
+		return nil, err
+	}
+	r.ndjson = true
+	return r, nil
+}
+
+// WriteFilesByOwner generates the files-by-owner report.
+func (r *JSONReporter) WriteFilesByOwner(records []audit.FileRecord) error {
+	r.opts.Order.SortFileRecords(records)
+	return writeJSON(r, "files_by_owner", records)
+}
+
+// WriteExternalSharing generates the external-sharing report.
+func (r *JSONReporter) WriteExternalSharing(records []audit.ExternalShareRecord) error {
+	audit.SortExternalShares(records)
+	return writeJSON(r, "external_sharing", records)
+}
+
+// WritePublicShares generates the public-shares report.
+func (r *JSONReporter) WritePublicShares(records []audit.ExternalShareRecord) error {
+	audit.SortExternalShares(records)
+	return writeJSON(r, "public_shares", records)
+}
+
+// WriteDomainShares generates the domain-wide shares report.
+func (r *JSONReporter) WriteDomainShares(records []audit.ExternalShareRecord) error {
+	audit.SortExternalShares(records)
+	return writeJSON(r, "domain_shares", records)
+}
+
+// WriteDuplicates generates the duplicates report.
+func (r *JSONReporter) WriteDuplicates(groups []audit.DuplicateGroup) error {
+	return writeJSON(r, "duplicates", groups)
+}
+
+// WriteNewShares generates the new-shares report.
+func (r *JSONReporter) WriteNewShares(records []audit.ExternalShareRecord) error {
+	audit.SortExternalShares(records)
+	return writeJSON(r, "new_shares", records)
+}
+
+// WriteExternalOwners generates the external-owners report.
+func (r *JSONReporter) WriteExternalOwners(records []audit.FileRecord) error {
+	r.opts.Order.SortFileRecords(records)
+	return writeJSON(r, "external_owners", records)
+}
+
+// WriteSharedWithMe generates the shared-with-me report.
+func (r *JSONReporter) WriteSharedWithMe(records []audit.FileRecord) error {
+	r.opts.Order.SortFileRecords(records)
+	return writeJSON(r, "shared_with_me", records)
+}
+
+// WriteOwners generates the owners report in the order given.
+func (r *JSONReporter) WriteOwners(summaries []audit.OwnerSummary) error {
+	return writeJSON(r, "owners", summaries)
+}
+
+// WriteRoleDistribution generates the role distribution report in the
+// order given.
+func (r *JSONReporter) WriteRoleDistribution(counts []audit.RoleCount) error {
+	return writeJSON(r, "role_distribution", counts)
+}
+
+// WriteManifest writes manifest.json listing the reports written by this reporter.
+func (r *JSONReporter) WriteManifest(meta RunMeta) error {
+	return writeManifest(r.outputDir, meta, r.written, r.checksums)
+}
+
+// manifestFiles returns the reports written so far and their checksums.
+func (r *JSONReporter) manifestFiles(RunMeta) ([]string, checksums, error) {
+	return r.written, r.checksums, nil
+}
+
+// OutputDir returns the output directory path.
+func (r *JSONReporter) OutputDir() string {
+	return r.outputDir
+}
+
+// FileName returns the report file name for base, e.g. base.json.
+func (r *JSONReporter) FileName(base string) string {
+	if name, ok := r.opts.FileNames[base]; ok {
+		return name
+	}
+	return FileName(r.format(), base)
+}
+
+// format returns the output format written by the reporter.
+func (r *JSONReporter) format() string {
+	if r.ndjson {
+		return FormatNDJSON
+	}
+	return FormatJSON
+}
+
+// writeJSON writes items to the report named base, encoding one item at a
+// time. With opts.JSONFields, each item is limited to those fields.
+func writeJSON[T any](r *JSONReporter, base string, items []T) (err error) {
+	name := r.FileName(base)
+	file, err := createAtomic(filepath.Join(r.outputDir, name))
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Abort()
+
+	var fields *fieldSelection
+	if len(r.opts.JSONFields) > 0 {
+		if fields, err = newFieldSelection(reflect.TypeFor[T](), r.opts.JSONFields); err != nil {
+			return err
+		}
+	}
+	indent := r.opts.JSONIndent && !r.ndjson
+
+	// Items are encoded compactly into buf, then indented if needed.
+	var buf, indented bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	w := bufio.NewWriter(file)
+
+	// An empty array report is [] rather than null.
+	if !r.ndjson {
+		w.WriteByte('[')
+	}
+	for i, item := range items {
+		buf.Reset()
+		if fields != nil {
+			err = fields.encode(&buf, reflect.ValueOf(item))
+		} else {
+			err = enc.Encode(item)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write record: %w", err)
+		}
+		data := bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+
+		switch {
+		case r.ndjson:
+			w.Write(data)
+			w.WriteByte('\n')
+		case indent:
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			w.WriteString("\n  ")
+			indented.Reset()
+			if err := json.Indent(&indented, data, "  ", "  "); err != nil {
+				return fmt.Errorf("failed to write record: %w", err)
+			}
+			w.Write(indented.Bytes())
+		default:
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			w.Write(data)
+		}
+	}
+	if !r.ndjson {
+		if indent && len(items) > 0 {
+			w.WriteByte('\n')
+		}
+		w.WriteString("]\n")
+	}
+
+	// bufio.Writer keeps the first write error and returns it here.
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write records: %w", err)
+	}
+	if err := r.checksums.commit(file); err != nil {
+		return err
+	}
+
+	r.track(name)
+	return nil
+}
+
+// track records a report file, relative to the output directory, for the manifest.
+func (r *JSONReporter) track(name string) {
+	for _, w := range r.written {
+		if w == name {
+			return
+		}
+	}
+	r.written = append(r.written, name)
+}
+
+// fieldSelection encodes struct values as JSON objects holding only the
+// selected top-level fields, in the order selected, without encoding the
+// other fields.
+type fieldSelection struct {
+	fields []selectedField
+}
+
+// selectedField is a struct field written by a fieldSelection.
+type selectedField struct {
+	key       []byte // the quoted key followed by a colon
+	index     int
+	omitEmpty bool
+	omitZero  bool
+}
+
+// newFieldSelection returns the selection of fields from the JSON keys of
+// struct type t. Fields t does not have are left out.
+func newFieldSelection(t reflect.Type, fields []string) (*fieldSelection, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot select JSON fields of %s records", t)
+	}
+
+	byKey := make(map[string]selectedField, t.NumField())
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if !f.IsExported() || tag == "-" {
+			continue
+		}
+		key, opts, _ := strings.Cut(tag, ",")
+		if key == "" {
+			key = f.Name
+		}
+		byKey[key] = selectedField{
+			index:     i,
+			omitEmpty: slices.Contains(strings.Split(opts, ","), "omitempty"),
+			omitZero:  slices.Contains(strings.Split(opts, ","), "omitzero"),
+		}
+	}
+
+	sel := &fieldSelection{}
+	for _, key := range fields {
+		f, ok := byKey[key]
+		if !ok {
+			continue
+		}
+		quoted, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		f.key = append(quoted, ':')
+		sel.fields = append(sel.fields, f)
+	}
+	return sel, nil
+}
+
+// encode writes the selected fields of the struct v to buf as one compact
+// JSON object, applying the omitempty and omitzero options of each field
+// as encoding/json does.
+func (s *fieldSelection) encode(buf *bytes.Buffer, v reflect.Value) error {
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+
+	buf.WriteByte('{')
+	first := true
+	for _, f := range s.fields {
+		value := v.Field(f.index)
+		if (f.omitEmpty && isEmptyJSONValue(value)) || (f.omitZero && isZeroJSONValue(value)) {
+			continue
+		}
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+		buf.Write(f.key)
+		if err := enc.Encode(value.Interface()); err != nil {
+			return err
+		}
+		// Encode ends each value with a newline.
+		buf.Truncate(buf.Len() - 1)
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+// isEmptyJSONValue reports whether v is empty in the sense of the
+// omitempty option.
+func isEmptyJSONValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
+
+// isZeroJSONValue reports whether v is zero in the sense of the omitzero
+// option: its IsZero method, if it has one, reports true, or it is the
+// zero value of its type.
+func isZeroJSONValue(v reflect.Value) bool {
+	if z, ok := v.Interface().(interface{ IsZero() bool }); ok {
+		return z.IsZero()
+	}
+	return v.IsZero()
+}
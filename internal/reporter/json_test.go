@@ -0,0 +1,308 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package reporter
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+	"github.com/leansecurity-co/gwork/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testShareRecords() []audit.ExternalShareRecord {
+	return []audit.ExternalShareRecord{
+		{OwnerEmail: "b@example.com", FileID: "2", FileName: "b.txt", PermissionType: "anyone", PermissionRole: "reader"},
+		{OwnerEmail: "a@example.com", FileID: "1", FileName: "a.txt", SharedWithEmail: "guest@partner.com",
+			SharedWithDomain: "partner.com", PermissionType: "user", PermissionRole: "writer",
+			ExpirationTime: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)},
+	}
+}
+
+func TestJSONReporter_IndentedVsCompact(t *testing.T) {
+	compactDir := t.TempDir()
+	compact, err := NewJSONReporter(compactDir, Options{})
+	require.NoError(t, err)
+	require.NoError(t, compact.WriteExternalSharing(testShareRecords()))
+
+	prettyDir := t.TempDir()
+	pretty, err := NewJSONReporter(prettyDir, Options{JSONIndent: true})
+	require.NoError(t, err)
+	require.NoError(t, pretty.WriteExternalSharing(testShareRecords()))
+
+	compactData, err := os.ReadFile(filepath.Join(compactDir, "external_sharing.json"))
+	require.NoError(t, err)
+	prettyData, err := os.ReadFile(filepath.Join(prettyDir, "external_sharing.json"))
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, strings.Count(string(compactData), "\n"), "compact output is a single line")
+	assert.Greater(t, strings.Count(string(prettyData), "\n"), 1)
+	assert.Contains(t, string(prettyData), "\n  {\n    \"owner_email\": \"a@example.com\"")
+	assert.Less(t, len(compactData), len(prettyData))
+
+	// Both decode to the same records.
+	var fromCompact, fromPretty []map[string]any
+	require.NoError(t, json.Unmarshal(compactData, &fromCompact))
+	require.NoError(t, json.Unmarshal(prettyData, &fromPretty))
+	assert.Equal(t, fromCompact, fromPretty)
+
+	require.Len(t, fromCompact, 2)
+	assert.Equal(t, "a@example.com", fromCompact[0]["owner_email"], "records are sorted by owner")
+	assert.Equal(t, "2025-03-01T12:00:00Z", fromCompact[0]["expiration_time"])
+	assert.NotContains(t, fromCompact[1], "expiration_time", "zero times are omitted")
+}
+
+func TestJSONReporter_NDJSONIgnoresIndent(t *testing.T) {
+	tmpDir := t.TempDir()
+	reporter, err := NewNDJSONReporter(tmpDir, Options{JSONIndent: true})
+	require.NoError(t, err)
+	require.NoError(t, reporter.WriteExternalSharing(testShareRecords()))
+
+	file, err := os.Open(filepath.Join(tmpDir, "external_sharing.ndjson"))
+	require.NoError(t, err)
+	defer file.Close() //nolint:errcheck // test cleanup
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	require.NoError(t, scanner.Err())
+
+	require.Len(t, lines, 2, "one object per line")
+	for _, line := range lines {
+		var rec map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &rec))
+		assert.NotContains(t, line, "\n  ")
+	}
+	assert.Contains(t, lines[0], `"owner_email":"a@example.com"`)
+}
+
+func TestJSONReporter_EmptyRecords(t *testing.T) {
+	tmpDir := t.TempDir()
+	reporter, err := NewJSONReporter(tmpDir, Options{})
+	require.NoError(t, err)
+	require.NoError(t, reporter.WriteFilesByOwner(nil))
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, "files_by_owner.json"))
+	require.NoError(t, err)
+	assert.Equal(t, "[]\n", string(data))
+}
+
+func TestJSONReporter_JSONFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		indent bool
+	}{
+		{name: "json", format: FormatJSON},
+		{name: "indented json", format: FormatJSON, indent: true},
+		{name: "ndjson", format: FormatNDJSON},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			rep, err := New(tt.format, dir, Options{
+				JSONIndent: tt.indent,
+				JSONFields: []string{"permission_role", "file_id", "expiration_time", "no_such_field"},
+			})
+			require.NoError(t, err)
+			require.NoError(t, rep.WriteExternalSharing(testShareRecords()))
+
+			data, err := os.ReadFile(filepath.Join(dir, rep.FileName("external_sharing")))
+			require.NoError(t, err)
+
+			var objects []map[string]any
+			if tt.format == FormatNDJSON {
+				for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+					var obj map[string]any
+					require.NoError(t, json.Unmarshal([]byte(line), &obj))
+					objects = append(objects, obj)
+				}
+			} else {
+				require.NoError(t, json.Unmarshal(data, &objects))
+			}
+
+			assert.Equal(t, []map[string]any{
+				{"permission_role": "writer", "file_id": "1", "expiration_time": "2025-03-01T12:00:00Z"},
+				{"permission_role": "reader", "file_id": "2"},
+			}, objects, "unselected fields are absent, not empty")
+			assert.NotContains(t, string(data), "owner_email")
+			assert.Less(t, strings.Index(string(data), "permission_role"), strings.Index(string(data), "file_id"),
+				"fields are written in the order selected")
+		})
+	}
+}
+
+func TestJSONReporter_IndentMatchesEncoder(t *testing.T) {
+	dir := t.TempDir()
+	rep, err := NewJSONReporter(dir, Options{JSONIndent: true})
+	require.NoError(t, err)
+	records := testShareRecords()
+	require.NoError(t, rep.WriteExternalSharing(records))
+
+	// Items are written one at a time, as encoding the whole slice would.
+	var want strings.Builder
+	enc := json.NewEncoder(&want)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	require.NoError(t, enc.Encode(records))
+
+	data, err := os.ReadFile(filepath.Join(dir, "external_sharing.json"))
+	require.NoError(t, err)
+	assert.Equal(t, want.String(), string(data))
+}
+
+func TestJSONReporter_JSONFieldsMatchEncoding(t *testing.T) {
+	// Selecting every field writes what encoding/json would, including its
+	// omitempty and omitzero rules.
+	dir := t.TempDir()
+	rep, err := NewNDJSONReporter(dir, Options{JSONFields: config.ValidJSONFields})
+	require.NoError(t, err)
+	records := testShareRecords()
+	require.NoError(t, rep.WriteExternalSharing(records))
+
+	data, err := os.ReadFile(filepath.Join(dir, "external_sharing.ndjson"))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, len(records))
+	for i, rec := range records {
+		want, err := json.Marshal(rec)
+		require.NoError(t, err)
+		assert.JSONEq(t, string(want), lines[i])
+	}
+}
+
+func TestValidJSONFieldsMatchRecords(t *testing.T) {
+	var keys []string
+	for _, record := range []any{
+		audit.FileRecord{}, audit.ExternalShareRecord{}, audit.DuplicateGroup{},
+		audit.OwnerSummary{}, audit.RoleCount{},
+	} {
+		typ := reflect.TypeOf(record)
+		for i := range typ.NumField() {
+			key, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+			if key != "" && key != "-" && !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	assert.ElementsMatch(t, keys, config.ValidJSONFields)
+}
+
+func TestJSONReporter_JSONFieldsNoHTMLEscaping(t *testing.T) {
+	dir := t.TempDir()
+	rep, err := NewJSONReporter(dir, Options{JSONFields: []string{"file_name"}})
+	require.NoError(t, err)
+	require.NoError(t, rep.WriteFilesByOwner([]audit.FileRecord{{FileID: "1", FileName: "R&D <plan>"}}))
+
+	data, err := os.ReadFile(filepath.Join(dir, "files_by_owner.json"))
+	require.NoError(t, err)
+	assert.Equal(t, `[{"file_name":"R&D <plan>"}]`+"\n", string(data))
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		format  string
+		wantErr bool
+	}{
+		{format: "csv"},
+		{format: ""},
+		{format: "json"},
+		{format: "ndjson"},
+		{format: "xml", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			rep, err := New(tt.format, t.TempDir(), Options{})
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "unsupported output format")
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, rep)
+		})
+	}
+}
+
+func TestJSONReporter_PermissionID(t *testing.T) {
+	dir := t.TempDir()
+	rep, err := NewJSONReporter(dir, Options{})
+	require.NoError(t, err)
+
+	records := []audit.ExternalShareRecord{
+		{OwnerEmail: "a@example.com", FileID: "1", FileName: "a.txt", PermissionType: "anyone", PermissionRole: "reader", PermissionID: "anyoneWithLink"},
+		{OwnerEmail: "b@example.com", FileID: "2", FileName: "b.txt", PermissionType: "anyone", PermissionRole: "reader"},
+	}
+	require.NoError(t, rep.WriteExternalSharing(records))
+
+	data, err := os.ReadFile(filepath.Join(dir, "external_sharing.json"))
+	require.NoError(t, err)
+
+	var got []map[string]any
+	require.NoError(t, json.Unmarshal(data, &got))
+	require.Len(t, got, 2)
+	assert.Equal(t, "anyoneWithLink", got[0]["permission_id"])
+	assert.NotContains(t, got[1], "permission_id", "omitted when unknown")
+}
+
+func TestJSONReporter_WriteRoleDistribution(t *testing.T) {
+	dir := t.TempDir()
+	rep, err := NewJSONReporter(dir, Options{})
+	require.NoError(t, err)
+
+	counts := []audit.RoleCount{{Scope: audit.ScopeExternal, Role: "writer", Count: 3}}
+	require.NoError(t, rep.WriteRoleDistribution(counts))
+
+	data, err := os.ReadFile(filepath.Join(dir, "role_distribution.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"scope":"external","role":"writer","count":3}]`, string(data))
+}
+
+func TestWriteCombinedJSON(t *testing.T) {
+	files := &audit.AuditResult{
+		TotalFiles: 2,
+		FileRecords: []audit.FileRecord{
+			{OwnerEmail: "b@example.com", FileID: "2", SizeBytes: 5},
+			{OwnerEmail: "a@example.com", FileID: "1", SizeBytes: 10},
+		},
+	}
+	sharing := &audit.AuditResult{TotalFiles: 2, TotalExternalShares: 2, ExternalShares: testShareRecords()}
+	meta := RunMeta{Version: "1.2.3", Domain: "example.com", Timestamp: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)}
+
+	var buf strings.Builder
+	require.NoError(t, WriteCombinedJSON(&buf, NewCombinedReport(meta, files, sharing), false))
+	assert.Equal(t, 1, strings.Count(buf.String(), "\n"), "compact output is a single line")
+
+	var doc CombinedReport
+	require.NoError(t, json.Unmarshal([]byte(buf.String()), &doc))
+	assert.Equal(t, "1.2.3", doc.Version)
+	assert.Equal(t, "example.com", doc.Domain)
+	assert.Equal(t, audit.Totals{Files: 2, ExternalShares: 2, PublicShares: 1, Bytes: 15}, doc.Summary)
+	require.Len(t, doc.Files, 2)
+	assert.Equal(t, "a@example.com", doc.Files[0].OwnerEmail, "files are sorted by owner")
+	require.Len(t, doc.ExternalShares, 2)
+	assert.Equal(t, "a@example.com", doc.ExternalShares[0].OwnerEmail, "shares are sorted by owner")
+}
+
+func TestWriteCombinedJSON_Empty(t *testing.T) {
+	var buf strings.Builder
+	report := NewCombinedReport(RunMeta{}, &audit.AuditResult{}, &audit.AuditResult{})
+	require.NoError(t, WriteCombinedJSON(&buf, report, true))
+
+	assert.Contains(t, buf.String(), `"files": []`)
+	assert.Contains(t, buf.String(), `"external_sharing": []`)
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ManifestFileName is the name of the run manifest written to the output directory.
+const ManifestFileName = "manifest.json"
+
+// RunMeta describes an audit run for the manifest.
+type RunMeta struct {
+	Version    string
+	Timestamp  time.Time
+	Domain     string
+	Filters    map[string]string
+	ConfigFile string
+}
+
+// Manifest is the provenance record written alongside a report bundle.
+type Manifest struct {
+	Version    string            `json:"version"`
+	Timestamp  time.Time         `json:"timestamp"`
+	Domain     string            `json:"domain"`
+	Filters    map[string]string `json:"filters"`
+	ConfigFile string            `json:"config_file"`
+	Files      []ManifestFile    `json:"files"`
+
+	// SpreadsheetURL and Sheets describe the spreadsheet written by the
+	// sheets output format.
+	SpreadsheetURL string   `json:"spreadsheet_url,omitempty"`
+	Sheets         []string `json:"sheets,omitempty"`
+}
+
+// ManifestFile describes a generated report file.
+type ManifestFile struct {
+	Path      string `json:"path"`
+	SizeBytes int64  `json:"size_bytes"`
+}
+
+// writeManifest writes the manifest for the given report files, whose paths
+// are relative to outputDir, and SHA256SUMS listing them and the manifest.
+// sums holds the checksums recorded as the files were written.
+func writeManifest(outputDir string, meta RunMeta, files []string, sums checksums) error {
+	manifest, err := buildManifest(outputDir, meta, files)
+	if err != nil {
+		return err
+	}
+	if err := saveManifest(outputDir, manifest); err != nil {
+		return err
+	}
+	return writeChecksums(outputDir, append(files[:len(files):len(files)], ManifestFileName), sums)
+}
+
+// buildManifest returns the manifest for the given report files, whose paths
+// are relative to outputDir.
+func buildManifest(outputDir string, meta RunMeta, files []string) (Manifest, error) {
+	filters := meta.Filters
+	if filters == nil {
+		filters = map[string]string{}
+	}
+
+	manifest := Manifest{
+		Version:    meta.Version,
+		Timestamp:  meta.Timestamp,
+		Domain:     meta.Domain,
+		Filters:    filters,
+		ConfigFile: meta.ConfigFile,
+		Files:      make([]ManifestFile, 0, len(files)),
+	}
+
+	for _, rel := range files {
+		info, err := os.Stat(filepath.Join(outputDir, rel))
+		if err != nil {
+			return Manifest{}, fmt.Errorf("failed to stat report file: %w", err)
+		}
+		manifest.Files = append(manifest.Files, ManifestFile{
+			Path:      filepath.ToSlash(rel),
+			SizeBytes: info.Size(),
+		})
+	}
+
+	return manifest, nil
+}
+
+// saveManifest writes manifest to the output directory.
+func saveManifest(outputDir string, manifest Manifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(outputDir, ManifestFileName), data, 0600); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package reporter
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSVReporter_WriteManifest(t *testing.T) {
+	tmpDir := t.TempDir()
+	reporter, err := NewCSVReporter(tmpDir)
+	require.NoError(t, err)
+
+	require.NoError(t, reporter.WriteFilesByOwner([]audit.FileRecord{
+		{OwnerEmail: "alice@example.com", FileID: "1", FileName: "a.txt"},
+	}))
+	require.NoError(t, reporter.WriteExternalSharing([]audit.ExternalShareRecord{
+		{OwnerEmail: "alice@example.com", FileID: "1", FileName: "a.txt", PermissionType: "anyone"},
+	}))
+
+	meta := RunMeta{
+		Version:    "1.2.3",
+		Timestamp:  time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+		Domain:     "example.com",
+		Filters:    map[string]string{"direct-only": "true"},
+		ConfigFile: "/etc/gwork/.gwork.yaml",
+	}
+	require.NoError(t, reporter.WriteManifest(meta))
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, ManifestFileName))
+	require.NoError(t, err)
+
+	var manifest Manifest
+	require.NoError(t, json.Unmarshal(data, &manifest))
+
+	assert.Equal(t, "1.2.3", manifest.Version)
+	assert.True(t, meta.Timestamp.Equal(manifest.Timestamp))
+	assert.Equal(t, "example.com", manifest.Domain)
+	assert.Equal(t, map[string]string{"direct-only": "true"}, manifest.Filters)
+	assert.Equal(t, "/etc/gwork/.gwork.yaml", manifest.ConfigFile)
+
+	require.Len(t, manifest.Files, 2)
+	assert.Equal(t, "files_by_owner.csv", manifest.Files[0].Path)
+	assert.Equal(t, "external_sharing.csv", manifest.Files[1].Path)
+
+	for _, f := range manifest.Files {
+		info, err := os.Stat(filepath.Join(tmpDir, f.Path))
+		require.NoError(t, err)
+		assert.Equal(t, info.Size(), f.SizeBytes)
+	}
+}
+
+func TestCSVReporter_WriteManifest_OnlyWrittenFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	reporter, err := NewCSVReporter(tmpDir)
+	require.NoError(t, err)
+
+	require.NoError(t, reporter.WriteFilesByOwner(nil))
+	require.NoError(t, reporter.WriteFilesByOwner(nil))
+	require.NoError(t, reporter.WriteManifest(RunMeta{}))
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, ManifestFileName))
+	require.NoError(t, err)
+
+	var manifest Manifest
+	require.NoError(t, json.Unmarshal(data, &manifest))
+	require.Len(t, manifest.Files, 1)
+	assert.Equal(t, "files_by_owner.csv", manifest.Files[0].Path)
+	assert.NotNil(t, manifest.Filters)
+}
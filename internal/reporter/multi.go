@@ -0,0 +1,148 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package reporter
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+)
+
+// fileReporter is implemented by reporters whose reports are files in
+// OutputDir, so that MultiReporter can list them in one manifest.
+type fileReporter interface {
+	Reporter
+	// manifestFiles records meta wherever the reporter keeps it besides
+	// the manifest, and returns the report files written so far, relative
+	// to OutputDir, with the checksums recorded as they were written.
+	manifestFiles(meta RunMeta) ([]string, checksums, error)
+}
+
+// MultiReporter writes every report in several formats at once, e.g. CSV
+// for people and JSON for tooling, from a single audit. All of its
+// reporters write to the same output directory and share one manifest.
+type MultiReporter struct {
+	reporters []fileReporter
+}
+
+// NewMultiReporter creates a reporter writing each report with every given
+// format, in order, to outputDir.
+func NewMultiReporter(formats []string, outputDir string, opts Options) (*MultiReporter, error) {
+	r := &MultiReporter{}
+	for _, format := range formats {
+		rep, err := New(format, outputDir, opts)
+		if err != nil {
+			return nil, err
+		}
+		fileRep, ok := rep.(fileReporter)
+		if !ok {
+			return nil, fmt.Errorf("output format %s cannot be combined with other formats", format)
+		}
+		r.reporters = append(r.reporters, fileRep)
+	}
+	if len(r.reporters) == 0 {
+		return nil, errors.New("no output formats given")
+	}
+	return r, nil
+}
+
+// Reporters returns the reporter of each format, in order.
+func (r *MultiReporter) Reporters() []Reporter {
+	reporters := make([]Reporter, len(r.reporters))
+	for i, rep := range r.reporters {
+		reporters[i] = rep
+	}
+	return reporters
+}
+
+// each calls write with every reporter, stopping at the first error.
+func (r *MultiReporter) each(write func(Reporter) error) error {
+	for _, rep := range r.reporters {
+		if err := write(rep); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteFilesByOwner writes the files-by-owner report in every format.
+func (r *MultiReporter) WriteFilesByOwner(records []audit.FileRecord) error {
+	return r.each(func(rep Reporter) error { return rep.WriteFilesByOwner(records) })
+}
+
+// WriteExternalSharing writes the external-sharing report in every format.
+func (r *MultiReporter) WriteExternalSharing(records []audit.ExternalShareRecord) error {
+	return r.each(func(rep Reporter) error { return rep.WriteExternalSharing(records) })
+}
+
+// WritePublicShares writes the public-shares report in every format.
+func (r *MultiReporter) WritePublicShares(records []audit.ExternalShareRecord) error {
+	return r.each(func(rep Reporter) error { return rep.WritePublicShares(records) })
+}
+
+// WriteDomainShares writes the domain-wide shares report in every format.
+func (r *MultiReporter) WriteDomainShares(records []audit.ExternalShareRecord) error {
+	return r.each(func(rep Reporter) error { return rep.WriteDomainShares(records) })
+}
+
+// WriteDuplicates writes the duplicates report in every format.
+func (r *MultiReporter) WriteDuplicates(groups []audit.DuplicateGroup) error {
+	return r.each(func(rep Reporter) error { return rep.WriteDuplicates(groups) })
+}
+
+// WriteNewShares writes the new-shares report in every format.
+func (r *MultiReporter) WriteNewShares(records []audit.ExternalShareRecord) error {
+	return r.each(func(rep Reporter) error { return rep.WriteNewShares(records) })
+}
+
+// WriteExternalOwners writes the external-owners report in every format.
+func (r *MultiReporter) WriteExternalOwners(records []audit.FileRecord) error {
+	return r.each(func(rep Reporter) error { return rep.WriteExternalOwners(records) })
+}
+
+// WriteSharedWithMe writes the shared-with-me report in every format.
+func (r *MultiReporter) WriteSharedWithMe(records []audit.FileRecord) error {
+	return r.each(func(rep Reporter) error { return rep.WriteSharedWithMe(records) })
+}
+
+// WriteOwners writes the owners report in every format.
+func (r *MultiReporter) WriteOwners(summaries []audit.OwnerSummary) error {
+	return r.each(func(rep Reporter) error { return rep.WriteOwners(summaries) })
+}
+
+// WriteRoleDistribution writes the role distribution report in every
+// format.
+func (r *MultiReporter) WriteRoleDistribution(counts []audit.RoleCount) error {
+	return r.each(func(rep Reporter) error { return rep.WriteRoleDistribution(counts) })
+}
+
+// WriteManifest writes one manifest.json and SHA256SUMS listing the reports
+// written in every format.
+func (r *MultiReporter) WriteManifest(meta RunMeta) error {
+	var files []string
+	sums := make(checksums)
+	for _, rep := range r.reporters {
+		repFiles, repSums, err := rep.manifestFiles(meta)
+		if err != nil {
+			return err
+		}
+		files = append(files, repFiles...)
+		for path, sum := range repSums {
+			sums[path] = sum
+		}
+	}
+	return writeManifest(r.OutputDir(), meta, files, sums)
+}
+
+// OutputDir returns the output directory path.
+func (r *MultiReporter) OutputDir() string {
+	return r.reporters[0].OutputDir()
+}
+
+// FileName returns the file name of the report named base in the first
+// format.
+func (r *MultiReporter) FileName(base string) string {
+	return r.reporters[0].FileName(base)
+}
@@ -0,0 +1,81 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package reporter
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiReporter_WritesEveryFormat(t *testing.T) {
+	dir := t.TempDir()
+	rep, err := NewMultiReporter([]string{FormatCSV, FormatJSON, FormatNDJSON, FormatSQLite}, dir, Options{})
+	require.NoError(t, err)
+
+	require.NoError(t, rep.WriteFilesByOwner([]audit.FileRecord{
+		{OwnerEmail: "bob@example.com", FileID: "2", FileName: "b.txt"},
+		{OwnerEmail: "alice@example.com", FileID: "1", FileName: "a.txt"},
+	}))
+	require.NoError(t, rep.WriteExternalSharing([]audit.ExternalShareRecord{
+		{OwnerEmail: "alice@example.com", FileID: "1", FileName: "a.txt", PermissionType: "anyone"},
+	}))
+	require.NoError(t, rep.WriteManifest(RunMeta{Version: "1.0.0", Domain: "example.com"}))
+
+	want := []string{
+		"files_by_owner.csv", "external_sharing.csv",
+		"files_by_owner.json", "external_sharing.json",
+		"files_by_owner.ndjson", "external_sharing.ndjson",
+		"gwork.db",
+	}
+	for _, name := range want {
+		assert.FileExists(t, filepath.Join(dir, name))
+	}
+
+	var records []audit.FileRecord
+	data, err := os.ReadFile(filepath.Join(dir, "files_by_owner.json"))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &records))
+	assert.Len(t, records, 2)
+
+	data, err = os.ReadFile(filepath.Join(dir, ManifestFileName))
+	require.NoError(t, err)
+	var manifest Manifest
+	require.NoError(t, json.Unmarshal(data, &manifest))
+	var listed []string
+	for _, f := range manifest.Files {
+		listed = append(listed, f.Path)
+	}
+	assert.Equal(t, want, listed, "one manifest lists the reports of every format")
+
+	paths, _ := readChecksums(t, dir)
+	assert.Equal(t, append(want, ManifestFileName), paths)
+
+	assert.Equal(t, dir, rep.OutputDir())
+	assert.Equal(t, "external_sharing.csv", rep.FileName("external_sharing"))
+	assert.Len(t, rep.Reporters(), 4)
+}
+
+func TestNewMultiReporter_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		formats []string
+		wantErr string
+	}{
+		{name: "unknown format", formats: []string{FormatCSV, "html"}, wantErr: "unsupported output format: html"},
+		{name: "no formats", wantErr: "no output formats given"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewMultiReporter(tt.formats, t.TempDir(), Options{})
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
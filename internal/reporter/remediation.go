@@ -0,0 +1,108 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package reporter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+)
+
+// remediationHeader starts every remediation script. It defines revoke,
+// which deletes one permission through the Drive API, and refuses to run
+// without an access token.
+const remediationHeader = `#!/bin/sh
+# Suggested revocations generated by gwork.
+#
+# gwork does not revoke anything itself. Every revoke line below is
+# commented out: review each one, uncomment the shares you want removed,
+# then run this script with an OAuth access token allowed to manage the
+# files, e.g.
+#
+#   GWORK_ACCESS_TOKEN=$(gcloud auth print-access-token) sh remediation.sh
+#
+# Revoking a permission cannot be undone from this script.
+set -eu
+
+: "${GWORK_ACCESS_TOKEN:?set GWORK_ACCESS_TOKEN to an OAuth access token}"
+
+revoke() {
+	curl -sSf -X DELETE \
+		-H "Authorization: Bearer ${GWORK_ACCESS_TOKEN}" \
+		"https://www.googleapis.com/drive/v3/files/$1/permissions/$2?supportsAllDrives=true"
+}
+`
+
+// IsRemediationCandidate reports whether rec is suggested for revocation:
+// a public share or one with a risk score of at least audit.RiskHigh.
+func IsRemediationCandidate(rec audit.ExternalShareRecord) bool {
+	return audit.IsPublicPermissionType(rec.PermissionType) || rec.Risk >= audit.RiskHigh
+}
+
+// RemediationScript returns a shell script suggesting one revoke line per
+// file and permission ID of the remediation candidates in records. Every
+// revoke line is commented out. Inherited permissions are listed against
+// the item they are inherited from, since Drive only deletes them there,
+// and records without a permission ID are skipped.
+func RemediationScript(records []audit.ExternalShareRecord) string {
+	var b strings.Builder
+	b.WriteString(remediationHeader)
+
+	seen := make(map[string]bool)
+	for _, rec := range records {
+		if !IsRemediationCandidate(rec) || rec.PermissionID == "" {
+			continue
+		}
+		fileID := rec.FileID
+		if rec.Inherited && rec.InheritedFrom != "" {
+			fileID = rec.InheritedFrom
+		}
+		key := fileID + "\x00" + rec.PermissionID
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		fmt.Fprintf(&b, "\n# %s\n", remediationComment(rec))
+		if fileID != rec.FileID {
+			fmt.Fprintf(&b, "# inherited by %s; revoking it on %s removes it from every file below\n", rec.FileID, fileID)
+		}
+		fmt.Fprintf(&b, "# revoke %s %s\n", shellQuote(fileID), shellQuote(rec.PermissionID))
+	}
+	return b.String()
+}
+
+// WriteRemediationScript writes RemediationScript(records) to path,
+// creating its directory if needed.
+func WriteRemediationScript(path string, records []audit.ExternalShareRecord) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create remediation script directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(RemediationScript(records)), 0600); err != nil {
+		return fmt.Errorf("failed to write remediation script: %w", err)
+	}
+	return nil
+}
+
+// remediationComment describes the share of rec on one line.
+func remediationComment(rec audit.ExternalShareRecord) string {
+	grantee := rec.SharedWithEmail
+	if grantee == "" {
+		grantee = rec.SharedWithDomain
+	}
+	if grantee == "" {
+		grantee = rec.PermissionType
+	}
+	comment := fmt.Sprintf("%s (%s) owned by %s: %s %s, risk %d",
+		rec.FileName, rec.FileID, rec.OwnerEmail, grantee, rec.PermissionRole, rec.Risk)
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(comment)
+}
+
+// shellQuote quotes s as a single shell word.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
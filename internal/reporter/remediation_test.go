@@ -0,0 +1,106 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package reporter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// revokeLines returns the commented revoke lines of script.
+func revokeLines(script string) []string {
+	var lines []string
+	for _, line := range strings.Split(script, "\n") {
+		if strings.HasPrefix(line, "# revoke ") {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func TestRemediationScript(t *testing.T) {
+	tests := []struct {
+		name    string
+		records []audit.ExternalShareRecord
+		want    []string
+	}{
+		{
+			name: "public share",
+			records: []audit.ExternalShareRecord{
+				{FileID: "f1", PermissionID: "anyoneWithLink", PermissionType: "anyone", Risk: audit.RiskMedium},
+			},
+			want: []string{"# revoke 'f1' 'anyoneWithLink'"},
+		},
+		{
+			name: "high risk share",
+			records: []audit.ExternalShareRecord{
+				{FileID: "f2", PermissionID: "123", PermissionType: "user", SharedWithEmail: "x@rival.com", Risk: audit.RiskHigh},
+			},
+			want: []string{"# revoke 'f2' '123'"},
+		},
+		{
+			name: "low risk shares excluded",
+			records: []audit.ExternalShareRecord{
+				{FileID: "f3", PermissionID: "456", PermissionType: "user", Risk: audit.RiskLow},
+				{FileID: "f4", PermissionID: "789", PermissionType: "domain", Risk: audit.RiskLow},
+			},
+		},
+		{
+			name: "missing permission ID skipped",
+			records: []audit.ExternalShareRecord{
+				{FileID: "f5", PermissionType: "anyone", Risk: audit.RiskMedium},
+			},
+		},
+		{
+			name: "inherited revoked on parent once",
+			records: []audit.ExternalShareRecord{
+				{FileID: "f6", PermissionID: "anyoneWithLink", PermissionType: "anyone", Inherited: true, InheritedFrom: "folder1"},
+				{FileID: "f7", PermissionID: "anyoneWithLink", PermissionType: "anyone", Inherited: true, InheritedFrom: "folder1"},
+			},
+			want: []string{"# revoke 'folder1' 'anyoneWithLink'"},
+		},
+		{
+			name: "quotes in IDs",
+			records: []audit.ExternalShareRecord{
+				{FileID: "f'8", PermissionID: "p", PermissionType: "anyone"},
+			},
+			want: []string{`# revoke 'f'\''8' 'p'`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script := RemediationScript(tt.records)
+			assert.True(t, strings.HasPrefix(script, "#!/bin/sh\n"))
+			assert.Equal(t, tt.want, revokeLines(script))
+			for _, line := range strings.Split(script, "\n") {
+				assert.False(t, strings.HasPrefix(line, "revoke "), "revoke lines must be commented out: %q", line)
+			}
+		})
+	}
+}
+
+func TestRemediationScript_CommentIsOneLine(t *testing.T) {
+	script := RemediationScript([]audit.ExternalShareRecord{
+		{FileID: "f1", FileName: "a\nrevoke 'x' 'y'", PermissionID: "p", PermissionType: "anyone"},
+	})
+	assert.Contains(t, script, "# a revoke 'x' 'y' (f1)")
+	assert.NotContains(t, script, "\nrevoke ")
+}
+
+func TestWriteRemediationScript(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out", "remediation.sh")
+	records := []audit.ExternalShareRecord{{FileID: "f1", PermissionID: "p1", PermissionType: "anyone"}}
+	require.NoError(t, WriteRemediationScript(path, records))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, RemediationScript(records), string(data))
+}
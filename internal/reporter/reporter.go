@@ -4,7 +4,25 @@
 // Package reporter provides output formatting for audit results.
 package reporter
 
-import "github.com/leansecurity-co/gwork/internal/audit"
+import (
+	"fmt"
+	"time"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+)
+
+// Supported output formats.
+const (
+	FormatCSV    = "csv"
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+	FormatXLSX   = "xlsx"
+	// FormatSQLite writes every report of a run into one SQLite database.
+	FormatSQLite = "sqlite"
+	// FormatSheets writes to Google Sheets. It needs a SheetsAPI, so
+	// reporters for it are created with NewGSheetReporter rather than New.
+	FormatSheets = "sheets"
+)
 
 // Reporter defines the interface for audit result output.
 type Reporter interface {
@@ -13,4 +31,137 @@ type Reporter interface {
 
 	// WriteExternalSharing writes external sharing report.
 	WriteExternalSharing(records []audit.ExternalShareRecord) error
+
+	// WritePublicShares writes public exposure report.
+	WritePublicShares(records []audit.ExternalShareRecord) error
+
+	// WriteDomainShares writes the report of files shared with the whole
+	// organization.
+	WriteDomainShares(records []audit.ExternalShareRecord) error
+
+	// WriteDuplicates writes the report of files sharing an owner, name
+	// and size.
+	WriteDuplicates(groups []audit.DuplicateGroup) error
+
+	// WriteNewShares writes the report of external shares that were not
+	// in the previous run's baseline.
+	WriteNewShares(records []audit.ExternalShareRecord) error
+
+	// WriteExternalOwners writes the report of files owned by accounts
+	// outside the primary domain and its aliases.
+	WriteExternalOwners(records []audit.FileRecord) error
+
+	// WriteSharedWithMe writes the report of files shared with the
+	// authenticated user by accounts outside the primary domain and its
+	// aliases.
+	WriteSharedWithMe(records []audit.FileRecord) error
+
+	// WriteOwners writes owners inventory report.
+	WriteOwners(summaries []audit.OwnerSummary) error
+
+	// WriteRoleDistribution writes share counts by scope and role.
+	WriteRoleDistribution(counts []audit.RoleCount) error
+
+	// WriteManifest writes a manifest describing the run and the reports
+	// written so far.
+	WriteManifest(meta RunMeta) error
+
+	// OutputDir returns the directory reports are written to.
+	OutputDir() string
+
+	// FileName returns the file name, relative to OutputDir, of the report
+	// named base, e.g. "external_sharing.csv".
+	FileName(base string) string
+}
+
+// Options controls optional report columns.
+type Options struct {
+	// IncludeTrashed adds a trashed column to the reports.
+	IncludeTrashed bool
+	// IncludeLinkStatus adds a link_sharing_enabled column to the files
+	// report.
+	IncludeLinkStatus bool
+	// AgeAt adds created_age_days and modified_age_days columns to the
+	// files report, counting whole days up to AgeAt, usually the run time.
+	// The zero time leaves the columns out.
+	AgeAt time.Time
+	// ExpandGroups adds group_member_count and has_external_members columns
+	// to the sharing report.
+	ExpandGroups bool
+	// Explain adds an explanation column to the sharing report.
+	Explain bool
+	// SourceDomain adds a source_domain column, the audited domain of each
+	// record, to the file and share reports of a multi-domain audit.
+	SourceDomain bool
+	// JSONIndent indents JSON array reports. NDJSON is always compact.
+	JSONIndent bool
+	// JSONFields limits each object in JSON and NDJSON reports to these
+	// top-level fields, in this order. Empty keeps every field.
+	JSONFields []string
+	// Delimiter separates CSV fields; zero means a comma. A tab writes
+	// .tsv files.
+	Delimiter rune
+	// SplitByOwner writes one files report per owner under files/ plus a
+	// files_index report instead of files_by_owner. Supported for CSV only.
+	SplitByOwner bool
+	// MaxRowsPerFile splits CSV record reports with more rows into
+	// numbered segments, e.g. files_by_owner.001.csv. Zero means no limit.
+	MaxRowsPerFile int
+	// FileNames overrides the file name, relative to the output directory,
+	// of the reports with the given base names, e.g. "external_sharing".
+	FileNames map[string]string
+	// HeaderLabels renames columns in the header row of CSV, XLSX and
+	// Google Sheets reports, keyed by column name. Columns without a label
+	// keep their name. JSON keys are not affected.
+	HeaderLabels map[string]string
+	// Order sorts the records of file reports and the owners of split
+	// outputs. The zero value sorts by owner, then file name.
+	Order audit.RecordOrder
+
+	// rawText writes free-text values unsanitized. Reporters whose cells
+	// are never evaluated as formulas set it; CSV keeps values sanitized.
+	rawText bool
+}
+
+// labelHeader returns header with each column replaced by its label in
+// HeaderLabels, if it has one.
+func (o Options) labelHeader(header []string) []string {
+	if len(o.HeaderLabels) == 0 {
+		return header
+	}
+	labeled := make([]string, len(header))
+	for i, column := range header {
+		labeled[i] = column
+		if label, ok := o.HeaderLabels[column]; ok {
+			labeled[i] = label
+		}
+	}
+	return labeled
+}
+
+// New creates the reporter for the given output format.
+func New(format, outputDir string, opts Options) (Reporter, error) {
+	switch format {
+	case FormatCSV, "":
+		return NewCSVReporterWithOptions(outputDir, opts)
+	case FormatJSON:
+		return NewJSONReporter(outputDir, opts)
+	case FormatNDJSON:
+		return NewNDJSONReporter(outputDir, opts)
+	case FormatXLSX:
+		return NewXLSXReporter(outputDir, opts)
+	case FormatSQLite:
+		return NewSQLiteReporter(outputDir, opts)
+	default:
+		return nil, fmt.Errorf("unsupported output format: %s", format)
+	}
+}
+
+// FileName returns the report file name for base in the given format,
+// e.g. "external_sharing.csv".
+func FileName(format, base string) string {
+	if format == "" {
+		format = FormatCSV
+	}
+	return base + "." + format
 }
@@ -0,0 +1,323 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package reporter
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+)
+
+// timestampLayout is the layout of timestamps in tabular reports.
+const timestampLayout = "2006-01-02T15:04:05Z"
+
+// formatOptionalBool formats b, or "" when it is unknown.
+func formatOptionalBool(b *bool) string {
+	if b == nil {
+		return ""
+	}
+	return strconv.FormatBool(*b)
+}
+
+// formatTimestamp formats t in UTC, or returns "" for the zero time.
+func formatTimestamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(timestampLayout)
+}
+
+// ageDays returns the number of whole days from t to now, or "" when t is
+// the zero time or after now.
+func ageDays(t, now time.Time) string {
+	if t.IsZero() || t.After(now) {
+		return ""
+	}
+	return strconv.FormatInt(int64(now.Sub(t)/(24*time.Hour)), 10)
+}
+
+// text returns the free-text value for a report cell, sanitized against
+// formula injection unless opts.rawText is set.
+func (o Options) text(value string) string {
+	if o.rawText {
+		return value
+	}
+	return sanitizeCSVField(value)
+}
+
+// fileRecordHeader returns the column names of the files report.
+func fileRecordHeader(opts Options) []string {
+	header := []string{
+		"owner_email", "file_id", "file_name", "file_type",
+		"created_time", "modified_time", "size_bytes", "owner_name", "location",
+		"drive_name", "viewed_by_me_time",
+	}
+	if opts.IncludeTrashed {
+		header = append(header, "trashed")
+	}
+	if opts.IncludeLinkStatus {
+		header = append(header, "link_sharing_enabled")
+	}
+	if !opts.AgeAt.IsZero() {
+		header = append(header, "created_age_days", "modified_age_days")
+	}
+	if opts.SourceDomain {
+		header = append(header, "source_domain")
+	}
+	return header
+}
+
+// fileRecordRow returns the files report row for rec.
+func fileRecordRow(rec audit.FileRecord, opts Options) []string {
+	row := []string{
+		opts.text(rec.OwnerEmail),
+		rec.FileID,
+		opts.text(rec.FileName),
+		rec.FileType,
+		formatTimestamp(rec.CreatedTime),
+		formatTimestamp(rec.ModifiedTime),
+		strconv.FormatInt(rec.SizeBytes, 10),
+		opts.text(rec.OwnerName),
+		rec.Location,
+		opts.text(rec.DriveName),
+		formatTimestamp(rec.ViewedByMeTime),
+	}
+	if opts.IncludeTrashed {
+		row = append(row, strconv.FormatBool(rec.Trashed))
+	}
+	if opts.IncludeLinkStatus {
+		row = append(row, formatOptionalBool(rec.LinkSharingEnabled))
+	}
+	if !opts.AgeAt.IsZero() {
+		row = append(row, ageDays(rec.CreatedTime, opts.AgeAt), ageDays(rec.ModifiedTime, opts.AgeAt))
+	}
+	if opts.SourceDomain {
+		row = append(row, rec.SourceDomain)
+	}
+	return row
+}
+
+// externalShareHeader returns the column names of the sharing report.
+func externalShareHeader(opts Options) []string {
+	header := []string{
+		"owner_email", "file_id", "file_name", "shared_with_email",
+		"shared_with_domain", "permission_type", "permission_role", "shared_date",
+		"owner_name", "inherited", "inherited_from", "expiration_time", "location",
+		"drive_name", "flagged", "flag_reason", "label", "risk", "permission_id",
+		"grantee_deleted",
+	}
+	if opts.IncludeTrashed {
+		header = append(header, "trashed")
+	}
+	if opts.ExpandGroups {
+		header = append(header, "group_member_count", "has_external_members")
+	}
+	if opts.Explain {
+		header = append(header, "explanation")
+	}
+	if opts.SourceDomain {
+		header = append(header, "source_domain")
+	}
+	return header
+}
+
+// externalShareRow returns the sharing report row for rec.
+func externalShareRow(rec audit.ExternalShareRecord, opts Options) []string {
+	row := []string{
+		opts.text(rec.OwnerEmail),
+		rec.FileID,
+		opts.text(rec.FileName),
+		opts.text(rec.SharedWithEmail),
+		opts.text(rec.SharedWithDomain),
+		rec.PermissionType,
+		rec.PermissionRole,
+		formatTimestamp(rec.SharedDate),
+		opts.text(rec.OwnerName),
+		strconv.FormatBool(rec.Inherited),
+		rec.InheritedFrom,
+		formatTimestamp(rec.ExpirationTime),
+		rec.Location,
+		opts.text(rec.DriveName),
+		strconv.FormatBool(rec.Flagged),
+		rec.FlagReason,
+		opts.text(rec.Label),
+		strconv.Itoa(rec.Risk),
+		rec.PermissionID,
+		strconv.FormatBool(rec.GranteeDeleted),
+	}
+	if opts.IncludeTrashed {
+		row = append(row, strconv.FormatBool(rec.Trashed))
+	}
+	if opts.ExpandGroups {
+		row = append(row, groupMemberCount(rec), groupHasExternalMembers(rec))
+	}
+	if opts.Explain {
+		row = append(row, opts.text(rec.Explanation))
+	}
+	if opts.SourceDomain {
+		row = append(row, rec.SourceDomain)
+	}
+	return row
+}
+
+// publicShareHeader returns the column names of the public shares report.
+func publicShareHeader(opts Options) []string {
+	header := []string{
+		"owner_email", "file_id", "file_name", "permission_type", "permission_role",
+		"web_view_link", "owner_name", "inherited", "inherited_from", "expiration_time",
+		"location", "drive_name", "label", "risk", "permission_id",
+	}
+	if opts.IncludeTrashed {
+		header = append(header, "trashed")
+	}
+	if opts.SourceDomain {
+		header = append(header, "source_domain")
+	}
+	return header
+}
+
+// publicShareRow returns the public shares report row for rec.
+func publicShareRow(rec audit.ExternalShareRecord, opts Options) []string {
+	row := []string{
+		opts.text(rec.OwnerEmail),
+		rec.FileID,
+		opts.text(rec.FileName),
+		rec.PermissionType,
+		rec.PermissionRole,
+		rec.WebViewLink,
+		opts.text(rec.OwnerName),
+		strconv.FormatBool(rec.Inherited),
+		rec.InheritedFrom,
+		formatTimestamp(rec.ExpirationTime),
+		rec.Location,
+		opts.text(rec.DriveName),
+		opts.text(rec.Label),
+		strconv.Itoa(rec.Risk),
+		rec.PermissionID,
+	}
+	if opts.IncludeTrashed {
+		row = append(row, strconv.FormatBool(rec.Trashed))
+	}
+	if opts.SourceDomain {
+		row = append(row, rec.SourceDomain)
+	}
+	return row
+}
+
+// domainShareHeader returns the column names of the domain-wide shares
+// report.
+func domainShareHeader(opts Options) []string {
+	header := []string{
+		"owner_email", "file_id", "file_name", "shared_with_domain", "permission_role",
+		"web_view_link", "owner_name", "inherited", "inherited_from", "expiration_time",
+		"location", "drive_name", "label", "risk", "permission_id",
+	}
+	if opts.IncludeTrashed {
+		header = append(header, "trashed")
+	}
+	if opts.SourceDomain {
+		header = append(header, "source_domain")
+	}
+	return header
+}
+
+// domainShareRow returns the domain-wide shares report row for rec.
+func domainShareRow(rec audit.ExternalShareRecord, opts Options) []string {
+	row := []string{
+		opts.text(rec.OwnerEmail),
+		rec.FileID,
+		opts.text(rec.FileName),
+		rec.SharedWithDomain,
+		rec.PermissionRole,
+		rec.WebViewLink,
+		opts.text(rec.OwnerName),
+		strconv.FormatBool(rec.Inherited),
+		rec.InheritedFrom,
+		formatTimestamp(rec.ExpirationTime),
+		rec.Location,
+		opts.text(rec.DriveName),
+		opts.text(rec.Label),
+		strconv.Itoa(rec.Risk),
+		rec.PermissionID,
+	}
+	if opts.IncludeTrashed {
+		row = append(row, strconv.FormatBool(rec.Trashed))
+	}
+	if opts.SourceDomain {
+		row = append(row, rec.SourceDomain)
+	}
+	return row
+}
+
+// ownerSummaryHeader is the column names of the owners report.
+var ownerSummaryHeader = []string{"owner_email", "owner_name", "file_count", "total_bytes"}
+
+// ownerSummaryRow returns the owners report row for s.
+func ownerSummaryRow(s audit.OwnerSummary, opts Options) []string {
+	return []string{
+		opts.text(s.OwnerEmail),
+		opts.text(s.OwnerName),
+		strconv.Itoa(s.FileCount),
+		strconv.FormatInt(s.TotalBytes, 10),
+	}
+}
+
+// duplicateGroupHeader is the column names of the duplicates report.
+var duplicateGroupHeader = []string{"owner_email", "owner_name", "file_name", "size_bytes", "file_count", "file_ids"}
+
+// duplicateGroupRow returns the duplicates report row for g. The file IDs
+// share one column, separated by semicolons.
+func duplicateGroupRow(g audit.DuplicateGroup, opts Options) []string {
+	return []string{
+		opts.text(g.OwnerEmail),
+		opts.text(g.OwnerName),
+		opts.text(g.FileName),
+		strconv.FormatInt(g.SizeBytes, 10),
+		strconv.Itoa(len(g.FileIDs)),
+		strings.Join(g.FileIDs, ";"),
+	}
+}
+
+// roleCountHeader is the column names of the role distribution report.
+var roleCountHeader = []string{"scope", "role", "count"}
+
+// roleCountRow returns the role distribution report row for c.
+func roleCountRow(c audit.RoleCount, opts Options) []string {
+	return []string{c.Scope, opts.text(c.Role), strconv.Itoa(c.Count)}
+}
+
+// ColumnNames returns the name of every column a CSV, XLSX or Google Sheets
+// report can have, with all optional columns enabled, sorted.
+func ColumnNames() []string {
+	all := Options{
+		IncludeTrashed: true, IncludeLinkStatus: true, AgeAt: time.Unix(0, 0),
+		ExpandGroups: true, Explain: true, SourceDomain: true,
+	}
+	var names []string
+	for _, header := range [][]string{
+		fileRecordHeader(all), externalShareHeader(all), publicShareHeader(all), domainShareHeader(all),
+		ownerSummaryHeader, duplicateGroupHeader, roleCountHeader, filesIndexHeader,
+	} {
+		names = append(names, header...)
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+// CheckHeaderLabels rejects labels for columns no report has, so a typo in
+// output.header_labels or output.labels_file does not go unnoticed.
+func CheckHeaderLabels(labels map[string]string) error {
+	columns := ColumnNames()
+	for _, column := range slices.Sorted(maps.Keys(labels)) {
+		if _, found := slices.BinarySearch(columns, column); !found {
+			return fmt.Errorf("header label for unknown column %q", column)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package reporter
+
+// formulaPrefixes are the leading characters that make spreadsheet
+// applications interpret a cell as a formula.
+const formulaPrefixes = "=+-@\t\r"
+
+// sanitizeCSVField neutralizes values that a spreadsheet would evaluate as a
+// formula (CSV injection) by prefixing them with a single quote. Values that
+// do not start with a formula character are returned unchanged.
+func sanitizeCSVField(value string) string {
+	if value == "" {
+		return value
+	}
+	for i := 0; i < len(formulaPrefixes); i++ {
+		if value[0] == formulaPrefixes[i] {
+			return "'" + value
+		}
+	}
+	return value
+}
@@ -0,0 +1,81 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package reporter
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitizeCSVField(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{name: "equals formula", value: "=HYPERLINK(\"http://evil\")", expected: "'=HYPERLINK(\"http://evil\")"},
+		{name: "plus formula", value: "+1+1", expected: "'+1+1"},
+		{name: "minus formula", value: "-2+3", expected: "'-2+3"},
+		{name: "at formula", value: "@SUM(A1:A2)", expected: "'@SUM(A1:A2)"},
+		{name: "leading tab", value: "\t=1", expected: "'\t=1"},
+		{name: "leading carriage return", value: "\r=1", expected: "'\r=1"},
+		{name: "normal file name", value: "Q1 Budget.xlsx", expected: "Q1 Budget.xlsx"},
+		{name: "normal email", value: "user@example.com", expected: "user@example.com"},
+		{name: "formula character later in value", value: "a=b+c", expected: "a=b+c"},
+		{name: "empty", value: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, sanitizeCSVField(tt.value))
+		})
+	}
+}
+
+func TestCSVReporter_SanitizesFormulas(t *testing.T) {
+	tmpDir := t.TempDir()
+	reporter, err := NewCSVReporter(tmpDir)
+	require.NoError(t, err)
+
+	files := []audit.FileRecord{
+		{OwnerEmail: "a@example.com", OwnerName: "@admin", FileID: "1", FileName: "=cmd|' /C calc'!A0"},
+		{OwnerEmail: "b@example.com", OwnerName: "Bob", FileID: "2", FileName: "report.pdf"},
+	}
+	require.NoError(t, reporter.WriteFilesByOwner(files))
+
+	shares := []audit.ExternalShareRecord{
+		{OwnerEmail: "a@example.com", FileID: "1", FileName: "+evil", SharedWithEmail: "-x@partner.com",
+			SharedWithDomain: "partner.com", PermissionType: "user"},
+	}
+	require.NoError(t, reporter.WriteExternalSharing(shares))
+
+	readRows := func(name string) [][]string {
+		file, err := os.Open(filepath.Join(tmpDir, name))
+		require.NoError(t, err)
+		defer file.Close() //nolint:errcheck // test cleanup
+
+		rows, err := csv.NewReader(file).ReadAll()
+		require.NoError(t, err)
+		return rows
+	}
+
+	fileRows := readRows("files_by_owner.csv")
+	require.Len(t, fileRows, 3)
+	assert.Equal(t, "'=cmd|' /C calc'!A0", fileRows[1][2])
+	assert.Equal(t, "'@admin", fileRows[1][7])
+	assert.Equal(t, "report.pdf", fileRows[2][2])
+	assert.Equal(t, "Bob", fileRows[2][7])
+
+	shareRows := readRows("external_sharing.csv")
+	require.Len(t, shareRows, 2)
+	assert.Equal(t, "'+evil", shareRows[1][2])
+	assert.Equal(t, "'-x@partner.com", shareRows[1][3])
+	assert.Equal(t, "partner.com", shareRows[1][4])
+}
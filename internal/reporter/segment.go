@@ -0,0 +1,108 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package reporter
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// ownerKeyed is a record grouped by owner in reports.
+type ownerKeyed interface {
+	OwnerKey() string
+}
+
+// SegmentFileName returns the name of segment n (from 1) of the report
+// file name, e.g. files_by_owner.002.csv for files_by_owner.csv.
+func SegmentFileName(name string, n int) string {
+	ext := filepath.Ext(name)
+	return fmt.Sprintf("%s.%03d%s", strings.TrimSuffix(name, ext), n, ext)
+}
+
+// segmentByOwner splits records, sorted by owner, into segments of at most
+// maxRows records. A segment ends early rather than split an owner's
+// records, unless the owner alone has more than maxRows records.
+func segmentByOwner[T ownerKeyed](records []T, maxRows int) [][]T {
+	var segments [][]T
+	start := 0
+	for i := 0; i < len(records); {
+		end := i + 1
+		for end < len(records) && records[end].OwnerKey() == records[i].OwnerKey() {
+			end++
+		}
+
+		// Start a new segment for an owner that does not fit the current one.
+		if end-start > maxRows && i > start {
+			segments = append(segments, records[start:i])
+			start = i
+		}
+		for end-start > maxRows {
+			segments = append(segments, records[start:start+maxRows])
+			start += maxRows
+		}
+		i = end
+	}
+	if start < len(records) {
+		segments = append(segments, records[start:])
+	}
+	return segments
+}
+
+// writeRecords writes records, already sorted by owner, to the report named
+// base. With opts.MaxRowsPerFile set and exceeded, the records are written
+// to numbered segments, each with the header.
+func writeRecords[T ownerKeyed](r *CSVReporter, base string, header []string, records []T, row func(T) []string) error {
+	name := r.FileName(base)
+	if r.opts.MaxRowsPerFile <= 0 || len(records) <= r.opts.MaxRowsPerFile {
+		if err := writeRows(r, name, header, records, row); err != nil {
+			return err
+		}
+		r.track(name)
+		return nil
+	}
+
+	segments := segmentByOwner(records, r.opts.MaxRowsPerFile)
+	for i, segment := range segments {
+		segmentName := SegmentFileName(name, i+1)
+		if err := writeRows(r, segmentName, header, segment, row); err != nil {
+			return err
+		}
+		r.track(segmentName)
+	}
+	r.segments[base] = len(segments)
+	return nil
+}
+
+// writeRows writes header and one row per record to name, relative to the
+// output directory, in the order given.
+func writeRows[T any](r *CSVReporter, name string, header []string, records []T, row func(T) []string) error {
+	file, err := createAtomic(filepath.Join(r.outputDir, name))
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Abort()
+
+	writer := r.newWriter(file)
+
+	if err := writer.Write(r.opts.labelHeader(header)); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+
+	for i, rec := range records {
+		if err := writer.Write(row(rec)); err != nil {
+			return fmt.Errorf("failed to write record: %w", err)
+		}
+		if err := flushPeriodically(writer, i); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to flush writer: %w", err)
+	}
+
+	return r.checksums.commit(file)
+}
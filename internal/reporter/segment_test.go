@@ -0,0 +1,126 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package reporter
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSegmentFileName(t *testing.T) {
+	assert.Equal(t, "files_by_owner.001.csv", SegmentFileName("files_by_owner.csv", 1))
+	assert.Equal(t, "external_sharing.012.tsv", SegmentFileName("external_sharing.tsv", 12))
+	assert.Equal(t, "report.1000", SegmentFileName("report", 1000))
+}
+
+func TestSegmentByOwner(t *testing.T) {
+	records := func(owners ...string) []audit.FileRecord {
+		out := make([]audit.FileRecord, 0, len(owners))
+		for _, o := range owners {
+			out = append(out, audit.FileRecord{OwnerEmail: o})
+		}
+		return out
+	}
+	owners := func(segments [][]audit.FileRecord) [][]string {
+		out := make([][]string, 0, len(segments))
+		for _, seg := range segments {
+			var keys []string
+			for _, rec := range seg {
+				keys = append(keys, rec.OwnerEmail)
+			}
+			out = append(out, keys)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name    string
+		records []audit.FileRecord
+		maxRows int
+		want    [][]string
+	}{
+		{
+			name:    "owners packed without splitting",
+			records: records("a", "a", "b", "c", "c"),
+			maxRows: 3,
+			want:    [][]string{{"a", "a", "b"}, {"c", "c"}},
+		},
+		{
+			name:    "segment ends early to keep an owner together",
+			records: records("a", "a", "b", "b"),
+			maxRows: 3,
+			want:    [][]string{{"a", "a"}, {"b", "b"}},
+		},
+		{
+			name:    "owner larger than the limit is split",
+			records: records("a", "b", "b", "b", "b", "b", "c"),
+			maxRows: 2,
+			want:    [][]string{{"a"}, {"b", "b"}, {"b", "b"}, {"b", "c"}},
+		},
+		{
+			name:    "empty",
+			records: nil,
+			maxRows: 2,
+			want:    [][]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := segmentByOwner(tt.records, tt.maxRows)
+			for _, seg := range got {
+				assert.LessOrEqual(t, len(seg), tt.maxRows)
+			}
+			assert.Equal(t, tt.want, owners(got))
+		})
+	}
+}
+
+func TestCSVReporter_MaxRowsPerFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	reporter, err := NewCSVReporterWithOptions(tmpDir, Options{MaxRowsPerFile: 2})
+	require.NoError(t, err)
+
+	records := []audit.FileRecord{
+		{OwnerEmail: "carol@example.com", FileID: "5"},
+		{OwnerEmail: "alice@example.com", FileID: "1"},
+		{OwnerEmail: "bob@example.com", FileID: "3"},
+		{OwnerEmail: "alice@example.com", FileID: "2"},
+		{OwnerEmail: "bob@example.com", FileID: "4"},
+	}
+	require.NoError(t, reporter.WriteFilesByOwner(records))
+	require.NoError(t, reporter.WriteExternalSharing(testShareRecords()))
+	require.NoError(t, reporter.WriteManifest(RunMeta{}))
+
+	assert.Equal(t, 3, reporter.Segments("files_by_owner"))
+	assert.Zero(t, reporter.Segments("external_sharing"), "reports within the limit are not split")
+	assert.NoFileExists(t, filepath.Join(tmpDir, "files_by_owner.csv"))
+	assert.FileExists(t, filepath.Join(tmpDir, "external_sharing.csv"))
+
+	wantOwners := []string{"alice@example.com", "bob@example.com", "carol@example.com"}
+	for i, owner := range wantOwners {
+		name := SegmentFileName("files_by_owner.csv", i+1)
+		file, err := os.Open(filepath.Join(tmpDir, name))
+		require.NoError(t, err)
+		rows, err := csv.NewReader(file).ReadAll()
+		require.NoError(t, file.Close())
+		require.NoError(t, err)
+
+		assert.Equal(t, fileRecordHeader(Options{}), rows[0], "%s has a header", name)
+		for _, row := range rows[1:] {
+			assert.Equal(t, owner, row[0], "%s holds a single owner", name)
+		}
+	}
+	assert.NoFileExists(t, filepath.Join(tmpDir, SegmentFileName("files_by_owner.csv", 4)))
+
+	manifest, err := os.ReadFile(filepath.Join(tmpDir, ManifestFileName))
+	require.NoError(t, err)
+	assert.Contains(t, string(manifest), "files_by_owner.003.csv")
+}
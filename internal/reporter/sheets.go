@@ -0,0 +1,284 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package reporter
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+	"google.golang.org/api/sheets/v4"
+)
+
+// sheetsAppendBatch is the number of rows sent per append request, keeping
+// each request well below the Sheets API payload limit.
+const sheetsAppendBatch = 5000
+
+// Spreadsheet identifies a spreadsheet created by a SheetsAPI.
+type Spreadsheet struct {
+	ID  string
+	URL string
+}
+
+// SheetsAPI is the subset of the Google Sheets API used by GSheetReporter.
+type SheetsAPI interface {
+	// CreateSpreadsheet creates a spreadsheet named title with a single
+	// tab named sheet.
+	CreateSpreadsheet(ctx context.Context, title, sheet string) (Spreadsheet, error)
+
+	// AddSheet adds a tab named sheet to the spreadsheet.
+	AddSheet(ctx context.Context, spreadsheetID, sheet string) error
+
+	// AppendRows appends rows to the tab named sheet.
+	AppendRows(ctx context.Context, spreadsheetID, sheet string, rows [][]string) error
+}
+
+// GSheetReporter writes reports as tabs of a single Google Sheets
+// spreadsheet, created on the first write. The manifest is still written to
+// the local output directory.
+type GSheetReporter struct {
+	ctx         context.Context
+	api         SheetsAPI
+	title       string
+	outputDir   string
+	opts        Options
+	spreadsheet *Spreadsheet
+	written     []string
+}
+
+// NewGSheetReporter creates a reporter that writes to a new spreadsheet
+// named title using api.
+func NewGSheetReporter(ctx context.Context, api SheetsAPI, title, outputDir string, opts Options) (*GSheetReporter, error) {
+	if err := os.MkdirAll(outputDir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	// Rows are appended with RAW input, which never evaluates formulas.
+	opts.rawText = true
+	return &GSheetReporter{ctx: ctx, api: api, title: title, outputDir: outputDir, opts: opts}, nil
+}
+
+// WriteFilesByOwner writes the files_by_owner tab.
+func (r *GSheetReporter) WriteFilesByOwner(records []audit.FileRecord) error {
+	r.opts.Order.SortFileRecords(records)
+	rows := make([][]string, 0, len(records))
+	for _, rec := range records {
+		rows = append(rows, fileRecordRow(rec, r.opts))
+	}
+	return r.writeSheet("files_by_owner", fileRecordHeader(r.opts), rows)
+}
+
+// WriteExternalSharing writes the external_sharing tab.
+func (r *GSheetReporter) WriteExternalSharing(records []audit.ExternalShareRecord) error {
+	audit.SortExternalShares(records)
+	rows := make([][]string, 0, len(records))
+	for _, rec := range records {
+		rows = append(rows, externalShareRow(rec, r.opts))
+	}
+	return r.writeSheet("external_sharing", externalShareHeader(r.opts), rows)
+}
+
+// WritePublicShares writes the public_shares tab.
+func (r *GSheetReporter) WritePublicShares(records []audit.ExternalShareRecord) error {
+	audit.SortExternalShares(records)
+	rows := make([][]string, 0, len(records))
+	for _, rec := range records {
+		rows = append(rows, publicShareRow(rec, r.opts))
+	}
+	return r.writeSheet("public_shares", publicShareHeader(r.opts), rows)
+}
+
+// WriteDomainShares writes the domain_shares tab.
+func (r *GSheetReporter) WriteDomainShares(records []audit.ExternalShareRecord) error {
+	audit.SortExternalShares(records)
+	rows := make([][]string, 0, len(records))
+	for _, rec := range records {
+		rows = append(rows, domainShareRow(rec, r.opts))
+	}
+	return r.writeSheet("domain_shares", domainShareHeader(r.opts), rows)
+}
+
+// WriteDuplicates writes the duplicates tab.
+func (r *GSheetReporter) WriteDuplicates(groups []audit.DuplicateGroup) error {
+	rows := make([][]string, 0, len(groups))
+	for _, g := range groups {
+		rows = append(rows, duplicateGroupRow(g, r.opts))
+	}
+	return r.writeSheet("duplicates", duplicateGroupHeader, rows)
+}
+
+// WriteNewShares writes the new_shares tab.
+func (r *GSheetReporter) WriteNewShares(records []audit.ExternalShareRecord) error {
+	audit.SortExternalShares(records)
+	rows := make([][]string, 0, len(records))
+	for _, rec := range records {
+		rows = append(rows, externalShareRow(rec, r.opts))
+	}
+	return r.writeSheet("new_shares", externalShareHeader(r.opts), rows)
+}
+
+// WriteExternalOwners writes the external_owners tab.
+func (r *GSheetReporter) WriteExternalOwners(records []audit.FileRecord) error {
+	r.opts.Order.SortFileRecords(records)
+	rows := make([][]string, 0, len(records))
+	for _, rec := range records {
+		rows = append(rows, fileRecordRow(rec, r.opts))
+	}
+	return r.writeSheet("external_owners", fileRecordHeader(r.opts), rows)
+}
+
+// WriteSharedWithMe writes the shared_with_me tab.
+func (r *GSheetReporter) WriteSharedWithMe(records []audit.FileRecord) error {
+	r.opts.Order.SortFileRecords(records)
+	rows := make([][]string, 0, len(records))
+	for _, rec := range records {
+		rows = append(rows, fileRecordRow(rec, r.opts))
+	}
+	return r.writeSheet("shared_with_me", fileRecordHeader(r.opts), rows)
+}
+
+// WriteOwners writes the owners tab in the order given.
+func (r *GSheetReporter) WriteOwners(summaries []audit.OwnerSummary) error {
+	rows := make([][]string, 0, len(summaries))
+	for _, s := range summaries {
+		rows = append(rows, ownerSummaryRow(s, r.opts))
+	}
+	return r.writeSheet("owners", ownerSummaryHeader, rows)
+}
+
+// WriteRoleDistribution writes the role_distribution tab in the order given.
+func (r *GSheetReporter) WriteRoleDistribution(counts []audit.RoleCount) error {
+	rows := make([][]string, 0, len(counts))
+	for _, c := range counts {
+		rows = append(rows, roleCountRow(c, r.opts))
+	}
+	return r.writeSheet("role_distribution", roleCountHeader, rows)
+}
+
+// WriteManifest writes manifest.json to the output directory, recording the
+// spreadsheet URL instead of report files.
+func (r *GSheetReporter) WriteManifest(meta RunMeta) error {
+	manifest, err := buildManifest(r.outputDir, meta, nil)
+	if err != nil {
+		return err
+	}
+	manifest.SpreadsheetURL = r.URL()
+	manifest.Sheets = r.written
+	return saveManifest(r.outputDir, manifest)
+}
+
+// OutputDir returns the directory the manifest is written to.
+func (r *GSheetReporter) OutputDir() string {
+	return r.outputDir
+}
+
+// FileName returns the tab name for the report named base.
+func (r *GSheetReporter) FileName(base string) string {
+	return base
+}
+
+// URL returns the URL of the spreadsheet, or "" if nothing has been
+// written yet.
+func (r *GSheetReporter) URL() string {
+	if r.spreadsheet == nil {
+		return ""
+	}
+	return r.spreadsheet.URL
+}
+
+// writeSheet writes header and rows to a new tab named name, creating the
+// spreadsheet on first use.
+func (r *GSheetReporter) writeSheet(name string, header []string, rows [][]string) error {
+	if r.spreadsheet == nil {
+		sheet, err := r.api.CreateSpreadsheet(r.ctx, r.title, name)
+		if err != nil {
+			return fmt.Errorf("failed to create spreadsheet: %w", err)
+		}
+		r.spreadsheet = &sheet
+	} else if !r.hasSheet(name) {
+		if err := r.api.AddSheet(r.ctx, r.spreadsheet.ID, name); err != nil {
+			return fmt.Errorf("failed to add sheet %s: %w", name, err)
+		}
+	}
+
+	all := append([][]string{r.opts.labelHeader(header)}, rows...)
+	for start := 0; start < len(all); start += sheetsAppendBatch {
+		end := min(start+sheetsAppendBatch, len(all))
+		if err := r.api.AppendRows(r.ctx, r.spreadsheet.ID, name, all[start:end]); err != nil {
+			return fmt.Errorf("failed to write sheet %s: %w", name, err)
+		}
+	}
+
+	if !r.hasSheet(name) {
+		r.written = append(r.written, name)
+	}
+	return nil
+}
+
+// hasSheet reports whether a tab named name has been written.
+func (r *GSheetReporter) hasSheet(name string) bool {
+	for _, w := range r.written {
+		if w == name {
+			return true
+		}
+	}
+	return false
+}
+
+// sheetsService implements SheetsAPI with the Google Sheets API.
+type sheetsService struct {
+	service *sheets.Service
+}
+
+// NewSheetsAPI returns a SheetsAPI backed by service.
+func NewSheetsAPI(service *sheets.Service) SheetsAPI {
+	return &sheetsService{service: service}
+}
+
+// CreateSpreadsheet creates a spreadsheet with a single tab.
+func (s *sheetsService) CreateSpreadsheet(ctx context.Context, title, sheet string) (Spreadsheet, error) {
+	created, err := s.service.Spreadsheets.Create(&sheets.Spreadsheet{
+		Properties: &sheets.SpreadsheetProperties{Title: title},
+		Sheets: []*sheets.Sheet{
+			{Properties: &sheets.SheetProperties{Title: sheet}},
+		},
+	}).Context(ctx).Do()
+	if err != nil {
+		return Spreadsheet{}, err
+	}
+	return Spreadsheet{ID: created.SpreadsheetId, URL: created.SpreadsheetUrl}, nil
+}
+
+// AddSheet adds a tab to the spreadsheet.
+func (s *sheetsService) AddSheet(ctx context.Context, spreadsheetID, sheet string) error {
+	_, err := s.service.Spreadsheets.BatchUpdate(spreadsheetID, &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{
+			{AddSheet: &sheets.AddSheetRequest{Properties: &sheets.SheetProperties{Title: sheet}}},
+		},
+	}).Context(ctx).Do()
+	return err
+}
+
+// AppendRows appends rows after the last row of the tab. Values are stored
+// as entered so that cell contents are never evaluated as formulas.
+func (s *sheetsService) AppendRows(ctx context.Context, spreadsheetID, sheet string, rows [][]string) error {
+	values := make([][]interface{}, len(rows))
+	for i, row := range rows {
+		values[i] = make([]interface{}, len(row))
+		for j, v := range row {
+			values[i][j] = v
+		}
+	}
+
+	_, err := s.service.Spreadsheets.Values.Append(spreadsheetID, sheetRange(sheet), &sheets.ValueRange{
+		Values: values,
+	}).ValueInputOption("RAW").InsertDataOption("INSERT_ROWS").Context(ctx).Do()
+	return err
+}
+
+// sheetRange returns the A1 range addressing the whole tab named sheet.
+func sheetRange(sheet string) string {
+	return "'" + strings.ReplaceAll(sheet, "'", "''") + "'"
+}
@@ -0,0 +1,139 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package reporter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSheetsAPI records the spreadsheets, tabs and rows written to it.
+type fakeSheetsAPI struct {
+	title     string
+	sheets    []string
+	rows      map[string][][]string
+	createErr error
+	appends   int
+}
+
+func (f *fakeSheetsAPI) CreateSpreadsheet(_ context.Context, title, sheet string) (Spreadsheet, error) {
+	if f.createErr != nil {
+		return Spreadsheet{}, f.createErr
+	}
+	f.title = title
+	f.sheets = append(f.sheets, sheet)
+	return Spreadsheet{ID: "sheet-1", URL: "https://docs.google.com/spreadsheets/d/sheet-1/edit"}, nil
+}
+
+func (f *fakeSheetsAPI) AddSheet(_ context.Context, spreadsheetID, sheet string) error {
+	if spreadsheetID != "sheet-1" {
+		return errors.New("unknown spreadsheet")
+	}
+	f.sheets = append(f.sheets, sheet)
+	return nil
+}
+
+func (f *fakeSheetsAPI) AppendRows(_ context.Context, spreadsheetID, sheet string, rows [][]string) error {
+	if spreadsheetID != "sheet-1" {
+		return errors.New("unknown spreadsheet")
+	}
+	if f.rows == nil {
+		f.rows = make(map[string][][]string)
+	}
+	f.appends++
+	f.rows[sheet] = append(f.rows[sheet], rows...)
+	return nil
+}
+
+func TestGSheetReporter_WritesTabs(t *testing.T) {
+	api := &fakeSheetsAPI{}
+	dir := t.TempDir()
+	rep, err := NewGSheetReporter(context.Background(), api, "audit", dir, Options{})
+	require.NoError(t, err)
+	assert.Empty(t, rep.URL())
+
+	files := []audit.FileRecord{
+		{OwnerEmail: "bob@example.com", FileID: "f2", FileName: "b.txt", FileType: "text/plain", SizeBytes: 2, Location: audit.LocationMyDrive},
+		{OwnerEmail: "alice@example.com", FileID: "f1", FileName: "=cmd", FileType: "text/plain", SizeBytes: 1, Location: audit.LocationMyDrive},
+	}
+	require.NoError(t, rep.WriteFilesByOwner(files))
+
+	shares := []audit.ExternalShareRecord{{
+		OwnerEmail:       "alice@example.com",
+		FileID:           "f1",
+		FileName:         "a.txt",
+		SharedWithEmail:  "ext@other.com",
+		SharedWithDomain: "other.com",
+		PermissionType:   "user",
+		PermissionRole:   "reader",
+		SharedDate:       time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}}
+	require.NoError(t, rep.WriteExternalSharing(shares))
+
+	assert.Equal(t, "audit", api.title)
+	assert.Equal(t, []string{"files_by_owner", "external_sharing"}, api.sheets)
+	assert.Equal(t, "https://docs.google.com/spreadsheets/d/sheet-1/edit", rep.URL())
+
+	assert.Equal(t, [][]string{
+		fileRecordHeader(Options{}),
+		// RAW input never evaluates formulas, so values are written as they are.
+		{"alice@example.com", "f1", "=cmd", "text/plain", "", "", "1", "", "my_drive", "", ""},
+		{"bob@example.com", "f2", "b.txt", "text/plain", "", "", "2", "", "my_drive", "", ""},
+	}, api.rows["files_by_owner"])
+
+	require.Len(t, api.rows["external_sharing"], 2)
+	assert.Equal(t, externalShareHeader(Options{}), api.rows["external_sharing"][0])
+	assert.Equal(t, "ext@other.com", api.rows["external_sharing"][1][3])
+	assert.Equal(t, "2025-01-02T03:04:05Z", api.rows["external_sharing"][1][7])
+
+	require.NoError(t, rep.WriteManifest(RunMeta{Version: "test"}))
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFileName))
+	require.NoError(t, err)
+	var manifest Manifest
+	require.NoError(t, json.Unmarshal(data, &manifest))
+	assert.Equal(t, rep.URL(), manifest.SpreadsheetURL)
+	assert.Equal(t, []string{"files_by_owner", "external_sharing"}, manifest.Sheets)
+	assert.Empty(t, manifest.Files)
+}
+
+func TestGSheetReporter_BatchesRows(t *testing.T) {
+	api := &fakeSheetsAPI{}
+	rep, err := NewGSheetReporter(context.Background(), api, "audit", t.TempDir(), Options{})
+	require.NoError(t, err)
+
+	summaries := make([]audit.OwnerSummary, sheetsAppendBatch)
+	for i := range summaries {
+		summaries[i] = audit.OwnerSummary{OwnerEmail: "owner@example.com", FileCount: 1}
+	}
+	require.NoError(t, rep.WriteOwners(summaries))
+
+	assert.Equal(t, 2, api.appends)
+	assert.Len(t, api.rows["owners"], sheetsAppendBatch+1)
+	assert.Equal(t, ownerSummaryHeader, api.rows["owners"][0])
+}
+
+func TestGSheetReporter_CreateError(t *testing.T) {
+	api := &fakeSheetsAPI{createErr: errors.New("permission denied")}
+	rep, err := NewGSheetReporter(context.Background(), api, "audit", t.TempDir(), Options{})
+	require.NoError(t, err)
+
+	err = rep.WriteRoleDistribution([]audit.RoleCount{{Scope: audit.ScopePublic, Role: "reader", Count: 1}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "permission denied")
+	assert.Empty(t, rep.URL())
+}
+
+func TestSheetRange(t *testing.T) {
+	assert.Equal(t, "'files_by_owner'", sheetRange("files_by_owner"))
+	assert.Equal(t, "'bob''s'", sheetRange("bob's"))
+}
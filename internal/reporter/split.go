@@ -0,0 +1,115 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package reporter
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+)
+
+// filesIndexHeader is the column names of the files_index report.
+var filesIndexHeader = []string{"owner_email", "owner_name", "file_count", "total_bytes", "file"}
+
+// OwnerFilesDir is the directory, relative to the output directory, that
+// holds per-owner files reports when splitting by owner.
+const OwnerFilesDir = "files"
+
+// writeSplitByOwner writes one files report per owner to files/<owner>.csv
+// and an index of those reports to files_index.csv (.tsv for tab-separated
+// output).
+func (r *CSVReporter) writeSplitByOwner(records []audit.FileRecord) error {
+	if err := os.MkdirAll(filepath.Join(r.outputDir, OwnerFilesDir), 0750); err != nil {
+		return fmt.Errorf("failed to create owner files directory: %w", err)
+	}
+
+	groups := r.opts.Order.GroupByOwner(records)
+	used := make(map[string]bool, len(groups))
+	index := make([][]string, 0, len(groups))
+
+	for _, group := range groups {
+		name := path.Join(OwnerFilesDir, uniqueFileName(used, r.FileName(ownerFileName(group[0].OwnerKey()))))
+		if err := r.writeFileRecords(name, group); err != nil {
+			return err
+		}
+		r.track(name)
+
+		summary := audit.SummarizeByOwner(group)[0]
+		index = append(index, []string{
+			sanitizeCSVField(summary.OwnerEmail),
+			sanitizeCSVField(summary.OwnerName),
+			strconv.Itoa(summary.FileCount),
+			strconv.FormatInt(summary.TotalBytes, 10),
+			name,
+		})
+	}
+
+	if err := r.writeOwnerIndex(index); err != nil {
+		return err
+	}
+
+	r.track(r.FileName("files_index"))
+	return nil
+}
+
+// writeOwnerIndex writes the files_index report listing the per-owner reports.
+func (r *CSVReporter) writeOwnerIndex(rows [][]string) error {
+	file, err := createAtomic(filepath.Join(r.outputDir, r.FileName("files_index")))
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Abort()
+
+	writer := r.newWriter(file)
+
+	if err := writer.Write(r.opts.labelHeader(filesIndexHeader)); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return fmt.Errorf("failed to write record: %w", err)
+	}
+
+	return r.checksums.commit(file)
+}
+
+// ownerFileName turns an owner key into a safe file name stem. Letters,
+// digits and "@", ".", "-", "_" are kept; anything else, including path
+// separators, becomes "_". Owners without a usable key map to "unknown".
+func ownerFileName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r - 'A' + 'a'
+		case r == '@', r == '.', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+
+	name = strings.TrimLeft(name, ".")
+	if name == "" {
+		return "unknown"
+	}
+	return name
+}
+
+// uniqueFileName returns name, adding a numeric suffix before the extension
+// when the name was already used, and marks the result as used.
+func uniqueFileName(used map[string]bool, name string) string {
+	ext := path.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	for i := 2; used[name]; i++ {
+		name = stem + "-" + strconv.Itoa(i) + ext
+	}
+	used[name] = true
+	return name
+}
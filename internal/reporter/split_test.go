@@ -0,0 +1,113 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package reporter
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSVReporter_SplitByOwner(t *testing.T) {
+	dir := t.TempDir()
+	rep, err := NewCSVReporterWithOptions(dir, Options{SplitByOwner: true})
+	require.NoError(t, err)
+
+	records := []audit.FileRecord{
+		{OwnerEmail: "bob@example.com", FileID: "1", FileName: "b.txt", SizeBytes: 10},
+		{OwnerEmail: "alice@example.com", FileID: "2", FileName: "a.txt", SizeBytes: 5},
+		{OwnerEmail: "bob@example.com", FileID: "3", FileName: "a.txt", SizeBytes: 20},
+		{OwnerName: "Former Employee", FileID: "4", FileName: "c.txt"},
+	}
+	require.NoError(t, rep.WriteFilesByOwner(records))
+
+	assert.NoFileExists(t, filepath.Join(dir, "files_by_owner.csv"))
+
+	bob := readCSVFile(t, filepath.Join(dir, "files", "bob@example.com.csv"))
+	require.Len(t, bob, 3)
+	assert.Equal(t, "owner_email", bob[0][0])
+	assert.Equal(t, []string{"3", "1"}, []string{bob[1][1], bob[2][1]})
+
+	alice := readCSVFile(t, filepath.Join(dir, "files", "alice@example.com.csv"))
+	require.Len(t, alice, 2)
+	assert.Equal(t, "2", alice[1][1])
+
+	assert.FileExists(t, filepath.Join(dir, "files", "display_former_employee.csv"))
+
+	index := readCSVFile(t, filepath.Join(dir, "files_index.csv"))
+	assert.Equal(t, [][]string{
+		{"owner_email", "owner_name", "file_count", "total_bytes", "file"},
+		{"alice@example.com", "", "1", "5", "files/alice@example.com.csv"},
+		{"bob@example.com", "", "2", "30", "files/bob@example.com.csv"},
+		{"", "Former Employee", "1", "0", "files/display_former_employee.csv"},
+	}, index)
+
+	assert.Equal(t, []string{
+		"files/alice@example.com.csv",
+		"files/bob@example.com.csv",
+		"files/display_former_employee.csv",
+		"files_index.csv",
+	}, rep.written)
+}
+
+func TestCSVReporter_SplitByOwner_Collisions(t *testing.T) {
+	dir := t.TempDir()
+	rep, err := NewCSVReporterWithOptions(dir, Options{SplitByOwner: true})
+	require.NoError(t, err)
+
+	records := []audit.FileRecord{
+		{OwnerEmail: "a/b@example.com", FileID: "1"},
+		{OwnerEmail: "a\\b@example.com", FileID: "2"},
+	}
+	require.NoError(t, rep.WriteFilesByOwner(records))
+
+	entries, err := os.ReadDir(filepath.Join(dir, "files"))
+	require.NoError(t, err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	assert.ElementsMatch(t, []string{"a_b@example.com.csv", "a_b@example.com-2.csv"}, names)
+}
+
+func TestOwnerFileName(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{key: "alice@example.com", want: "alice@example.com"},
+		{key: "Alice.Smith@Example.com", want: "alice.smith@example.com"},
+		{key: "../../etc/passwd", want: "_.._etc_passwd"},
+		{key: `a\b/c@example.com`, want: "a_b_c@example.com"},
+		{key: "display:Jane Doe", want: "display_jane_doe"},
+		{key: "..", want: "unknown"},
+		{key: "", want: "unknown"},
+		{key: "josé@example.com", want: "jos_@example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got := ownerFileName(tt.key)
+			assert.Equal(t, tt.want, got)
+			assert.NotContains(t, got, "/")
+			assert.NotContains(t, got, `\`)
+		})
+	}
+}
+
+func readCSVFile(t *testing.T, path string) [][]string {
+	t.Helper()
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close() //nolint:errcheck // test cleanup
+
+	rows, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+	return rows
+}
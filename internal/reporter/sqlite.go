@@ -0,0 +1,381 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package reporter
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+
+	_ "modernc.org/sqlite" // registers the pure-Go "sqlite" driver
+)
+
+// DefaultSQLiteFileName is the database written to the output directory by
+// the sqlite format when output.file is not set.
+const DefaultSQLiteFileName = "gwork.db"
+
+// sqliteSchema creates the tables of the audit database. Every row belongs
+// to a run, so databases accumulate the results of successive audits.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS runs (
+	run_id      INTEGER PRIMARY KEY AUTOINCREMENT,
+	started_at  TEXT NOT NULL,
+	version     TEXT NOT NULL DEFAULT '',
+	domain      TEXT NOT NULL DEFAULT '',
+	config_file TEXT NOT NULL DEFAULT '',
+	filters     TEXT NOT NULL DEFAULT '{}'
+);
+
+CREATE TABLE IF NOT EXISTS files (
+	run_id               INTEGER NOT NULL REFERENCES runs(run_id),
+	report               TEXT NOT NULL,
+	owner_email          TEXT NOT NULL,
+	owner_name           TEXT NOT NULL,
+	file_id              TEXT NOT NULL,
+	file_name            TEXT NOT NULL,
+	file_type            TEXT NOT NULL,
+	created_time         TEXT,
+	modified_time        TEXT,
+	size_bytes           INTEGER NOT NULL,
+	trashed              INTEGER NOT NULL,
+	location             TEXT NOT NULL,
+	drive_name           TEXT NOT NULL,
+	viewed_by_me_time    TEXT,
+	link_sharing_enabled INTEGER,
+	source_domain        TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS files_owner_email ON files(owner_email);
+CREATE INDEX IF NOT EXISTS files_file_id ON files(file_id);
+
+CREATE TABLE IF NOT EXISTS shares (
+	run_id               INTEGER NOT NULL REFERENCES runs(run_id),
+	report               TEXT NOT NULL,
+	owner_email          TEXT NOT NULL,
+	owner_name           TEXT NOT NULL,
+	file_id              TEXT NOT NULL,
+	file_name            TEXT NOT NULL,
+	shared_with_email    TEXT NOT NULL,
+	shared_with_domain   TEXT NOT NULL,
+	permission_type      TEXT NOT NULL,
+	permission_role      TEXT NOT NULL,
+	permission_id        TEXT NOT NULL,
+	inherited            INTEGER NOT NULL,
+	inherited_from       TEXT NOT NULL,
+	expiration_time      TEXT,
+	web_view_link        TEXT NOT NULL,
+	location             TEXT NOT NULL,
+	drive_name           TEXT NOT NULL,
+	grantee_deleted      INTEGER NOT NULL,
+	trashed              INTEGER NOT NULL,
+	domain_wide          INTEGER NOT NULL,
+	flagged              INTEGER NOT NULL,
+	flag_reason          TEXT NOT NULL,
+	explanation          TEXT NOT NULL,
+	label                TEXT NOT NULL,
+	risk                 INTEGER NOT NULL,
+	group_member_count   INTEGER NOT NULL,
+	has_external_members INTEGER NOT NULL,
+	source_domain        TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS shares_owner_email ON shares(owner_email);
+CREATE INDEX IF NOT EXISTS shares_file_id ON shares(file_id);
+
+CREATE TABLE IF NOT EXISTS owners (
+	run_id      INTEGER NOT NULL REFERENCES runs(run_id),
+	owner_email TEXT NOT NULL,
+	owner_name  TEXT NOT NULL,
+	file_count  INTEGER NOT NULL,
+	total_bytes INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS owners_owner_email ON owners(owner_email);
+
+CREATE TABLE IF NOT EXISTS duplicates (
+	run_id      INTEGER NOT NULL REFERENCES runs(run_id),
+	group_id    INTEGER NOT NULL,
+	owner_email TEXT NOT NULL,
+	owner_name  TEXT NOT NULL,
+	file_name   TEXT NOT NULL,
+	size_bytes  INTEGER NOT NULL,
+	file_id     TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS duplicates_file_id ON duplicates(file_id);
+
+CREATE TABLE IF NOT EXISTS role_distribution (
+	run_id INTEGER NOT NULL REFERENCES runs(run_id),
+	scope  TEXT NOT NULL,
+	role   TEXT NOT NULL,
+	count  INTEGER NOT NULL
+);
+`
+
+// SQLiteReporter writes all reports of a run into one SQLite database,
+// adding a row to the runs table and tagging every record with its run_id.
+// The database is opened for each write, so the reporter needs no Close.
+type SQLiteReporter struct {
+	outputDir string
+	name      string
+	runID     int64
+	written   bool
+}
+
+// NewSQLiteReporter creates a reporter that writes to a SQLite database in
+// outputDir. The database is named DefaultSQLiteFileName unless
+// opts.FileNames overrides it; all reports share the one database.
+func NewSQLiteReporter(outputDir string, opts Options) (*SQLiteReporter, error) {
+	if err := os.MkdirAll(outputDir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	name := DefaultSQLiteFileName
+	for _, override := range opts.FileNames {
+		name = override
+	}
+	return &SQLiteReporter{outputDir: outputDir, name: name}, nil
+}
+
+// WriteFilesByOwner inserts the files-by-owner records.
+func (r *SQLiteReporter) WriteFilesByOwner(records []audit.FileRecord) error {
+	return r.insertFiles("files_by_owner", records)
+}
+
+// WriteExternalSharing inserts the external sharing records.
+func (r *SQLiteReporter) WriteExternalSharing(records []audit.ExternalShareRecord) error {
+	return r.insertShares("external_sharing", records)
+}
+
+// WritePublicShares inserts the public share records.
+func (r *SQLiteReporter) WritePublicShares(records []audit.ExternalShareRecord) error {
+	return r.insertShares("public_shares", records)
+}
+
+// WriteDomainShares inserts the domain-wide share records.
+func (r *SQLiteReporter) WriteDomainShares(records []audit.ExternalShareRecord) error {
+	return r.insertShares("domain_shares", records)
+}
+
+// WriteDuplicates inserts one row per file of each duplicate group,
+// numbering the groups from 1 within the run.
+func (r *SQLiteReporter) WriteDuplicates(groups []audit.DuplicateGroup) error {
+	return r.withTx(func(tx *sql.Tx) error {
+		stmt, err := tx.Prepare(`INSERT INTO duplicates (run_id, group_id, owner_email, owner_name, file_name, size_bytes, file_id) VALUES (?, ?, ?, ?, ?, ?, ?)`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close() //nolint:errcheck // closed with the transaction
+
+		for i, g := range groups {
+			for _, id := range g.FileIDs {
+				if _, err := stmt.Exec(r.runID, i+1, g.OwnerEmail, g.OwnerName, g.FileName, g.SizeBytes, id); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// WriteNewShares inserts the share records that are new since the last
+// run.
+func (r *SQLiteReporter) WriteNewShares(records []audit.ExternalShareRecord) error {
+	return r.insertShares("new_shares", records)
+}
+
+// WriteExternalOwners inserts the externally owned file records.
+func (r *SQLiteReporter) WriteExternalOwners(records []audit.FileRecord) error {
+	return r.insertFiles("external_owners", records)
+}
+
+// WriteSharedWithMe inserts the records of files shared with the user.
+func (r *SQLiteReporter) WriteSharedWithMe(records []audit.FileRecord) error {
+	return r.insertFiles("shared_with_me", records)
+}
+
+// WriteOwners inserts the owner summaries.
+func (r *SQLiteReporter) WriteOwners(summaries []audit.OwnerSummary) error {
+	return r.withTx(func(tx *sql.Tx) error {
+		stmt, err := tx.Prepare(`INSERT INTO owners (run_id, owner_email, owner_name, file_count, total_bytes) VALUES (?, ?, ?, ?, ?)`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close() //nolint:errcheck // closed with the transaction
+
+		for _, s := range summaries {
+			if _, err := stmt.Exec(r.runID, s.OwnerEmail, s.OwnerName, s.FileCount, s.TotalBytes); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// WriteRoleDistribution inserts the share counts by scope and role.
+func (r *SQLiteReporter) WriteRoleDistribution(counts []audit.RoleCount) error {
+	return r.withTx(func(tx *sql.Tx) error {
+		stmt, err := tx.Prepare(`INSERT INTO role_distribution (run_id, scope, role, count) VALUES (?, ?, ?, ?)`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close() //nolint:errcheck // closed with the transaction
+
+		for _, c := range counts {
+			if _, err := stmt.Exec(r.runID, c.Scope, c.Role, c.Count); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// WriteManifest records meta in the run's row and writes manifest.json
+// listing the database.
+func (r *SQLiteReporter) WriteManifest(meta RunMeta) error {
+	files, sums, err := r.manifestFiles(meta)
+	if err != nil {
+		return err
+	}
+	return writeManifest(r.outputDir, meta, files, sums)
+}
+
+// manifestFiles records meta in the run's row and returns the database file.
+func (r *SQLiteReporter) manifestFiles(meta RunMeta) ([]string, checksums, error) {
+	filters, err := json.Marshal(meta.Filters)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode filters: %w", err)
+	}
+	if meta.Filters == nil {
+		filters = []byte("{}")
+	}
+
+	err = r.withTx(func(tx *sql.Tx) error {
+		_, err := tx.Exec(`UPDATE runs SET version = ?, domain = ?, config_file = ?, filters = ? WHERE run_id = ?`,
+			meta.Version, meta.Domain, meta.ConfigFile, string(filters), r.runID)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return []string{r.name}, nil, nil
+}
+
+// OutputDir returns the output directory path.
+func (r *SQLiteReporter) OutputDir() string {
+	return r.outputDir
+}
+
+// FileName returns the database file name, which holds every report.
+func (r *SQLiteReporter) FileName(string) string {
+	return r.name
+}
+
+// RunID returns the run_id of the rows written by this reporter, or zero
+// before the first write.
+func (r *SQLiteReporter) RunID() int64 {
+	return r.runID
+}
+
+// insertFiles inserts file records tagged with report.
+func (r *SQLiteReporter) insertFiles(report string, records []audit.FileRecord) error {
+	return r.withTx(func(tx *sql.Tx) error {
+		stmt, err := tx.Prepare(`INSERT INTO files (run_id, report, owner_email, owner_name, file_id, file_name,
+			file_type, created_time, modified_time, size_bytes, trashed, location, drive_name, viewed_by_me_time,
+			link_sharing_enabled, source_domain) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close() //nolint:errcheck // closed with the transaction
+
+		for _, rec := range records {
+			var linkSharing sql.NullBool
+			if rec.LinkSharingEnabled != nil {
+				linkSharing = sql.NullBool{Bool: *rec.LinkSharingEnabled, Valid: true}
+			}
+			if _, err := stmt.Exec(r.runID, report, rec.OwnerEmail, rec.OwnerName, rec.FileID, rec.FileName,
+				rec.FileType, sqliteTime(rec.CreatedTime), sqliteTime(rec.ModifiedTime), rec.SizeBytes,
+				rec.Trashed, rec.Location, rec.DriveName, sqliteTime(rec.ViewedByMeTime), linkSharing, rec.SourceDomain); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// insertShares inserts share records tagged with report.
+func (r *SQLiteReporter) insertShares(report string, records []audit.ExternalShareRecord) error {
+	return r.withTx(func(tx *sql.Tx) error {
+		stmt, err := tx.Prepare(`INSERT INTO shares (run_id, report, owner_email, owner_name, file_id, file_name,
+			shared_with_email, shared_with_domain, permission_type, permission_role, permission_id, inherited,
+			inherited_from, expiration_time, web_view_link, location, drive_name, grantee_deleted, trashed, domain_wide,
+			flagged, flag_reason, explanation, label, risk, group_member_count, has_external_members, source_domain)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close() //nolint:errcheck // closed with the transaction
+
+		for _, rec := range records {
+			if _, err := stmt.Exec(r.runID, report, rec.OwnerEmail, rec.OwnerName, rec.FileID, rec.FileName,
+				rec.SharedWithEmail, rec.SharedWithDomain, rec.PermissionType, rec.PermissionRole, rec.PermissionID,
+				rec.Inherited, rec.InheritedFrom, sqliteTime(rec.ExpirationTime), rec.WebViewLink, rec.Location, rec.DriveName, rec.GranteeDeleted, rec.Trashed,
+				rec.DomainWide, rec.Flagged, rec.FlagReason, rec.Explanation, rec.Label, rec.Risk,
+				rec.GroupMemberCount, rec.HasExternalMembers, rec.SourceDomain); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// withTx opens the database, creates the schema and the run's row on
+// first use, and runs fn in a transaction that is committed when fn
+// succeeds.
+func (r *SQLiteReporter) withTx(fn func(tx *sql.Tx) error) error {
+	db, err := sql.Open("sqlite", filepath.Join(r.outputDir, r.name))
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close() //nolint:errcheck // writes are committed below
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after Commit
+
+	if !r.written {
+		if _, err := tx.Exec(sqliteSchema); err != nil {
+			return fmt.Errorf("failed to create database schema: %w", err)
+		}
+		res, err := tx.Exec(`INSERT INTO runs (started_at) VALUES (?)`, time.Now().UTC().Format(time.RFC3339))
+		if err != nil {
+			return fmt.Errorf("failed to record run: %w", err)
+		}
+		if r.runID, err = res.LastInsertId(); err != nil {
+			return fmt.Errorf("failed to record run: %w", err)
+		}
+	}
+
+	if err := fn(tx); err != nil {
+		return fmt.Errorf("failed to write records: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to write records: %w", err)
+	}
+
+	r.written = true
+	return nil
+}
+
+// sqliteTime formats t as RFC3339 in UTC, or NULL for the zero time.
+func sqliteTime(t time.Time) sql.NullString {
+	if t.IsZero() {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: t.UTC().Format(timestampLayout), Valid: true}
+}
@@ -0,0 +1,129 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package reporter
+
+import (
+	"database/sql"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSQLiteRun(t *testing.T, dir string) *SQLiteReporter {
+	t.Helper()
+
+	rep, err := New(FormatSQLite, dir, Options{})
+	require.NoError(t, err)
+	sqliteRep := rep.(*SQLiteReporter)
+
+	linkShared := true
+	require.NoError(t, rep.WriteFilesByOwner([]audit.FileRecord{
+		{OwnerEmail: "alice@example.com", FileID: "f1", FileName: "a.txt", SizeBytes: 10,
+			ModifiedTime: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC), LinkSharingEnabled: &linkShared},
+		{OwnerEmail: "bob@example.com", FileID: "f2", FileName: "b.txt"},
+	}))
+	require.NoError(t, rep.WriteExternalSharing([]audit.ExternalShareRecord{
+		{OwnerEmail: "alice@example.com", FileID: "f1", PermissionType: "user", SharedWithEmail: "guest@partner.com"},
+		{OwnerEmail: "alice@example.com", FileID: "f1", PermissionType: "anyone", Risk: 90},
+	}))
+	require.NoError(t, rep.WritePublicShares([]audit.ExternalShareRecord{
+		{OwnerEmail: "alice@example.com", FileID: "f1", PermissionType: "anyone", Risk: 90},
+	}))
+	require.NoError(t, rep.WriteOwners([]audit.OwnerSummary{{OwnerEmail: "alice@example.com", FileCount: 1, TotalBytes: 10}}))
+	require.NoError(t, rep.WriteManifest(RunMeta{
+		Version: "1.2.3", Domain: "example.com", Filters: map[string]string{"owner_domain": "example.com"},
+	}))
+	return sqliteRep
+}
+
+func countRows(t *testing.T, db *sql.DB, query string, args ...any) int {
+	t.Helper()
+	var n int
+	require.NoError(t, db.QueryRow(query, args...).Scan(&n))
+	return n
+}
+
+func TestSQLiteReporter(t *testing.T) {
+	dir := t.TempDir()
+	rep := writeSQLiteRun(t, dir)
+	assert.Equal(t, int64(1), rep.RunID())
+	assert.Equal(t, DefaultSQLiteFileName, rep.FileName("external_sharing"))
+
+	db, err := sql.Open("sqlite", filepath.Join(dir, DefaultSQLiteFileName))
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // test cleanup
+
+	assert.Equal(t, 2, countRows(t, db, `SELECT COUNT(*) FROM files WHERE report = 'files_by_owner'`))
+	assert.Equal(t, 2, countRows(t, db, `SELECT COUNT(*) FROM shares WHERE report = 'external_sharing'`))
+	assert.Equal(t, 1, countRows(t, db, `SELECT COUNT(*) FROM shares WHERE report = 'public_shares'`))
+	assert.Equal(t, 1, countRows(t, db, `SELECT COUNT(*) FROM owners`))
+
+	var version, domain, filters string
+	require.NoError(t, db.QueryRow(`SELECT version, domain, filters FROM runs WHERE run_id = 1`).Scan(&version, &domain, &filters))
+	assert.Equal(t, "1.2.3", version)
+	assert.Equal(t, "example.com", domain)
+	assert.JSONEq(t, `{"owner_domain":"example.com"}`, filters)
+
+	var modified sql.NullString
+	var linkSharing sql.NullBool
+	require.NoError(t, db.QueryRow(`SELECT modified_time, link_sharing_enabled FROM files WHERE file_id = 'f1'`).Scan(&modified, &linkSharing))
+	assert.Equal(t, "2025-03-01T12:00:00Z", modified.String)
+	assert.True(t, linkSharing.Valid && linkSharing.Bool)
+	require.NoError(t, db.QueryRow(`SELECT modified_time, link_sharing_enabled FROM files WHERE file_id = 'f2'`).Scan(&modified, &linkSharing))
+	assert.False(t, modified.Valid, "zero times are stored as NULL")
+	assert.False(t, linkSharing.Valid)
+
+	var indexes []string
+	rows, err := db.Query(`SELECT name FROM sqlite_master WHERE type = 'index' AND name NOT LIKE 'sqlite_%' ORDER BY name`)
+	require.NoError(t, err)
+	for rows.Next() {
+		var name string
+		require.NoError(t, rows.Scan(&name))
+		indexes = append(indexes, name)
+	}
+	require.NoError(t, rows.Err())
+	assert.Equal(t, []string{"duplicates_file_id", "files_file_id", "files_owner_email", "owners_owner_email", "shares_file_id", "shares_owner_email"}, indexes)
+
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFileName))
+	require.NoError(t, err)
+	var manifest Manifest
+	require.NoError(t, json.Unmarshal(data, &manifest))
+	require.Len(t, manifest.Files, 1)
+	assert.Equal(t, DefaultSQLiteFileName, manifest.Files[0].Path)
+}
+
+func TestSQLiteReporter_RunsAccumulate(t *testing.T) {
+	dir := t.TempDir()
+	first := writeSQLiteRun(t, dir)
+	second := writeSQLiteRun(t, dir)
+	assert.Equal(t, int64(1), first.RunID())
+	assert.Equal(t, int64(2), second.RunID())
+
+	db, err := sql.Open("sqlite", filepath.Join(dir, DefaultSQLiteFileName))
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck // test cleanup
+
+	assert.Equal(t, 2, countRows(t, db, `SELECT COUNT(*) FROM runs`))
+	assert.Equal(t, 4, countRows(t, db, `SELECT COUNT(*) FROM files`))
+	assert.Equal(t, 2, countRows(t, db, `SELECT COUNT(*) FROM files WHERE run_id = ?`, second.RunID()))
+	assert.Equal(t, 3, countRows(t, db, `SELECT COUNT(*) FROM shares WHERE run_id = ?`, second.RunID()))
+}
+
+func TestSQLiteReporter_FileNameOverride(t *testing.T) {
+	dir := t.TempDir()
+	rep, err := NewSQLiteReporter(dir, Options{FileNames: map[string]string{"external_sharing": "audit.db"}})
+	require.NoError(t, err)
+
+	require.NoError(t, rep.WriteExternalSharing(nil))
+	require.NoError(t, rep.WritePublicShares(nil))
+	assert.Equal(t, "audit.db", rep.FileName("public_shares"))
+	assert.FileExists(t, filepath.Join(dir, "audit.db"))
+	assert.NoFileExists(t, filepath.Join(dir, DefaultSQLiteFileName))
+}
@@ -0,0 +1,207 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package reporter
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+)
+
+// csvStream is a CSV report written in pieces and published atomically.
+type csvStream struct {
+	reporter *CSVReporter
+	name     string
+	file     *atomicFile
+	writer   *csv.Writer
+	rows     int
+}
+
+// startStream creates the report base in the output directory and writes
+// header.
+func (r *CSVReporter) startStream(base string, header []string) (*csvStream, error) {
+	name := r.FileName(base)
+	file, err := createAtomic(filepath.Join(r.outputDir, name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file: %w", err)
+	}
+
+	writer := r.newWriter(file)
+	if err := writer.Write(r.opts.labelHeader(header)); err != nil {
+		file.Abort()
+		return nil, fmt.Errorf("failed to write header: %w", err)
+	}
+
+	return &csvStream{reporter: r, name: name, file: file, writer: writer}, nil
+}
+
+// writeRow appends row to the report.
+func (s *csvStream) writeRow(row []string) error {
+	if err := s.writer.Write(row); err != nil {
+		return fmt.Errorf("failed to write record: %w", err)
+	}
+	if err := flushPeriodically(s.writer, s.rows); err != nil {
+		return err
+	}
+	s.rows++
+	return nil
+}
+
+// Commit flushes the report and moves it into place.
+func (s *csvStream) Commit() error {
+	s.writer.Flush()
+	if err := s.writer.Error(); err != nil {
+		return fmt.Errorf("failed to flush writer: %w", err)
+	}
+	if err := s.reporter.checksums.commit(s.file); err != nil {
+		return err
+	}
+	s.reporter.track(s.name)
+	return nil
+}
+
+// Abort discards the report unless it was committed.
+func (s *csvStream) Abort() {
+	s.file.Abort()
+}
+
+// FileRecordStream writes the files-by-owner CSV in chunks, for audits that
+// hold one owner's files at a time. Rows are written in the order given.
+// Commit publishes the report; Abort discards it.
+type FileRecordStream struct {
+	*csvStream
+}
+
+// StreamFilesByOwner starts a files-by-owner CSV and writes its header.
+func (r *CSVReporter) StreamFilesByOwner() (*FileRecordStream, error) {
+	if r.opts.SplitByOwner {
+		return nil, errors.New("streaming the files report is not supported with split by owner")
+	}
+
+	stream, err := r.startStream("files_by_owner", fileRecordHeader(r.opts))
+	if err != nil {
+		return nil, err
+	}
+	return &FileRecordStream{stream}, nil
+}
+
+// Write appends records to the report.
+func (s *FileRecordStream) Write(records []audit.FileRecord) error {
+	for _, rec := range records {
+		if err := s.writeRow(fileRecordRow(rec, s.reporter.opts)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ShareRecordStream writes the external-sharing CSV one record at a time,
+// for audits that stream their shares. Rows are written in the order given
+// rather than sorted. Commit publishes the report; Abort discards it.
+type ShareRecordStream struct {
+	*csvStream
+}
+
+// StreamExternalSharing starts an external-sharing CSV and writes its
+// header.
+func (r *CSVReporter) StreamExternalSharing() (*ShareRecordStream, error) {
+	stream, err := r.startStream("external_sharing", externalShareHeader(r.opts))
+	if err != nil {
+		return nil, err
+	}
+	return &ShareRecordStream{stream}, nil
+}
+
+// Write appends rec to the report.
+func (s *ShareRecordStream) Write(rec audit.ExternalShareRecord) error {
+	return s.writeRow(externalShareRow(rec, s.reporter.opts))
+}
+
+// ShareRecordAppender appends rows to the external-sharing CSV in place,
+// for audits resumed from a checkpoint, which keep the rows written before
+// the interruption. Rows are written in the order given rather than sorted.
+// Close adds the report to the manifest.
+type ShareRecordAppender struct {
+	reporter *CSVReporter
+	name     string
+	file     *os.File
+	writer   *csv.Writer
+}
+
+// AppendExternalSharing opens the external-sharing CSV to append to it,
+// first cutting it to size to drop the rows written after the checkpoint
+// that recorded size. A size of 0 starts a new report with its header.
+func (r *CSVReporter) AppendExternalSharing(size int64) (_ *ShareRecordAppender, err error) {
+	if r.opts.MaxRowsPerFile > 0 {
+		return nil, errors.New("appending to the external sharing report is not supported with max rows per file")
+	}
+
+	name := r.FileName("external_sharing")
+	file, err := os.OpenFile(filepath.Join(r.outputDir, name), os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = file.Close()
+		}
+	}()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+	if info.Size() < size {
+		return nil, fmt.Errorf("report %s is shorter than recorded by the checkpoint", name)
+	}
+	if err := file.Truncate(size); err != nil {
+		return nil, fmt.Errorf("failed to truncate file: %w", err)
+	}
+	if _, err := file.Seek(size, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to seek file: %w", err)
+	}
+
+	writer := r.newWriter(file)
+	if size == 0 {
+		if err := writer.Write(r.opts.labelHeader(externalShareHeader(r.opts))); err != nil {
+			return nil, fmt.Errorf("failed to write header: %w", err)
+		}
+	}
+	return &ShareRecordAppender{reporter: r, name: name, file: file, writer: writer}, nil
+}
+
+// Write appends records to the report and returns its size once they are
+// written to the file.
+func (a *ShareRecordAppender) Write(records []audit.ExternalShareRecord) (int64, error) {
+	for _, rec := range records {
+		if err := a.writer.Write(externalShareRow(rec, a.reporter.opts)); err != nil {
+			return 0, fmt.Errorf("failed to write record: %w", err)
+		}
+	}
+	a.writer.Flush()
+	if err := a.writer.Error(); err != nil {
+		return 0, fmt.Errorf("failed to flush writer: %w", err)
+	}
+	return a.file.Seek(0, io.SeekCurrent)
+}
+
+// Close closes the report and adds it to the manifest; its checksum is
+// computed from the file.
+func (a *ShareRecordAppender) Close() error {
+	a.writer.Flush()
+	if err := a.writer.Error(); err != nil {
+		_ = a.file.Close()
+		return fmt.Errorf("failed to flush writer: %w", err)
+	}
+	if err := a.file.Close(); err != nil {
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+	a.reporter.track(a.name)
+	return nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package reporter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSVReporter_StreamFilesByOwner(t *testing.T) {
+	records := []audit.FileRecord{
+		{OwnerEmail: "alice@example.com", FileID: "a1", FileName: "alpha.doc"},
+		{OwnerEmail: "alice@example.com", FileID: "a2", FileName: "zeta.doc"},
+		{OwnerEmail: "bob@example.com", FileID: "b1", FileName: "notes.txt"},
+	}
+
+	wholeDir := t.TempDir()
+	whole, err := NewCSVReporter(wholeDir)
+	require.NoError(t, err)
+	require.NoError(t, whole.WriteFilesByOwner(records))
+
+	streamDir := t.TempDir()
+	rep, err := NewCSVReporter(streamDir)
+	require.NoError(t, err)
+	stream, err := rep.StreamFilesByOwner()
+	require.NoError(t, err)
+	require.NoError(t, stream.Write(records[:2]))
+
+	_, err = os.Stat(filepath.Join(streamDir, "files_by_owner.csv"))
+	assert.True(t, os.IsNotExist(err), "the report is not published before Commit")
+
+	require.NoError(t, stream.Write(records[2:]))
+	require.NoError(t, stream.Commit())
+
+	want, err := os.ReadFile(filepath.Join(wholeDir, "files_by_owner.csv"))
+	require.NoError(t, err)
+	got, err := os.ReadFile(filepath.Join(streamDir, "files_by_owner.csv"))
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+	assert.Equal(t, []string{"files_by_owner.csv"}, rep.written)
+	assertNoTempFiles(t, streamDir)
+}
+
+func TestCSVReporter_StreamFilesByOwner_Abort(t *testing.T) {
+	tmpDir := t.TempDir()
+	rep, err := NewCSVReporter(tmpDir)
+	require.NoError(t, err)
+
+	stream, err := rep.StreamFilesByOwner()
+	require.NoError(t, err)
+	require.NoError(t, stream.Write([]audit.FileRecord{{FileID: "a1"}}))
+	stream.Abort()
+
+	_, err = os.Stat(filepath.Join(tmpDir, "files_by_owner.csv"))
+	assert.True(t, os.IsNotExist(err))
+	assertNoTempFiles(t, tmpDir)
+}
+
+func TestCSVReporter_StreamExternalSharing(t *testing.T) {
+	records := []audit.ExternalShareRecord{
+		{OwnerEmail: "bob@example.com", FileID: "b1", FileName: "notes.txt", SharedWithEmail: "x@partner.com", PermissionType: "user", PermissionRole: "reader"},
+		{OwnerEmail: "alice@example.com", FileID: "a1", FileName: "alpha.doc", PermissionType: "anyone", PermissionRole: "reader"},
+	}
+
+	tmpDir := t.TempDir()
+	rep, err := NewCSVReporter(tmpDir)
+	require.NoError(t, err)
+	stream, err := rep.StreamExternalSharing()
+	require.NoError(t, err)
+	defer stream.Abort()
+	for _, rec := range records {
+		require.NoError(t, stream.Write(rec))
+	}
+	require.NoError(t, stream.Commit())
+
+	rows := readCSVFile(t, filepath.Join(tmpDir, "external_sharing.csv"))
+	require.Len(t, rows, 3)
+	assert.Equal(t, externalShareHeader(Options{}), rows[0])
+	assert.Equal(t, "b1", rows[1][1], "rows are written in the order given")
+	assert.Equal(t, "a1", rows[2][1])
+	assert.Equal(t, []string{"external_sharing.csv"}, rep.written)
+	assertNoTempFiles(t, tmpDir)
+}
+
+func TestCSVReporter_AppendExternalSharing(t *testing.T) {
+	records := []audit.ExternalShareRecord{
+		{OwnerEmail: "bob@example.com", FileID: "b1", PermissionType: "user", PermissionRole: "reader"},
+		{OwnerEmail: "alice@example.com", FileID: "a1", PermissionType: "anyone", PermissionRole: "reader"},
+		{OwnerEmail: "carol@example.com", FileID: "c1", PermissionType: "domain", PermissionRole: "writer"},
+	}
+
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "external_sharing.csv")
+	rep, err := NewCSVReporter(tmpDir)
+	require.NoError(t, err)
+	report, err := rep.AppendExternalSharing(0)
+	require.NoError(t, err)
+	size, err := report.Write(records[:1])
+	require.NoError(t, err)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, info.Size(), size, "rows reach the file before Write returns")
+
+	// Rows written after the checkpoint recorded size are dropped.
+	_, err = report.Write(records[1:2])
+	require.NoError(t, err)
+	require.NoError(t, report.Close())
+
+	rep, err = NewCSVReporter(tmpDir)
+	require.NoError(t, err)
+	report, err = rep.AppendExternalSharing(size)
+	require.NoError(t, err)
+	_, err = report.Write(records[2:])
+	require.NoError(t, err)
+	require.NoError(t, report.Close())
+
+	rows := readCSVFile(t, path)
+	require.Len(t, rows, 3)
+	assert.Equal(t, externalShareHeader(Options{}), rows[0])
+	assert.Equal(t, "b1", rows[1][1])
+	assert.Equal(t, "c1", rows[2][1])
+	assert.Equal(t, []string{"external_sharing.csv"}, rep.written)
+
+	// A report shorter than the checkpoint records cannot be resumed.
+	_, err = rep.AppendExternalSharing(info.Size() * 10)
+	assert.ErrorContains(t, err, "shorter than recorded by the checkpoint")
+}
@@ -0,0 +1,34 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package reporter
+
+import (
+	"fmt"
+	"os"
+)
+
+// CheckWritable verifies that dir exists or can be created and that files
+// can be written to it, by creating and removing a temporary file.
+func CheckWritable(dir string) error {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	file, err := os.CreateTemp(dir, ".gwork-write-check-*")
+	if err != nil {
+		return fmt.Errorf("output directory %s is not writable: %w", dir, err)
+	}
+
+	name := file.Name()
+	if err := file.Close(); err != nil {
+		_ = os.Remove(name)
+		return fmt.Errorf("output directory %s is not writable: %w", dir, err)
+	}
+
+	if err := os.Remove(name); err != nil {
+		return fmt.Errorf("failed to remove write check file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package reporter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckWritable(t *testing.T) {
+	t.Run("writable directory", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, CheckWritable(dir))
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, entries, "the write check file should be removed")
+	})
+
+	t.Run("non-existent but creatable directory", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "reports", "2025")
+		require.NoError(t, CheckWritable(dir))
+		assert.DirExists(t, dir)
+	})
+
+	t.Run("parent is a file", func(t *testing.T) {
+		parent := filepath.Join(t.TempDir(), "file")
+		require.NoError(t, os.WriteFile(parent, []byte("x"), 0o600))
+
+		err := CheckWritable(filepath.Join(parent, "reports"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to create output directory")
+	})
+
+	t.Run("read-only directory", func(t *testing.T) {
+		if os.Geteuid() == 0 {
+			t.Skip("permission bits are not enforced for root")
+		}
+
+		dir := t.TempDir()
+		require.NoError(t, os.Chmod(dir, 0o500))
+		t.Cleanup(func() { _ = os.Chmod(dir, 0o700) })
+
+		err := CheckWritable(dir)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is not writable")
+	})
+}
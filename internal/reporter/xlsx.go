@@ -0,0 +1,254 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package reporter
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+)
+
+// XLSXReporter generates Excel workbooks, one per report, each with a single
+// worksheet named after the report.
+type XLSXReporter struct {
+	outputDir string
+	opts      Options
+	written   []string
+	checksums checksums
+}
+
+// NewXLSXReporter creates a new reporter that writes .xlsx workbooks.
+func NewXLSXReporter(outputDir string, opts Options) (*XLSXReporter, error) {
+	if err := os.MkdirAll(outputDir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	// Inline strings are never evaluated as formulas.
+	opts.rawText = true
+	return &XLSXReporter{outputDir: outputDir, opts: opts}, nil
+}
+
+// WriteFilesByOwner generates the files-by-owner workbook.
+func (r *XLSXReporter) WriteFilesByOwner(records []audit.FileRecord) error {
+	r.opts.Order.SortFileRecords(records)
+	rows := make([][]string, 0, len(records))
+	for _, rec := range records {
+		rows = append(rows, fileRecordRow(rec, r.opts))
+	}
+	return r.writeWorkbook("files_by_owner", fileRecordHeader(r.opts), rows)
+}
+
+// WriteExternalSharing generates the external-sharing workbook.
+func (r *XLSXReporter) WriteExternalSharing(records []audit.ExternalShareRecord) error {
+	audit.SortExternalShares(records)
+	rows := make([][]string, 0, len(records))
+	for _, rec := range records {
+		rows = append(rows, externalShareRow(rec, r.opts))
+	}
+	return r.writeWorkbook("external_sharing", externalShareHeader(r.opts), rows)
+}
+
+// WritePublicShares generates the public-shares workbook.
+func (r *XLSXReporter) WritePublicShares(records []audit.ExternalShareRecord) error {
+	audit.SortExternalShares(records)
+	rows := make([][]string, 0, len(records))
+	for _, rec := range records {
+		rows = append(rows, publicShareRow(rec, r.opts))
+	}
+	return r.writeWorkbook("public_shares", publicShareHeader(r.opts), rows)
+}
+
+// WriteDomainShares generates the domain-wide shares workbook.
+func (r *XLSXReporter) WriteDomainShares(records []audit.ExternalShareRecord) error {
+	audit.SortExternalShares(records)
+	rows := make([][]string, 0, len(records))
+	for _, rec := range records {
+		rows = append(rows, domainShareRow(rec, r.opts))
+	}
+	return r.writeWorkbook("domain_shares", domainShareHeader(r.opts), rows)
+}
+
+// WriteDuplicates generates the duplicates workbook.
+func (r *XLSXReporter) WriteDuplicates(groups []audit.DuplicateGroup) error {
+	rows := make([][]string, 0, len(groups))
+	for _, g := range groups {
+		rows = append(rows, duplicateGroupRow(g, r.opts))
+	}
+	return r.writeWorkbook("duplicates", duplicateGroupHeader, rows)
+}
+
+// WriteNewShares generates the new-shares workbook.
+func (r *XLSXReporter) WriteNewShares(records []audit.ExternalShareRecord) error {
+	audit.SortExternalShares(records)
+	rows := make([][]string, 0, len(records))
+	for _, rec := range records {
+		rows = append(rows, externalShareRow(rec, r.opts))
+	}
+	return r.writeWorkbook("new_shares", externalShareHeader(r.opts), rows)
+}
+
+// WriteExternalOwners generates the external-owners workbook.
+func (r *XLSXReporter) WriteExternalOwners(records []audit.FileRecord) error {
+	r.opts.Order.SortFileRecords(records)
+	rows := make([][]string, 0, len(records))
+	for _, rec := range records {
+		rows = append(rows, fileRecordRow(rec, r.opts))
+	}
+	return r.writeWorkbook("external_owners", fileRecordHeader(r.opts), rows)
+}
+
+// WriteSharedWithMe generates the shared-with-me workbook.
+func (r *XLSXReporter) WriteSharedWithMe(records []audit.FileRecord) error {
+	r.opts.Order.SortFileRecords(records)
+	rows := make([][]string, 0, len(records))
+	for _, rec := range records {
+		rows = append(rows, fileRecordRow(rec, r.opts))
+	}
+	return r.writeWorkbook("shared_with_me", fileRecordHeader(r.opts), rows)
+}
+
+// WriteOwners generates the owners workbook in the order given.
+func (r *XLSXReporter) WriteOwners(summaries []audit.OwnerSummary) error {
+	rows := make([][]string, 0, len(summaries))
+	for _, s := range summaries {
+		rows = append(rows, ownerSummaryRow(s, r.opts))
+	}
+	return r.writeWorkbook("owners", ownerSummaryHeader, rows)
+}
+
+// WriteRoleDistribution generates the role distribution workbook in the
+// order given.
+func (r *XLSXReporter) WriteRoleDistribution(counts []audit.RoleCount) error {
+	rows := make([][]string, 0, len(counts))
+	for _, c := range counts {
+		rows = append(rows, roleCountRow(c, r.opts))
+	}
+	return r.writeWorkbook("role_distribution", roleCountHeader, rows)
+}
+
+// WriteManifest writes manifest.json listing the reports written by this reporter.
+func (r *XLSXReporter) WriteManifest(meta RunMeta) error {
+	return writeManifest(r.outputDir, meta, r.written, r.checksums)
+}
+
+// manifestFiles returns the reports written so far and their checksums.
+func (r *XLSXReporter) manifestFiles(RunMeta) ([]string, checksums, error) {
+	return r.written, r.checksums, nil
+}
+
+// OutputDir returns the output directory path.
+func (r *XLSXReporter) OutputDir() string {
+	return r.outputDir
+}
+
+// FileName returns the report file name for base, e.g. base.xlsx.
+func (r *XLSXReporter) FileName(base string) string {
+	if name, ok := r.opts.FileNames[base]; ok {
+		return name
+	}
+	return FileName(FormatXLSX, base)
+}
+
+// writeWorkbook writes a workbook with a single worksheet named base.
+func (r *XLSXReporter) writeWorkbook(base string, header []string, rows [][]string) error {
+	name := r.FileName(base)
+	file, err := createAtomic(filepath.Join(r.outputDir, name))
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Abort()
+
+	zw := zip.NewWriter(file)
+	for _, part := range []struct{ name, content string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, base)},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	} {
+		w, err := zw.Create(part.name)
+		if err != nil {
+			return fmt.Errorf("failed to write workbook: %w", err)
+		}
+		if _, err := io.WriteString(w, part.content); err != nil {
+			return fmt.Errorf("failed to write workbook: %w", err)
+		}
+	}
+
+	w, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return fmt.Errorf("failed to write workbook: %w", err)
+	}
+	if err := writeWorksheet(w, r.opts.labelHeader(header), rows); err != nil {
+		return fmt.Errorf("failed to write record: %w", err)
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to write workbook: %w", err)
+	}
+	if err := r.checksums.commit(file); err != nil {
+		return err
+	}
+
+	r.written = append(r.written, name)
+	return nil
+}
+
+// writeWorksheet writes a worksheet holding header and rows as inline
+// strings, so values are never evaluated as formulas.
+func writeWorksheet(w io.Writer, header []string, rows [][]string) error {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	for i, row := range append([][]string{header}, rows...) {
+		buf.WriteString("<row>")
+		for _, v := range row {
+			buf.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+			if err := xml.EscapeText(&buf, []byte(v)); err != nil {
+				return err
+			}
+			buf.WriteString("</t></is></c>")
+		}
+		buf.WriteString("</row>")
+
+		// Hand rows to the zip writer in chunks to bound memory.
+		if i%flushEvery == 0 {
+			if _, err := w.Write(buf.Bytes()); err != nil {
+				return err
+			}
+			buf.Reset()
+		}
+	}
+
+	buf.WriteString("</sheetData></worksheet>")
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// Static parts of a single-sheet workbook.
+const (
+	xlsxContentTypes = xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`
+
+	xlsxRootRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+
+	xlsxWorkbook = xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+	xlsxWorkbookRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`
+)
@@ -0,0 +1,142 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package reporter
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readXLSXRows returns the cell values of the first worksheet in path.
+func readXLSXRows(t *testing.T, path string) [][]string {
+	t.Helper()
+
+	zr, err := zip.OpenReader(path)
+	require.NoError(t, err)
+	defer zr.Close() //nolint:errcheck // test cleanup
+
+	sheet, err := zr.Open("xl/worksheets/sheet1.xml")
+	require.NoError(t, err)
+	data, err := io.ReadAll(sheet)
+	require.NoError(t, err)
+
+	var ws struct {
+		Rows []struct {
+			Cells []struct {
+				Text string `xml:"is>t"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	require.NoError(t, xml.Unmarshal(data, &ws))
+
+	rows := make([][]string, len(ws.Rows))
+	for i, row := range ws.Rows {
+		for _, c := range row.Cells {
+			rows[i] = append(rows[i], c.Text)
+		}
+	}
+	return rows
+}
+
+func TestXLSXReporter_WriteOwners(t *testing.T) {
+	tmpDir := t.TempDir()
+	rep, err := NewXLSXReporter(tmpDir, Options{})
+	require.NoError(t, err)
+
+	require.NoError(t, rep.WriteOwners([]audit.OwnerSummary{
+		{OwnerEmail: "alice@example.com", OwnerName: "Alice <Ops> & Co", FileCount: 2, TotalBytes: 10},
+		{OwnerEmail: "bob@example.com", FileCount: 1},
+	}))
+
+	assert.Equal(t, "owners.xlsx", rep.FileName("owners"))
+	rows := readXLSXRows(t, filepath.Join(tmpDir, "owners.xlsx"))
+	assert.Equal(t, [][]string{
+		ownerSummaryHeader,
+		{"alice@example.com", "Alice <Ops> & Co", "2", "10"},
+		{"bob@example.com", "", "1", "0"},
+	}, rows)
+
+	zr, err := zip.OpenReader(filepath.Join(tmpDir, "owners.xlsx"))
+	require.NoError(t, err)
+	defer zr.Close() //nolint:errcheck // test cleanup
+	names := make([]string, 0, len(zr.File))
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	assert.ElementsMatch(t, []string{
+		"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml",
+		"xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml",
+	}, names)
+	assertNoTempFiles(t, tmpDir)
+}
+
+func TestXLSXReporter_WritesFormulaCharactersUnchanged(t *testing.T) {
+	tmpDir := t.TempDir()
+	rep, err := NewXLSXReporter(tmpDir, Options{})
+	require.NoError(t, err)
+
+	require.NoError(t, rep.WriteFilesByOwner([]audit.FileRecord{
+		{OwnerEmail: "alice@example.com", FileID: "f1", FileName: "=cmd", OwnerName: "-Ops"},
+	}))
+
+	// Inline strings are never evaluated, so no quote is added.
+	rows := readXLSXRows(t, filepath.Join(tmpDir, "files_by_owner.xlsx"))
+	require.Len(t, rows, 2)
+	assert.Equal(t, "=cmd", rows[1][2])
+	assert.Equal(t, "-Ops", rows[1][7])
+}
+
+func TestXLSXReporter_ManifestAndFileNames(t *testing.T) {
+	tmpDir := t.TempDir()
+	rep, err := NewXLSXReporter(tmpDir, Options{FileNames: map[string]string{"external_sharing": "q3.xlsx"}})
+	require.NoError(t, err)
+
+	require.NoError(t, rep.WriteExternalSharing([]audit.ExternalShareRecord{
+		{OwnerEmail: "alice@example.com", FileID: "1", FileName: "a.txt", SharedWithEmail: "x@partner.com"},
+	}))
+	require.NoError(t, rep.WriteManifest(RunMeta{}))
+
+	rows := readXLSXRows(t, filepath.Join(tmpDir, "q3.xlsx"))
+	require.Len(t, rows, 2)
+	assert.Equal(t, externalShareHeader(Options{}), rows[0])
+	assert.Equal(t, "x@partner.com", rows[1][3])
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, ManifestFileName))
+	require.NoError(t, err)
+	var manifest Manifest
+	require.NoError(t, json.Unmarshal(data, &manifest))
+	require.Len(t, manifest.Files, 1)
+	assert.Equal(t, "q3.xlsx", manifest.Files[0].Path)
+}
+
+func TestNew_FormatsAndFileNames(t *testing.T) {
+	tests := []struct {
+		format   string
+		wantType Reporter
+		wantName string
+	}{
+		{format: FormatCSV, wantType: &CSVReporter{}, wantName: "report.out"},
+		{format: FormatJSON, wantType: &JSONReporter{}, wantName: "report.out"},
+		{format: FormatXLSX, wantType: &XLSXReporter{}, wantName: "report.out"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			rep, err := New(tt.format, t.TempDir(), Options{FileNames: map[string]string{"owners": "report.out"}})
+			require.NoError(t, err)
+			assert.IsType(t, tt.wantType, rep)
+			assert.Equal(t, tt.wantName, rep.FileName("owners"))
+			assert.NotEqual(t, tt.wantName, rep.FileName("public_shares"), "other reports keep their names")
+		})
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// HTTPSink POSTs payloads as JSON to a URL.
+type HTTPSink struct {
+	url     string
+	headers http.Header
+	client  *http.Client
+}
+
+// NewHTTPSink creates a new HTTPSink. Headers use the "Name: value" form.
+func NewHTTPSink(rawURL string, headers []string) (*HTTPSink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid post URL: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid post URL %q: must be an absolute http or https URL", rawURL)
+	}
+
+	h := make(http.Header)
+	for _, header := range headers {
+		name, value, err := ParseHeader(header)
+		if err != nil {
+			return nil, err
+		}
+		h.Add(name, value)
+	}
+
+	return &HTTPSink{
+		url:     rawURL,
+		headers: h,
+		client:  http.DefaultClient,
+	}, nil
+}
+
+// ParseHeader splits a "Name: value" header into its name and value.
+func ParseHeader(header string) (string, string, error) {
+	name, value, ok := strings.Cut(header, ":")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" {
+		return "", "", fmt.Errorf("invalid header %q: expected \"Name: value\"", header)
+	}
+	return name, strings.TrimSpace(value), nil
+}
+
+// Send POSTs the payload as JSON. The request is bound to ctx, so a context
+// deadline limits the whole exchange. Non-2xx responses are returned as errors.
+func (s *HTTPSink) Send(ctx context.Context, payload *Payload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	for name, values := range s.headers {
+		for _, v := range values {
+			req.Header.Add(name, v)
+		}
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post results: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // body is drained below
+
+	// Drain a bounded amount so the connection can be reused.
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("post to %s failed: %s: %s", s.url, resp.Status, strings.TrimSpace(string(snippet)))
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPSink_Send(t *testing.T) {
+	var gotHeaders http.Header
+	var gotMethod string
+	var gotPayload Payload
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotHeaders = r.Header.Clone()
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(body, &gotPayload))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	s, err := NewHTTPSink(server.URL, []string{"Authorization: Bearer secret", "X-Team:  security "})
+	require.NoError(t, err)
+
+	result := &audit.AuditResult{
+		TotalFiles:          10,
+		FilesProcessed:      9,
+		TotalExternalShares: 2,
+		ExternalShares: []audit.ExternalShareRecord{
+			{FileID: "1", PermissionType: "anyone"},
+			{FileID: "2", PermissionType: "user", SharedWithEmail: "guest@other.com"},
+		},
+	}
+
+	require.NoError(t, s.Send(context.Background(), NewPayload("example.com", true, result)))
+
+	assert.Equal(t, http.MethodPost, gotMethod)
+	assert.Equal(t, "Bearer secret", gotHeaders.Get("Authorization"))
+	assert.Equal(t, "security", gotHeaders.Get("X-Team"))
+	assert.Equal(t, "application/json", gotHeaders.Get("Content-Type"))
+
+	assert.Equal(t, "example.com", gotPayload.Domain)
+	assert.Equal(t, Summary{TotalFiles: 10, FilesProcessed: 9, ExternalShares: 2, PublicShares: 1}, gotPayload.Summary)
+	require.Len(t, gotPayload.ExternalShares, 2)
+	assert.Equal(t, "guest@other.com", gotPayload.ExternalShares[1].SharedWithEmail)
+}
+
+func TestHTTPSink_SendSummaryOnly(t *testing.T) {
+	var raw map[string]json.RawMessage
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&raw))
+	}))
+	defer server.Close()
+
+	s, err := NewHTTPSink(server.URL, nil)
+	require.NoError(t, err)
+
+	result := &audit.AuditResult{
+		TotalFiles:     1,
+		FileRecords:    []audit.FileRecord{{FileID: "1"}},
+		ExternalShares: []audit.ExternalShareRecord{{FileID: "1"}},
+	}
+	require.NoError(t, s.Send(context.Background(), NewPayload("example.com", false, result)))
+
+	assert.Contains(t, raw, "summary")
+	assert.NotContains(t, raw, "files")
+	assert.NotContains(t, raw, "external_shares")
+}
+
+func TestHTTPSink_SendNon2xx(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "token expired", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	s, err := NewHTTPSink(server.URL, nil)
+	require.NoError(t, err)
+
+	err = s.Send(context.Background(), NewPayload("example.com", false))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "401")
+	assert.Contains(t, err.Error(), "token expired")
+}
+
+func TestHTTPSink_SendRespectsContext(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	s, err := NewHTTPSink(server.URL, nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err = s.Send(ctx, NewPayload("example.com", false))
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestNewHTTPSink_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		headers []string
+		wantErr string
+	}{
+		{name: "relative URL", url: "/hook", wantErr: "invalid post URL"},
+		{name: "unsupported scheme", url: "ftp://example.com/hook", wantErr: "invalid post URL"},
+		{name: "header without colon", url: "https://example.com", headers: []string{"Authorization"}, wantErr: "invalid header"},
+		{name: "header without name", url: "https://example.com", headers: []string{": value"}, wantErr: "invalid header"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewHTTPSink(tt.url, tt.headers)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package sink delivers audit results to external systems.
+package sink
+
+import (
+	"context"
+	"time"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+)
+
+// Sink delivers an audit payload to an external system.
+type Sink interface {
+	Send(ctx context.Context, payload *Payload) error
+}
+
+// Payload is the JSON document sent to a sink after an audit.
+type Payload struct {
+	Timestamp      time.Time                   `json:"timestamp"`
+	Domain         string                      `json:"domain"`
+	Summary        Summary                     `json:"summary"`
+	Files          []audit.FileRecord          `json:"files,omitempty"`
+	ExternalShares []audit.ExternalShareRecord `json:"external_shares,omitempty"`
+}
+
+// Summary holds the totals of an audit run.
+type Summary struct {
+	TotalFiles     int `json:"total_files"`
+	FilesProcessed int `json:"files_processed"`
+	ExternalShares int `json:"external_shares"`
+	PublicShares   int `json:"public_shares"`
+	Errors         int `json:"errors"`
+}
+
+// NewPayload builds a payload from one or more audit results. Records are
+// only included when includeRecords is set; the summary is always present.
+func NewPayload(domain string, includeRecords bool, results ...*audit.AuditResult) *Payload {
+	payload := &Payload{
+		Timestamp: time.Now().UTC(),
+		Domain:    domain,
+	}
+
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+
+		// The results of audit all cover the same listing, so files are
+		// counted once rather than per result.
+		payload.Summary.TotalFiles = max(payload.Summary.TotalFiles, result.TotalFiles)
+		payload.Summary.FilesProcessed = max(payload.Summary.FilesProcessed, result.FilesProcessed)
+		payload.Summary.ExternalShares += result.TotalExternalShares
+		payload.Summary.PublicShares += audit.CountPublicShares(result.ExternalShares)
+		payload.Summary.Errors += result.ErrorCount()
+
+		if includeRecords {
+			payload.Files = append(payload.Files, result.FileRecords...)
+			payload.ExternalShares = append(payload.ExternalShares, result.ExternalShares...)
+		}
+	}
+
+	return payload
+}
@@ -0,0 +1,23 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package sink
+
+import (
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPayload_CountsSharedListingOnce(t *testing.T) {
+	// audit all's files and sharing results cover the same 10 files.
+	files := &audit.AuditResult{TotalFiles: 10, FilesProcessed: 10}
+	sharing := &audit.AuditResult{TotalFiles: 10, FilesProcessed: 9, TotalExternalShares: 3}
+
+	payload := NewPayload("example.com", false, files, sharing)
+
+	assert.Equal(t, 10, payload.Summary.TotalFiles)
+	assert.Equal(t, 10, payload.Summary.FilesProcessed)
+	assert.Equal(t, 3, payload.Summary.ExternalShares)
+}
@@ -0,0 +1,28 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package tui
+
+import (
+	"encoding/base64"
+	"net/url"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+)
+
+// FileLink returns the web link of the shared file: the webViewLink Drive
+// returned, or an open link built from the file ID when the report has
+// none.
+func FileLink(rec audit.ExternalShareRecord) string {
+	if rec.WebViewLink != "" {
+		return rec.WebViewLink
+	}
+	return "https://drive.google.com/open?id=" + url.QueryEscape(rec.FileID)
+}
+
+// CopySequence returns the OSC 52 escape sequence that asks the terminal to
+// put text on the system clipboard. It also works over SSH, where gwork
+// cannot reach the local clipboard itself.
+func CopySequence(text string) string {
+	return "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\a"
+}
@@ -0,0 +1,54 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package tui
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+)
+
+// LoadShares reads the share records of a report written by gwork in the
+// json or ndjson format, such as external_sharing.json. CSV reports leave
+// out fields the browser shows, so they are not supported.
+func LoadShares(path string) ([]audit.ExternalShareRecord, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open report: %w", err)
+	}
+	defer file.Close() //nolint:errcheck // read-only
+
+	var records []audit.ExternalShareRecord
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		if err := json.NewDecoder(file).Decode(&records); err != nil {
+			return nil, fmt.Errorf("failed to read report: %w", err)
+		}
+	case ".ndjson":
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		for line := 1; scanner.Scan(); line++ {
+			if strings.TrimSpace(scanner.Text()) == "" {
+				continue
+			}
+			var rec audit.ExternalShareRecord
+			if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+				return nil, fmt.Errorf("failed to read report line %d: %w", line, err)
+			}
+			records = append(records, rec)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read report: %w", err)
+		}
+	default:
+		return nil, errors.New("unsupported report: use a .json or .ndjson sharing report")
+	}
+	return records, nil
+}
@@ -0,0 +1,41 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+	"github.com/leansecurity-co/gwork/internal/reporter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadShares(t *testing.T) {
+	for _, format := range []string{reporter.FormatJSON, reporter.FormatNDJSON} {
+		t.Run(format, func(t *testing.T) {
+			dir := t.TempDir()
+			rep, err := reporter.New(format, dir, reporter.Options{})
+			require.NoError(t, err)
+			require.NoError(t, rep.WriteExternalSharing(testShares()))
+
+			records, err := LoadShares(filepath.Join(dir, rep.FileName("external_sharing")))
+			require.NoError(t, err)
+			want := testShares()
+			audit.SortExternalShares(want)
+			assert.Equal(t, want, records)
+		})
+	}
+}
+
+func TestLoadShares_Unsupported(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "external_sharing.csv")
+	require.NoError(t, os.WriteFile(path, []byte("owner_email\n"), 0o600))
+
+	_, err := LoadShares(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), ".json or .ndjson")
+}
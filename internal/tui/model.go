@@ -0,0 +1,218 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package tui implements gwork tui, an interactive browser for the shares
+// found by a sharing audit. Model holds the view state, filtering and
+// selection, independently of how it is drawn.
+package tui
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+)
+
+// Model is the state of the share browser: the loaded shares, the active
+// filters and the cursor over the shares that pass them.
+type Model struct {
+	all  []audit.ExternalShareRecord
+	rows []audit.ExternalShareRecord
+
+	minRisk int
+	owner   string
+	grantee string
+
+	cursor int
+	offset int
+	height int
+	detail bool
+}
+
+// NewModel returns a model over records showing height rows per page.
+// Records are listed riskiest first, then in report order.
+func NewModel(records []audit.ExternalShareRecord, height int) *Model {
+	all := slices.Clone(records)
+	audit.SortExternalShares(all)
+	slices.SortStableFunc(all, func(a, b audit.ExternalShareRecord) int {
+		return cmp.Compare(b.Risk, a.Risk)
+	})
+
+	m := &Model{all: all, height: max(height, 1)}
+	m.refilter()
+	return m
+}
+
+// Rows returns the shares that pass the filters.
+func (m *Model) Rows() []audit.ExternalShareRecord {
+	return m.rows
+}
+
+// Total returns the number of shares loaded, filtered or not.
+func (m *Model) Total() int {
+	return len(m.all)
+}
+
+// Page returns the rows on the current page and the index of the first.
+func (m *Model) Page() ([]audit.ExternalShareRecord, int) {
+	end := min(m.offset+m.height, len(m.rows))
+	return m.rows[m.offset:end], m.offset
+}
+
+// Cursor returns the index of the selected row in Rows.
+func (m *Model) Cursor() int {
+	return m.cursor
+}
+
+// Selected returns the selected share, or false when no share passes the
+// filters.
+func (m *Model) Selected() (audit.ExternalShareRecord, bool) {
+	if len(m.rows) == 0 {
+		return audit.ExternalShareRecord{}, false
+	}
+	return m.rows[m.cursor], true
+}
+
+// SetHeight changes the number of rows per page, keeping the cursor on the
+// page.
+func (m *Model) SetHeight(height int) {
+	m.height = max(height, 1)
+	m.setCursor(m.cursor)
+}
+
+// Move moves the cursor by delta rows, stopping at the first and last, and
+// scrolls so it stays on the page.
+func (m *Model) Move(delta int) {
+	m.setCursor(m.cursor + delta)
+}
+
+// PageDown moves the cursor one page down.
+func (m *Model) PageDown() {
+	m.Move(m.height)
+}
+
+// PageUp moves the cursor one page up.
+func (m *Model) PageUp() {
+	m.Move(-m.height)
+}
+
+// ToggleDetail switches between the table and the details of the selected
+// share.
+func (m *Model) ToggleDetail() {
+	m.detail = !m.detail && len(m.rows) > 0
+}
+
+// Detail reports whether the details of the selected share are shown.
+func (m *Model) Detail() bool {
+	return m.detail
+}
+
+// SetMinRisk shows only shares with a risk score of at least risk. Zero
+// shows every share.
+func (m *Model) SetMinRisk(risk int) {
+	m.minRisk = risk
+	m.refilter()
+}
+
+// SetOwner shows only shares of files whose owner email or name contains
+// owner, ignoring case. Empty clears the filter.
+func (m *Model) SetOwner(owner string) {
+	m.owner = strings.ToLower(strings.TrimSpace(owner))
+	m.refilter()
+}
+
+// SetGrantee shows only shares whose grantee email or domain contains
+// grantee, ignoring case; "anyone" matches public shares. Empty clears the
+// filter.
+func (m *Model) SetGrantee(grantee string) {
+	m.grantee = strings.ToLower(strings.TrimSpace(grantee))
+	m.refilter()
+}
+
+// ClearFilters shows every share again.
+func (m *Model) ClearFilters() {
+	m.minRisk, m.owner, m.grantee = 0, "", ""
+	m.refilter()
+}
+
+// Filters describes the active filters, e.g. "risk>=2 owner~alice", or
+// returns "" when there are none.
+func (m *Model) Filters() string {
+	var parts []string
+	if m.minRisk > 0 {
+		parts = append(parts, "risk>="+strconv.Itoa(m.minRisk))
+	}
+	if m.owner != "" {
+		parts = append(parts, "owner~"+m.owner)
+	}
+	if m.grantee != "" {
+		parts = append(parts, "grantee~"+m.grantee)
+	}
+	return strings.Join(parts, " ")
+}
+
+// matches reports whether rec passes the active filters.
+func (m *Model) matches(rec audit.ExternalShareRecord) bool {
+	if rec.Risk < m.minRisk {
+		return false
+	}
+	if m.owner != "" && !containsFold(m.owner, rec.OwnerEmail, rec.OwnerName) {
+		return false
+	}
+	if m.grantee != "" {
+		public := m.grantee == "anyone" && rec.PermissionType == "anyone"
+		if !public && !containsFold(m.grantee, rec.SharedWithEmail, rec.SharedWithDomain) {
+			return false
+		}
+	}
+	return true
+}
+
+// refilter recomputes the visible rows, keeping the selected share selected
+// when it still passes the filters.
+func (m *Model) refilter() {
+	selected, hadSelection := m.Selected()
+
+	m.rows = m.rows[:0:0]
+	cursor, found := 0, false
+	for _, rec := range m.all {
+		if !m.matches(rec) {
+			continue
+		}
+		if hadSelection && !found && rec == selected {
+			cursor, found = len(m.rows), true
+		}
+		m.rows = append(m.rows, rec)
+	}
+
+	m.offset = 0
+	m.setCursor(cursor)
+	if len(m.rows) == 0 {
+		m.detail = false
+	}
+}
+
+// setCursor moves the cursor to i, clamped to the rows, and scrolls so it
+// stays on the page.
+func (m *Model) setCursor(i int) {
+	m.cursor = max(min(i, len(m.rows)-1), 0)
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+m.height {
+		m.offset = m.cursor - m.height + 1
+	}
+}
+
+// containsFold reports whether any of values contains the lowercase
+// substring sub, ignoring case.
+func containsFold(sub string, values ...string) bool {
+	for _, v := range values {
+		if strings.Contains(strings.ToLower(v), sub) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package tui
+
+import (
+	"testing"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testShares() []audit.ExternalShareRecord {
+	return []audit.ExternalShareRecord{
+		{FileID: "1", FileName: "roadmap", OwnerEmail: "alice@example.com", OwnerName: "Alice",
+			PermissionType: "anyone", Risk: 2},
+		{FileID: "2", FileName: "budget", OwnerEmail: "bob@example.com",
+			PermissionType: "user", SharedWithEmail: "eve@rival.com", SharedWithDomain: "rival.com", Risk: 3},
+		{FileID: "3", FileName: "notes", OwnerEmail: "alice@example.com", OwnerName: "Alice",
+			PermissionType: "user", SharedWithEmail: "pat@partner.com", SharedWithDomain: "partner.com", Risk: 1},
+		{FileID: "4", FileName: "plan", OwnerEmail: "carol@example.com",
+			PermissionType: "domain", SharedWithDomain: "partner.com", Risk: 1},
+	}
+}
+
+// fileIDs returns the file IDs of records in order.
+func fileIDs(records []audit.ExternalShareRecord) []string {
+	ids := make([]string, 0, len(records))
+	for _, rec := range records {
+		ids = append(ids, rec.FileID)
+	}
+	return ids
+}
+
+func TestModel_Filters(t *testing.T) {
+	tests := []struct {
+		name        string
+		apply       func(m *Model)
+		wantIDs     []string
+		wantFilters string
+	}{
+		{name: "none, riskiest first", apply: func(*Model) {}, wantIDs: []string{"2", "1", "3", "4"}},
+		{name: "min risk", apply: func(m *Model) { m.SetMinRisk(2) }, wantIDs: []string{"2", "1"}, wantFilters: "risk>=2"},
+		{name: "owner email", apply: func(m *Model) { m.SetOwner("ALICE@") }, wantIDs: []string{"1", "3"}, wantFilters: "owner~alice@"},
+		{name: "owner name", apply: func(m *Model) { m.SetOwner("alice") }, wantIDs: []string{"1", "3"}, wantFilters: "owner~alice"},
+		{name: "grantee email", apply: func(m *Model) { m.SetGrantee("eve@") }, wantIDs: []string{"2"}, wantFilters: "grantee~eve@"},
+		{name: "grantee domain", apply: func(m *Model) { m.SetGrantee("Partner.com") }, wantIDs: []string{"3", "4"}, wantFilters: "grantee~partner.com"},
+		{name: "grantee anyone", apply: func(m *Model) { m.SetGrantee("anyone") }, wantIDs: []string{"1"}, wantFilters: "grantee~anyone"},
+		{
+			name:        "combined",
+			apply:       func(m *Model) { m.SetOwner("alice"); m.SetGrantee("partner") },
+			wantIDs:     []string{"3"},
+			wantFilters: "owner~alice grantee~partner",
+		},
+		{name: "no match", apply: func(m *Model) { m.SetMinRisk(4) }, wantIDs: []string{}, wantFilters: "risk>=4"},
+		{
+			name:    "cleared",
+			apply:   func(m *Model) { m.SetMinRisk(3); m.SetOwner("bob"); m.ClearFilters() },
+			wantIDs: []string{"2", "1", "3", "4"},
+		},
+		{
+			name:    "empty text clears one filter",
+			apply:   func(m *Model) { m.SetOwner("bob"); m.SetOwner(" ") },
+			wantIDs: []string{"2", "1", "3", "4"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewModel(testShares(), 10)
+			tt.apply(m)
+			assert.Equal(t, tt.wantIDs, fileIDs(m.Rows()))
+			assert.Equal(t, tt.wantFilters, m.Filters())
+			assert.Equal(t, 4, m.Total())
+		})
+	}
+}
+
+func TestModel_FilterKeepsSelection(t *testing.T) {
+	m := NewModel(testShares(), 10)
+	m.Move(2)
+	selected, ok := m.Selected()
+	require.True(t, ok)
+	require.Equal(t, "3", selected.FileID)
+
+	m.SetOwner("alice")
+	selected, ok = m.Selected()
+	require.True(t, ok)
+	assert.Equal(t, "3", selected.FileID, "the selected share still passes the filter")
+	assert.Equal(t, 1, m.Cursor())
+
+	m.SetGrantee("anyone")
+	selected, ok = m.Selected()
+	require.True(t, ok)
+	assert.Equal(t, "1", selected.FileID, "the cursor moves to the first row when the selection is filtered out")
+}
+
+func TestModel_NoRows(t *testing.T) {
+	m := NewModel(testShares(), 10)
+	m.ToggleDetail()
+	require.True(t, m.Detail())
+
+	m.SetMinRisk(9)
+	_, ok := m.Selected()
+	assert.False(t, ok)
+	assert.False(t, m.Detail(), "details close when nothing is selected")
+
+	m.ToggleDetail()
+	assert.False(t, m.Detail())
+	m.Move(1)
+	assert.Equal(t, 0, m.Cursor())
+	page, offset := m.Page()
+	assert.Empty(t, page)
+	assert.Equal(t, 0, offset)
+}
+
+func TestModel_Paging(t *testing.T) {
+	m := NewModel(testShares(), 3)
+
+	page, offset := m.Page()
+	assert.Equal(t, []string{"2", "1", "3"}, fileIDs(page))
+	assert.Equal(t, 0, offset)
+
+	m.Move(3)
+	page, offset = m.Page()
+	assert.Equal(t, []string{"1", "3", "4"}, fileIDs(page), "the page scrolls to keep the cursor visible")
+	assert.Equal(t, 1, offset)
+
+	m.PageDown()
+	assert.Equal(t, 3, m.Cursor(), "the cursor stops at the last row")
+	m.PageUp()
+	assert.Equal(t, 0, m.Cursor())
+	_, offset = m.Page()
+	assert.Equal(t, 0, offset)
+
+	m.Move(-1)
+	assert.Equal(t, 0, m.Cursor(), "the cursor stops at the first row")
+}
+
+func TestFileLink(t *testing.T) {
+	assert.Equal(t, "https://docs.google.com/d/1", FileLink(audit.ExternalShareRecord{FileID: "1", WebViewLink: "https://docs.google.com/d/1"}))
+	assert.Equal(t, "https://drive.google.com/open?id=a%2Bb", FileLink(audit.ExternalShareRecord{FileID: "a+b"}))
+}
+
+func TestCopySequence(t *testing.T) {
+	assert.Equal(t, "\x1b]52;c;aGk=\a", CopySequence("hi"))
+}
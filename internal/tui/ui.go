@@ -0,0 +1,302 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package tui
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+)
+
+// helpText lists the keys handled by Run.
+const helpText = `Keys:
+  j / k, ↓ / ↑     next / previous share    n / p, PgDn / PgUp   next / previous page
+  Enter            show or hide details     c                    copy the file's web link
+  r                only shares with risk >= a value (empty clears)
+  o                only owners matching a text (empty clears)
+  g                only grantees matching a text, or "anyone" (empty clears)
+  x                clear all filters        ?                    help
+  Esc              cancel a filter prompt   q, Ctrl-C            quit`
+
+// chromeLines is the number of screen lines around the table: the title,
+// the column header, the row count, the status and the prompt line.
+const chromeLines = 8
+
+// Run shows m full screen on out and reads keys from in until q or Ctrl-C.
+func Run(in io.Reader, out io.Writer, m *Model) error {
+	w := &lockedWriter{w: out}
+	p := tea.NewProgram(&browser{model: m, out: w, status: "Press ? for help."},
+		tea.WithInput(in), tea.WithOutput(w), tea.WithAltScreen())
+	_, err := p.Run()
+	return err
+}
+
+// lockedWriter serializes writes, so the OSC 52 copy sequence is never
+// written into the middle of a frame.
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
+
+// copiedMsg reports the result of copying link to the clipboard.
+type copiedMsg struct {
+	link string
+	err  error
+}
+
+// browser is the bubbletea model drawing a Model. While prompt is set, keys
+// edit input, the value of the risk ('r'), owner ('o') or grantee ('g')
+// filter.
+type browser struct {
+	model  *Model
+	out    io.Writer
+	status string
+	prompt rune
+	input  []rune
+}
+
+func (b *browser) Init() tea.Cmd {
+	return nil
+}
+
+func (b *browser) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		b.model.SetHeight(msg.Height - chromeLines)
+	case copiedMsg:
+		if msg.err != nil {
+			b.status = "Copy failed: " + msg.err.Error()
+		} else {
+			b.status = "Copied " + msg.link
+		}
+	case tea.KeyMsg:
+		if b.prompt != 0 {
+			b.editPrompt(msg)
+			return b, nil
+		}
+		return b, b.handleKey(msg)
+	}
+	return b, nil
+}
+
+// handleKey runs the command bound to key.
+func (b *browser) handleKey(key tea.KeyMsg) tea.Cmd {
+	b.status = ""
+	switch key.String() {
+	case "q", "ctrl+c":
+		return tea.Quit
+	case "j", "down":
+		b.model.Move(1)
+	case "k", "up":
+		b.model.Move(-1)
+	case "n", "pgdown":
+		b.model.PageDown()
+	case "p", "pgup":
+		b.model.PageUp()
+	case "enter":
+		b.model.ToggleDetail()
+	case "r", "o", "g":
+		b.prompt, b.input = key.Runes[0], nil
+	case "x":
+		b.model.ClearFilters()
+	case "c":
+		return b.copyLink()
+	case "?":
+		b.status = helpText
+	default:
+		b.status = fmt.Sprintf("Unknown key %q. Press ? for help.", sanitize(key.String()))
+	}
+	return nil
+}
+
+// editPrompt edits the filter value being typed, applying it on Enter.
+func (b *browser) editPrompt(key tea.KeyMsg) {
+	switch key.Type {
+	case tea.KeyEnter:
+		b.applyFilter(strings.TrimSpace(string(b.input)))
+		b.prompt = 0
+	case tea.KeyEsc, tea.KeyCtrlC:
+		b.prompt = 0
+	case tea.KeyBackspace:
+		if len(b.input) > 0 {
+			b.input = b.input[:len(b.input)-1]
+		}
+	case tea.KeySpace:
+		b.input = append(b.input, ' ')
+	case tea.KeyRunes:
+		b.input = append(b.input, []rune(sanitize(string(key.Runes)))...)
+	}
+}
+
+// applyFilter sets the filter of the current prompt to value.
+func (b *browser) applyFilter(value string) {
+	b.status = ""
+	switch b.prompt {
+	case 'r':
+		risk := 0
+		if value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				b.status = fmt.Sprintf("Invalid risk %q.", value)
+				return
+			}
+			risk = n
+		}
+		b.model.SetMinRisk(risk)
+	case 'o':
+		b.model.SetOwner(value)
+	case 'g':
+		b.model.SetGrantee(value)
+	}
+}
+
+// copyLink returns a command writing the selected file's web link to the
+// clipboard.
+func (b *browser) copyLink() tea.Cmd {
+	rec, ok := b.model.Selected()
+	if !ok {
+		b.status = "No share selected."
+		return nil
+	}
+	link := sanitize(FileLink(rec))
+	return func() tea.Msg {
+		_, err := io.WriteString(b.out, CopySequence(link))
+		return copiedMsg{link: link, err: err}
+	}
+}
+
+// View draws the table or the details of the selected share, followed by
+// the status and the prompt line.
+func (b *browser) View() string {
+	m := b.model
+	var s strings.Builder
+
+	filters := m.Filters()
+	if filters == "" {
+		filters = "none"
+	}
+	fmt.Fprintf(&s, "gwork: %d of %d shares  filters: %s\n\n", len(m.Rows()), m.Total(), sanitize(filters))
+
+	if rec, ok := m.Selected(); ok && m.Detail() {
+		writeDetail(&s, rec)
+	} else {
+		writeTable(&s, m)
+	}
+
+	if b.status != "" {
+		fmt.Fprintf(&s, "\n%s\n", b.status)
+	}
+	switch b.prompt {
+	case 'r':
+		fmt.Fprintf(&s, "Minimum risk: %s█", string(b.input))
+	case 'o':
+		fmt.Fprintf(&s, "Owner: %s█", string(b.input))
+	case 'g':
+		fmt.Fprintf(&s, "Grantee: %s█", string(b.input))
+	}
+	return s.String()
+}
+
+// writeTable writes the current page of shares, marking the selected one.
+func writeTable(b *strings.Builder, m *Model) {
+	rows, offset := m.Page()
+	if len(rows) == 0 {
+		b.WriteString("No shares match the filters.\n")
+		return
+	}
+
+	w := tabwriter.NewWriter(b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\tRISK\tLABEL\tOWNER\tGRANTEE\tROLE\tFILE")
+	for i, rec := range rows {
+		marker := " "
+		if offset+i == m.Cursor() {
+			marker = ">"
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\t%s\n", marker, rec.Risk, sanitize(rec.Label),
+			truncate(sanitize(rec.OwnerEmail), 30), truncate(sanitize(grantee(rec)), 30),
+			sanitize(rec.PermissionRole), truncate(sanitize(rec.FileName), 40))
+	}
+	_ = w.Flush()
+	fmt.Fprintf(b, "\nRows %d-%d of %d\n", offset+1, offset+len(rows), len(m.Rows()))
+}
+
+// writeDetail writes every field of rec that has a value.
+func writeDetail(b *strings.Builder, rec audit.ExternalShareRecord) {
+	w := tabwriter.NewWriter(b, 0, 0, 2, ' ', 0)
+	field := func(name, value string) {
+		if value = sanitize(value); value != "" {
+			fmt.Fprintf(w, "%s\t%s\n", name, value)
+		}
+	}
+	field("File", rec.FileName)
+	field("File ID", rec.FileID)
+	field("Link", FileLink(rec))
+	field("Owner", strings.TrimSpace(rec.OwnerName+" <"+rec.OwnerEmail+">"))
+	field("Grantee", grantee(rec))
+	field("Type", rec.PermissionType)
+	field("Role", rec.PermissionRole)
+	field("Permission ID", rec.PermissionID)
+	field("Label", rec.Label)
+	field("Risk", strconv.Itoa(rec.Risk))
+	field("Location", rec.Location)
+	if rec.Inherited {
+		field("Inherited from", rec.InheritedFrom)
+	}
+	if !rec.ExpirationTime.IsZero() {
+		field("Expires", rec.ExpirationTime.UTC().Format(time.RFC3339))
+	}
+	if rec.Flagged {
+		field("Flagged", rec.FlagReason)
+	}
+	field("Explanation", rec.Explanation)
+	field("Source domain", rec.SourceDomain)
+	_ = w.Flush()
+}
+
+// grantee returns who the share grants access to.
+func grantee(rec audit.ExternalShareRecord) string {
+	switch {
+	case rec.PermissionType == "anyone":
+		return "anyone"
+	case rec.SharedWithEmail != "":
+		return rec.SharedWithEmail
+	default:
+		return rec.SharedWithDomain
+	}
+}
+
+// sanitize removes C0 and C1 control characters and DEL from s. File names,
+// owners and labels come from Drive or a report file, and must not be able
+// to send escape sequences to the terminal.
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || (r >= 0x7f && r <= 0x9f) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// truncate shortens s to at most n runes, ending with "…" when cut.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
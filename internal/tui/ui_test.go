@@ -0,0 +1,45 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/leansecurity-co/gwork/internal/audit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitize(t *testing.T) {
+	assert.Equal(t, "report]52;c;aGk=.pdf", sanitize("report\x1b]52;c;aGk=\a.pdf"))
+	assert.Equal(t, "ab", sanitize("a\u009bb\x7f"))
+	assert.Equal(t, "plan – v2", sanitize("plan – v2"))
+}
+
+func TestBrowser_ViewStripsControlCharacters(t *testing.T) {
+	records := []audit.ExternalShareRecord{{FileID: "1", FileName: "evil\x1b]52;c;aGk=\a", OwnerEmail: "a\x1b[2J@example.com",
+		PermissionType: "anyone", Explanation: "shared\u009b31m"}}
+	b := &browser{model: NewModel(records, 10)}
+
+	assert.NotContains(t, b.View(), "\x1b")
+	b.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	view := b.View()
+	assert.NotContains(t, view, "\x1b")
+	assert.NotContains(t, view, "\u009b")
+	assert.Contains(t, view, "shared31m")
+}
+
+func TestBrowser_FilterPrompt(t *testing.T) {
+	b := &browser{model: NewModel(testShares(), 10)}
+
+	b.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	b.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2")})
+	assert.Contains(t, b.View(), "Minimum risk: 2")
+	b.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, []string{"2", "1"}, fileIDs(b.model.Rows()))
+
+	b.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	b.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Len(t, b.model.Rows(), 4)
+}
@@ -6,36 +6,170 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
 
 	"github.com/leansecurity-co/gwork/internal/audit"
+	"github.com/leansecurity-co/gwork/internal/auth"
 	"github.com/leansecurity-co/gwork/internal/config"
+	"github.com/leansecurity-co/gwork/internal/history"
 	"github.com/leansecurity-co/gwork/internal/reporter"
+	"github.com/leansecurity-co/gwork/internal/sink"
+	"github.com/leansecurity-co/gwork/internal/tui"
 	"github.com/leansecurity-co/gwork/pkg/exitcode"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var (
 	version = "0.1.0"
 
-	cfgFile string
-	verbose bool
-	quiet   bool
+	cfgFile     string
+	verbose     bool
+	quiet       bool
+	errorFormat string
+
+	corpora        string
+	pageSize       int64
+	maxAPICalls    int64
+	maxQPS         int
+	driveIDs       []string
+	includeTrashed bool
+	linkStatus     bool
+	jsonPretty     bool
+	jsonFields     []string
+	outputFormat   string
+	outputFile     string
+	splitByOwner   bool
+	maxRowsPerFile int
+	maxRows        int
+	chunkByOwner   bool
+	driveQuery     string
+	strict         bool
+	strictUnknown  bool
+	roleDist       bool
+	delimiter      string
+	ignoreFileIDs  []string
+	ignoreFileList string
+	labelsFile     string
+	sortOwnersBy   string
+	sortFilesBy    string
+	activityLog    string
+	ownerDomains   []string
+	sharedWith     []string
+	expandGroups   bool
+
+	anonymize     bool
+	anonymizeSalt string
+
+	directOnly     bool
+	deletedOnly    bool
+	excludeAdmin   bool
+	flaggedOnly    bool
+	explain        bool
+	withAge        bool
+	resumeOwner    string
+	expiringWithin time.Duration
+
+	notAccessedSince dateValue
+
+	failAbove         int
+	countOnly         bool
+	toStdout          bool
+	remediationScript string
+	fullAudit         bool
+
+	sampleSize int
+	sampleSeed int64
+
+	postURL     string
+	postHeaders []string
+	postRecords bool
+	postTimeout time.Duration
+
+	tuiReport string
+
+	streamShares bool
+
+	checkpointPath string
+	resumeAudit    bool
 )
 
+// streamBuffer is the number of share records a streaming sharing audit
+// holds between the permission workers and the report writer.
+const streamBuffer = 256
+
 func main() {
-	if err := rootCmd.Execute(); err != nil {
-		os.Exit(exitcode.InternalError)
+	err := rootCmd.Execute()
+	if closeErr := closeActivityLog(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		printError(os.Stderr, err, errorFormat)
+		os.Exit(exitcode.FromError(err))
+	}
+}
+
+// errorOutput is the JSON object printed to stderr by --error-format json.
+type errorOutput struct {
+	Error    string `json:"error"`
+	Code     int    `json:"code"`
+	Category string `json:"category"`
+}
+
+// printError writes err to w, either as a single JSON object or, by default,
+// as the "Error: ..." line cobra would print.
+func printError(w io.Writer, err error, format string) {
+	if format != "json" {
+		fmt.Fprintln(w, "Error:", err)
+		return
+	}
+	code := exitcode.FromError(err)
+	_ = json.NewEncoder(w).Encode(errorOutput{
+		Error:    err.Error(),
+		Code:     code,
+		Category: exitcode.Category(code),
+	})
+}
+
+// validateErrorFormat rejects unknown --error-format values.
+func validateErrorFormat(cmd *cobra.Command, args []string) error {
+	switch errorFormat {
+	case "text", "json":
+		return nil
+	default:
+		return exitcode.Wrap(exitcode.ConfigError,
+			fmt.Errorf("invalid --error-format %q: must be text or json", errorFormat))
 	}
 }
 
+// flagError reports a command-line flag that cannot be parsed as a
+// configuration error. Cobra calls it for every command.
+func flagError(cmd *cobra.Command, err error) error {
+	return exitcode.Wrap(exitcode.ConfigError, err)
+}
+
 var rootCmd = &cobra.Command{
 	Use:   "gwork",
 	Short: "Google Workspace security and audit tool",
 	Long: `gwork is a CLI tool for auditing Google Workspace Drive files.
 It helps identify files shared externally and generates reports
 grouped by file owner.`,
+	// main prints errors itself so --error-format can apply to them, and
+	// usage text would not be valid JSON, so it is never printed on error.
+	SilenceErrors:     true,
+	SilenceUsage:      true,
+	PersistentPreRunE: validateErrorFormat,
+	RunE:              runDefault,
 }
 
 var auditCmd = &cobra.Command{
@@ -58,6 +192,61 @@ var auditSharingCmd = &cobra.Command{
 	RunE:  runAuditSharing,
 }
 
+var auditPublicCmd = &cobra.Command{
+	Use:   "public",
+	Short: "Generate public shares CSV",
+	Long:  `Generate a list of files shared with anyone, with or without a link.`,
+	RunE:  runAuditPublic,
+}
+
+var auditDomainSharesCmd = &cobra.Command{
+	Use:   "domain-shares",
+	Short: "Generate domain-wide shares CSV",
+	Long:  `Generate a list of files shared with everyone in the organization's own domain.`,
+	RunE:  runAuditDomainShares,
+}
+
+var auditExternalOwnersCmd = &cobra.Command{
+	Use:   "external-owners",
+	Short: "Generate externally owned files CSV",
+	Long:  `Generate a list of files owned by accounts outside the organization's domain and its aliases.`,
+	RunE:  runAuditExternalOwners,
+}
+
+var auditSharedWithMeCmd = &cobra.Command{
+	Use:   "shared-with-me",
+	Short: "Generate CSV of files shared with you from outside",
+	Long: `Generate a list of the files in your "Shared with me" collection owned by
+accounts outside the organization's domain and its aliases. It works with
+OAuth user credentials in place of a service account key, without
+domain-wide delegation.`,
+	RunE: runAuditSharedWithMe,
+}
+
+var auditFileCmd = &cobra.Command{
+	Use:   "file <fileID>",
+	Short: "Show one file's permissions",
+	Long: `Print the metadata and full permission list of a single file, marking
+external, public and flagged permissions. Nothing is written to the output
+directory.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAuditFile,
+}
+
+var auditOwnersCmd = &cobra.Command{
+	Use:   "owners",
+	Short: "Generate owners inventory CSV",
+	Long:  `List distinct file owners with their file counts and total bytes, without fetching permissions.`,
+	RunE:  runAuditOwners,
+}
+
+var auditDuplicatesCmd = &cobra.Command{
+	Use:   "duplicates",
+	Short: "Generate duplicate files CSV",
+	Long:  `List groups of files with the same owner, name and size, without fetching permissions.`,
+	RunE:  runAuditDuplicates,
+}
+
 var auditAllCmd = &cobra.Command{
 	Use:   "all",
 	Short: "Run all audits",
@@ -78,6 +267,51 @@ var configInitCmd = &cobra.Command{
 	RunE:  runConfigInit,
 }
 
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the effective configuration",
+	Long: `Print the configuration gwork will use, after merging the config file,
+GWORK_* environment variables and command-line flags, as YAML.`,
+	RunE: runConfigShow,
+}
+
+var configSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema of the config file",
+	Long: `Print a JSON Schema describing .gwork.yaml, for validating the config
+in editors and CI.`,
+	Args: cobra.NoArgs,
+	RunE: runConfigSchema,
+}
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show recorded audit runs",
+	Long:  `Print the audit totals recorded in the configured history file.`,
+	RunE:  runHistory,
+}
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify <output-dir>",
+	Short: "Check report files against SHA256SUMS",
+	Long: `Recompute the SHA-256 of every report file listed in the SHA256SUMS file
+of an output directory and compare it with the recorded one. Each file is
+printed with OK or FAIL; the command exits with code 5 if any file is
+missing or has changed.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runVerify,
+}
+
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Browse external shares interactively",
+	Long: `Run the external sharing audit, or load a sharing report written earlier
+with --report, and browse the shares riskiest first. Keys filter by risk,
+owner or grantee and copy a file's web link; press ? for the list.`,
+	Args: cobra.NoArgs,
+	RunE: runTUI,
+}
+
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print the version number",
@@ -87,163 +321,2136 @@ var versionCmd = &cobra.Command{
 }
 
 func init() {
-	rootCmd.PersistentFlags().StringVarP(&cfgFile, "config", "c", "", "config file (default is .gwork.yaml)")
+	rootCmd.PersistentFlags().StringVarP(&cfgFile, "config", "c", "", "config file, or - to read YAML from stdin (default is .gwork.yaml)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "enable verbose output")
 	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "suppress non-error output")
+	rootCmd.PersistentFlags().StringVar(&errorFormat, "error-format", "text", "format of the error printed on failure: text or json")
+	rootCmd.SetFlagErrorFunc(flagError)
+
+	addAuditFlags(auditCmd.PersistentFlags())
 
 	// Build command tree
 	rootCmd.AddCommand(auditCmd)
 	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(historyCmd)
+	rootCmd.AddCommand(tuiCmd)
+	rootCmd.AddCommand(verifyCmd)
 	rootCmd.AddCommand(versionCmd)
 
 	auditCmd.AddCommand(auditFilesCmd)
 	auditCmd.AddCommand(auditSharingCmd)
+	auditCmd.AddCommand(auditPublicCmd)
+	auditCmd.AddCommand(auditDomainSharesCmd)
+	auditCmd.AddCommand(auditExternalOwnersCmd)
+	auditCmd.AddCommand(auditSharedWithMeCmd)
+	auditCmd.AddCommand(auditOwnersCmd)
+	auditCmd.AddCommand(auditDuplicatesCmd)
+	auditCmd.AddCommand(auditFileCmd)
 	auditCmd.AddCommand(auditAllCmd)
 
+	auditSharingCmd.Flags().BoolVar(&streamShares, "stream", false, "write each share to the report as it is found instead of holding them all; rows are not sorted")
+	auditSharingCmd.Flags().StringVar(&checkpointPath, "checkpoint", "", "record the audit's progress in `FILE` so an interrupted run can be continued with --resume")
+	auditSharingCmd.Flags().BoolVar(&resumeAudit, "resume", false, "continue the audit recorded in the --checkpoint file, skipping the files it already audited")
+	auditAllCmd.Flags().BoolVar(&toStdout, "stdout", false, "write one JSON document with files, sharing results and totals to stdout instead of reports")
+
 	configCmd.AddCommand(configInitCmd)
+	configCmd.AddCommand(configShowCmd)
+	configCmd.AddCommand(configSchemaCmd)
+
+	// config show accepts the audit flags so their overrides are shown.
+	addAuditFlags(configShowCmd.Flags())
+
+	addAuditFlags(tuiCmd.Flags())
+	tuiCmd.Flags().StringVar(&tuiReport, "report", "", "browse this .json or .ndjson sharing report instead of running an audit")
 }
 
-func loadConfig() (*config.Config, error) {
-	return config.Load(cfgFile)
+// addAuditFlags registers the flags shared by all audit subcommands.
+func addAuditFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&corpora, "corpora", "", "Drive corpora to list: user, domain, drive or allDrives (overrides config)")
+	flags.Int64Var(&pageSize, "page-size", 0, "number of items per API request, 1-1000 (overrides config)")
+	flags.StringVar(&activityLog, "activity-log", "", "append a JSON line for each Drive API call to this file, as an audit trail of the run")
+	flags.Int64Var(&maxAPICalls, "max-api-calls", 0, "stop after this many Drive API calls and report partial results, 0 for no limit (overrides config)")
+	flags.IntVar(&maxQPS, "max-qps", 0, "start at most this many Drive API calls per second, 0 for no limit (overrides config)")
+	flags.StringArrayVar(&driveIDs, "drive-id", nil, "shared drive ID to audit; repeat to audit several drives")
+	flags.StringArrayVar(&ignoreFileIDs, "ignore-file", nil, "file ID to leave out of reports, added to audit.ignore_file_ids (repeatable)")
+	flags.StringVar(&ignoreFileList, "ignore-file-list", "", "path to a file of IDs to leave out of reports, one per line (overrides config)")
+	flags.DurationVar(&expiringWithin, "expiring-within", 0, "only report shares expiring within this duration, e.g. 168h")
+	flags.Var(&notAccessedSince, "not-accessed-since", "only report files the admin last viewed before this date (YYYY-MM-DD or RFC3339)")
+	flags.StringSliceVar(&ownerDomains, "owner-domain", nil, "only report files owned by users in this domain (repeatable or comma-separated)")
+	flags.StringSliceVar(&sharedWith, "shared-with", nil, "only report shares to this email address or @domain (repeatable or comma-separated)")
+	flags.BoolVar(&directOnly, "direct-only", false, "only report permissions granted directly on a file, not inherited ones")
+	flags.BoolVar(&deletedOnly, "deleted-grantees-only", false, "only report shares with accounts Drive reports as deleted")
+	flags.BoolVar(&excludeAdmin, "exclude-admin", false, "leave out files owned by the impersonated google.admin_email")
+	flags.StringVar(&resumeOwner, "resume-from-owner", "", "skip owners that sort before this email, to restart an interrupted run")
+	flags.BoolVar(&flaggedOnly, "flagged-only", false, "only report shares with a grantee in audit.flagged_domains")
+	flags.BoolVar(&explain, "explain", false, "add an explanation column saying why each share was reported")
+	flags.BoolVar(&anonymize, "anonymize", false, "replace emails, names and file names in reports with salted hashes")
+	flags.StringVar(&anonymizeSalt, "anonymize-salt", "", "salt for --anonymize to make the mapping reproducible (default: random per run)")
+	flags.StringVar(&outputFormat, "format", "", "output format: csv, json, ndjson, xlsx, sheets, or auto to infer it from --output-file; a comma-separated list such as csv,json writes each (overrides config)")
+	flags.StringVar(&outputFile, "output-file", "", "write the report of a single-report command to this path instead of the output directory (overrides config)")
+	flags.BoolVar(&jsonPretty, "json-pretty", false, "indent JSON reports (overrides config; NDJSON is always compact)")
+	flags.StringSliceVar(&jsonFields, "json-fields", nil, "only write these fields to JSON and NDJSON reports (repeatable or comma-separated; overrides config)")
+	flags.StringVar(&labelsFile, "labels-file", "", "YAML file mapping column names to header labels for CSV, XLSX and Sheets reports (overrides config)")
+	flags.StringVar(&sortOwnersBy, "sort-owners-by", "", "order owners in file reports, split outputs and audit owners by email, bytes or count, the largest first (overrides config)")
+	flags.StringVar(&sortFilesBy, "sort-files-by", "", "order each owner's files by name, size (largest first) or modified (newest first) (overrides config)")
+	flags.StringVar(&delimiter, "delimiter", "", "CSV field delimiter: a single character, or tab for .tsv output (overrides config)")
+	flags.BoolVar(&chunkByOwner, "chunk-by-owner", false, "list and write the files report one owner at a time to bound memory (overrides config)")
+	flags.IntVar(&maxRows, "max-rows", 0, "keep at most N rows across the record reports, dropping the lowest-risk shares and smallest files first, 0 for no limit")
+	flags.IntVar(&maxRowsPerFile, "max-rows-per-file", 0, "split CSV reports into numbered files of at most N rows, 0 for no limit (overrides config)")
+	flags.BoolVar(&splitByOwner, "split-by-owner", false, "write one CSV per owner under files/ plus files_index.csv (overrides config)")
+	flags.BoolVar(&roleDist, "role-distribution", false, "also write role_distribution with share counts by scope and role (overrides config)")
+	flags.BoolVar(&includeTrashed, "include-trashed", false, "include trashed files and add a trashed column to reports")
+	flags.BoolVar(&withAge, "with-age", false, "add created_age_days and modified_age_days columns to the files report")
+	flags.BoolVar(&linkStatus, "include-link-status", false, "fetch each file's permissions and add a link_sharing_enabled column to the files report (overrides config)")
+	flags.BoolVar(&fullAudit, "full", false, "with audit.incremental, fetch every file's permissions instead of reusing those of unchanged files")
+	flags.IntVar(&sampleSize, "sample", 0, "audit a uniform random sample of N files and extrapolate totals")
+	flags.Int64Var(&sampleSeed, "sample-seed", 0, "seed for --sample to make the selection reproducible (default: random per run)")
+	flags.BoolVar(&countOnly, "count-only", false, "print only the totals as JSON, without writing reports")
+	flags.IntVar(&failAbove, "fail-above", 0, "exit with code 4 if any share's risk score is above this threshold")
+	flags.StringVar(&remediationScript, "remediation-script", "", "write a shell script of commented-out revocations for public and high-risk shares to this path")
+	flags.StringVar(&postURL, "post-url", "", "POST a JSON summary of the results to this URL after the audit")
+	flags.StringArrayVar(&postHeaders, "post-header", nil, "header to send with --post-url, as \"Name: value\" (repeatable)")
+	flags.BoolVar(&postRecords, "post-records", false, "include the full records in the --post-url payload")
+	flags.DurationVar(&postTimeout, "post-timeout", 30*time.Second, "timeout for the --post-url request")
+	flags.BoolVar(&strict, "strict", false, "report malformed data from the API, such as invalid timestamps, as errors (overrides config)")
+	flags.BoolVar(&strictUnknown, "strict-unknown", false, "report shares with permission types gwork does not recognize as external (overrides config)")
+	flags.BoolVar(&expandGroups, "expand-groups", false, "resolve members of groups shared with; requires the Admin SDK Directory scope")
+	flags.StringVar(&driveQuery, "query", "", "advanced: Drive search query restricting the files audited, e.g. \"'FOLDER_ID' in parents\" (overrides config)")
 }
 
-func runAuditFiles(cmd *cobra.Command, args []string) error {
-	cfg, err := loadConfig()
+// defaultConfig is the config loaded by runDefault, which loadConfig uses
+// instead of loading it again: --config - can only read stdin once.
+var defaultConfig *config.Config
+
+// runDefault runs the audit subcommand named by default_command when gwork
+// is invoked without a subcommand, and prints the help text otherwise.
+// Without --config, a missing or invalid config file also prints the help
+// text, as gwork did before default_command existed.
+func runDefault(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(cfgFile)
 	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+		if cfgFile == "" {
+			return cmd.Help()
+		}
+		return fmt.Errorf("failed to load config: %w", exitcode.Wrap(exitcode.ConfigError, err))
+	}
+	if cfg.DefaultCommand == "" {
+		return cmd.Help()
 	}
 
-	ctx := context.Background()
-	auditor, err := audit.NewAuditor(cfg)
-	if err != nil {
-		return fmt.Errorf("failed to create auditor: %w", err)
+	sub, _, err := auditCmd.Find([]string{cfg.DefaultCommand})
+	if err != nil || sub == auditCmd || sub.RunE == nil {
+		return exitcode.Wrap(exitcode.ConfigError, fmt.Errorf("default_command %q is not an audit subcommand", cfg.DefaultCommand))
 	}
 
-	if !quiet {
-		fmt.Println("Fetching files from Google Drive...")
+	// Parsing no arguments merges the audit flags into sub's flag set, as
+	// running "gwork audit <name>" would.
+	if err := sub.ParseFlags(nil); err != nil {
+		return err
+	}
+	defaultConfig = cfg
+	defer func() { defaultConfig = nil }()
+	return sub.RunE(sub, nil)
+}
+
+func loadConfig(cmd *cobra.Command) (*config.Config, error) {
+	cfg := defaultConfig
+	if cfg == nil {
+		var err error
+		if cfg, err = config.Load(cfgFile); err != nil {
+			return nil, exitcode.Wrap(exitcode.ConfigError, err)
+		}
+	}
+
+	if err := applyFlagOverrides(cmd, cfg); err != nil {
+		return nil, err
 	}
 
-	result, err := auditor.AuditFiles(ctx)
+	// Other audit commands would silently audit a single domain.
+	if len(cfg.Google.Domains) > 0 && cmd.Parent() == auditCmd && cmd.Name() != "all" {
+		return nil, exitcode.Wrap(exitcode.ConfigError,
+			fmt.Errorf("google.domains is only supported by audit all, not audit %s", cmd.Name()))
+	}
+
+	if verbose && cfg.DomainDerived() {
+		fmt.Printf("Using domain %s from google.admin_email\n", cfg.Google.Domain)
+	}
+
+	return cfg, nil
+}
+
+// applyFlagOverrides applies command-line flags on top of the loaded config
+// and re-validates the result. Flags only take effect when explicitly set.
+func applyFlagOverrides(cmd *cobra.Command, cfg *config.Config) error {
+	flags := cmd.Flags()
+
+	if flags.Changed("corpora") {
+		cfg.Audit.Corpora = corpora
+	}
+	if flags.Changed("page-size") {
+		cfg.Audit.PageSize = pageSize
+	}
+	if flags.Changed("max-api-calls") {
+		cfg.Audit.MaxAPICalls = maxAPICalls
+	}
+	if flags.Changed("max-qps") {
+		cfg.Audit.MaxQPS = maxQPS
+	}
+	if flags.Changed("drive-id") {
+		cfg.Audit.DriveIDs = driveIDs
+	}
+	if flags.Changed("ignore-file") {
+		cfg.Audit.IgnoreFileIDs = append(cfg.Audit.IgnoreFileIDs, ignoreFileIDs...)
+	}
+	if flags.Changed("ignore-file-list") {
+		cfg.Audit.IgnoreFileList = ignoreFileList
+	}
+	if flags.Changed("labels-file") {
+		cfg.Output.LabelsFile = labelsFile
+	}
+	if flags.Changed("sort-owners-by") {
+		cfg.Output.SortOwnersBy = sortOwnersBy
+	}
+	if flags.Changed("sort-files-by") {
+		cfg.Output.SortFilesBy = sortFilesBy
+	}
+	if flags.Changed("include-trashed") {
+		cfg.Audit.IncludeTrashed = includeTrashed
+	}
+	if flags.Changed("include-link-status") {
+		cfg.Audit.IncludeLinkStatus = linkStatus
+	}
+	if flags.Changed("query") {
+		cfg.Audit.Query = driveQuery
+	}
+	if flags.Changed("expand-groups") {
+		cfg.Audit.ExpandGroups = expandGroups
+	}
+	if flags.Changed("strict") {
+		cfg.Audit.Strict = strict
+	}
+	if flags.Changed("strict-unknown") {
+		cfg.Audit.StrictUnknown = strictUnknown
+	}
+	if flags.Changed("format") {
+		cfg.Output.Format = outputFormat
+	}
+	if flags.Changed("output-file") {
+		cfg.Output.File = outputFile
+	}
+	if flags.Changed("json-pretty") {
+		cfg.Output.JSONIndent = jsonPretty
+	}
+	if flags.Changed("json-fields") {
+		cfg.Output.JSONFields = jsonFields
+	}
+	if flags.Changed("delimiter") {
+		cfg.Output.Delimiter = delimiter
+	}
+	if flags.Changed("chunk-by-owner") {
+		cfg.Audit.ChunkByOwner = chunkByOwner
+	}
+	if flags.Changed("max-rows-per-file") {
+		cfg.Output.MaxRowsPerFile = maxRowsPerFile
+	}
+	if flags.Changed("split-by-owner") {
+		cfg.Output.SplitByOwner = splitByOwner
+	}
+	if flags.Changed("role-distribution") {
+		cfg.Output.RoleDistribution = roleDist
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return exitcode.Wrap(exitcode.ConfigError, fmt.Errorf("invalid configuration: %w", err))
+	}
+
+	return nil
+}
+
+func runAuditFiles(cmd *cobra.Command, args []string) error {
+	return runAuditJob(cmd, auditJob{
+		check: checkChunkOptions,
+		audit: func(run *auditRun) error {
+			if run.cfg.Audit.ChunkByOwner {
+				return runAuditFilesChunked(run)
+			}
+			return auditAllFiles(run)
+		},
+		write: func(run *auditRun) error {
+			rep, err := run.openReporter("files_by_owner")
+			if err != nil {
+				return err
+			}
+			if err := rep.WriteFilesByOwner(run.filesReport.FileRecords); err != nil {
+				return fmt.Errorf("failed to write report: %w", err)
+			}
+			return nil
+		},
+		summary: printFilesSummary,
+	})
+}
+
+// auditAllFiles lists every file into run.files.
+func auditAllFiles(run *auditRun) error {
+	progress("Fetching files from Google Drive...")
+	result, err := run.auditor.AuditFiles(run.ctx)
 	if err != nil {
 		return fmt.Errorf("audit failed: %w", err)
 	}
+	run.files = result
+	return nil
+}
+
+// checkChunkOptions rejects the options that need every file at once when
+// audit.chunk_by_owner is set.
+func checkChunkOptions(cmd *cobra.Command, cfg *config.Config) error {
+	if !cfg.Audit.ChunkByOwner {
+		return nil
+	}
+	for _, conflict := range []struct {
+		set  bool
+		name string
+	}{
+		{countOnly, "--count-only"},
+		{sampleSize > 0, "--sample"},
+		{maxRows > 0, "--max-rows"},
+	} {
+		if conflict.set {
+			return exitcode.Wrap(exitcode.ConfigError, fmt.Errorf("%s is not supported with audit.chunk_by_owner", conflict.name))
+		}
+	}
+	return nil
+}
+
+// runAuditFilesChunked runs the files audit one owner at a time, writing
+// each owner's rows as they are listed instead of holding every file.
+func runAuditFilesChunked(run *auditRun) error {
+	cfg := run.cfg
+	rep, err := run.openReporter("files_by_owner")
+	if err != nil {
+		return err
+	}
+	csvRep, ok := rep.(*reporter.CSVReporter)
+	if !ok {
+		return exitcode.Wrap(exitcode.ConfigError, errors.New("audit.chunk_by_owner requires output.format csv"))
+	}
 
-	rep, err := reporter.NewCSVReporter(cfg.Output.Directory)
+	var anonymizer *audit.Anonymizer
+	if anonymize {
+		// A single anonymizer keeps the mapping consistent across owners.
+		if anonymizer, err = audit.NewAnonymizer(anonymizeSalt); err != nil {
+			return err
+		}
+	}
+
+	// Owners are always visited in email order; config validation rejects
+	// other owner orders, so only the file order applies to each chunk.
+	order, err := audit.NewRecordOrder("", cfg.Output.SortFilesBy)
 	if err != nil {
-		return fmt.Errorf("failed to create reporter: %w", err)
+		return err
 	}
 
-	if err := rep.WriteFilesByOwner(result.FileRecords); err != nil {
+	stream, err := csvRep.StreamFilesByOwner()
+	if err != nil {
 		return fmt.Errorf("failed to write report: %w", err)
 	}
+	defer stream.Abort()
 
-	if !quiet {
-		fmt.Printf("Files audit complete. Total files: %d\n", result.TotalFiles)
-		fmt.Printf("Report saved to: %s/files_by_owner.csv\n", rep.OutputDir())
+	progress("Fetching files from Google Drive one owner at a time...")
+
+	var filtered audit.FilterStats
+	result, err := run.auditor.AuditFilesByOwnerChunks(run.ctx, func(records []audit.FileRecord) error {
+		chunk := &audit.AuditResult{FileRecords: records}
+		applyFilters(cfg, chunk)
+		filtered.Merge(chunk.Filtered)
+		order.SortFileRecords(chunk.FileRecords)
+		if anonymizer != nil {
+			chunk.FileRecords = anonymizer.FileRecords(chunk.FileRecords)
+		}
+		return stream.Write(chunk.FileRecords)
+	})
+	if err != nil {
+		return fmt.Errorf("audit failed: %w", err)
+	}
+	result.Filtered = filtered
+
+	if err := stream.Commit(); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
 	}
+	run.files = result
+	return nil
+}
 
+// printFilesSummary prints the outcome of a files audit.
+func printFilesSummary(run *auditRun) error {
+	result := run.files
+	fmt.Printf("Files audit complete. Total files: %d\n", result.TotalFiles)
+	printSuppressed(result)
+	printFiltered(result)
+	printSampleEstimate(result, "")
+	printFilesReportPath(run.cfg, run.rep)
+	if err := printCategorySummary(result.FileRecords); err != nil {
+		return err
+	}
+	printWarnings(run.cfg, run.filesReport, "files have malformed data")
+	printTiming(result)
 	return nil
 }
 
 func runAuditSharing(cmd *cobra.Command, args []string) error {
-	cfg, err := loadConfig()
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+	return runAuditJob(cmd, auditJob{
+		check: func(cmd *cobra.Command, cfg *config.Config) error {
+			if err := checkStreamOptions(cmd, cfg); err != nil {
+				return err
+			}
+			return checkCheckpointOptions(cfg)
+		},
+		snapshots: true,
+		audit: func(run *auditRun) error {
+			if streamShares {
+				return runAuditSharingStreamed(run)
+			}
+			if checkpointPath != "" {
+				return runAuditSharingCheckpointed(run)
+			}
+
+			progress("Analyzing external sharing...")
+			result, err := run.auditor.AuditExternalSharing(run.ctx)
+			if err != nil {
+				return fmt.Errorf("audit failed: %w", err)
+			}
+			run.sharing = result
+			return nil
+		},
+		alerts: true,
+		write: func(run *auditRun) error {
+			rep, err := run.openReporter("external_sharing")
+			if err != nil {
+				return err
+			}
+			if err := rep.WriteExternalSharing(run.sharingReport.ExternalShares); err != nil {
+				return fmt.Errorf("failed to write report: %w", err)
+			}
+			return writeShareExtras(run)
+		},
+		history: true,
+		summary: printSharingSummary,
+	})
+}
+
+// writeShareExtras writes the role distribution report and the
+// remediation script of run.sharing, when enabled.
+func writeShareExtras(run *auditRun) error {
+	if err := writeRoleDistribution(run.cfg, run.rep, run.sharing.ExternalShares); err != nil {
+		return err
 	}
+	return writeRemediationScript(run.sharing.ExternalShares)
+}
 
-	ctx := context.Background()
-	auditor, err := audit.NewAuditor(cfg)
-	if err != nil {
-		return fmt.Errorf("failed to create auditor: %w", err)
+// printSharingSummary prints the outcome of an external sharing audit.
+func printSharingSummary(run *auditRun) error {
+	result := run.sharing
+	fmt.Printf("Sharing audit complete. Files processed: %d\n", result.FilesProcessed)
+	if result.ResumedFiles > 0 {
+		fmt.Printf("Resumed from checkpoint: %d files already audited\n", result.ResumedFiles)
 	}
+	fmt.Printf("External shares found: %d\n", result.TotalExternalShares)
+	printNewShares(run.newShares)
+	printSuppressed(result)
+	printFiltered(result)
+	printCachedFiles(result)
+	printSampleEstimate(result, "external shares")
+	fmt.Printf("Report saved to: %s\n", reportPath(run.rep, "external_sharing"))
+	if err := printRoleDistribution(run.cfg, result.ExternalShares); err != nil {
+		return err
+	}
+	printAlerts(os.Stdout, run.alerts, useColor(os.Stdout))
 
-	if !quiet {
-		fmt.Println("Analyzing external sharing...")
+	printWarnings(run.cfg, run.sharingReport, "files could not be processed")
+	printTiming(result)
+	return nil
+}
+
+// checkStreamOptions rejects the options that need every share at once
+// when --stream is set.
+func checkStreamOptions(cmd *cobra.Command, cfg *config.Config) error {
+	if !streamShares {
+		return nil
+	}
+	for _, conflict := range []struct {
+		set  bool
+		name string
+	}{
+		{countOnly, "--count-only"},
+		{sampleSize > 0, "--sample"},
+		{maxRows > 0, "--max-rows"},
+		{cmd.Flags().Changed("fail-above"), "--fail-above"},
+		{remediationScript != "", "--remediation-script"},
+		{postRecords, "--post-records"},
+		{cfg.Output.HistoryFile != "", "output.history_file"},
+		{cfg.Output.RoleDistribution, "output.role_distribution"},
+		{cfg.Output.MaxRowsPerFile > 0, "output.max_rows_per_file"},
+	} {
+		if conflict.set {
+			return exitcode.Wrap(exitcode.ConfigError, fmt.Errorf("%s is not supported with --stream", conflict.name))
+		}
 	}
 
-	result, err := auditor.AuditExternalSharing(ctx)
+	formats, err := cfg.Output.EffectiveFormats()
 	if err != nil {
-		return fmt.Errorf("audit failed: %w", err)
+		return exitcode.Wrap(exitcode.ConfigError, err)
+	}
+	if len(formats) != 1 || (formats[0] != reporter.FormatCSV && formats[0] != "") {
+		return exitcode.Wrap(exitcode.ConfigError, errors.New("--stream requires output.format csv"))
+	}
+	return nil
+}
+
+// checkCheckpointOptions rejects --resume without --checkpoint and the
+// options whose results a checkpoint cannot resume. The report is appended
+// to as the audit runs, so it must be a single CSV file.
+func checkCheckpointOptions(cfg *config.Config) error {
+	if checkpointPath == "" {
+		if resumeAudit {
+			return exitcode.Wrap(exitcode.ConfigError, errors.New("--resume requires --checkpoint"))
+		}
+		return nil
+	}
+	for _, conflict := range []struct {
+		set  bool
+		name string
+	}{
+		{streamShares, "--stream"},
+		{sampleSize > 0, "--sample"},
+		{countOnly, "--count-only"},
+		{maxRows > 0, "--max-rows"},
+		{cfg.Output.MaxRowsPerFile > 0, "output.max_rows_per_file"},
+		// A random salt would anonymize the resumed rows differently.
+		{anonymize && anonymizeSalt == "", "--anonymize without --anonymize-salt"},
+	} {
+		if conflict.set {
+			return exitcode.Wrap(exitcode.ConfigError, fmt.Errorf("--checkpoint is not supported with %s", conflict.name))
+		}
 	}
 
-	rep, err := reporter.NewCSVReporter(cfg.Output.Directory)
+	formats, err := cfg.Output.EffectiveFormats()
 	if err != nil {
-		return fmt.Errorf("failed to create reporter: %w", err)
+		return exitcode.Wrap(exitcode.ConfigError, err)
 	}
+	if len(formats) != 1 || (formats[0] != reporter.FormatCSV && formats[0] != "") {
+		return exitcode.Wrap(exitcode.ConfigError, errors.New("--checkpoint requires output.format csv"))
+	}
+	return nil
+}
 
-	if err := rep.WriteExternalSharing(result.ExternalShares); err != nil {
-		return fmt.Errorf("failed to write report: %w", err)
+// useCheckpoint opens the --checkpoint file and sets it on auditor, with
+// the report opened by openReport receiving the shares of each batch. With
+// --resume the progress recorded in the file is read first and the audit
+// continues from it; a missing file starts a new audit. A last line cut
+// short by the interruption is cut off, so the lines appended next can be
+// read back. Otherwise the file is truncated. openReport is passed the
+// report size recorded by the checkpoint, or 0 for a new audit. It returns
+// nil without --checkpoint.
+func useCheckpoint(cfg *config.Config, auditor *audit.Auditor, openReport func(size int64) (audit.ShareAppender, error)) (*os.File, error) {
+	if checkpointPath == "" {
+		return nil, nil
 	}
 
-	if !quiet {
-		fmt.Printf("Sharing audit complete. Files processed: %d\n", result.FilesProcessed)
-		fmt.Printf("External shares found: %d\n", result.TotalExternalShares)
-		fmt.Printf("Report saved to: %s/external_sharing.csv\n", rep.OutputDir())
-
-		if len(result.Errors) > 0 {
-			fmt.Printf("Warnings: %d files could not be processed\n", len(result.Errors))
-			if verbose {
-				for _, e := range result.Errors {
-					fmt.Printf("  - %v\n", e)
-				}
+	var resume *audit.Checkpoint
+	if resumeAudit {
+		f, err := os.Open(checkpointPath)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+		case err != nil:
+			return nil, exitcode.Wrap(exitcode.ConfigError, fmt.Errorf("failed to open checkpoint: %w", err))
+		default:
+			resume, err = audit.ReadCheckpoint(f, cfg.Google.Domain)
+			f.Close()
+			if err != nil {
+				return nil, exitcode.Wrap(exitcode.ConfigError, fmt.Errorf("failed to resume from %s: %w", checkpointPath, err))
 			}
 		}
 	}
 
+	flag := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	var reportSize int64
+	if resume != nil {
+		if resume.ReportSize == 0 {
+			return nil, exitcode.Wrap(exitcode.ConfigError,
+				fmt.Errorf("failed to resume from %s: the checkpoint does not record the report size", checkpointPath))
+		}
+		flag, reportSize = os.O_WRONLY|os.O_APPEND, resume.ReportSize
+	}
+	f, err := os.OpenFile(checkpointPath, flag, 0o600)
+	if err != nil {
+		return nil, exitcode.Wrap(exitcode.ConfigError, fmt.Errorf("failed to open checkpoint: %w", err))
+	}
+	if resume != nil {
+		if err := f.Truncate(resume.Size); err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("failed to truncate checkpoint: %w", err)
+		}
+	}
+
+	report, err := openReport(reportSize)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	auditor.SetCheckpoint(f, resume, report)
+	return f, nil
+}
+
+// finishCheckpoint removes the --checkpoint file once the reports of a
+// complete audit are written. Audits cut short by the API call budget keep
+// it so that --resume can continue them.
+func finishCheckpoint(f *os.File, result *audit.AuditResult) error {
+	if f == nil || result.BudgetExceeded {
+		return nil
+	}
+	if err := os.Remove(f.Name()); err != nil {
+		return fmt.Errorf("failed to remove checkpoint: %w", err)
+	}
 	return nil
 }
 
-func runAuditAll(cmd *cobra.Command, args []string) error {
-	cfg, err := loadConfig()
+// runAuditSharingCheckpointed runs the sharing audit with --checkpoint,
+// appending the shares of each checkpointed batch to the report, so a
+// resumed run keeps the rows written before the interruption. Rows are in
+// file ID order rather than sorted.
+func runAuditSharingCheckpointed(run *auditRun) error {
+	cfg := run.cfg
+	rep, err := run.openReporter("external_sharing")
 	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+		return err
+	}
+	csvRep, ok := rep.(*reporter.CSVReporter)
+	if !ok {
+		return fmt.Errorf("unexpected reporter %T for --checkpoint", rep)
 	}
 
-	ctx := context.Background()
-	auditor, err := audit.NewAuditor(cfg)
-	if err != nil {
-		return fmt.Errorf("failed to create auditor: %w", err)
+	var anonymizer *audit.Anonymizer
+	if anonymize {
+		// A single anonymizer keeps the report and the result consistent.
+		if anonymizer, err = audit.NewAnonymizer(anonymizeSalt); err != nil {
+			return err
+		}
 	}
 
-	if !quiet {
-		fmt.Println("Running all audits...")
+	var report *shareReport
+	checkpoint, err := useCheckpoint(cfg, run.auditor, func(size int64) (audit.ShareAppender, error) {
+		appender, err := csvRep.AppendExternalSharing(size)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open report: %w", err)
+		}
+		report = &shareReport{ShareRecordAppender: appender, cfg: cfg, anonymizer: anonymizer}
+		return report, nil
+	})
+	if err != nil {
+		return err
 	}
+	run.checkpoint = checkpoint
 
-	filesResult, sharingResult, err := auditor.AuditAll(ctx)
+	progress("Analyzing external sharing...")
+	result, err := run.auditor.AuditExternalSharing(run.ctx)
+	closeErr := report.Close()
+	if errors.Is(err, context.Canceled) {
+		return fmt.Errorf("audit interrupted, continue it with --resume: %w", err)
+	}
 	if err != nil {
 		return fmt.Errorf("audit failed: %w", err)
 	}
+	if closeErr != nil {
+		return fmt.Errorf("failed to write report: %w", closeErr)
+	}
+
+	if run.diff, err = compareBaseline(cfg, result); err != nil {
+		return err
+	}
+
+	// The result and new shares are filtered and anonymized like the
+	// report rows, for the summary, the history and --post-url.
+	for _, r := range []*audit.AuditResult{result, run.diff.added} {
+		if r == nil {
+			continue
+		}
+		applyFilters(cfg, r)
+		if anonymizer != nil {
+			r.ExternalShares = anonymizer.ExternalShares(r.ExternalShares)
+		}
+	}
+	run.sharing = result
+	run.alerts = audit.EvaluateAlerts(cfg.Alert, result)
+	return writeShareExtras(run)
+}
 
-	rep, err := reporter.NewCSVReporter(cfg.Output.Directory)
+// shareReport appends the shares of each checkpointed batch to the external
+// sharing report, filtered and anonymized as postProcess does.
+type shareReport struct {
+	*reporter.ShareRecordAppender
+	cfg        *config.Config
+	anonymizer *audit.Anonymizer
+}
+
+func (r *shareReport) AppendShares(records []audit.ExternalShareRecord) (int64, error) {
+	// The checkpoint keeps the shares as found, so a copy is filtered.
+	chunk := &audit.AuditResult{ExternalShares: slices.Clone(records)}
+	applyFilters(r.cfg, chunk)
+	if r.anonymizer != nil {
+		chunk.ExternalShares = r.anonymizer.ExternalShares(chunk.ExternalShares)
+	}
+	return r.Write(chunk.ExternalShares)
+}
+
+// runAuditSharingStreamed runs the sharing audit with --stream, writing
+// each share as the permission workers find it instead of holding every
+// share.
+func runAuditSharingStreamed(run *auditRun) error {
+	cfg := run.cfg
+	rep, err := run.openReporter("external_sharing")
 	if err != nil {
-		return fmt.Errorf("failed to create reporter: %w", err)
+		return err
+	}
+	csvRep, ok := rep.(*reporter.CSVReporter)
+	if !ok {
+		return fmt.Errorf("unexpected reporter %T for --stream", rep)
 	}
 
-	if err := rep.WriteFilesByOwner(filesResult.FileRecords); err != nil {
-		return fmt.Errorf("failed to write files report: %w", err)
+	var anonymizer *audit.Anonymizer
+	if anonymize {
+		// A single anonymizer keeps the mapping consistent across records.
+		if anonymizer, err = audit.NewAnonymizer(anonymizeSalt); err != nil {
+			return err
+		}
 	}
 
-	if err := rep.WriteExternalSharing(sharingResult.ExternalShares); err != nil {
-		return fmt.Errorf("failed to write sharing report: %w", err)
+	stream, err := csvRep.StreamExternalSharing()
+	if err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
 	}
+	defer stream.Abort()
 
-	if !quiet {
-		fmt.Printf("Files audit complete. Total files: %d\n", filesResult.TotalFiles)
-		fmt.Printf("Report saved to: %s/files_by_owner.csv\n", rep.OutputDir())
-		fmt.Printf("Sharing audit complete. Files processed: %d\n", sharingResult.FilesProcessed)
-		fmt.Printf("External shares found: %d\n", sharingResult.TotalExternalShares)
-		fmt.Printf("Report saved to: %s/external_sharing.csv\n", rep.OutputDir())
-
-		if len(sharingResult.Errors) > 0 {
-			fmt.Printf("Warnings: %d files could not be processed\n", len(sharingResult.Errors))
-			if verbose {
-				for _, e := range sharingResult.Errors {
-					fmt.Printf("  - %v\n", e)
+	progress("Analyzing external sharing, writing shares as they are found...")
+
+	// The writer cancels the audit if it fails, and keeps draining so the
+	// workers are never left blocked on a full channel.
+	ctx, cancel := context.WithCancel(run.ctx)
+	defer cancel()
+	records := make(chan audit.ExternalShareRecord, streamBuffer)
+	var filtered audit.FilterStats
+	var counts audit.ShareCounts
+	written := make(chan error, 1)
+	go func() {
+		var writeErr error
+		for record := range records {
+			if writeErr != nil {
+				continue
+			}
+			chunk := &audit.AuditResult{ExternalShares: []audit.ExternalShareRecord{record}}
+			applyFilters(cfg, chunk)
+			filtered.Merge(chunk.Filtered)
+			counts.AddShares(chunk.ExternalShares)
+			if anonymizer != nil {
+				chunk.ExternalShares = anonymizer.ExternalShares(chunk.ExternalShares)
+			}
+			for _, rec := range chunk.ExternalShares {
+				if writeErr = stream.Write(rec); writeErr != nil {
+					cancel()
+					break
 				}
 			}
 		}
+		written <- writeErr
+	}()
+
+	result, err := run.auditor.AuditExternalSharingStream(ctx, records)
+	if writeErr := <-written; writeErr != nil {
+		return fmt.Errorf("failed to write report: %w", writeErr)
+	}
+	if err != nil {
+		return fmt.Errorf("audit failed: %w", err)
+	}
+	result.Filtered = filtered
+	for _, c := range filtered {
+		result.TotalExternalShares -= c.Shares
+	}
+	counts.External = result.TotalExternalShares
+	run.alerts = audit.EvaluateAlertCounts(cfg.Alert, counts)
+
+	if err := stream.Commit(); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	run.sharing = result
+	return nil
+}
+
+func runAuditPublic(cmd *cobra.Command, args []string) error {
+	return runAuditJob(cmd, auditJob{
+		snapshots: true,
+		audit: func(run *auditRun) error {
+			progress("Analyzing public sharing...")
+			result, err := run.auditor.AuditPublicShares(run.ctx)
+			if err != nil {
+				return fmt.Errorf("audit failed: %w", err)
+			}
+			run.sharing = result
+			return nil
+		},
+		write: func(run *auditRun) error {
+			rep, err := run.openReporter("public_shares")
+			if err != nil {
+				return err
+			}
+			if err := rep.WritePublicShares(run.sharingReport.ExternalShares); err != nil {
+				return fmt.Errorf("failed to write report: %w", err)
+			}
+			return writeShareExtras(run)
+		},
+		summary: func(run *auditRun) error {
+			result := run.sharing
+			fmt.Printf("Public sharing audit complete. Files processed: %d\n", result.FilesProcessed)
+			fmt.Printf("Public shares found: %d\n", result.TotalExternalShares)
+			printSuppressed(result)
+			printFiltered(result)
+			printCachedFiles(result)
+			printSampleEstimate(result, "public shares")
+			fmt.Printf("Report saved to: %s\n", reportPath(run.rep, "public_shares"))
+			if err := printRoleDistribution(run.cfg, result.ExternalShares); err != nil {
+				return err
+			}
+
+			printWarnings(run.cfg, run.sharingReport, "files could not be processed")
+			printTiming(result)
+			return nil
+		},
+	})
+}
+
+func runAuditDomainShares(cmd *cobra.Command, args []string) error {
+	return runAuditJob(cmd, auditJob{
+		snapshots: true,
+		audit: func(run *auditRun) error {
+			progress("Analyzing domain-wide sharing...")
+			result, err := run.auditor.AuditDomainShares(run.ctx)
+			if err != nil {
+				return fmt.Errorf("audit failed: %w", err)
+			}
+			run.sharing = result
+			return nil
+		},
+		write: func(run *auditRun) error {
+			rep, err := run.openReporter("domain_shares")
+			if err != nil {
+				return err
+			}
+			if err := rep.WriteDomainShares(run.sharingReport.ExternalShares); err != nil {
+				return fmt.Errorf("failed to write report: %w", err)
+			}
+			return nil
+		},
+		summary: func(run *auditRun) error {
+			result := run.sharing
+			fmt.Printf("Domain-wide sharing audit complete. Files processed: %d\n", result.FilesProcessed)
+			fmt.Printf("Domain-wide shares found: %d\n", result.TotalExternalShares)
+			printSuppressed(result)
+			printFiltered(result)
+			printCachedFiles(result)
+			printSampleEstimate(result, "domain-wide shares")
+			fmt.Printf("Report saved to: %s\n", reportPath(run.rep, "domain_shares"))
+
+			printWarnings(run.cfg, run.sharingReport, "files could not be processed")
+			printTiming(result)
+			return nil
+		},
+	})
+}
+
+func runAuditExternalOwners(cmd *cobra.Command, args []string) error {
+	return runAuditJob(cmd, auditJob{
+		audit: func(run *auditRun) error {
+			progress("Checking file ownership...")
+			result, err := run.auditor.AuditExternalOwners(run.ctx)
+			if err != nil {
+				return fmt.Errorf("audit failed: %w", err)
+			}
+			run.files = result
+			return nil
+		},
+		write: func(run *auditRun) error {
+			rep, err := run.openReporter("external_owners")
+			if err != nil {
+				return err
+			}
+			if err := rep.WriteExternalOwners(run.filesReport.FileRecords); err != nil {
+				return fmt.Errorf("failed to write report: %w", err)
+			}
+			return nil
+		},
+		summary: func(run *auditRun) error {
+			result := run.files
+			fmt.Printf("External owners audit complete. Total files: %d\n", result.TotalFiles)
+			fmt.Printf("Externally owned files found: %d\n", len(result.FileRecords))
+			printSuppressed(result)
+			printFiltered(result)
+			printSampleEstimate(result, "")
+			fmt.Printf("Report saved to: %s\n", reportPath(run.rep, "external_owners"))
+			printWarnings(run.cfg, run.filesReport, "files have malformed data")
+			printTiming(result)
+			return nil
+		},
+	})
+}
+
+func runAuditSharedWithMe(cmd *cobra.Command, args []string) error {
+	return runAuditJob(cmd, auditJob{
+		audit: func(run *auditRun) error {
+			progress("Checking files shared with you...")
+			result, err := run.auditor.AuditSharedWithMe(run.ctx)
+			if err != nil {
+				return fmt.Errorf("audit failed: %w", err)
+			}
+			run.files = result
+			return nil
+		},
+		write: func(run *auditRun) error {
+			rep, err := run.openReporter("shared_with_me")
+			if err != nil {
+				return err
+			}
+			if err := rep.WriteSharedWithMe(run.filesReport.FileRecords); err != nil {
+				return fmt.Errorf("failed to write report: %w", err)
+			}
+			return nil
+		},
+		summary: func(run *auditRun) error {
+			result := run.files
+			fmt.Printf("Shared-with-me audit complete. Files shared with you: %d\n", result.TotalFiles)
+			fmt.Printf("Shared from outside the domain: %d\n", len(result.FileRecords))
+			printSuppressed(result)
+			printFiltered(result)
+			printSampleEstimate(result, "")
+			fmt.Printf("Report saved to: %s\n", reportPath(run.rep, "shared_with_me"))
+			printWarnings(run.cfg, run.filesReport, "files have malformed data")
+			printTiming(result)
+			return nil
+		},
+	})
+}
+
+func runAuditFile(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	ctx, stop := interruptContext()
+	defer stop()
+	auditor, err := newAuditor(ctx, cmd, cfg)
+	if err != nil {
+		return err
 	}
 
+	result, err := auditor.AuditSingleFile(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("audit failed: %w", err)
+	}
+
+	return printSingleFile(cmd.OutOrStdout(), result)
+}
+
+func runAuditOwners(cmd *cobra.Command, args []string) error {
+	var owners []audit.OwnerSummary
+	return runAuditJob(cmd, auditJob{
+		audit: auditAllFiles,
+		write: func(run *auditRun) error {
+			order, err := audit.NewRecordOrder(run.cfg.Output.SortOwnersBy, run.cfg.Output.SortFilesBy)
+			if err != nil {
+				return err
+			}
+			owners = audit.SummarizeByOwner(run.files.FileRecords)
+			order.SortOwnerSummaries(owners)
+
+			rep, err := run.openReporter("owners")
+			if err != nil {
+				return err
+			}
+			if err := rep.WriteOwners(owners); err != nil {
+				return fmt.Errorf("failed to write report: %w", err)
+			}
+			return nil
+		},
+		summary: func(run *auditRun) error {
+			result := run.files
+			fmt.Printf("Owners inventory complete. Total files: %d, owners: %d\n", result.TotalFiles, len(owners))
+			printSuppressed(result)
+			printFiltered(result)
+			printSampleEstimate(result, "")
+			fmt.Printf("Report saved to: %s\n", reportPath(run.rep, "owners"))
+			printTiming(result)
+			return nil
+		},
+	})
+}
+
+func runAuditDuplicates(cmd *cobra.Command, args []string) error {
+	var duplicates []audit.DuplicateGroup
+	return runAuditJob(cmd, auditJob{
+		audit: auditAllFiles,
+		write: func(run *auditRun) error {
+			duplicates = audit.FindDuplicates(run.files.FileRecords)
+
+			rep, err := run.openReporter("duplicates")
+			if err != nil {
+				return err
+			}
+			if err := rep.WriteDuplicates(duplicates); err != nil {
+				return fmt.Errorf("failed to write report: %w", err)
+			}
+			return nil
+		},
+		summary: func(run *auditRun) error {
+			result := run.files
+			fmt.Printf("Duplicates audit complete. Total files: %d, duplicate groups: %d\n", result.TotalFiles, len(duplicates))
+			printSuppressed(result)
+			printFiltered(result)
+			printSampleEstimate(result, "")
+			fmt.Printf("Report saved to: %s\n", reportPath(run.rep, "duplicates"))
+			printTiming(result)
+			return nil
+		},
+	})
+}
+
+func runAuditAll(cmd *cobra.Command, args []string) error {
+	return runAuditJob(cmd, auditJob{
+		check:       checkAuditAllOptions,
+		ownAuditors: true,
+		snapshots:   true,
+		audit: func(run *auditRun) error {
+			opts, err := runOptions(run.cmd)
+			if err != nil {
+				return err
+			}
+
+			progress("Running all audits...")
+
+			if opts.FileSnapshots, err = loadFileSnapshots(run.cfg); err != nil {
+				return err
+			}
+
+			report, err := runAudit(run.ctx, run.cfg, opts)
+			if err != nil {
+				return fmt.Errorf("audit failed: %w", err)
+			}
+			run.files, run.sharing = report.FilesResult, report.SharingResult
+			return nil
+		},
+		alerts: true,
+		write: func(run *auditRun) error {
+			if toStdout {
+				return printCombined(os.Stdout, run.cmd, run.cfg, run.filesReport, run.sharingReport)
+			}
+
+			rep, err := run.openReporter("")
+			if err != nil {
+				return err
+			}
+			if err := rep.WriteFilesByOwner(run.filesReport.FileRecords); err != nil {
+				return fmt.Errorf("failed to write files report: %w", err)
+			}
+			if err := rep.WriteExternalSharing(run.sharingReport.ExternalShares); err != nil {
+				return fmt.Errorf("failed to write sharing report: %w", err)
+			}
+			return writeShareExtras(run)
+		},
+		history: true,
+		summary: func(run *auditRun) error {
+			if err := printFilesSummary(run); err != nil {
+				return err
+			}
+			return printSharingSummary(run)
+		},
+	})
+}
+
+// checkAuditAllOptions rejects the options audit all does not support.
+func checkAuditAllOptions(cmd *cobra.Command, cfg *config.Config) error {
+	if toStdout && countOnly {
+		return exitcode.Wrap(exitcode.ConfigError, errors.New("--stdout cannot be combined with --count-only"))
+	}
+	if toStdout && cmd.Flags().Changed("output-file") {
+		return exitcode.Wrap(exitcode.ConfigError, errors.New("--stdout cannot be combined with --output-file"))
+	}
+	if cfg.Output.File != "" && !toStdout {
+		return exitcode.Wrap(exitcode.ConfigError,
+			errors.New("output.file is not supported by audit all, which writes several reports; use output.directory"))
+	}
+	if cfg.Audit.ChunkByOwner {
+		return exitcode.Wrap(exitcode.ConfigError,
+			errors.New("audit.chunk_by_owner is only supported by audit files"))
+	}
+	return nil
+}
+
+// runAudit runs audit all's files and sharing audits: on every domain of
+// google.domains when set, else on google.domain. Domains that failed are
+// reported on stderr.
+func runAudit(ctx context.Context, cfg *config.Config, opts audit.RunOptions) (*audit.AuditReport, error) {
+	if len(cfg.Google.Domains) == 0 {
+		return audit.RunAudit(ctx, cfg, opts)
+	}
+
+	report, err := audit.RunMultiDomainAudit(ctx, cfg, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, domainErr := range report.DomainErrors {
+		fmt.Fprintf(os.Stderr, "Warning: audit of %s failed, its records are missing: %v\n", domainErr.Domain, domainErr.Err)
+	}
+	return report, nil
+}
+
+// postProcess applies the output transforms selected on the command line to
+// audit results before they are written.
+func postProcess(cfg *config.Config, results ...*audit.AuditResult) error {
+	for _, result := range results {
+		applyFilters(cfg, result)
+	}
+
+	if !anonymize {
+		return nil
+	}
+
+	// A single anonymizer keeps the mapping consistent across reports.
+	anonymizer, err := audit.NewAnonymizer(anonymizeSalt)
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		result.FileRecords = anonymizer.FileRecords(result.FileRecords)
+		result.ExternalShares = anonymizer.ExternalShares(result.ExternalShares)
+	}
+
+	return nil
+}
+
+// applyFilters drops records excluded by the filter flags and updates totals.
+func applyFilters(cfg *config.Config, result *audit.AuditResult) {
+	if excludeAdmin {
+		admins := adminEmails(cfg)
+		result.FilterFiles("exclude-admin", func(records []audit.FileRecord) []audit.FileRecord {
+			return audit.ExcludeOwners(records, admins)
+		})
+		result.FilterShares("exclude-admin", func(records []audit.ExternalShareRecord) []audit.ExternalShareRecord {
+			return audit.ExcludeOwners(records, admins)
+		})
+	}
+	if len(ownerDomains) > 0 {
+		result.FilterFiles("owner-domain", func(records []audit.FileRecord) []audit.FileRecord {
+			return audit.FilterFilesByOwnerDomain(records, ownerDomains)
+		})
+		result.FilterShares("owner-domain", func(records []audit.ExternalShareRecord) []audit.ExternalShareRecord {
+			return audit.FilterSharesByOwnerDomain(records, ownerDomains)
+		})
+	}
+	if resumeOwner != "" {
+		result.FilterFiles("resume-from-owner", func(records []audit.FileRecord) []audit.FileRecord {
+			return audit.ResumeFromOwner(records, resumeOwner)
+		})
+		result.FilterShares("resume-from-owner", func(records []audit.ExternalShareRecord) []audit.ExternalShareRecord {
+			return audit.ResumeFromOwner(records, resumeOwner)
+		})
+	}
+	if len(sharedWith) > 0 {
+		result.FilterShares("shared-with", func(records []audit.ExternalShareRecord) []audit.ExternalShareRecord {
+			return audit.FilterSharedWith(records, sharedWith)
+		})
+	}
+	if directOnly {
+		result.FilterShares("direct-only", audit.FilterDirectOnly)
+	}
+	if deletedOnly {
+		result.FilterShares("deleted-grantees-only", audit.FilterDeletedGrantees)
+	}
+	if flaggedOnly {
+		result.FilterShares("flagged-only", audit.FilterFlagged)
+	}
+	if expiringWithin > 0 {
+		now := time.Now()
+		result.FilterShares("expiring-within", func(records []audit.ExternalShareRecord) []audit.ExternalShareRecord {
+			return audit.FilterExpiringWithin(records, now, expiringWithin)
+		})
+	}
+	if !notAccessedSince.IsZero() {
+		result.FilterFiles("not-accessed-since", func(records []audit.FileRecord) []audit.FileRecord {
+			return audit.FilterNotAccessedSince(records, notAccessedSince.Time)
+		})
+	}
+}
+
+// adminEmails returns the admin addresses gwork impersonates: google.admin_email,
+// or that of each of google.domains.
+func adminEmails(cfg *config.Config) []string {
+	var admins []string
+	if cfg.Google.AdminEmail != "" {
+		admins = append(admins, cfg.Google.AdminEmail)
+	}
+	for _, d := range cfg.Google.Domains {
+		admins = append(admins, d.AdminEmail)
+	}
+	return admins
+}
+
+// checkOutputWritable fails before a long audit rather than when writing
+// the reports. Count-only runs write no reports, so it is skipped for them.
+func checkOutputWritable(cfg *config.Config) error {
+	if countOnly {
+		return nil
+	}
+	if err := reporter.CheckWritable(outputDir(cfg)); err != nil {
+		return exitcode.Wrap(exitcode.ConfigError, err)
+	}
+	return nil
+}
+
+// printCounts writes the totals of results to w as a single JSON object,
+// for --count-only.
+func printCounts(w io.Writer, results ...*audit.AuditResult) error {
+	data, err := json.Marshal(audit.Summarize(results...))
+	if err != nil {
+		return fmt.Errorf("failed to encode totals: %w", err)
+	}
+	if _, err := fmt.Fprintln(w, string(data)); err != nil {
+		return fmt.Errorf("failed to write totals: %w", err)
+	}
+	return nil
+}
+
+// printCombined writes the files and sharing results and their totals to w
+// as a single JSON document, for --stdout.
+func printCombined(w io.Writer, cmd *cobra.Command, cfg *config.Config, filesResult, sharingResult *audit.AuditResult) error {
+	report := reporter.NewCombinedReport(runMeta(cmd, cfg), filesResult, sharingResult)
+	return reporter.WriteCombinedJSON(w, report, cfg.Output.JSONIndent)
+}
+
+// printSingleFile writes the metadata of the file in result to w, followed
+// by a table of its permissions.
+func printSingleFile(w io.Writer, result *audit.SingleFileResult) error {
+	f := result.File
+	fmt.Fprintf(w, "File:     %s (%s)\n", f.FileName, f.FileID)
+	fmt.Fprintf(w, "Owner:    %s\n", f.OwnerEmail)
+	fmt.Fprintf(w, "Type:     %s\n", f.FileType)
+	if f.DriveName != "" {
+		fmt.Fprintf(w, "Location: %s (%s)\n", f.Location, f.DriveName)
+	} else {
+		fmt.Fprintf(w, "Location: %s\n", f.Location)
+	}
+	if !f.ModifiedTime.IsZero() {
+		fmt.Fprintf(w, "Modified: %s\n", f.ModifiedTime.Format(time.RFC3339))
+	}
+	if f.Trashed {
+		fmt.Fprintln(w, "Trashed:  yes")
+	}
+	fmt.Fprintln(w)
+
+	if len(result.Permissions) == 0 {
+		fmt.Fprintln(w, "No permissions returned.")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TYPE\tROLE\tGRANTEE\tINHERITED\tEXPIRES\tFLAGS")
+	for _, p := range result.Permissions {
+		grantee := p.EmailAddress
+		if grantee == "" {
+			grantee = p.Domain
+		}
+		if grantee == "" {
+			grantee = "-"
+		}
+		expires := "-"
+		if !p.ExpirationTime.IsZero() {
+			expires = p.ExpirationTime.Format(time.DateOnly)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%t\t%s\t%s\n",
+			p.Type, p.Role, grantee, p.Inherited, expires, permissionFlags(p))
+	}
+	return tw.Flush()
+}
+
+// permissionFlags lists the external, public and flagged markers of p, or
+// "-" when it has none.
+func permissionFlags(p audit.FilePermission) string {
+	var flags []string
+	if p.External {
+		flags = append(flags, "external")
+	}
+	if p.Public {
+		flags = append(flags, "public")
+	}
+	if p.Flagged {
+		flags = append(flags, "flagged")
+	}
+	if len(flags) == 0 {
+		return "-"
+	}
+	return strings.Join(flags, ",")
+}
+
+// checkFailAbove returns a FindingsDetected error when --fail-above is set
+// and a share in results has a risk score above it. Reports are written
+// before it is called.
+func checkFailAbove(cmd *cobra.Command, results ...*audit.AuditResult) error {
+	if !cmd.Flags().Changed("fail-above") {
+		return nil
+	}
+
+	count := 0
+	for _, result := range results {
+		count += audit.CountAboveRisk(result.ExternalShares, failAbove)
+	}
+	if count > 0 {
+		return exitcode.Wrap(exitcode.FindingsDetected,
+			fmt.Errorf("%d shares have a risk score above %d", count, failAbove))
+	}
+	return nil
+}
+
+// checkFindings returns checkFailAbove's error or, with
+// alert.fail_on_breach, an error exiting with the same code when an alert
+// threshold is breached.
+func checkFindings(cmd *cobra.Command, cfg *config.Config, alerts []audit.Alert, results ...*audit.AuditResult) error {
+	if err := checkFailAbove(cmd, results...); err != nil {
+		return err
+	}
+
+	if breached := audit.CountBreached(alerts); breached > 0 && cfg.Alert.FailOnBreach {
+		return exitcode.Wrap(exitcode.FindingsDetected,
+			fmt.Errorf("%d alert thresholds breached", breached))
+	}
+	return nil
+}
+
+// dateValue is a flag value holding a date given as YYYY-MM-DD (midnight
+// UTC) or an RFC3339 timestamp.
+type dateValue struct {
+	time.Time
+}
+
+// Set parses s as a date or timestamp.
+func (d *dateValue) Set(s string) error {
+	for _, layout := range []string{time.DateOnly, time.RFC3339} {
+		if t, err := time.Parse(layout, s); err == nil {
+			d.Time = t
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid date %q: use YYYY-MM-DD or RFC3339", s)
+}
+
+// String returns the date in RFC3339 form, or "" if unset.
+func (d *dateValue) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format(time.RFC3339)
+}
+
+// Type returns the flag type shown in usage.
+func (d *dateValue) Type() string {
+	return "date"
+}
+
+// runOptions validates the audit flags and returns the options they set.
+func runOptions(cmd *cobra.Command) (audit.RunOptions, error) {
+	if sampleSize < 0 {
+		return audit.RunOptions{}, exitcode.Wrap(exitcode.ConfigError, fmt.Errorf("--sample must not be negative, got %d", sampleSize))
+	}
+	if maxRows < 0 {
+		return audit.RunOptions{}, exitcode.Wrap(exitcode.ConfigError, fmt.Errorf("--max-rows must not be negative, got %d", maxRows))
+	}
+	if failAbove < 0 {
+		return audit.RunOptions{}, exitcode.Wrap(exitcode.ConfigError, fmt.Errorf("--fail-above must not be negative, got %d", failAbove))
+	}
+	for _, pattern := range sharedWith {
+		if !strings.Contains(pattern, "@") {
+			return audit.RunOptions{}, exitcode.Wrap(exitcode.ConfigError, fmt.Errorf("--shared-with must be an email address or @domain, got %q", pattern))
+		}
+	}
+
+	opts := audit.RunOptions{SampleSize: sampleSize, SampleSeed: sampleSeed, Explain: explain}
+	if !quiet {
+		opts.ThrottleLog = os.Stderr
+	}
+	if activityLog != "" {
+		f, err := openActivityLog(activityLog)
+		if err != nil {
+			return audit.RunOptions{}, err
+		}
+		opts.ActivityLog = f
+	}
+	if sampleSize > 0 && !cmd.Flags().Changed("sample-seed") {
+		opts.SampleSeed = time.Now().UnixNano()
+	}
+	return opts, nil
+}
+
+// newAuditor creates the auditor for cfg with the sampling flags applied.
+// ctx bounds authentication and must outlive the audit.
+func newAuditor(ctx context.Context, cmd *cobra.Command, cfg *config.Config) (*audit.Auditor, error) {
+	opts, err := runOptions(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	auditor, err := audit.NewAuditorContext(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create auditor: %w", err)
+	}
+
+	if opts.SampleSize > 0 {
+		auditor.SetSample(opts.SampleSize, opts.SampleSeed)
+	}
+	auditor.SetExplain(opts.Explain)
+	auditor.SetThrottleLog(opts.ThrottleLog)
+	auditor.SetActivityLog(opts.ActivityLog)
+
+	return auditor, nil
+}
+
+// activityFile is the open --activity-log file, closed by main once the
+// command has run.
+var activityFile *activityLogFile
+
+// activityLogFile is the --activity-log file. Drive calls do not fail when
+// their line cannot be written, so it remembers the first failed write and
+// closeActivityLog reports it, failing a run whose trail is missing lines.
+type activityLogFile struct {
+	f *os.File
+
+	mu  sync.Mutex
+	err error
+}
+
+func (a *activityLogFile) Write(p []byte) (int, error) {
+	n, err := a.f.Write(p)
+	if err != nil {
+		a.mu.Lock()
+		if a.err == nil {
+			a.err = err
+		}
+		a.mu.Unlock()
+	}
+	return n, err
+}
+
+// openActivityLog opens the --activity-log file at path for appending,
+// creating it if needed, so successive runs add to one trail. Later calls
+// return the file already open.
+func openActivityLog(path string) (*activityLogFile, error) {
+	if activityFile != nil {
+		return activityFile, nil
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, exitcode.Wrap(exitcode.ConfigError, fmt.Errorf("failed to open activity log: %w", err))
+	}
+	activityFile = &activityLogFile{f: f}
+	return activityFile, nil
+}
+
+// closeActivityLog closes the --activity-log file, if one was opened, and
+// returns the first error writing or closing it.
+func closeActivityLog() error {
+	if activityFile == nil {
+		return nil
+	}
+	a := activityFile
+	activityFile = nil
+	closeErr := a.f.Close()
+	if a.err != nil {
+		return fmt.Errorf("failed to write activity log: %w", a.err)
+	}
+	if closeErr != nil {
+		return fmt.Errorf("failed to close activity log: %w", closeErr)
+	}
+	return nil
+}
+
+// loadFileSnapshots returns the file permissions saved by the previous
+// incremental run, for Auditor.SetFileSnapshots. It returns nil unless
+// audit.incremental is set, and an empty map with --full, when there are
+// none yet, or when they were saved for another domain, so the run
+// fetches everything but still saves its permissions.
+func loadFileSnapshots(cfg *config.Config) (map[string]audit.FileSnapshot, error) {
+	if !cfg.Audit.Incremental {
+		return nil, nil
+	}
+	if fullAudit {
+		return map[string]audit.FileSnapshot{}, nil
+	}
+
+	saved, err := history.ReadFileSnapshots(history.FileSnapshotsPath(cfg.Output.HistoryFile))
+	if err != nil {
+		return nil, err
+	}
+	if saved == nil || saved.Domain != cfg.AuditedDomain() || saved.Files == nil {
+		return map[string]audit.FileSnapshot{}, nil
+	}
+	return saved.Files, nil
+}
+
+// useFileSnapshots sets the file permissions saved by the previous
+// incremental run on auditor; see loadFileSnapshots.
+func useFileSnapshots(cfg *config.Config, auditor *audit.Auditor) error {
+	snapshots, err := loadFileSnapshots(cfg)
+	if err != nil {
+		return err
+	}
+	auditor.SetFileSnapshots(snapshots)
+	return nil
+}
+
+// saveFileSnapshots saves the file permissions used by result for the
+// next incremental run. Sampled runs and runs cut short by the API call
+// budget or resumed from a checkpoint keep the previous snapshots, as they
+// only cover some files.
+func saveFileSnapshots(cfg *config.Config, result *audit.AuditResult) error {
+	if result.FileSnapshots == nil || result.SampledFiles > 0 || result.BudgetExceeded || result.ResumedFiles > 0 {
+		return nil
+	}
+
+	snapshots := history.FileSnapshots{
+		Timestamp: time.Now().UTC(),
+		Domain:    cfg.AuditedDomain(),
+		Files:     result.FileSnapshots,
+	}
+	return history.WriteFileSnapshots(history.FileSnapshotsPath(cfg.Output.HistoryFile), snapshots)
+}
+
+// printCachedFiles notes how many files reused the permissions saved by
+// the previous incremental run.
+func printCachedFiles(result *audit.AuditResult) {
+	if result.CachedFiles > 0 {
+		fmt.Printf("Reused saved permissions of %d unchanged files\n", result.CachedFiles)
+	}
+}
+
+// printSuppressed notes how many files the ignore list left out.
+func printSuppressed(result *audit.AuditResult) {
+	if result.SuppressedCount > 0 {
+		fmt.Printf("Ignored %d files on the ignore list\n", result.SuppressedCount)
+	}
+}
+
+// printFiltered prints how many records each filter flag removed, leaving
+// out flags that removed nothing.
+func printFiltered(result *audit.AuditResult) {
+	for _, c := range result.Filtered {
+		var removed []string
+		if c.Files > 0 {
+			removed = append(removed, fmt.Sprintf("%d files", c.Files))
+		}
+		if c.Shares > 0 {
+			removed = append(removed, fmt.Sprintf("%d shares", c.Shares))
+		}
+		if len(removed) > 0 {
+			fmt.Printf("Filtered out by --%s: %s\n", c.Stage, strings.Join(removed, ", "))
+		}
+	}
+}
+
+// ANSI escape sequences used to color alerts on a terminal.
+const (
+	ansiRed   = "\x1b[1;31m"
+	ansiGreen = "\x1b[32m"
+	ansiReset = "\x1b[0m"
+)
+
+// printAlerts writes a line per alert threshold to w: breaches in red and
+// thresholds met in green when color is set.
+func printAlerts(w io.Writer, alerts []audit.Alert, color bool) {
+	for _, a := range alerts {
+		line, code := fmt.Sprintf("OK: %d %s, within alert.%s of %d", a.Count, a.Label, a.Key, a.Limit), ansiGreen
+		if a.Breached() {
+			line, code = fmt.Sprintf("ALERT: %d %s, above alert.%s of %d", a.Count, a.Label, a.Key, a.Limit), ansiRed
+		}
+		if color {
+			line = code + line + ansiReset
+		}
+		fmt.Fprintln(w, line)
+	}
+}
+
+// useColor reports whether output to f should be colored: f is a terminal
+// and the NO_COLOR environment variable is not set.
+func useColor(f *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// printSampleEstimate notes that a result was sampled and, when label is
+// set, prints the share count extrapolated to all files.
+func printSampleEstimate(result *audit.AuditResult, label string) {
+	if result.SampledFiles == 0 {
+		return
+	}
+
+	fmt.Printf("Sampled %d of %d files\n", result.SampledFiles, result.TotalFiles)
+	if label != "" {
+		fmt.Printf("Estimated %s across all files: %d\n", label, result.Extrapolate(result.TotalExternalShares))
+	}
+}
+
+// newSink creates the result sink selected on the command line, or nil when
+// results are not pushed anywhere.
+func newSink() (sink.Sink, error) {
+	if postURL == "" {
+		return nil, nil
+	}
+
+	httpSink, err := sink.NewHTTPSink(postURL, postHeaders)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create result sink: %w", err)
+	}
+
+	return httpSink, nil
+}
+
+// sendResults pushes the audit results to the sink, if one is configured.
+func sendResults(ctx context.Context, s sink.Sink, cfg *config.Config, results ...*audit.AuditResult) error {
+	if s == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, postTimeout)
+	defer cancel()
+
+	if err := s.Send(ctx, sink.NewPayload(cfg.AuditedDomain(), postRecords, results...)); err != nil {
+		return fmt.Errorf("failed to send results: %w", err)
+	}
+
+	return nil
+}
+
+// printFilesReportPath prints where the files report was written.
+func printFilesReportPath(cfg *config.Config, rep reporter.Reporter) {
+	if cfg.Output.SplitByOwner {
+		fmt.Printf("Reports saved to: %s/%s (index: %s/%s)\n",
+			rep.OutputDir(), reporter.OwnerFilesDir, rep.OutputDir(), rep.FileName("files_index"))
+		return
+	}
+	fmt.Printf("Report saved to: %s\n", reportPath(rep, "files_by_owner"))
+}
+
+// outputDir returns the directory reports are written to: that of
+// output.file when set, otherwise output.directory.
+func outputDir(cfg *config.Config) string {
+	if cfg.Output.File != "" {
+		return filepath.Dir(cfg.Output.File)
+	}
+	return cfg.Output.Directory
+}
+
+// newReporter creates the report writer for the configured output. primary
+// is the base name of the report written under output.file, if set.
+func newReporter(ctx context.Context, cfg *config.Config, primary string) (reporter.Reporter, error) {
+	delim, err := config.ParseDelimiter(cfg.Output.Delimiter)
+	if err != nil {
+		return nil, err
+	}
+
+	formats, err := cfg.Output.EffectiveFormats()
+	if err != nil {
+		return nil, err
+	}
+
+	labels, err := cfg.Output.Labels()
+	if err != nil {
+		return nil, err
+	}
+	if err := reporter.CheckHeaderLabels(labels); err != nil {
+		return nil, exitcode.Wrap(exitcode.ConfigError, err)
+	}
+
+	order, err := audit.NewRecordOrder(cfg.Output.SortOwnersBy, cfg.Output.SortFilesBy)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := reporter.Options{
+		IncludeTrashed:    cfg.Audit.IncludeTrashed,
+		IncludeLinkStatus: cfg.Audit.IncludeLinkStatus,
+		ExpandGroups:      cfg.Audit.ExpandGroups,
+		Explain:           explain,
+		SourceDomain:      len(cfg.Google.Domains) > 0,
+		JSONIndent:        cfg.Output.JSONIndent,
+		JSONFields:        cfg.Output.JSONFields,
+		SplitByOwner:      cfg.Output.SplitByOwner,
+		MaxRowsPerFile:    cfg.Output.MaxRowsPerFile,
+		Delimiter:         delim,
+		HeaderLabels:      labels,
+		Order:             order,
+	}
+	if withAge {
+		opts.AgeAt = time.Now().UTC()
+	}
+	if cfg.Output.File != "" && primary != "" {
+		opts.FileNames = map[string]string{primary: filepath.Base(cfg.Output.File)}
+	}
+
+	if len(formats) > 1 {
+		return reporter.NewMultiReporter(formats, outputDir(cfg), opts)
+	}
+	if formats[0] == reporter.FormatSheets {
+		return newSheetsReporter(ctx, cfg, opts)
+	}
+	return reporter.New(formats[0], outputDir(cfg), opts)
+}
+
+// newSheetsReporter creates a reporter that writes to a new spreadsheet in
+// the admin's Drive.
+func newSheetsReporter(ctx context.Context, cfg *config.Config, opts reporter.Options) (reporter.Reporter, error) {
+	authenticator, err := auth.NewAuthenticator(cfg.Google.ServiceAccountFile, cfg.Google.AdminEmail)
+	if err != nil {
+		return nil, exitcode.Wrap(exitcode.AuthError, fmt.Errorf("failed to create authenticator: %w", err))
+	}
+	authenticator.SetQuotaProject(cfg.Google.QuotaProject)
+	if cfg.Google.ProxyURL != "" || cfg.Google.CACertFile != "" {
+		transport, err := auth.NewTransport(cfg.Google.ProxyURL, cfg.Google.CACertFile)
+		if err != nil {
+			return nil, exitcode.Wrap(exitcode.ConfigError, fmt.Errorf("failed to configure HTTP transport: %w", err))
+		}
+		authenticator.SetTransport(transport)
+	}
+
+	service, err := authenticator.GetSheetsService(ctx)
+	if err != nil {
+		return nil, exitcode.Wrap(exitcode.AuthError, fmt.Errorf("failed to create sheets service: %w", err))
+	}
+
+	title := fmt.Sprintf("gwork audit %s %s", cfg.AuditedDomain(), time.Now().UTC().Format(time.RFC3339))
+	return reporter.NewGSheetReporter(ctx, reporter.NewSheetsAPI(service), title, cfg.Output.Directory, opts)
+}
+
+// reportPath returns where the report named base was written, for printing.
+func reportPath(rep reporter.Reporter, base string) string {
+	if multi, ok := rep.(*reporter.MultiReporter); ok {
+		paths := make([]string, 0, len(multi.Reporters()))
+		for _, r := range multi.Reporters() {
+			paths = append(paths, reportPath(r, base))
+		}
+		return strings.Join(paths, ", ")
+	}
+	if sheet, ok := rep.(*reporter.GSheetReporter); ok {
+		return fmt.Sprintf("%s (sheet %s)", sheet.URL(), rep.FileName(base))
+	}
+	if csvRep, ok := rep.(*reporter.CSVReporter); ok {
+		if n := csvRep.Segments(base); n > 0 {
+			return fmt.Sprintf("%s/%s (%d files)", rep.OutputDir(), reporter.SegmentFileName(rep.FileName(base), 1), n)
+		}
+	}
+	return rep.OutputDir() + "/" + rep.FileName(base)
+}
+
+// runMeta describes the current run for the report manifest. Every flag set
+// on the command line is recorded as an applied filter.
+func runMeta(cmd *cobra.Command, cfg *config.Config) reporter.RunMeta {
+	filters := make(map[string]string)
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		// Secrets such as the salt and post headers are never recorded.
+		switch f.Name {
+		case "config", "verbose", "quiet", "anonymize-salt", "post-header":
+			return
+		}
+		filters[f.Name] = f.Value.String()
+	})
+
+	return reporter.RunMeta{
+		Version:    version,
+		Timestamp:  time.Now().UTC(),
+		Domain:     cfg.AuditedDomain(),
+		Filters:    filters,
+		ConfigFile: cfg.Source(),
+	}
+}
+
+// recordHistory appends the sharing audit totals to the history file, if
+// configured. newShares is the count from writeNewShares, if any.
+func recordHistory(cfg *config.Config, result *audit.AuditResult, newShares *int) error {
+	if cfg.Output.HistoryFile == "" {
+		return nil
+	}
+
+	entry := history.Entry{
+		Timestamp:      time.Now().UTC(),
+		Domain:         cfg.AuditedDomain(),
+		TotalFiles:     result.TotalFiles,
+		ExternalShares: result.TotalExternalShares,
+		PublicShares:   audit.CountPublicShares(result.ExternalShares),
+		NewShares:      newShares,
+	}
+
+	if err := history.Append(cfg.Output.HistoryFile, entry); err != nil {
+		return fmt.Errorf("failed to record history: %w", err)
+	}
+
+	return nil
+}
+
+// shareDiff is the comparison of a sharing result with the new-shares
+// baseline kept next to the history file. It is made before filters and
+// --anonymize change the shares, so the baseline always holds every share
+// as found.
+type shareDiff struct {
+	path string
+	// added holds the shares missing from the baseline, or is nil when
+	// there is no baseline to compare against.
+	added *audit.AuditResult
+	// keys replace the baseline, unless nil because the result does not
+	// hold every share.
+	keys []string
+}
+
+// compareBaseline compares result with the baseline kept next to the
+// history file. A baseline saved for another google.domain is ignored. It
+// returns an empty diff when no history file is configured.
+func compareBaseline(cfg *config.Config, result *audit.AuditResult) (*shareDiff, error) {
+	if cfg.Output.HistoryFile == "" {
+		return &shareDiff{}, nil
+	}
+
+	diff := &shareDiff{path: history.BaselinePath(cfg.Output.HistoryFile)}
+	baseline, err := history.ReadBaseline(diff.path)
+	if err != nil {
+		return nil, err
+	}
+	if baseline != nil && baseline.Domain == cfg.AuditedDomain() {
+		diff.added = &audit.AuditResult{ExternalShares: audit.NewShares(result.ExternalShares, baseline.Keys)}
+	}
+
+	// Sampled, partial and truncated results do not hold every share, so
+	// they are compared but never become the baseline.
+	if result.SampledFiles == 0 && !result.BudgetExceeded && !result.Truncated {
+		diff.keys = audit.ShareKeys(result.ExternalShares)
+	}
+	return diff, nil
+}
+
+// writeNewShares writes the new shares of diff, filtered and anonymized
+// like the other reports by then, and replaces the baseline. It returns the
+// number of new shares, or nil when no history file is configured or there
+// is no baseline yet.
+func writeNewShares(cfg *config.Config, rep reporter.Reporter, diff *shareDiff) (*int, error) {
+	if diff.path == "" {
+		return nil, nil
+	}
+
+	var count *int
+	if diff.added != nil {
+		if err := rep.WriteNewShares(diff.added.ExternalShares); err != nil {
+			return nil, fmt.Errorf("failed to write new shares report: %w", err)
+		}
+		n := len(diff.added.ExternalShares)
+		count = &n
+	}
+
+	if diff.keys == nil {
+		return count, nil
+	}
+	snapshot := history.Baseline{
+		Timestamp: time.Now().UTC(),
+		Domain:    cfg.AuditedDomain(),
+		Keys:      diff.keys,
+	}
+	if err := history.WriteBaseline(diff.path, snapshot); err != nil {
+		return nil, err
+	}
+
+	return count, nil
+}
+
+// writeRemediationScript writes the --remediation-script, if requested,
+// suggesting revocations for the public and high-risk shares in records.
+func writeRemediationScript(records []audit.ExternalShareRecord) error {
+	if remediationScript == "" {
+		return nil
+	}
+	if err := reporter.WriteRemediationScript(remediationScript, records); err != nil {
+		return err
+	}
+	if !quiet {
+		fmt.Printf("Remediation script saved to: %s\n", remediationScript)
+	}
+	return nil
+}
+
+// printNewShares prints the number of shares new since the last run, if
+// there was one to compare against.
+func printNewShares(count *int) {
+	if count == nil {
+		return
+	}
+	fmt.Printf("New external shares since last run: %d\n", *count)
+}
+
+// runVerify checks the report files of an output directory against its
+// SHA256SUMS, printing the result of each file.
+func runVerify(cmd *cobra.Command, args []string) error {
+	results, err := reporter.VerifyChecksums(args[0])
+	if err != nil {
+		return exitcode.Wrap(exitcode.ConfigError, err)
+	}
+
+	out := cmd.OutOrStdout()
+	failed := 0
+	for _, result := range results {
+		switch {
+		case result.Err == nil:
+			fmt.Fprintf(out, "%s: OK\n", result.Path)
+			continue
+		case errors.Is(result.Err, reporter.ErrChecksumMismatch):
+			fmt.Fprintf(out, "%s: FAIL (checksum mismatch)\n", result.Path)
+		case errors.Is(result.Err, os.ErrNotExist):
+			fmt.Fprintf(out, "%s: FAIL (missing)\n", result.Path)
+		default:
+			fmt.Fprintf(out, "%s: FAIL (%v)\n", result.Path, result.Err)
+		}
+		failed++
+	}
+
+	if failed > 0 {
+		return exitcode.Wrap(exitcode.VerificationFailed,
+			fmt.Errorf("%d of %d files failed verification", failed, len(results)))
+	}
+	return nil
+}
+
+// tuiPageSize is the number of shares gwork tui shows per page.
+const tuiPageSize = 20
+
+// runTUI browses the external shares of a live sharing audit, or of the
+// report given with --report.
+func runTUI(cmd *cobra.Command, args []string) error {
+	var records []audit.ExternalShareRecord
+	if tuiReport != "" {
+		loaded, err := tui.LoadShares(tuiReport)
+		if err != nil {
+			return exitcode.Wrap(exitcode.ConfigError, err)
+		}
+		records = loaded
+	} else {
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		if len(cfg.Google.Domains) > 0 {
+			return exitcode.Wrap(exitcode.ConfigError,
+				errors.New("google.domains is not supported by tui; browse an audit all report with --report"))
+		}
+
+		ctx, stop := interruptContext()
+		defer stop()
+		auditor, err := newAuditor(ctx, cmd, cfg)
+		if err != nil {
+			return err
+		}
+
+		if !quiet {
+			fmt.Println("Analyzing external sharing...")
+		}
+
+		result, err := auditor.AuditExternalSharing(ctx)
+		// The browser reads Ctrl-C as a key.
+		stop()
+		if err != nil {
+			return fmt.Errorf("audit failed: %w", err)
+		}
+		if err := postProcess(cfg, result); err != nil {
+			return err
+		}
+		records = result.ExternalShares
+	}
+
+	return tui.Run(cmd.InOrStdin(), cmd.OutOrStdout(), tui.NewModel(records, tuiPageSize))
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if cfg.Output.HistoryFile == "" {
+		return fmt.Errorf("output.history_file is not configured")
+	}
+
+	entries, err := history.Read(cfg.Output.HistoryFile)
+	if err != nil {
+		return err
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No audit runs recorded.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIMESTAMP\tDOMAIN\tFILES\tEXTERNAL\tPUBLIC")
+	for _, e := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\n",
+			e.Timestamp.Format(time.RFC3339), e.Domain, e.TotalFiles, e.ExternalShares, e.PublicShares)
+	}
+	return w.Flush()
+}
+
+// printWarnings prints how many errors the audit hit, listing the stored
+// errors in verbose mode and noting any dropped once the error cap was
+// reached. cfg decides how unrecognized permission types are described.
+func printWarnings(cfg *config.Config, result *audit.AuditResult, what string) {
+	if result.BudgetExceeded {
+		fmt.Println("Warning: API call budget (audit.max_api_calls) reached; results are partial")
+	}
+	if len(result.UnknownPermissionTypes) > 0 {
+		outcome := "their shares are only reported with --strict-unknown"
+		if cfg.Audit.StrictUnknown {
+			outcome = "their shares are reported as external because of --strict-unknown"
+		}
+		fmt.Printf("Warning: Drive returned permission types gwork does not recognize: %s; %s\n",
+			strings.Join(result.UnknownPermissionTypes, ", "), outcome)
+	}
+	if result.UnexpandedGroups > 0 {
+		fmt.Printf("Note: %d groups outside google.domain were not expanded; "+
+			"the Directory API only lists members of groups in your own Workspace\n", result.UnexpandedGroups)
+	}
+	if result.Truncated {
+		fmt.Printf("Warning: --max-rows kept %d of %d rows; the report is truncated\n",
+			len(result.FileRecords)+len(result.ExternalShares), result.UntruncatedRows)
+	}
+	if result.ErrorCount() == 0 {
+		return
+	}
+	fmt.Printf("Warnings: %d %s\n", result.ErrorCount(), what)
+	if verbose {
+		for _, e := range result.Errors {
+			fmt.Printf("  - %v\n", e)
+		}
+	}
+	if result.DroppedErrorCount > 0 {
+		fmt.Printf("  (%d errors not kept after reaching audit.max_errors)\n", result.DroppedErrorCount)
+	}
+}
+
+// warnOwnersMissing writes a warning to w for each result whose files all
+// lack an owner. It is printed even with --quiet, as the report it comes
+// with has an empty owner column.
+func warnOwnersMissing(w io.Writer, results ...*audit.AuditResult) {
+	for _, result := range results {
+		if result.OwnersMissing > 0 {
+			fmt.Fprintf(w, "Warning: 0 owners resolved across %d files; Drive did not return the owners field. "+
+				"Check that audit.file_fields includes owners and that domain-wide delegation grants %s\n",
+				result.OwnersMissing, strings.Join(auth.DriveScopes, ", "))
+		}
+	}
+}
+
+// printTiming prints where the audit spent its time in verbose mode.
+func printTiming(result *audit.AuditResult) {
+	if !verbose {
+		return
+	}
+
+	t := result.Timing
+	fmt.Printf("Timing: total %s, listing files %s", t.Total.Round(time.Millisecond), t.ListFiles.Round(time.Millisecond))
+	if t.FetchPermissions > 0 {
+		fmt.Printf(", fetching permissions %s", t.FetchPermissions.Round(time.Millisecond))
+	}
+	fmt.Println()
+
+	total := t.API.Total()
+	if total.Calls == 0 {
+		return
+	}
+	fmt.Printf("API calls: %d (files.list %d, avg %s; permissions.list %d, avg %s)\n",
+		total.Calls,
+		t.API.ListFiles.Calls, t.API.ListFiles.Average().Round(time.Millisecond),
+		t.API.ListPermissions.Calls, t.API.ListPermissions.Average().Round(time.Millisecond))
+}
+
+// writeRoleDistribution writes the role distribution report when enabled.
+func writeRoleDistribution(cfg *config.Config, rep reporter.Reporter, records []audit.ExternalShareRecord) error {
+	if !cfg.Output.RoleDistribution {
+		return nil
+	}
+	if err := rep.WriteRoleDistribution(roleDistribution(cfg, records)); err != nil {
+		return fmt.Errorf("failed to write role distribution: %w", err)
+	}
+	return nil
+}
+
+// printRoleDistribution prints share counts by scope and role.
+func printRoleDistribution(cfg *config.Config, records []audit.ExternalShareRecord) error {
+	counts := roleDistribution(cfg, records)
+	if len(counts) == 0 {
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SCOPE\tROLE\tSHARES")
+	for _, c := range counts {
+		fmt.Fprintf(w, "%s\t%s\t%d\n", c.Scope, c.Role, c.Count)
+	}
+	return w.Flush()
+}
+
+// roleDistribution counts records by scope and role, treating the primary
+// domain and its aliases as internal.
+func roleDistribution(cfg *config.Config, records []audit.ExternalShareRecord) []audit.RoleCount {
+	internal := append([]string{cfg.Google.Domain}, cfg.Google.DomainAliases...)
+	return audit.RoleDistribution(records, internal...)
+}
+
+// printCategorySummary prints file counts and sizes per file category.
+func printCategorySummary(records []audit.FileRecord) error {
+	summaries := audit.SummarizeByCategory(records)
+	if len(summaries) == 0 {
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CATEGORY\tFILES\tBYTES")
+	for _, c := range summaries {
+		fmt.Fprintf(w, "%s\t%d\t%d\n", c.Category, c.FileCount, c.TotalBytes)
+	}
+	return w.Flush()
+}
+
+func runConfigShow(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return exitcode.Wrap(exitcode.ConfigError, fmt.Errorf("failed to load config: %w", err))
+	}
+
+	out := cmd.OutOrStdout()
+	if source := cfg.Source(); source != "" {
+		fmt.Fprintf(out, "# Loaded from %s\n", source)
+	}
+
+	// Only the service account file path is part of the config; the key
+	// itself is never read here.
+	return cfg.Write(out)
+}
+
+func runConfigSchema(cmd *cobra.Command, args []string) error {
+	enc := json.NewEncoder(cmd.OutOrStdout())
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(config.Schema()); err != nil {
+		return fmt.Errorf("failed to write schema: %w", err)
+	}
 	return nil
 }
 
@@ -4,6 +4,8 @@
 // Package exitcode defines exit codes for the gwork CLI.
 package exitcode
 
+import "errors"
+
 const (
 	// Success indicates the command completed successfully.
 	Success = 0
@@ -17,6 +19,71 @@ const (
 	// APIError indicates a Google API error.
 	APIError = 3
 
+	// FindingsDetected indicates the audit completed but found shares above
+	// the --fail-above risk threshold, or breached an alert threshold with
+	// alert.fail_on_breach set.
+	FindingsDetected = 4
+
+	// VerificationFailed indicates that report files did not match their
+	// recorded checksums.
+	VerificationFailed = 5
+
 	// InternalError indicates an internal error.
 	InternalError = 10
 )
+
+// Error is an error that carries the exit code the CLI should exit with.
+type Error struct {
+	Code int
+	Err  error
+}
+
+// Error returns the message of the wrapped error.
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Wrap attaches an exit code to err. It returns nil if err is nil.
+func Wrap(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Code: code, Err: err}
+}
+
+// FromError returns the exit code for err: Success for nil, the code of the
+// first Error in its chain, or InternalError otherwise.
+func FromError(err error) int {
+	if err == nil {
+		return Success
+	}
+	var exitErr *Error
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return InternalError
+}
+
+// Category returns the failure category for an exit code: "config", "auth",
+// "api", "findings", "verification" or "internal".
+func Category(code int) string {
+	switch code {
+	case ConfigError:
+		return "config"
+	case AuthError:
+		return "auth"
+	case APIError:
+		return "api"
+	case FindingsDetected:
+		return "findings"
+	case VerificationFailed:
+		return "verification"
+	default:
+		return "internal"
+	}
+}
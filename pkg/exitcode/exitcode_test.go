@@ -4,6 +4,8 @@
 package exitcode
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -56,8 +58,53 @@ func TestExitCodesAreUnique(t *testing.T) {
 		AuthError:     "AuthError",
 		APIError:      "APIError",
 		InternalError: "InternalError",
+
+		FindingsDetected:   "FindingsDetected",
+		VerificationFailed: "VerificationFailed",
 	}
 
 	// Ensure all codes are unique
-	assert.Equal(t, 5, len(codes), "All exit codes should be unique")
+	assert.Equal(t, 7, len(codes), "All exit codes should be unique")
+}
+
+func TestFromError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{name: "nil error", err: nil, expected: Success},
+		{name: "plain error", err: errors.New("boom"), expected: InternalError},
+		{name: "wrapped code", err: Wrap(ConfigError, errors.New("bad config")), expected: ConfigError},
+		{
+			name:     "code further down the chain",
+			err:      fmt.Errorf("command failed: %w", Wrap(APIError, errors.New("quota"))),
+			expected: APIError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, FromError(tt.err))
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+	assert.NoError(t, Wrap(ConfigError, nil))
+
+	cause := errors.New("bad config")
+	err := Wrap(ConfigError, cause)
+	assert.Equal(t, "bad config", err.Error())
+	assert.ErrorIs(t, err, cause)
+}
+
+func TestCategory(t *testing.T) {
+	assert.Equal(t, "config", Category(ConfigError))
+	assert.Equal(t, "auth", Category(AuthError))
+	assert.Equal(t, "api", Category(APIError))
+	assert.Equal(t, "findings", Category(FindingsDetected))
+	assert.Equal(t, "verification", Category(VerificationFailed))
+	assert.Equal(t, "internal", Category(InternalError))
+	assert.Equal(t, "internal", Category(42))
 }
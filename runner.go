@@ -0,0 +1,257 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+	"github.com/leansecurity-co/gwork/internal/config"
+	"github.com/leansecurity-co/gwork/internal/reporter"
+	"github.com/leansecurity-co/gwork/internal/sink"
+	"github.com/spf13/cobra"
+)
+
+// auditJob is what sets an audit subcommand apart: the audit it runs and
+// the reports it writes. runAuditJob does the steps they share.
+type auditJob struct {
+	// check rejects option combinations the subcommand does not support.
+	check func(cmd *cobra.Command, cfg *config.Config) error
+	// ownAuditors is set when audit creates its auditors itself, so
+	// run.auditor is left nil.
+	ownAuditors bool
+	// snapshots sets the file permissions saved by the previous
+	// incremental run on run.auditor, and saves those of run.sharing.
+	snapshots bool
+	// audit runs the audit, setting run.files or run.sharing. Audits that
+	// write their rows as they run also set run.rep, and run.alerts.
+	audit func(run *auditRun) error
+	// alerts evaluates the alert thresholds on run.sharing.
+	alerts bool
+	// write writes the reports of run.filesReport or run.sharingReport,
+	// setting run.rep.
+	write func(run *auditRun) error
+	// history writes the new shares report and records run.sharing in
+	// output.history_file. Audits that filter their shares themselves
+	// also set run.diff first.
+	history bool
+	// summary prints the outcome unless --quiet.
+	summary func(run *auditRun) error
+}
+
+// auditRun is the state of an audit subcommand run by runAuditJob.
+type auditRun struct {
+	cmd     *cobra.Command
+	cfg     *config.Config
+	ctx     context.Context
+	sink    sink.Sink
+	auditor *audit.Auditor
+
+	// files and sharing are the results of the audit: subcommands set the
+	// one they run, audit all both. The reports are the same results
+	// truncated to --max-rows.
+	files, sharing             *audit.AuditResult
+	filesReport, sharingReport *audit.AuditResult
+
+	alerts     []audit.Alert
+	checkpoint *os.File
+	diff       *shareDiff
+	newShares  *int
+	rep        reporter.Reporter
+}
+
+// runAuditJob runs the audit subcommand job: it loads the config, checks
+// that the reports can be written before the audit starts, runs the audit
+// and post-processes its results, then writes the reports and manifest,
+// sends the results to --post-url and returns the findings exit code.
+func runAuditJob(cmd *cobra.Command, job auditJob) error {
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if job.check != nil {
+		if err := job.check(cmd, cfg); err != nil {
+			return err
+		}
+	}
+
+	if !toStdout {
+		if err := checkOutputWritable(cfg); err != nil {
+			return err
+		}
+	}
+
+	resultSink, err := newSink()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := interruptContext()
+	defer stop()
+
+	run := &auditRun{cmd: cmd, cfg: cfg, ctx: ctx, sink: resultSink}
+	return run.execute(job)
+}
+
+// interruptContext returns a context canceled by Ctrl-C or SIGTERM, so
+// authentication and the audit stop and a checkpointed audit can be
+// resumed. Once it is canceled, a second Ctrl-C ends the process. stop
+// releases the signals.
+func interruptContext() (ctx context.Context, stop context.CancelFunc) {
+	ctx, stop = signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	context.AfterFunc(ctx, stop)
+	return ctx, stop
+}
+
+// execute runs job once the config is loaded and checked. run.auditor is
+// created unless already set or the job creates its own.
+func (run *auditRun) execute(job auditJob) error {
+	// The combined document or the totals are the only thing written to
+	// stdout.
+	if toStdout || countOnly {
+		quiet, verbose = true, false
+	}
+
+	cfg := run.cfg
+	if run.auditor == nil && !job.ownAuditors {
+		auditor, err := newAuditor(run.ctx, run.cmd, cfg)
+		if err != nil {
+			return err
+		}
+		run.auditor = auditor
+	}
+	if job.snapshots && run.auditor != nil {
+		if err := useFileSnapshots(cfg, run.auditor); err != nil {
+			return err
+		}
+	}
+	defer func() {
+		if run.checkpoint != nil {
+			run.checkpoint.Close()
+		}
+	}()
+
+	if err := job.audit(run); err != nil {
+		return err
+	}
+	warnOwnersMissing(os.Stderr, run.results()...)
+
+	if job.history && run.diff == nil {
+		diff, err := compareBaseline(cfg, run.sharing)
+		if err != nil {
+			return err
+		}
+		run.diff = diff
+	}
+
+	if job.snapshots {
+		if err := saveFileSnapshots(cfg, run.sharing); err != nil {
+			return err
+		}
+	}
+
+	if run.rep != nil {
+		// The audit filtered and wrote its rows as it ran.
+		run.filesReport, run.sharingReport = run.files, run.sharing
+	} else {
+		processed := run.results()
+		if run.diff != nil && run.diff.added != nil {
+			processed = append(processed, run.diff.added)
+		}
+		if err := postProcess(cfg, processed...); err != nil {
+			return err
+		}
+		if job.alerts {
+			run.alerts = audit.EvaluateAlerts(cfg.Alert, run.sharing)
+		}
+
+		if countOnly {
+			if err := printCounts(os.Stdout, run.results()...); err != nil {
+				return err
+			}
+			if err := sendResults(run.ctx, run.sink, cfg, run.results()...); err != nil {
+				return err
+			}
+			return checkFindings(run.cmd, cfg, run.alerts, run.results()...)
+		}
+
+		// --max-rows caps the rows of all reports together, keeping shares
+		// before files as within one report.
+		reports := audit.WithMaxRowsTotal(maxRows, run.sharing, run.files)
+		run.sharingReport, run.filesReport = reports[0], reports[1]
+		if err := job.write(run); err != nil {
+			return err
+		}
+	}
+
+	if job.history && run.rep != nil {
+		newShares, err := writeNewShares(cfg, run.rep, run.diff)
+		if err != nil {
+			return err
+		}
+		run.newShares = newShares
+	}
+
+	if run.rep != nil {
+		if err := run.rep.WriteManifest(runMeta(run.cmd, cfg)); err != nil {
+			return fmt.Errorf("failed to write manifest: %w", err)
+		}
+	}
+
+	if job.history {
+		if err := recordHistory(cfg, run.sharing, run.newShares); err != nil {
+			return err
+		}
+	}
+
+	if err := sendResults(run.ctx, run.sink, cfg, run.results()...); err != nil {
+		return err
+	}
+
+	if err := finishCheckpoint(run.checkpoint, run.sharing); err != nil {
+		return err
+	}
+
+	if !quiet {
+		if err := job.summary(run); err != nil {
+			return err
+		}
+	}
+
+	return checkFindings(run.cmd, cfg, run.alerts, run.results()...)
+}
+
+// results returns the results of the audit, files first.
+func (run *auditRun) results() []*audit.AuditResult {
+	var results []*audit.AuditResult
+	for _, result := range []*audit.AuditResult{run.files, run.sharing} {
+		if result != nil {
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// openReporter creates the reporter of the run; primary is as for
+// newReporter.
+func (run *auditRun) openReporter(primary string) (reporter.Reporter, error) {
+	rep, err := newReporter(run.ctx, run.cfg, primary)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reporter: %w", err)
+	}
+	run.rep = rep
+	return rep, nil
+}
+
+// progress prints msg unless --quiet.
+func progress(msg string) {
+	if !quiet {
+		fmt.Println(msg)
+	}
+}
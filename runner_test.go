@@ -0,0 +1,88 @@
+// Copyright 2025 Lean Security Co.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/leansecurity-co/gwork/internal/audit"
+	"github.com/leansecurity-co/gwork/internal/drive"
+	"github.com/leansecurity-co/gwork/internal/drive/drivetest"
+	"github.com/leansecurity-co/gwork/internal/sink"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditRun_CountOnly(t *testing.T) {
+	oldQuiet, oldVerbose := quiet, verbose
+	quiet, verbose = false, false
+	t.Cleanup(func() { quiet, verbose, countOnly = oldQuiet, oldVerbose, false })
+
+	var posts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts.Add(1)
+	}))
+	t.Cleanup(server.Close)
+	resultSink, err := sink.NewHTTPSink(server.URL, nil)
+	require.NoError(t, err)
+
+	cfg := newTestConfig(t)
+	cfg.Output.Directory = t.TempDir()
+	client := drive.NewClientWithOptions(&drivetest.FakeAPI{Files: 10}, drive.Options{Domain: "example.com"})
+	run := &auditRun{
+		cmd:     newTestAuditCmd(t, "--count-only"),
+		cfg:     cfg,
+		ctx:     context.Background(),
+		sink:    resultSink,
+		auditor: audit.NewAuditorWithClient(cfg, client),
+	}
+
+	out := captureStdout(t, func() {
+		require.NoError(t, run.execute(auditJob{
+			audit: auditAllFiles,
+			write: func(run *auditRun) error {
+				t.Error("count-only runs write no reports")
+				return nil
+			},
+			summary: printFilesSummary,
+		}))
+	})
+
+	// Progress messages are suppressed, so stdout is only the totals.
+	var counts map[string]any
+	require.NoError(t, json.Unmarshal([]byte(out), &counts), out)
+	assert.EqualValues(t, 10, counts["files"])
+	assert.True(t, quiet)
+
+	assert.EqualValues(t, 1, posts.Load(), "results are still sent to --post-url")
+
+	entries, err := os.ReadDir(cfg.Output.Directory)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestInterruptContext(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("os.Interrupt cannot be sent to a process on Windows")
+	}
+	ctx, stop := interruptContext()
+	defer stop()
+
+	self, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	require.NoError(t, self.Signal(os.Interrupt))
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Ctrl-C did not cancel the context")
+	}
+}